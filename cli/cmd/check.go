@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"text/tabwriter"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/linkerd/linkerd2/controller/api/public"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -14,25 +22,72 @@ const (
 	retryStatus   = "[retry]"
 	failStatus    = "[FAIL]"
 	warningStatus = "[warning]"
+	skippedStatus = "[skipped]"
 )
 
 type checkOptions struct {
 	versionOverride string
 	preInstallOnly  bool
+	preUpgradeOnly  bool
 	dataPlaneOnly   bool
 	wait            time.Duration
 	namespace       string
 	singleNamespace bool
+	certExpiry      time.Duration
+	proxySample     int
+	proxyLogLines   int
+	stuckTimeout    time.Duration
+	cniNamespace    string
+	skipChecks      []string
+	asUser          string
+	asGroups        []string
+	requestTimeout  time.Duration
+	caFile          string
+	insecure        bool
+	kubeQPS         float32
+	kubeBurst       int
+	minKubeVersion  string
+	versionTimeout  time.Duration
+	versionURL      string
+	versionCAFile   string
+	versionCacheTTL time.Duration
+	anonymous       bool
+	showVersionURL  bool
+	apiPayloads     bool
+	apiProxyPath    public.ProxyPathOptions
+	outputFormat    string
 }
 
 func newCheckOptions() *checkOptions {
 	return &checkOptions{
 		versionOverride: "",
 		preInstallOnly:  false,
+		preUpgradeOnly:  false,
 		dataPlaneOnly:   false,
 		wait:            300 * time.Second,
 		namespace:       "",
 		singleNamespace: false,
+		certExpiry:      time.Hour,
+		proxySample:     10,
+		proxyLogLines:   0,
+		stuckTimeout:    5 * time.Minute,
+		cniNamespace:    "linkerd-cni",
+		skipChecks:      []string{},
+		asUser:          "",
+		asGroups:        []string{},
+		requestTimeout:  k8s.DefaultRequestTimeout,
+		kubeQPS:         k8s.DefaultQPS,
+		kubeBurst:       k8s.DefaultBurst,
+		minKubeVersion:  "",
+		versionTimeout:  version.DefaultLatestVersionTimeout,
+		versionURL:      os.Getenv("LINKERD_VERSION_CHECK_URL"),
+		versionCAFile:   "",
+		versionCacheTTL: version.DefaultLatestVersionCacheTTL,
+		anonymous:       envBool("LINKERD_VERSION_CHECK_ANONYMOUS"),
+		showVersionURL:  false,
+		apiPayloads:     false,
+		apiProxyPath:    public.ProxyPathOptions{},
+		outputFormat:    "",
 	}
 }
 
@@ -54,21 +109,64 @@ non-zero exit code.`,
   # Check that the Linkerd control plane can be installed in the "test" namespace
   linkerd check --pre --linkerd-namespace test
 
+  # Check that the Linkerd control plane can be upgraded in place
+  linkerd check --pre-upgrade
+
   # Check that the Linkerd data plane proxies in the "app" namespace are up and running
   linkerd check --proxy --namespace app`,
 		Args: cobra.NoArgs,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validateOutputFormat(); err != nil {
+				return err
+			}
+			if options.preInstallOnly && options.preUpgradeOnly {
+				return fmt.Errorf("--pre and --pre-upgrade are mutually exclusive")
+			}
+			if _, err := options.kubeVersionFloor(); err != nil {
+				return err
+			}
+			if err := options.apiProxyPath.Validate(); err != nil {
+				return err
+			}
+
 			configureAndRunChecks(options)
+			return nil
 		},
 	}
 
 	cmd.Args = cobra.NoArgs
 	cmd.PersistentFlags().StringVar(&options.versionOverride, "expected-version", options.versionOverride, "Overrides the version used when checking if Linkerd is running the latest version (mostly for testing)")
 	cmd.PersistentFlags().BoolVar(&options.preInstallOnly, "pre", options.preInstallOnly, "Only run pre-installation checks, to determine if the control plane can be installed")
+	cmd.PersistentFlags().BoolVar(&options.preUpgradeOnly, "pre-upgrade", options.preUpgradeOnly, "Only run pre-upgrade checks, to determine if the control plane can be upgraded in place")
 	cmd.PersistentFlags().BoolVar(&options.dataPlaneOnly, "proxy", options.dataPlaneOnly, "Only run data-plane checks, to determine if the data plane is healthy")
 	cmd.PersistentFlags().DurationVar(&options.wait, "wait", options.wait, "Retry and wait for some checks to succeed if they don't pass the first time")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace to use for --proxy checks (default: all namespaces)")
 	cmd.PersistentFlags().BoolVar(&options.singleNamespace, "single-namespace", options.singleNamespace, "When running pre-installation checks (--pre), only check the permissions required to operate the control plane in a single namespace")
+	cmd.PersistentFlags().DurationVar(&options.certExpiry, "cert-expiry-window", options.certExpiry, "When running data-plane checks (--proxy), warn if a proxy certificate expires within this window")
+	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; currently only \"table\" (default) and \"json\" are supported")
+	cmd.PersistentFlags().IntVar(&options.proxySample, "proxy-sample-size", options.proxySample, "When running data-plane checks (--proxy), the maximum number of proxies per namespace to scrape metrics from")
+	cmd.PersistentFlags().IntVar(&options.proxyLogLines, "proxy-log-lines", options.proxyLogLines, "When running data-plane checks (--proxy) for a single namespace (--namespace), scan this many trailing lines of each sampled proxy's logs for known errors (default: disabled)")
+	cmd.PersistentFlags().DurationVar(&options.stuckTimeout, "stuck-terminating-timeout", options.stuckTimeout, "When running data-plane checks (--proxy), warn about pods that have been Terminating for longer than this with only the proxy still running")
+	cmd.PersistentFlags().StringSliceVar(&options.skipChecks, "skip-checks", options.skipChecks, "Checks to skip, given as a category (e.g. \"linkerd-data-plane\") or as a category and description, as printed (e.g. \"linkerd-data-plane: data plane proxies are ready\")")
+	cmd.PersistentFlags().StringVar(&options.asUser, "as", options.asUser, "Username to impersonate for Kubernetes API requests, to check whether the checks pass for that user")
+	cmd.PersistentFlags().StringSliceVar(&options.asGroups, "as-group", options.asGroups, "Group to impersonate for Kubernetes API requests; can be repeated to specify multiple groups")
+	cmd.PersistentFlags().DurationVar(&options.requestTimeout, "request-timeout", options.requestTimeout, "Timeout for each request made to the Kubernetes API")
+	cmd.PersistentFlags().StringVar(&options.caFile, "certificate-authority", options.caFile, "Path to a PEM-encoded CA bundle to verify the Kubernetes API server's certificate with, instead of the kubeconfig's")
+	cmd.PersistentFlags().BoolVar(&options.insecure, "insecure-skip-tls-verify", options.insecure, "Do not verify the Kubernetes API server's certificate; its connections may be intercepted, so this is reported as a warning")
+	cmd.PersistentFlags().Float32Var(&options.kubeQPS, "kube-qps", options.kubeQPS, "Maximum sustained rate of requests per second made to the Kubernetes API; a negative value disables the limit")
+	cmd.PersistentFlags().IntVar(&options.kubeBurst, "kube-burst", options.kubeBurst, "Maximum number of requests made to the Kubernetes API at once, above the --kube-qps rate")
+	cmd.PersistentFlags().StringVar(&options.minKubeVersion, "min-kube-version", options.minKubeVersion, "Oldest Kubernetes version to accept, e.g. \"1.12.0\" (default: the oldest version supported by the control plane, or required to install it with --pre)")
+	cmd.PersistentFlags().DurationVar(&options.versionTimeout, "version-check-timeout", options.versionTimeout, "Timeout for each request made to the version endpoint to determine the latest Linkerd version")
+	cmd.PersistentFlags().StringVar(&options.versionURL, "version-check-url", options.versionURL, "Base URL of an alternate endpoint serving version.json, such as an internal mirror, to determine the latest Linkerd version from [$LINKERD_VERSION_CHECK_URL]")
+	cmd.PersistentFlags().StringVar(&options.versionCAFile, "version-check-ca-file", options.versionCAFile, "Path to a PEM-encoded CA bundle to verify the version endpoint's certificate with")
+	cmd.PersistentFlags().DurationVar(&options.versionCacheTTL, "version-check-cache-ttl", options.versionCacheTTL, "How long the latest Linkerd version is cached for before the version endpoint is queried again; a negative value disables the cache")
+	cmd.PersistentFlags().BoolVar(&options.anonymous, "anonymous-version-check", options.anonymous, "Do not send the CLI version, the install's UUID or any other parameter when querying the version endpoint [$LINKERD_VERSION_CHECK_ANONYMOUS]")
+	cmd.PersistentFlags().BoolVar(&options.showVersionURL, "show-version-check-request", options.showVersionURL, "Print the request made to the version endpoint, including the parameters sent with it, once the checks have run")
+	cmd.PersistentFlags().BoolVar(&options.apiPayloads, "log-api-payloads", options.apiPayloads, "In verbose mode, also log the messages of the calls made to the public API, which may include the names of workloads")
+	cmd.PersistentFlags().StringVar(&options.apiProxyPath.Namespace, "api-proxy-namespace", options.apiProxyPath.Namespace, "Namespace of the service the public API is reached at through the Kubernetes API server's service proxy (default: the control plane's namespace)")
+	cmd.PersistentFlags().StringVar(&options.apiProxyPath.ServiceName, "api-proxy-service", options.apiProxyPath.ServiceName, "Name of the service the public API is reached at through the Kubernetes API server's service proxy (default: \"api\")")
+	cmd.PersistentFlags().StringVar(&options.apiProxyPath.PortName, "api-proxy-port", options.apiProxyPath.PortName, "Name or number of the port of the service the public API is reached at through the Kubernetes API server's service proxy (default: \"http\")")
+	cmd.PersistentFlags().StringVar(&options.cniNamespace, "cni-namespace", options.cniNamespace, "Namespace in which the linkerd-cni DaemonSet is installed, when the control plane runs in CNI mode")
 
 	return cmd
 }
@@ -78,30 +176,99 @@ func configureAndRunChecks(options *checkOptions) {
 
 	if options.preInstallOnly {
 		checks = append(checks, healthcheck.LinkerdPreInstallChecks)
+	} else if options.preUpgradeOnly {
+		checks = append(checks, healthcheck.LinkerdPreUpgradeChecks)
 	} else if options.dataPlaneOnly {
 		checks = append(checks, healthcheck.LinkerdAPIChecks)
+		checks = append(checks, healthcheck.LinkerdControlPlaneChecks)
 		checks = append(checks, healthcheck.LinkerdDataPlaneChecks)
 	} else {
 		checks = append(checks, healthcheck.LinkerdAPIChecks)
+		checks = append(checks, healthcheck.LinkerdControlPlaneChecks)
+	}
+
+	if !options.preInstallOnly && !options.preUpgradeOnly {
+		checks = append(checks, healthcheck.LinkerdCNIPluginChecks)
+		checks = append(checks, healthcheck.LinkerdMulticlusterChecks)
+		checks = append(checks, healthcheck.LinkerdExtensionChecks)
 	}
 
 	checks = append(checks, healthcheck.LinkerdVersionChecks)
 
+	// validated by the command before running the checks
+	minKubeVersion, _ := options.kubeVersionFloor()
+
+	// in verbose mode, every Kubernetes API request is logged, and summarized
+	// once the checks have run
+	var requestStats *k8s.RequestStats
+	var requestRecorder k8s.RequestRecorder
+	var callRecorder public.CallRecorder
+	if verbose {
+		requestStats = k8s.NewRequestStats()
+		requestRecorder = &loggingRequestRecorder{stats: requestStats}
+		callRecorder = loggingCallRecorder{}
+	}
+
 	hc := healthcheck.NewHealthChecker(checks, &healthcheck.HealthCheckOptions{
 		ControlPlaneNamespace:          controlPlaneNamespace,
 		DataPlaneNamespace:             options.namespace,
 		KubeConfig:                     kubeconfigPath,
 		KubeContext:                    kubeContext,
 		APIAddr:                        apiAddr,
+		APITLS:                         apiTLSOptions(),
 		VersionOverride:                options.versionOverride,
 		RetryDeadline:                  time.Now().Add(options.wait),
 		ShouldCheckKubeVersion:         true,
-		ShouldCheckControlPlaneVersion: !(options.preInstallOnly || options.dataPlaneOnly),
+		ShouldCheckControlPlaneVersion: !(options.preInstallOnly || options.preUpgradeOnly || options.dataPlaneOnly),
 		ShouldCheckDataPlaneVersion:    options.dataPlaneOnly,
 		SingleNamespace:                options.singleNamespace,
+		CertExpiryWarningWindow:        options.certExpiry,
+		MaxSampledProxies:              options.proxySample,
+		ProxyLogLines:                  options.proxyLogLines,
+		StuckTerminatingTimeout:        options.stuckTimeout,
+		CNINamespace:                   options.cniNamespace,
+		SkipChecks:                     options.skipChecks,
+		ImpersonateUser:                options.asUser,
+		ImpersonateGroups:              options.asGroups,
+		KubeRequestTimeout:             options.requestTimeout,
+		KubeCAFile:                     options.caFile,
+		KubeInsecureSkipTLSVerify:      options.insecure,
+		KubeQPS:                        options.kubeQPS,
+		KubeBurst:                      options.kubeBurst,
+		CacheKubeResponses:             true,
+		KubeRequestRecorder:            requestRecorder,
+		APICallRecorder:                callRecorder,
+		RecordAPIPayloads:              options.apiPayloads,
+		APIProxyPath:                   options.apiProxyPath,
+		MinKubeVersion:                 minKubeVersion,
+		LatestVersionTimeout:           options.versionTimeout,
+		LatestVersionURL:               options.versionURL,
+		LatestVersionCAFile:            options.versionCAFile,
+		LatestVersionCacheTTL:          options.versionCacheTTL,
+		AnonymousVersionCheck:          options.anonymous,
 	})
 
+	if options.outputFormat == "json" {
+		success := runChecksJSON(os.Stdout, hc)
+		if requestStats != nil {
+			writeRequestSummary(os.Stderr, requestStats.Summary())
+		}
+		if options.showVersionURL {
+			writeVersionCheckRequest(os.Stderr, hc)
+		}
+		if !success {
+			os.Exit(2)
+		}
+		return
+	}
+
 	success := runChecks(os.Stdout, hc)
+	if requestStats != nil {
+		writeRequestSummary(os.Stderr, requestStats.Summary())
+	}
+	if options.showVersionURL {
+		writeVersionCheckRequest(os.Stderr, hc)
+	}
 
 	fmt.Println("")
 
@@ -113,6 +280,104 @@ func configureAndRunChecks(options *checkOptions) {
 	fmt.Printf("Status check results are %s\n", okStatus)
 }
 
+// kubeVersionFloor returns the oldest Kubernetes version the checks accept:
+// the --min-kube-version override if set, else the version required to
+// install the control plane for pre-installation checks, else the zero
+// version, for the API's default.
+func (o *checkOptions) kubeVersionFloor() ([3]int, error) {
+	if o.minKubeVersion != "" {
+		version, err := k8s.ParseVersion(o.minKubeVersion)
+		if err != nil {
+			return [3]int{}, fmt.Errorf("--min-kube-version must be a Kubernetes version such as 1.12.0: %s", err)
+		}
+		return version, nil
+	}
+	if o.preInstallOnly {
+		return healthcheck.PreInstallMinKubeVersion, nil
+	}
+	return [3]int{}, nil
+}
+
+// loggingRequestRecorder logs each Kubernetes API request at debug level, and
+// aggregates them into stats.
+type loggingRequestRecorder struct {
+	stats *k8s.RequestStats
+}
+
+func (r *loggingRequestRecorder) RecordRequest(record k8s.RequestRecord) {
+	if record.Err != nil {
+		log.Debugf("Kubernetes API request %s %s failed after %s (attempt %d): %s", record.Method, record.URL, record.Duration, record.Attempt, record.Err)
+	} else {
+		log.Debugf("Kubernetes API request %s %s: %d in %s (attempt %d)", record.Method, record.URL, record.StatusCode, record.Duration, record.Attempt)
+	}
+	r.stats.RecordRequest(record)
+}
+
+// loggingCallRecorder logs each public API call at debug level, along with its
+// messages if they are recorded.
+type loggingCallRecorder struct{}
+
+func (loggingCallRecorder) RecordCall(record public.CallRecord) {
+	kind := "call"
+	if record.Stream {
+		kind = "stream"
+	}
+	if record.Err != nil {
+		log.Debugf("Public API %s %s failed with %s after %s (request %d bytes, %d messages received): %s",
+			kind, record.Method, record.Code, record.Duration, record.RequestSize, record.Messages, record.Err)
+	} else {
+		log.Debugf("Public API %s %s: %s in %s (request %d bytes, %d messages of %d bytes received)",
+			kind, record.Method, record.Code, record.Duration, record.RequestSize, record.Messages, record.ResponseSize)
+	}
+	if record.Request != nil {
+		log.Debugf("Public API %s %s request: %s", kind, record.Method, proto.CompactTextString(record.Request))
+	}
+	if record.Response != nil {
+		log.Debugf("Public API %s %s response: %s", kind, record.Method, proto.CompactTextString(record.Response))
+	}
+}
+
+// writeRequestSummary writes the count, errors and latency percentiles of the
+// Kubernetes API requests made by the checks, per method and path pattern.
+func writeRequestSummary(w io.Writer, stats []k8s.RequestStat) {
+	if len(stats) == 0 {
+		return
+	}
+
+	t := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(t, "\nKubernetes API requests:")
+	fmt.Fprintln(t, "METHOD\tPATH\tCOUNT\tERRORS\tP50\tP90\tP99\tMAX")
+	for _, s := range stats {
+		fmt.Fprintf(t, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n", s.Method, s.Pattern, s.Count, s.Errors,
+			s.P50.Round(time.Microsecond), s.P90.Round(time.Microsecond), s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond))
+	}
+	t.Flush()
+}
+
+// writeVersionCheckRequest prints the request made to the version endpoint
+// by the checks, if any.
+func writeVersionCheckRequest(w io.Writer, hc *healthcheck.HealthChecker) {
+	if request := hc.LatestVersionRequest(); request != "" {
+		fmt.Fprintf(w, "Version check request: GET %s\n", request)
+	}
+}
+
+// envBool returns the boolean value of the given environment variable, or
+// false if it is unset or isn't a boolean.
+func envBool(name string) bool {
+	value, _ := strconv.ParseBool(os.Getenv(name))
+	return value
+}
+
+func (o *checkOptions) validateOutputFormat() error {
+	switch o.outputFormat {
+	case "table", "json", "":
+		return nil
+	default:
+		return fmt.Errorf("--output currently only supports table and json")
+	}
+}
+
 func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 	prettyPrintResults := func(result *healthcheck.CheckResult) {
 		checkLabel := fmt.Sprintf("%s: %s", result.Category, result.Description)
@@ -128,12 +393,23 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 			return
 		}
 
+		if result.Skipped {
+			fmt.Fprintf(w, "%s%s%s -- %s%s", checkLabel, filler, skippedStatus, result.Err, lineBreak)
+			return
+		}
+
 		if result.Err != nil {
 			status := failStatus
 			if result.Warning {
 				status = warningStatus
 			}
 			fmt.Fprintf(w, "%s%s%s -- %s%s", checkLabel, filler, status, result.Err, lineBreak)
+			if apiErr, ok := result.Err.(*healthcheck.APIError); ok && verbose {
+				fmt.Fprintf(w, "    original error: %s%s", apiErr.Err, lineBreak)
+			}
+			if hint := result.HintURL(); hint != "" {
+				fmt.Fprintf(w, "    see %s for hints%s", hint, lineBreak)
+			}
 			return
 		}
 
@@ -142,3 +418,59 @@ func runChecks(w io.Writer, hc *healthcheck.HealthChecker) bool {
 
 	return hc.RunChecks(prettyPrintResults)
 }
+
+type jsonCheckOutput struct {
+	Success bool               `json:"success"`
+	Results []*jsonCheckResult `json:"results"`
+}
+
+type jsonCheckResult struct {
+	Category    string      `json:"category"`
+	Description string      `json:"description"`
+	Result      string      `json:"result"`
+	Error       string      `json:"error,omitempty"`
+	Hint        string      `json:"hint,omitempty"`
+	Payload     interface{} `json:"payload,omitempty"`
+}
+
+// runChecksJSON runs the checks and renders the final result of each as a
+// single JSON document. Intermediate retry results are omitted.
+func runChecksJSON(w io.Writer, hc *healthcheck.HealthChecker) bool {
+	output := jsonCheckOutput{Results: []*jsonCheckResult{}}
+
+	collectResults := func(result *healthcheck.CheckResult) {
+		if result.Retry {
+			return
+		}
+
+		entry := &jsonCheckResult{
+			Category:    result.Category,
+			Description: result.Description,
+			Result:      "success",
+			Payload:     result.Payload,
+		}
+		if result.Skipped {
+			entry.Result = "skipped"
+			entry.Error = result.Err.Error()
+		} else if result.Err != nil {
+			entry.Result = "error"
+			if result.Warning {
+				entry.Result = "warning"
+			}
+			entry.Error = result.Err.Error()
+			entry.Hint = result.HintURL()
+		}
+		output.Results = append(output.Results, entry)
+	}
+
+	output.Success = hc.RunChecks(collectResults)
+
+	b, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error marshalling check results to JSON: %s\n", err)
+		return false
+	}
+	fmt.Fprintf(w, "%s\n", b)
+
+	return output.Success
+}
@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
 )
 
 func TestCheckStatus(t *testing.T) {
@@ -15,12 +18,18 @@ func TestCheckStatus(t *testing.T) {
 			[]healthcheck.Checks{},
 			&healthcheck.HealthCheckOptions{},
 		)
-		hc.Add("category", "check1", func() error {
+		hc.Add("category", "check1", func(ctx context.Context) error {
 			return nil
 		})
-		hc.Add("category", "check2", func() error {
+		hc.Add("category", "check2", func(ctx context.Context) error {
 			return fmt.Errorf("This should contain instructions for fail")
 		})
+		hc.Add("category", "check3", func(ctx context.Context) error {
+			return &healthcheck.SkipError{Reason: "This should explain why the check was skipped"}
+		})
+		hc.Add("category", "check4", func(ctx context.Context) error {
+			return &healthcheck.HintError{Anchor: "l5d-check4", Err: fmt.Errorf("This should link to the documentation of the failure")}
+		})
 
 		output := bytes.NewBufferString("")
 		runChecks(output, hc)
@@ -37,3 +46,104 @@ func TestCheckStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckStatusJSON(t *testing.T) {
+	t.Run("Prints expected JSON output", func(t *testing.T) {
+		hc := healthcheck.NewHealthChecker(
+			[]healthcheck.Checks{},
+			&healthcheck.HealthCheckOptions{},
+		)
+		hc.Add("category", "check1", func(ctx context.Context) error {
+			return nil
+		})
+		hc.Add("category", "check2", func(ctx context.Context) error {
+			return fmt.Errorf("This should contain instructions for fail")
+		})
+		hc.Add("category", "check3", func(ctx context.Context) error {
+			return &healthcheck.SkipError{Reason: "This should explain why the check was skipped"}
+		})
+		hc.Add("category", "check4", func(ctx context.Context) error {
+			return &healthcheck.HintError{Anchor: "l5d-check4", Err: fmt.Errorf("This should link to the documentation of the failure")}
+		})
+
+		output := bytes.NewBufferString("")
+		runChecksJSON(output, hc)
+
+		goldenFileBytes, err := ioutil.ReadFile("testdata/check_output_json.golden")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedContent := string(goldenFileBytes)
+
+		if expectedContent != output.String() {
+			t.Fatalf("Expected function to render:\n%s\bbut got:\n%s", expectedContent, output)
+		}
+	})
+}
+
+func TestKubeVersionFloor(t *testing.T) {
+	t.Run("Requires the pre-install version for pre-installation checks", func(t *testing.T) {
+		options := newCheckOptions()
+		options.preInstallOnly = true
+
+		floor, err := options.kubeVersionFloor()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if floor != healthcheck.PreInstallMinKubeVersion {
+			t.Fatalf("Expected %v, got %v", healthcheck.PreInstallMinKubeVersion, floor)
+		}
+
+		floor, err = newCheckOptions().kubeVersionFloor()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if floor != [3]int{} {
+			t.Fatalf("Expected the API's default, got %v", floor)
+		}
+	})
+
+	t.Run("Uses the --min-kube-version override", func(t *testing.T) {
+		options := newCheckOptions()
+		options.preInstallOnly = true
+		options.minKubeVersion = "1.12"
+
+		floor, err := options.kubeVersionFloor()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if floor != [3]int{1, 12, 0} {
+			t.Fatalf("Expected [1 12 0], got %v", floor)
+		}
+
+		options.minKubeVersion = "latest"
+		if _, err := options.kubeVersionFloor(); err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+	})
+}
+
+func TestWriteRequestSummary(t *testing.T) {
+	var output bytes.Buffer
+	writeRequestSummary(&output, []k8s.RequestStat{
+		{Method: "GET", Pattern: "/api/v1/namespaces/{namespace}/pods", Count: 12, Errors: 1, P50: 4 * time.Millisecond, P90: 9 * time.Millisecond, P99: 12 * time.Millisecond, Max: 12 * time.Millisecond},
+		{Method: "GET", Pattern: "/version", Count: 1, P50: 1500 * time.Microsecond, P90: 1500 * time.Microsecond, P99: 1500 * time.Microsecond, Max: 1500 * time.Microsecond},
+	})
+
+	expected := `
+Kubernetes API requests:
+METHOD  PATH                                 COUNT  ERRORS  P50    P90    P99    MAX
+GET     /api/v1/namespaces/{namespace}/pods  12     1       4ms    9ms    12ms   12ms
+GET     /version                             1      0       1.5ms  1.5ms  1.5ms  1.5ms
+`
+	if output.String() != expected {
+		t.Fatalf("Unexpected summary:\n%s", output.String())
+	}
+
+	output.Reset()
+	writeRequestSummary(&output, nil)
+	if output.Len() != 0 {
+		t.Fatalf("Expected no summary without requests, got:\n%s", output.String())
+	}
+}
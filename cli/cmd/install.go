@@ -28,6 +28,7 @@ type installConfig struct {
 	CliVersion                       string
 	ControllerLogLevel               string
 	ControllerComponentLabel         string
+	ControllerNSLabel                string
 	CreatedByAnnotation              string
 	ProxyAPIPort                     uint
 	EnableTLS                        bool
@@ -153,6 +154,7 @@ func validateAndBuildConfig(options *installOptions) (*installConfig, error) {
 		CliVersion:                       k8s.CreatedByAnnotationValue(),
 		ControllerLogLevel:               options.controllerLogLevel,
 		ControllerComponentLabel:         k8s.ControllerComponentLabel,
+		ControllerNSLabel:                k8s.ControllerNSLabel,
 		CreatedByAnnotation:              k8s.CreatedByAnnotation,
 		ProxyAPIPort:                     options.proxyAPIPort,
 		EnableTLS:                        options.enableTLS(),
@@ -34,6 +34,7 @@ func TestRender(t *testing.T) {
 		CliVersion:                       "CliVersion",
 		ControllerLogLevel:               "ControllerLogLevel",
 		ControllerComponentLabel:         "ControllerComponentLabel",
+		ControllerNSLabel:                "ControllerNSLabel",
 		CreatedByAnnotation:              "CreatedByAnnotation",
 		ProxyAPIPort:                     123,
 		EnableTLS:                        true,
@@ -76,6 +77,7 @@ func TestRender(t *testing.T) {
 		CliVersion:                       "CliVersion",
 		ControllerLogLevel:               "ControllerLogLevel",
 		ControllerComponentLabel:         "ControllerComponentLabel",
+		ControllerNSLabel:                "ControllerNSLabel",
 		CreatedByAnnotation:              "CreatedByAnnotation",
 		ProxyAPIPort:                     123,
 		EnableTLS:                        true,
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/linkerd/linkerd2/controller/api/public"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	"github.com/linkerd/linkerd2/pkg/version"
@@ -25,6 +26,7 @@ const (
 
 var controlPlaneNamespace string
 var apiAddr string // An empty value means "use the Kubernetes configuration"
+var apiCAFile string
 var kubeconfigPath string
 var kubeContext string
 var verbose bool
@@ -58,6 +60,10 @@ var RootCmd = &cobra.Command{
 			return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 		}
 
+		if apiCAFile != "" && !public.IsAPIURL(apiAddr) {
+			return fmt.Errorf("--api-ca-file can only be used when --api-addr is an https URL")
+		}
+
 		return nil
 	},
 }
@@ -66,7 +72,8 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "linkerd-namespace", "l", defaultNamespace, "Namespace in which Linkerd is installed [$LINKERD_NAMESPACE]")
 	RootCmd.PersistentFlags().StringVar(&kubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file to use for CLI requests")
 	RootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Name of the kubeconfig context to use")
-	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
+	RootCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing), or at an https URL, such as that of an ingress or a LoadBalancer")
+	RootCmd.PersistentFlags().StringVar(&apiCAFile, "api-ca-file", "", "Path to a PEM-encoded CA bundle to verify the public API's certificate with when --api-addr is an https URL, instead of the system's roots")
 	RootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Turn on debug logging")
 
 	RootCmd.AddCommand(newCmdCheck())
@@ -97,6 +104,7 @@ func validatedPublicAPIClient(retryDeadline time.Time) pb.ApiClient {
 		KubeConfig:            kubeconfigPath,
 		KubeContext:           kubeContext,
 		APIAddr:               apiAddr,
+		APITLS:                apiTLSOptions(),
 		RetryDeadline:         retryDeadline,
 	})
 
@@ -106,7 +114,7 @@ func validatedPublicAPIClient(retryDeadline time.Time) pb.ApiClient {
 			return
 		}
 
-		if result.Err != nil && !result.Warning {
+		if result.Err != nil && !result.Warning && !result.Skipped {
 			var msg string
 			switch result.Category {
 			case healthcheck.KubernetesAPICategory:
@@ -130,6 +138,15 @@ func validatedPublicAPIClient(retryDeadline time.Time) pb.ApiClient {
 	return hc.PublicAPIClient()
 }
 
+// apiTLSOptions returns the TLS options of the connections made to the public
+// API at the --api-addr URL, if it is one.
+func apiTLSOptions() public.TLSOptions {
+	if !public.IsAPIURL(apiAddr) {
+		return public.TLSOptions{}
+	}
+	return public.TLSOptions{CAFile: apiCAFile}
+}
+
 type proxyConfigOptions struct {
 	linkerdVersion        string
 	proxyImage            string
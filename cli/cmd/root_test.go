@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+)
+
+func TestAPITLSOptions(t *testing.T) {
+	defer func() {
+		apiAddr = ""
+		apiCAFile = ""
+	}()
+
+	t.Run("Reads the CA bundle of an API URL from --api-ca-file", func(t *testing.T) {
+		err := RootCmd.PersistentFlags().Parse([]string{"--api-addr", "https://linkerd.example.com", "--api-ca-file", "/etc/linkerd/ca.pem"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := public.TLSOptions{CAFile: "/etc/linkerd/ca.pem"}
+		if options := apiTLSOptions(); !reflect.DeepEqual(options, expected) {
+			t.Fatalf("Expected TLS options %+v, got %+v", expected, options)
+		}
+	})
+
+	t.Run("Verifies the version client's connection with the CA bundle", func(t *testing.T) {
+		caFile, err := ioutil.TempFile("", "linkerd-api-ca")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer os.Remove(caFile.Name())
+		caFile.Close()
+
+		apiAddr = "https://linkerd.example.com"
+		apiCAFile = caFile.Name()
+
+		_, err = newVersionClient()
+		if err == nil || !strings.Contains(err.Error(), "contains no PEM-encoded certificates") {
+			t.Fatalf("Expected the empty CA bundle to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("Rejects --api-ca-file without an API URL", func(t *testing.T) {
+		apiAddr = "localhost:8085"
+		apiCAFile = "/etc/linkerd/ca.pem"
+
+		err := RootCmd.PersistentPreRunE(RootCmd, []string{})
+		expected := "--api-ca-file can only be used when --api-addr is an https URL"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+		if options := apiTLSOptions(); !reflect.DeepEqual(options, public.TLSOptions{}) {
+			t.Fatalf("Expected no TLS options, got %+v", options)
+		}
+	})
+}
@@ -75,6 +75,9 @@ func getServerVersion(client pb.ApiClient) string {
 
 // This client does not do any validation
 func newVersionClient() (pb.ApiClient, error) {
+	if public.IsAPIURL(apiAddr) {
+		return public.NewExternalClientForURL(controlPlaneNamespace, apiAddr, apiTLSOptions())
+	}
 	if apiAddr != "" {
 		return public.NewInternalClient(controlPlaneNamespace, apiAddr)
 	}
@@ -28,6 +28,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 rules:
 - apiGroups: ["extensions", "apps"]
   resources: ["deployments", "replicasets"]
@@ -47,6 +49,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: {{if not .SingleNamespace}}Cluster{{end}}Role
@@ -73,6 +77,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 rules:
 - apiGroups: [""]
   resources: ["pods"]
@@ -86,6 +92,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: {{if not .SingleNamespace}}Cluster{{end}}Role
@@ -253,6 +261,8 @@ kind: CustomResourceDefinition
 metadata:
   name: serviceprofiles.linkerd.io
   namespace: {{.Namespace}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
   annotations:
     {{.CreatedByAnnotation}}: {{.CliVersion}}
 spec:
@@ -658,6 +668,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 rules:
 - apiGroups: [""]
   resources: ["configmaps"]
@@ -689,6 +701,8 @@ metadata:
   {{- if .SingleNamespace}}
   namespace: {{.Namespace}}
   {{- end}}
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: {{if not .SingleNamespace}}Cluster{{end}}Role
@@ -836,16 +850,23 @@ kind: ClusterRole
 apiVersion: rbac.authorization.k8s.io/v1
 metadata:
   name: linkerd-{{.Namespace}}-proxy-injector
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 rules:
 - apiGroups: ["admissionregistration.k8s.io"]
   resources: ["mutatingwebhookconfigurations"]
   verbs: ["create", "update", "get", "watch"]
+- apiGroups: [""]
+  resources: ["namespaces"]
+  verbs: ["get"]
 
 ---
 kind: ClusterRoleBinding
 apiVersion: rbac.authorization.k8s.io/v1
 metadata:
   name: linkerd-{{.Namespace}}-proxy-injector
+  labels:
+    {{.ControllerNSLabel}}: {{.Namespace}}
 subjects:
 - kind: ServiceAccount
   name: linkerd-proxy-injector
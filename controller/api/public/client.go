@@ -4,9 +4,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
@@ -17,18 +22,37 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
 	apiRoot    = "/" // Must be absolute (with a leading slash).
 	apiVersion = "v1"
 	apiPrefix  = "api/" + apiVersion + "/" // Must be relative (without a leading slash).
+
+	// apiPort is the port the controller's public-api container serves on
+	apiPort = 8085
+)
+
+// The transports external clients reach the public API with.
+const (
+	// KubernetesProxyTransport reaches the public API through the Kubernetes
+	// API server's service proxy.
+	KubernetesProxyTransport = "kubernetes-proxy"
+
+	// PortForwardTransport reaches the public API through a port-forward to
+	// a controller pod.
+	PortForwardTransport = "port-forward"
+
+	// DirectTransport reaches the public API at a given address.
+	DirectTransport = "direct"
 )
 
 type grpcOverHttpClient struct {
 	serverURL             *url.URL
 	httpClient            *http.Client
 	controlPlaneNamespace string
+	retry                 retryPolicy
 }
 
 // TODO: This will replace Stat, once implemented
@@ -81,7 +105,39 @@ func (c *grpcOverHttpClient) TapByResource(ctx context.Context, req *pb.TapByRes
 	return &tapClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
 }
 
+// SelfCheckStream opens a stream of the self-check's results. Control planes
+// predating the stream don't serve it, which is reported as Unimplemented.
+func (c *grpcOverHttpClient) SelfCheckStream(ctx context.Context, req *healthcheckPb.SelfCheckRequest, _ ...grpc.CallOption) (pb.Api_SelfCheckStreamClient, error) {
+	url := c.endpointNameToPublicApiUrl("SelfCheckStream")
+	httpRsp, err := c.post(ctx, url, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIfResponseHasError(httpRsp); err != nil {
+		httpRsp.Body.Close()
+		if rspErr, ok := err.(*UnexpectedResponseError); ok && rspErr.StatusCode == http.StatusNotFound {
+			return nil, status.Error(codes.Unimplemented, "the control plane API doesn't serve SelfCheckStream")
+		}
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Debug("Closing response body after context marked as done")
+		httpRsp.Body.Close()
+	}()
+
+	return &selfCheckStreamClient{ctx: ctx, reader: bufio.NewReader(httpRsp.Body)}, nil
+}
+
 func (c *grpcOverHttpClient) apiRequest(ctx context.Context, endpoint string, req proto.Message, protoResponse proto.Message) error {
+	return c.retry.do(ctx, endpoint, func() error {
+		return c.apiRequestOnce(ctx, endpoint, req, protoResponse)
+	})
+}
+
+func (c *grpcOverHttpClient) apiRequestOnce(ctx context.Context, endpoint string, req proto.Message, protoResponse proto.Message) error {
 	url := c.endpointNameToPublicApiUrl(endpoint)
 
 	log.Debugf("Making gRPC-over-HTTP call to [%s] [%+v]", url.String(), req)
@@ -125,6 +181,14 @@ func (c *grpcOverHttpClient) post(ctx context.Context, url *url.URL, req proto.M
 	return rsp, err
 }
 
+// closeIdleConnections closes the client's idle connections, if its transport
+// pools them.
+func (c *grpcOverHttpClient) closeIdleConnections() {
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+}
+
 func (c *grpcOverHttpClient) endpointNameToPublicApiUrl(endpoint string) *url.URL {
 	return c.serverURL.ResolveReference(&url.URL{Path: endpoint})
 }
@@ -148,6 +212,36 @@ func (c tapClient) Context() context.Context     { return c.ctx }
 func (c tapClient) SendMsg(interface{}) error    { return nil }
 func (c tapClient) RecvMsg(interface{}) error    { return nil }
 
+type selfCheckStreamClient struct {
+	ctx    context.Context
+	reader *bufio.Reader
+}
+
+// Recv returns the next result of the self-check, or io.EOF once the stream
+// has ended.
+func (c selfCheckStreamClient) Recv() (*healthcheckPb.CheckResult, error) {
+	if _, err := c.reader.Peek(1); err == io.EOF {
+		return nil, io.EOF
+	}
+
+	var msg healthcheckPb.CheckResult
+	if err := fromByteStreamToProtocolBuffers(c.reader, &msg); err != nil {
+		if c.ctx.Err() != nil {
+			return nil, c.ctx.Err()
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// satisfy the pb.Api_SelfCheckStreamClient interface
+func (c selfCheckStreamClient) Header() (metadata.MD, error) { return nil, nil }
+func (c selfCheckStreamClient) Trailer() metadata.MD         { return nil }
+func (c selfCheckStreamClient) CloseSend() error             { return nil }
+func (c selfCheckStreamClient) Context() context.Context     { return c.ctx }
+func (c selfCheckStreamClient) SendMsg(interface{}) error    { return nil }
+func (c selfCheckStreamClient) RecvMsg(interface{}) error    { return nil }
+
 func fromByteStreamToProtocolBuffers(byteStreamContainingMessage *bufio.Reader, out proto.Message) error {
 	messageAsBytes, err := deserializePayloadFromReader(byteStreamContainingMessage)
 	if err != nil {
@@ -175,28 +269,221 @@ func newClient(apiURL *url.URL, httpClientToUse *http.Client, controlPlaneNamesp
 		serverURL:             serverUrl,
 		httpClient:            httpClientToUse,
 		controlPlaneNamespace: controlPlaneNamespace,
+		retry:                 defaultRetryPolicy,
 	}, nil
 }
 
+// newDirectClient returns a client for the public API served at the given
+// address, reached directly rather than through the Kubernetes API server.
+// Its idle connections are kept alive with TCP keepalives and closed before
+// load balancers time them out.
+func newDirectClient(addr string, controlPlaneNamespace string, tlsOptions TLSOptions) (pb.ApiClient, error) {
+	tlsConfig, err := tlsOptions.config()
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL, err := url.Parse(fmt.Sprintf("%s://%s/", tlsOptions.scheme(), addr))
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(apiURL, newDirectHTTPClient(tlsConfig), controlPlaneNamespace)
+}
+
+// newDirectHTTPClient returns the HTTP client the public API is reached with
+// directly, over TLS if tlsConfig is set.
+func newDirectHTTPClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: apiKeepAlive,
+			}).DialContext,
+			TLSClientConfig:     tlsConfig,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     apiIdleConnTimeout,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+	}
+}
+
 func NewInternalClient(controlPlaneNamespace string, kubeAPIHost string) (pb.ApiClient, error) {
-	apiURL, err := url.Parse(fmt.Sprintf("http://%s/", kubeAPIHost))
+	return newDirectClient(kubeAPIHost, controlPlaneNamespace, TLSOptions{})
+}
+
+// NewInternalClientWithTLS returns a connection to the public API served at
+// the given address, made with the given TLS options.
+func NewInternalClientWithTLS(controlPlaneNamespace string, kubeAPIHost string, tlsOptions TLSOptions) (*Conn, error) {
+	client, err := newDirectClient(kubeAPIHost, controlPlaneNamespace, tlsOptions)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(client, DirectTransport), nil
+}
+
+// IsAPIURL returns true if the given address of the public API is a full URL,
+// such as that of an ingress or a LoadBalancer in front of the controller,
+// rather than an in-cluster host:port.
+func IsAPIURL(addr string) bool {
+	return strings.Contains(addr, "://")
+}
+
+// NewExternalClientForURL returns a connection to the public API served at the
+// given https URL, e.g. by an ingress or a LoadBalancer, reached directly
+// without a kubeconfig. The connection is always made over TLS, with the
+// URL's host as the server name its certificate is verified against and as
+// the requests' authority, unless the TLS options override the server name;
+// their CA bundle overrides the system's roots.
+func NewExternalClientForURL(controlPlaneNamespace string, rawURL string, tlsOptions TLSOptions) (*Conn, error) {
+	apiURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public API URL [%s]: %s", rawURL, err)
+	}
+	if apiURL.Scheme != "https" || apiURL.Host == "" {
+		return nil, fmt.Errorf("invalid public API URL [%s]: must be an https URL", rawURL)
+	}
+	if apiURL.RawQuery != "" || apiURL.Fragment != "" {
+		return nil, fmt.Errorf("invalid public API URL [%s]: must not have a query or a fragment", rawURL)
+	}
+	// the API's paths are resolved relative to any prefix the ingress
+	// serves it at
+	if !strings.HasSuffix(apiURL.Path, "/") {
+		apiURL.Path += "/"
+	}
+
+	tlsConfig, err := tlsOptions.configFor(apiURL.Hostname())
 	if err != nil {
 		return nil, err
 	}
 
-	return newClient(apiURL, http.DefaultClient, controlPlaneNamespace)
+	client, err := newClient(apiURL, newDirectHTTPClient(tlsConfig), controlPlaneNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(client, DirectTransport), nil
 }
 
 func NewExternalClient(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI) (pb.ApiClient, error) {
-	apiURL, err := kubeAPI.UrlFor(controlPlaneNamespace, "/services/http:api:http/proxy/")
+	return NewExternalClientWithProxyPath(controlPlaneNamespace, kubeAPI, ProxyPathOptions{})
+}
+
+// NewExternalClientForService returns a client for the public API served by
+// the named service in the given namespace, reached through the Kubernetes
+// API server's service proxy.
+func NewExternalClientForService(namespace, serviceName string, kubeAPI *k8s.KubernetesAPI) (pb.ApiClient, error) {
+	return NewExternalClientWithProxyPath(namespace, kubeAPI, ProxyPathOptions{ServiceName: serviceName})
+}
+
+// NewExternalClientWithProxyPath returns a client for the public API reached
+// through the Kubernetes API server's service proxy, at the path composed from
+// the given options.
+func NewExternalClientWithProxyPath(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, proxyPath ProxyPathOptions) (pb.ApiClient, error) {
+	return NewExternalClientWithHTTPClient(controlPlaneNamespace, kubeAPI, proxyPath, nil)
+}
+
+// NewExternalClientWithHTTPClient returns a client for the public API reached
+// through the Kubernetes API server's service proxy, at the path composed from
+// the given options, making its requests with the given HTTP client, so that
+// they go through the same transport as the caller's Kubernetes API requests.
+// The HTTP client must authenticate its requests to the Kubernetes API server;
+// if nil, the client shared by the KubernetesAPI's methods is used.
+func NewExternalClientWithHTTPClient(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, proxyPath ProxyPathOptions, httpClient *http.Client) (pb.ApiClient, error) {
+	apiURL, err := ProxyURL(controlPlaneNamespace, kubeAPI, proxyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpClient == nil {
+		httpClient, err = kubeAPI.Client()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newClient(apiURL, httpClient, controlPlaneNamespace)
+}
+
+// NewExternalClientWithPortForward returns a connection to the public API
+// served by a running controller pod in the given namespace, reached through a
+// port-forward to that pod rather than through the Kubernetes API server's
+// service proxy, and made with the given TLS options. The port-forward runs
+// until the connection is closed or the context is done.
+func NewExternalClientWithPortForward(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, tlsOptions TLSOptions) (*Conn, error) {
+	selector := fmt.Sprintf("%s=controller", k8s.ControllerComponentLabel)
+	pods, err := kubeAPI.GetPodsByNamespace(ctx, controlPlaneNamespace, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var podName string
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			podName = pod.Name
+			break
+		}
+	}
+	if podName == "" {
+		return nil, fmt.Errorf("No running controller pod to port-forward to in the %s namespace", controlPlaneNamespace)
+	}
+
+	addr, closeForward, err := kubeAPI.PortForward(ctx, controlPlaneNamespace, podName, apiPort)
+	if err != nil {
+		return nil, &ConnectionError{Layer: TCPLayer, Err: err}
+	}
+
+	client, err := newDirectClient(addr, controlPlaneNamespace, tlsOptions)
 	if err != nil {
+		closeForward()
 		return nil, err
 	}
 
-	httpClientToUse, err := kubeAPI.NewClient()
+	log.Debugf("Port-forwarding to the public API of the \"%s/%s\" pod on [%s]", controlPlaneNamespace, podName, addr)
+	return newConn(client, PortForwardTransport, closeForward), nil
+}
+
+// NewExternalClientWithFallback returns a client for the public API reached
+// through the Kubernetes API server's service proxy, unless the proxy rejects
+// requests as forbidden or not found, as it does on clusters restricting the
+// proxy subresource, in which case it falls back to a port-forward running
+// until the connection is closed or the context is done, made with the given
+// TLS options. The service proxy is reached at the path composed from the
+// given proxy path options, with the given HTTP client, or the KubernetesAPI's
+// if nil. The connection reports the transport selected.
+func NewExternalClientWithFallback(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, proxyPath ProxyPathOptions, httpClient *http.Client, tlsOptions TLSOptions) (*Conn, error) {
+	client, err := NewExternalClientWithHTTPClient(controlPlaneNamespace, kubeAPI, proxyPath, httpClient)
 	if err != nil {
 		return nil, err
 	}
 
-	return newClient(apiURL, httpClientToUse, controlPlaneNamespace)
+	probeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = client.Version(probeCtx, &pb.Empty{})
+	if !isProxyRejection(err) {
+		// other failures are left to the calls made with the client; its
+		// HTTP client is the caller's, or shared with the Kubernetes API, so
+		// it isn't closed
+		return &Conn{ApiClient: client, Transport: KubernetesProxyTransport}, nil
+	}
+	log.Debugf("The Kubernetes API server's service proxy rejected the public API request, falling back to a port-forward: %s", err)
+
+	return NewExternalClientWithPortForward(ctx, controlPlaneNamespace, kubeAPI, tlsOptions)
+}
+
+// CheckConnection calls the public API's Version endpoint, and returns a
+// ConnectionError naming whether the TCP connection, the TLS handshake or the
+// call itself failed, if it did.
+func CheckConnection(ctx context.Context, client pb.ApiClient) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := client.Version(ctx, &pb.Empty{})
+	return classifyConnectionError(err)
+}
+
+// isProxyRejection returns true if the error is a 403 Forbidden or 404 Not
+// Found response.
+func isProxyRejection(err error) bool {
+	rsp, ok := err.(*UnexpectedResponseError)
+	return ok && (rsp.StatusCode == http.StatusForbidden || rsp.StatusCode == http.StatusNotFound)
 }
@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/pem"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/client-go/rest"
 )
 
 type mockTransport struct {
@@ -59,6 +67,149 @@ func TestNewInternalClient(t *testing.T) {
 	})
 }
 
+type recordingTransport struct {
+	transport http.RoundTripper
+	paths     []string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.paths = append(r.paths, req.URL.Path)
+	return r.transport.RoundTrip(req)
+}
+
+func TestNewExternalClientWithHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, bufferedReader(t, &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}))
+	}))
+	defer server.Close()
+
+	transport := &recordingTransport{transport: http.DefaultTransport}
+	kubeAPI := &k8s.KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+	client, err := NewExternalClientWithHTTPClient("linkerd", kubeAPI, ProxyPathOptions{}, &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := client.Version(context.Background(), &pb.Empty{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"/api/v1/namespaces/linkerd/services/http:api:http/proxy/api/v1/Version"}
+	if !reflect.DeepEqual(transport.paths, expected) {
+		t.Fatalf("Expected the given HTTP client to send %v, got %v", expected, transport.paths)
+	}
+}
+
+func TestNewExternalClientForURL(t *testing.T) {
+	var path, serverName string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, serverName = r.URL.Path, r.TLS.ServerName
+		io.Copy(w, bufferedReader(t, &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}))
+	}))
+	defer server.Close()
+
+	caFile, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile.Close()
+
+	t.Run("Reaches the API at the URL's path with the given server name", func(t *testing.T) {
+		conn, err := NewExternalClientForURL("linkerd", server.URL+"/linkerd", TLSOptions{CAFile: caFile.Name(), ServerName: "example.com"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if conn.Transport != DirectTransport {
+			t.Fatalf("Expected the transport [%s], got [%s]", DirectTransport, conn.Transport)
+		}
+
+		if _, err := conn.Version(context.Background(), &pb.Empty{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if path != "/linkerd/api/v1/Version" {
+			t.Fatalf("Expected a request to [/linkerd/api/v1/Version], got [%s]", path)
+		}
+		if serverName != "example.com" {
+			t.Fatalf("Expected the server name [example.com], got [%s]", serverName)
+		}
+	})
+
+	t.Run("Verifies the server's certificate with the system's roots by default", func(t *testing.T) {
+		conn, err := NewExternalClientForURL("linkerd", server.URL, TLSOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = CheckConnection(context.Background(), conn)
+		if connErr, ok := err.(*ConnectionError); !ok || connErr.Layer != TLSLayer {
+			t.Fatalf("Expected a TLS connection error, got [%v]", err)
+		}
+	})
+
+	t.Run("Rejects URLs other than https ones", func(t *testing.T) {
+		for _, rawURL := range []string{"http://linkerd-api.example.com", "linkerd-api.example.com:443", "https://linkerd-api.example.com/?watch=true"} {
+			if _, err := NewExternalClientForURL("linkerd", rawURL, TLSOptions{}); err == nil {
+				t.Fatalf("Expected an error for the URL [%s]", rawURL)
+			}
+		}
+	})
+}
+
+func TestNewExternalClientWithFallback(t *testing.T) {
+	testCases := []struct {
+		name              string
+		proxyStatus       int
+		expectedTransport string
+		expectedErr       string
+	}{
+		{"Uses the service proxy when it serves the public API", http.StatusOK, KubernetesProxyTransport, ""},
+		{"Leaves other proxy failures to the API calls", http.StatusServiceUnavailable, KubernetesProxyTransport, ""},
+		{"Falls back to a port-forward when the proxy is forbidden", http.StatusForbidden, "", "TCP connection to the public API failed: Failed to port-forward to port 8085 of the \"linkerd/linkerd-controller-1\" pod: "},
+		{"Falls back to a port-forward when the proxy is not found", http.StatusNotFound, "", "TCP connection to the public API failed: Failed to port-forward to port 8085 of the \"linkerd/linkerd-controller-1\" pod: "},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, "/api/v1/namespaces/linkerd/services/http:api:http/proxy/"):
+					w.WriteHeader(tc.proxyStatus)
+					if tc.proxyStatus == http.StatusOK {
+						io.Copy(w, bufferedReader(t, &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}))
+					}
+				case r.URL.Path == "/api/v1/namespaces/linkerd/pods":
+					w.Write([]byte(`{"items":[
+						{"metadata":{"name":"linkerd-controller-0"},"status":{"phase":"Pending"}},
+						{"metadata":{"name":"linkerd-controller-1"},"status":{"phase":"Running"}}]}`))
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte("port-forwarding is disabled"))
+				}
+			}))
+			defer server.Close()
+
+			kubeAPI := &k8s.KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+			conn, err := NewExternalClientWithFallback(context.Background(), "linkerd", kubeAPI, ProxyPathOptions{}, nil, TLSOptions{})
+			if tc.expectedErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tc.expectedErr) {
+					t.Fatalf("Expected error starting with [%s], got [%v]", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer conn.Close()
+			if conn.Transport != tc.expectedTransport {
+				t.Fatalf("Expected a client using the %s transport, got %s", tc.expectedTransport, conn.Transport)
+			}
+		})
+	}
+}
+
 func TestFromByteStreamToProtocolBuffers(t *testing.T) {
 	t.Run("Correctly marshalls an valid object", func(t *testing.T) {
 		versionInfo := pb.VersionInfo{
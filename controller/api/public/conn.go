@@ -0,0 +1,42 @@
+package public
+
+import (
+	"sync"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+// Conn is a public API client along with the resources it holds, such as its
+// port-forward and idle connections, which are released by Close. It can be
+// used for as many calls as needed, e.g. by the checks and then by the command
+// they validated the connection for.
+type Conn struct {
+	pb.ApiClient
+
+	// Transport is the transport the client reaches the public API with.
+	Transport string
+
+	closers []func()
+	once    sync.Once
+}
+
+func newConn(client pb.ApiClient, transport string, closers ...func()) *Conn {
+	if direct, ok := client.(*grpcOverHttpClient); ok {
+		closers = append(closers, direct.closeIdleConnections)
+	}
+	return &Conn{ApiClient: client, Transport: transport, closers: closers}
+}
+
+// Close releases the connection's resources. It may be called more than once,
+// and on a nil Conn; a Conn that is never closed is released once the context
+// it was made with is done, or when the process exits.
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+	c.once.Do(func() {
+		for _, close := range c.closers {
+			close()
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package public
+
+import (
+	"testing"
+)
+
+func TestConnClose(t *testing.T) {
+	t.Run("Releases the connection's resources once", func(t *testing.T) {
+		closed := 0
+		conn := newConn(&MockApiClient{}, PortForwardTransport, func() { closed++ })
+
+		conn.Close()
+		conn.Close()
+		if closed != 1 {
+			t.Fatalf("Expected the resources to be released once, got %d", closed)
+		}
+	})
+
+	t.Run("Is safe on a nil connection", func(t *testing.T) {
+		var conn *Conn
+		conn.Close()
+	})
+}
@@ -189,6 +189,66 @@ func (s *grpcServer) ListPods(ctx context.Context, req *pb.ListPodsRequest) (*pb
 }
 
 func (s *grpcServer) SelfCheck(ctx context.Context, in *healthcheckPb.SelfCheckRequest) (*healthcheckPb.SelfCheckResponse, error) {
+	response := &healthcheckPb.SelfCheckResponse{}
+	for _, check := range s.selfChecks(in) {
+		response.Results = append(response.Results, check(ctx))
+	}
+	return response, nil
+}
+
+// SelfCheckStream runs the requested subsystems' checks concurrently, and
+// sends each result as soon as its check completes, so that a slow subsystem
+// doesn't hold back the results of the others.
+func (s *grpcServer) SelfCheckStream(in *healthcheckPb.SelfCheckRequest, stream pb.Api_SelfCheckStreamServer) error {
+	ctx := stream.Context()
+	checks := s.selfChecks(in)
+
+	results := make(chan *healthcheckPb.CheckResult, len(checks))
+	for _, check := range checks {
+		go func(check func(context.Context) *healthcheckPb.CheckResult) {
+			results <- check(ctx)
+		}(check)
+	}
+
+	for range checks {
+		select {
+		case result := <-results:
+			if err := stream.Send(result); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// selfChecks returns the checks of the subsystems requested by the
+// self-check, or of all of them if none are.
+func (s *grpcServer) selfChecks(in *healthcheckPb.SelfCheckRequest) []func(context.Context) *healthcheckPb.CheckResult {
+	requested := func(subsystem string) bool {
+		if len(in.GetSubsystems()) == 0 {
+			return true
+		}
+		for _, name := range in.GetSubsystems() {
+			if name == subsystem {
+				return true
+			}
+		}
+		return false
+	}
+
+	checks := []func(context.Context) *healthcheckPb.CheckResult{}
+	if requested(K8sClientSubsystemName) {
+		checks = append(checks, s.checkK8sClient)
+	}
+	if requested(PromClientSubsystemName) {
+		checks = append(checks, s.checkPromClient)
+	}
+	return checks
+}
+
+func (s *grpcServer) checkK8sClient(ctx context.Context) *healthcheckPb.CheckResult {
 	k8sClientCheck := &healthcheckPb.CheckResult{
 		SubsystemName:    K8sClientSubsystemName,
 		CheckDescription: K8sClientCheckDescription,
@@ -199,25 +259,21 @@ func (s *grpcServer) SelfCheck(ctx context.Context, in *healthcheckPb.SelfCheckR
 		k8sClientCheck.Status = healthcheckPb.CheckStatus_ERROR
 		k8sClientCheck.FriendlyMessageToUser = fmt.Sprintf("Error calling the Kubernetes API: %s", err)
 	}
+	return k8sClientCheck
+}
 
+func (s *grpcServer) checkPromClient(ctx context.Context) *healthcheckPb.CheckResult {
 	promClientCheck := &healthcheckPb.CheckResult{
 		SubsystemName:    PromClientSubsystemName,
 		CheckDescription: PromClientCheckDescription,
 		Status:           healthcheckPb.CheckStatus_OK,
 	}
-	_, err = s.queryProm(ctx, fmt.Sprintf(podQuery, ""))
+	_, err := s.queryProm(ctx, fmt.Sprintf(podQuery, ""))
 	if err != nil {
 		promClientCheck.Status = healthcheckPb.CheckStatus_ERROR
 		promClientCheck.FriendlyMessageToUser = fmt.Sprintf("Error calling Prometheus from the control plane: %s", err)
 	}
-
-	response := &healthcheckPb.SelfCheckResponse{
-		Results: []*healthcheckPb.CheckResult{
-			k8sClientCheck,
-			promClientCheck,
-		},
-	}
-	return response, nil
+	return promClientCheck
 }
 
 func (s *grpcServer) Tap(req *pb.TapRequest, stream pb.Api_TapServer) error {
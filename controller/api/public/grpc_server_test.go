@@ -2,14 +2,17 @@ package public
 
 import (
 	"context"
+	"reflect"
 	"sort"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/duration"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	tap "github.com/linkerd/linkerd2/controller/gen/controller/tap"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/prometheus/common/model"
+	"google.golang.org/grpc"
 )
 
 type listPodsExpected struct {
@@ -174,3 +177,78 @@ spec:
 		}
 	})
 }
+
+func TestSelfCheck(t *testing.T) {
+	testCases := []struct {
+		subsystems []string
+		expected   []string
+		promQuery  bool
+	}{
+		{nil, []string{K8sClientSubsystemName, PromClientSubsystemName}, true},
+		{[]string{K8sClientSubsystemName}, []string{K8sClientSubsystemName}, false},
+		{[]string{PromClientSubsystemName, "unknown"}, []string{PromClientSubsystemName}, true},
+	}
+
+	for _, tc := range testCases {
+		k8sAPI, err := k8s.NewFakeAPI("")
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		prom := &MockProm{Res: model.Vector{}}
+		fakeGrpcServer := newGrpcServer(prom, tap.NewTapClient(nil), k8sAPI, "linkerd", []string{})
+		k8sAPI.Sync(nil)
+
+		rsp, err := fakeGrpcServer.SelfCheck(context.TODO(), &healthcheckPb.SelfCheckRequest{Subsystems: tc.subsystems})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		subsystems := []string{}
+		for _, result := range rsp.Results {
+			subsystems = append(subsystems, result.SubsystemName)
+		}
+		if !reflect.DeepEqual(subsystems, tc.expected) {
+			t.Fatalf("Expected the %v subsystems to be checked for %v, got %v", tc.expected, tc.subsystems, subsystems)
+		}
+		if (len(prom.QueriesExecuted) > 0) != tc.promQuery {
+			t.Fatalf("Expected Prometheus to be queried: %t, got queries %v", tc.promQuery, prom.QueriesExecuted)
+		}
+	}
+}
+
+type mockSelfCheckStreamServer struct {
+	ctx     context.Context
+	results []*healthcheckPb.CheckResult
+	grpc.ServerStream
+}
+
+func (s *mockSelfCheckStreamServer) Send(result *healthcheckPb.CheckResult) error {
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *mockSelfCheckStreamServer) Context() context.Context { return s.ctx }
+
+func TestSelfCheckStream(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI("")
+	if err != nil {
+		t.Fatalf("NewFakeAPI returned an error: %s", err)
+	}
+	fakeGrpcServer := newGrpcServer(&MockProm{Res: model.Vector{}}, tap.NewTapClient(nil), k8sAPI, "linkerd", []string{})
+	k8sAPI.Sync(nil)
+
+	stream := &mockSelfCheckStreamServer{ctx: context.TODO()}
+	if err := fakeGrpcServer.SelfCheckStream(&healthcheckPb.SelfCheckRequest{}, stream); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	subsystems := []string{}
+	for _, result := range stream.results {
+		subsystems = append(subsystems, result.SubsystemName)
+	}
+	sort.Strings(subsystems)
+	expected := []string{K8sClientSubsystemName, PromClientSubsystemName}
+	if !reflect.DeepEqual(subsystems, expected) {
+		t.Fatalf("Expected the results of the %v subsystems to be sent, got %v", expected, subsystems)
+	}
+}
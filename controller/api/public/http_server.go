@@ -17,11 +17,12 @@ import (
 )
 
 var (
-	statSummaryPath   = fullUrlPathFor("StatSummary")
-	versionPath       = fullUrlPathFor("Version")
-	listPodsPath      = fullUrlPathFor("ListPods")
-	tapByResourcePath = fullUrlPathFor("TapByResource")
-	selfCheckPath     = fullUrlPathFor("SelfCheck")
+	statSummaryPath     = fullUrlPathFor("StatSummary")
+	versionPath         = fullUrlPathFor("Version")
+	listPodsPath        = fullUrlPathFor("ListPods")
+	tapByResourcePath   = fullUrlPathFor("TapByResource")
+	selfCheckPath       = fullUrlPathFor("SelfCheck")
+	selfCheckStreamPath = fullUrlPathFor("SelfCheckStream")
 )
 
 type handler struct {
@@ -32,6 +33,12 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	log.WithFields(log.Fields{
 		"req.Method": req.Method, "req.URL": req.URL, "req.Form": req.Form,
 	}).Debugf("Serving %s %s", req.Method, req.URL.Path)
+	// Answer pings, which probe whether the API can be reached
+	if req.Method == http.MethodGet && req.URL.Path == "/"+pingPath {
+		w.Write([]byte("pong\n"))
+		return
+	}
+
 	// Validate request method
 	if req.Method != http.MethodPost {
 		writeErrorToHttpResponse(w, fmt.Errorf("POST required"))
@@ -50,6 +57,8 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		h.handleTapByResource(w, req)
 	case selfCheckPath:
 		h.handleSelfCheck(w, req)
+	case selfCheckStreamPath:
+		h.handleSelfCheckStream(w, req)
 	default:
 		http.NotFound(w, req)
 	}
@@ -119,6 +128,27 @@ func (h *handler) handleSelfCheck(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func (h *handler) handleSelfCheckStream(w http.ResponseWriter, req *http.Request) {
+	flushableWriter, err := newStreamingWriter(w)
+	if err != nil {
+		writeErrorToHttpResponse(w, err)
+		return
+	}
+
+	var protoRequest healthcheckPb.SelfCheckRequest
+	err = httpRequestToProto(req, &protoRequest)
+	if err != nil {
+		writeErrorToHttpResponse(w, err)
+		return
+	}
+
+	server := selfCheckStreamServer{w: flushableWriter, req: req}
+	err = h.grpcServer.SelfCheckStream(&protoRequest, server)
+	if err != nil {
+		log.Debugf("Self-check stream ended: %s", err)
+	}
+}
+
 func (h *handler) handleListPods(w http.ResponseWriter, req *http.Request) {
 	var protoRequest pb.ListPodsRequest
 	err := httpRequestToProto(req, &protoRequest)
@@ -186,6 +216,29 @@ func (s tapServer) Context() context.Context     { return s.req.Context() }
 func (s tapServer) SendMsg(interface{}) error    { return nil }
 func (s tapServer) RecvMsg(interface{}) error    { return nil }
 
+type selfCheckStreamServer struct {
+	w   flushableResponseWriter
+	req *http.Request
+}
+
+func (s selfCheckStreamServer) Send(msg *healthcheckPb.CheckResult) error {
+	err := writeProtoToHttpResponse(s.w, msg)
+	if err != nil {
+		return err
+	}
+
+	s.w.Flush()
+	return nil
+}
+
+// satisfy the pb.Api_SelfCheckStreamServer interface
+func (s selfCheckStreamServer) SetHeader(metadata.MD) error  { return nil }
+func (s selfCheckStreamServer) SendHeader(metadata.MD) error { return nil }
+func (s selfCheckStreamServer) SetTrailer(metadata.MD)       {}
+func (s selfCheckStreamServer) Context() context.Context     { return s.req.Context() }
+func (s selfCheckStreamServer) SendMsg(interface{}) error    { return nil }
+func (s selfCheckStreamServer) RecvMsg(interface{}) error    { return nil }
+
 func fullUrlPathFor(method string) string {
 	return apiRoot + apiPrefix + method
 }
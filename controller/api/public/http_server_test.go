@@ -3,20 +3,26 @@ package public
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	healcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type mockGrpcServer struct {
-	LastRequestReceived proto.Message
-	ResponseToReturn    proto.Message
-	TapStreamsToReturn  []*pb.TapEvent
-	ErrorToReturn       error
+	LastRequestReceived      proto.Message
+	ResponseToReturn         proto.Message
+	TapStreamsToReturn       []*pb.TapEvent
+	SelfCheckResultsToReturn []*healcheckPb.CheckResult
+	ErrorToReturn            error
 }
 
 func (m *mockGrpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
@@ -39,6 +45,17 @@ func (m *mockGrpcServer) SelfCheck(ctx context.Context, req *healcheckPb.SelfChe
 	return m.ResponseToReturn.(*healcheckPb.SelfCheckResponse), m.ErrorToReturn
 }
 
+func (m *mockGrpcServer) SelfCheckStream(req *healcheckPb.SelfCheckRequest, stream pb.Api_SelfCheckStreamServer) error {
+	m.LastRequestReceived = req
+	if m.ErrorToReturn == nil {
+		for _, msg := range m.SelfCheckResultsToReturn {
+			stream.Send(msg)
+		}
+	}
+
+	return m.ErrorToReturn
+}
+
 func (m *mockGrpcServer) Tap(req *pb.TapRequest, tapServer pb.Api_TapServer) error {
 	m.LastRequestReceived = req
 	if m.ErrorToReturn == nil {
@@ -183,6 +200,62 @@ func TestServer(t *testing.T) {
 		}
 	})
 
+	t.Run("Streams the self-check's results until the stream ends", func(t *testing.T) {
+		mockGrpcServer := &mockGrpcServer{}
+
+		server := httptest.NewServer(&handler{grpcServer: mockGrpcServer})
+		defer server.Close()
+
+		client, err := NewInternalClient("linkerd", strings.TrimPrefix(server.URL, "http://"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedResults := []*healcheckPb.CheckResult{
+			{SubsystemName: K8sClientSubsystemName, Status: healcheckPb.CheckStatus_OK},
+			{SubsystemName: PromClientSubsystemName, Status: healcheckPb.CheckStatus_ERROR},
+		}
+		mockGrpcServer.SelfCheckResultsToReturn = expectedResults
+
+		req := &healcheckPb.SelfCheckRequest{Subsystems: []string{K8sClientSubsystemName, PromClientSubsystemName}}
+		stream, err := client.SelfCheckStream(context.TODO(), req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, expectedResult := range expectedResults {
+			actualResult, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !proto.Equal(actualResult, expectedResult) {
+				t.Fatalf("Expecting self-check result to be [%v], but was [%v]", expectedResult, actualResult)
+			}
+		}
+		if _, err := stream.Recv(); err != io.EOF {
+			t.Fatalf("Expecting the stream to end, got %v", err)
+		}
+		if !proto.Equal(mockGrpcServer.LastRequestReceived, req) {
+			t.Fatalf("Expecting server call to receive [%v], but got [%v]", req, mockGrpcServer.LastRequestReceived)
+		}
+	})
+
+	t.Run("Reports a self-check stream that isn't served as unimplemented", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		client, err := NewInternalClient("linkerd", strings.TrimPrefix(server.URL, "http://"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		_, err = client.SelfCheckStream(context.TODO(), &healcheckPb.SelfCheckRequest{})
+		if status.Code(err) != codes.Unimplemented {
+			t.Fatalf("Expecting an Unimplemented error, got %v", err)
+		}
+	})
+
 	t.Run("Handles errors before opening keep-alive response", func(t *testing.T) {
 		mockGrpcServer := &mockGrpcServer{}
 
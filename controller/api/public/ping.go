@@ -0,0 +1,116 @@
+package public
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// pingPath is the path the public API answers plain HTTP GET requests on,
+	// relative to the URL it is served at
+	pingPath = "ping"
+
+	pingTimeout = 5 * time.Second
+
+	// maxPingBodyBytes bounds how much of a failed ping's response is read
+	maxPingBodyBytes = 4096
+)
+
+// The reasons a ping of the public API through the Kubernetes API server's
+// service proxy may fail for.
+const (
+	// PingProxyPathNotFound is the reason of a ping the service proxy found
+	// no service to send to, e.g. since the service name is wrong.
+	PingProxyPathNotFound = "proxy path not found"
+
+	// PingControllerNotListening is the reason of a ping the service proxy
+	// could not send to the controller, e.g. since its pod refused the
+	// connection or the service has no ready endpoints.
+	PingControllerNotListening = "controller not listening"
+
+	// PingFailed is the reason of any other failed ping.
+	PingFailed = "ping failed"
+)
+
+// PingError is returned when the public API cannot be pinged through the
+// Kubernetes API server's service proxy, with the URL pinged and the reason
+// it failed for.
+type PingError struct {
+	URL    string
+	Reason string
+	Err    error
+}
+
+func (e *PingError) Error() string {
+	return fmt.Sprintf("ping of the public API at [%s] failed: %s", e.URL, e.Err)
+}
+
+// PingProxy sends a plain HTTP GET request to the public API's ping endpoint
+// through the Kubernetes API server's service proxy, at the path composed from
+// the given options, with the given HTTP client, or the KubernetesAPI's if
+// nil. Unlike a call to the API, it tells whether the proxy path, the service
+// or the controller fails, returning a PingError naming the reason. Any
+// response from the controller itself succeeds, since controllers predating
+// the ping endpoint reject the request.
+func PingProxy(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, proxyPath ProxyPathOptions, httpClient *http.Client) error {
+	apiURL, err := ProxyURL(controlPlaneNamespace, kubeAPI, proxyPath)
+	if err != nil {
+		return err
+	}
+	pingURL := apiURL.ResolveReference(&url.URL{Path: pingPath})
+
+	if httpClient == nil {
+		httpClient, err = kubeAPI.Client()
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, pingURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	// the proxy's 5xx responses tell why the controller can't be reached
+	rsp, err := httpClient.Do(req.WithContext(k8s.WithFinalResponse(ctx)))
+	if err != nil {
+		return &PingError{URL: pingURL.String(), Reason: PingFailed, Err: err}
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// the errors of the Kubernetes API server are Status objects, which the
+	// controller never responds with
+	body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxPingBodyBytes))
+	var status metav1.Status
+	if err := json.Unmarshal(body, &status); err != nil || status.Kind != "Status" {
+		return nil
+	}
+
+	pingErr := &PingError{URL: pingURL.String(), Reason: PingFailed, Err: &UnexpectedResponseError{StatusCode: rsp.StatusCode, Status: rsp.Status}}
+	if status.Message != "" {
+		pingErr.Err = fmt.Errorf("%s: %s", rsp.Status, status.Message)
+	}
+	switch {
+	case rsp.StatusCode == http.StatusNotFound:
+		pingErr.Reason = PingProxyPathNotFound
+	case strings.Contains(status.Message, "connection refused") || strings.Contains(status.Message, "no endpoints available"):
+		pingErr.Reason = PingControllerNotListening
+	}
+	return pingErr
+}
@@ -0,0 +1,100 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/client-go/rest"
+)
+
+func TestPingProxy(t *testing.T) {
+	proxyPath := "/api/v1/namespaces/linkerd/services/http:api:http/proxy"
+
+	kubeStatus := func(code int, message string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			fmt.Fprintf(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":%q,"code":%d}`, message, code)
+		}
+	}
+	controller := func(h http.Handler) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, proxyPath+"/") {
+				kubeStatus(http.StatusNotFound, "the server could not find the requested resource")(w, r)
+				return
+			}
+			r.URL.Path = strings.TrimPrefix(r.URL.Path, proxyPath)
+			h.ServeHTTP(w, r)
+		}
+	}
+
+	testCases := []struct {
+		description string
+		handler     http.HandlerFunc
+		reason      string
+	}{
+		{
+			"succeeds when the controller answers the ping",
+			controller(&handler{grpcServer: &mockGrpcServer{}}),
+			"",
+		},
+		{
+			"succeeds when a controller predating the ping endpoint rejects it",
+			controller(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeErrorToHttpResponse(w, errors.New("POST required"))
+			})),
+			"",
+		},
+		{
+			"reports a service the proxy does not find",
+			kubeStatus(http.StatusNotFound, `services "api" not found`),
+			PingProxyPathNotFound,
+		},
+		{
+			"reports a controller refusing the connection",
+			kubeStatus(http.StatusServiceUnavailable, "error trying to reach service: dial tcp 10.1.2.3:8085: connect: connection refused"),
+			PingControllerNotListening,
+		},
+		{
+			"reports a service without endpoints",
+			kubeStatus(http.StatusServiceUnavailable, `no endpoints available for service "http:api:http"`),
+			PingControllerNotListening,
+		},
+		{
+			"reports a forbidden proxy",
+			kubeStatus(http.StatusForbidden, `services "http:api:http" is forbidden: User "jane" cannot get resource "services/proxy"`),
+			PingFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			kubeAPI := &k8s.KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+			err := PingProxy(context.Background(), "linkerd", kubeAPI, ProxyPathOptions{}, http.DefaultClient)
+			if tc.reason == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+
+			pingErr, ok := err.(*PingError)
+			if !ok || pingErr.Reason != tc.reason {
+				t.Fatalf("Expected a ping error for the reason [%s], got [%v]", tc.reason, err)
+			}
+			if expected := server.URL + proxyPath + "/ping"; pingErr.URL != expected {
+				t.Fatalf("Expected the ping URL [%s], got [%s]", expected, pingErr.URL)
+			}
+		})
+	}
+}
@@ -150,8 +150,20 @@ func checkIfResponseHasError(rsp *http.Response) error {
 	}
 
 	if rsp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Unexpected API response: %s", rsp.Status)
+		return &UnexpectedResponseError{StatusCode: rsp.StatusCode, Status: rsp.Status}
 	}
 
 	return nil
 }
+
+// UnexpectedResponseError is returned when a response has a status other than
+// 200 OK and carries no API error, e.g. when the request was rejected by the
+// Kubernetes API server proxying it.
+type UnexpectedResponseError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("Unexpected API response: %s", e.Status)
+}
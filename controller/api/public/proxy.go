@@ -0,0 +1,93 @@
+package public
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	defaultProxyServiceName = "api"
+	defaultProxyPortName    = "http"
+)
+
+// ProxyPathOptions overrides the segments of the path the public API is
+// reached at through the Kubernetes API server's service proxy,
+// /api/v1/namespaces/<namespace>/services/http:<service>:<port>/proxy/, for
+// distributions exposing the controller's API Service under another name.
+// Empty fields default to the control plane's namespace, and to the "api"
+// service and its "http" port.
+type ProxyPathOptions struct {
+	Namespace   string
+	ServiceName string
+
+	// PortName is the name, or the number, of the service's port.
+	PortName string
+}
+
+// withDefaults returns the options with their empty fields set to their
+// defaults.
+func (o ProxyPathOptions) withDefaults(controlPlaneNamespace string) ProxyPathOptions {
+	if o.Namespace == "" {
+		o.Namespace = controlPlaneNamespace
+	}
+	if o.ServiceName == "" {
+		o.ServiceName = defaultProxyServiceName
+	}
+	if o.PortName == "" {
+		o.PortName = defaultProxyPortName
+	}
+	return o
+}
+
+// Validate returns an error if any of the options set can't be used as a
+// segment of the proxy path. The segments are composed into the URL as is, so
+// rejecting anything but a Kubernetes name keeps them from addressing another
+// path on the Kubernetes API server.
+func (o ProxyPathOptions) Validate() error {
+	segments := []struct {
+		name     string
+		value    string
+		validate func(string) []string
+	}{
+		{"namespace", o.Namespace, validation.IsDNS1123Label},
+		{"service name", o.ServiceName, validation.IsDNS1123Label},
+		{"port name", o.PortName, validatePortName},
+	}
+
+	for _, s := range segments {
+		if s.value == "" {
+			continue
+		}
+		if strings.Contains(s.value, "/") {
+			return fmt.Errorf("invalid public API proxy %s [%s]: must not contain a slash", s.name, s.value)
+		}
+		if errs := s.validate(s.value); len(errs) > 0 {
+			return fmt.Errorf("invalid public API proxy %s [%s]: %s", s.name, s.value, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// validatePortName accepts a port's name or number, as the service proxy does.
+func validatePortName(port string) []string {
+	if n, err := strconv.Atoi(port); err == nil {
+		return validation.IsValidPortNum(n)
+	}
+	return validation.IsValidPortName(port)
+}
+
+// ProxyURL returns the URL of the public API reached through the Kubernetes
+// API server's service proxy, at the path composed from the given options.
+func ProxyURL(controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, proxyPath ProxyPathOptions) (*url.URL, error) {
+	if err := proxyPath.Validate(); err != nil {
+		return nil, err
+	}
+
+	proxyPath = proxyPath.withDefaults(controlPlaneNamespace)
+	return kubeAPI.UrlFor(proxyPath.Namespace, fmt.Sprintf("/services/http:%s:%s/proxy/", proxyPath.ServiceName, proxyPath.PortName))
+}
@@ -0,0 +1,71 @@
+package public
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/client-go/rest"
+)
+
+func TestProxyURL(t *testing.T) {
+	kubeAPI := &k8s.KubernetesAPI{Config: &rest.Config{Host: "https://kubernetes.example.com"}}
+
+	testCases := []struct {
+		proxyPath   ProxyPathOptions
+		expectedURL string
+		expectedErr string
+	}{
+		{
+			ProxyPathOptions{},
+			"https://kubernetes.example.com/api/v1/namespaces/linkerd/services/http:api:http/proxy/",
+			"",
+		},
+		{
+			ProxyPathOptions{Namespace: "mesh-system", ServiceName: "mesh-api", PortName: "admin-http"},
+			"https://kubernetes.example.com/api/v1/namespaces/mesh-system/services/http:mesh-api:admin-http/proxy/",
+			"",
+		},
+		{
+			ProxyPathOptions{PortName: "8085"},
+			"https://kubernetes.example.com/api/v1/namespaces/linkerd/services/http:api:8085/proxy/",
+			"",
+		},
+		{
+			ProxyPathOptions{ServiceName: "api/proxy/../../secrets"},
+			"",
+			"invalid public API proxy service name [api/proxy/../../secrets]: must not contain a slash",
+		},
+		{
+			ProxyPathOptions{Namespace: "kube-system/secrets"},
+			"",
+			"invalid public API proxy namespace [kube-system/secrets]: must not contain a slash",
+		},
+		{
+			ProxyPathOptions{PortName: "http?watch=true"},
+			"",
+			"invalid public API proxy port name [http?watch=true]: ",
+		},
+		{
+			ProxyPathOptions{PortName: "70000"},
+			"",
+			"invalid public API proxy port name [70000]: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		apiURL, err := ProxyURL("linkerd", kubeAPI, tc.proxyPath)
+		if tc.expectedErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedErr) {
+				t.Fatalf("Expected error starting with [%s] for %+v, got [%v]", tc.expectedErr, tc.proxyPath, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for %+v: %s", tc.proxyPath, err)
+		}
+		if apiURL.String() != tc.expectedURL {
+			t.Fatalf("Expected the URL [%s] for %+v, got [%s]", tc.expectedURL, tc.proxyPath, apiURL)
+		}
+	}
+}
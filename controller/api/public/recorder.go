@@ -0,0 +1,264 @@
+package public
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apiMethodPrefix is the prefix of the full gRPC method names of the public
+// API, e.g. "/linkerd2.public.Api/Version".
+const apiMethodPrefix = "/linkerd2.public.Api/"
+
+// CallRecord describes a call made to the public API. Retried calls are
+// recorded once, and streaming calls once their stream ends.
+type CallRecord struct {
+	// Method is the call's full gRPC method name.
+	Method string
+	Stream bool
+
+	// Code is the call's status, as a gRPC status code.
+	Code     codes.Code
+	Err      error
+	Duration time.Duration
+
+	// RequestSize is the size of the request message, and ResponseSize that
+	// of the response messages received, in bytes. Messages counts the
+	// response messages received.
+	RequestSize  int
+	ResponseSize int
+	Messages     int
+
+	// Request and Response are the call's messages, the last received for a
+	// stream. They are only recorded if payloads are, since they may hold
+	// the names of workloads.
+	Request  proto.Message
+	Response proto.Message
+}
+
+// CallRecorder is invoked with each call made by a client returned by
+// NewRecordingClient. It must be safe for concurrent use.
+type CallRecorder interface {
+	RecordCall(record CallRecord)
+}
+
+// NewRecordingClient returns a client making its calls with the given client,
+// and invoking the recorder with each of them. The calls' messages are only
+// recorded if recordPayloads is set.
+func NewRecordingClient(client pb.ApiClient, recorder CallRecorder, recordPayloads bool) pb.ApiClient {
+	return &recordingClient{client: client, recorder: recorder, recordPayloads: recordPayloads}
+}
+
+type recordingClient struct {
+	client         pb.ApiClient
+	recorder       CallRecorder
+	recordPayloads bool
+}
+
+// record invokes the recorder with the call to the method, started at the
+// given time, that got the response and error; for a stream, the last message
+// received.
+func (c *recordingClient) record(method string, stream bool, start time.Time, req proto.Message, rsp proto.Message, messages int, responseSize int, err error) {
+	record := CallRecord{
+		Method:       apiMethodPrefix + method,
+		Stream:       stream,
+		Code:         Code(err),
+		Err:          err,
+		Duration:     time.Since(start),
+		RequestSize:  proto.Size(req),
+		ResponseSize: responseSize,
+		Messages:     messages,
+	}
+	if c.recordPayloads {
+		record.Request = req
+		record.Response = rsp
+	}
+	c.recorder.RecordCall(record)
+}
+
+// recordUnary records the unary call to the method made by call.
+func (c *recordingClient) recordUnary(method string, req proto.Message, call func() (proto.Message, error)) {
+	start := time.Now()
+	rsp, err := call()
+	if err != nil {
+		c.record(method, false, start, req, nil, 0, 0, err)
+		return
+	}
+	c.record(method, false, start, req, rsp, 1, proto.Size(rsp), nil)
+}
+
+func (c *recordingClient) StatSummary(ctx context.Context, req *pb.StatSummaryRequest, opts ...grpc.CallOption) (rsp *pb.StatSummaryResponse, err error) {
+	c.recordUnary("StatSummary", req, func() (proto.Message, error) {
+		rsp, err = c.client.StatSummary(ctx, req, opts...)
+		return rsp, err
+	})
+	return
+}
+
+func (c *recordingClient) ListPods(ctx context.Context, req *pb.ListPodsRequest, opts ...grpc.CallOption) (rsp *pb.ListPodsResponse, err error) {
+	c.recordUnary("ListPods", req, func() (proto.Message, error) {
+		rsp, err = c.client.ListPods(ctx, req, opts...)
+		return rsp, err
+	})
+	return
+}
+
+func (c *recordingClient) Version(ctx context.Context, req *pb.Empty, opts ...grpc.CallOption) (rsp *pb.VersionInfo, err error) {
+	c.recordUnary("Version", req, func() (proto.Message, error) {
+		rsp, err = c.client.Version(ctx, req, opts...)
+		return rsp, err
+	})
+	return
+}
+
+func (c *recordingClient) SelfCheck(ctx context.Context, req *healthcheckPb.SelfCheckRequest, opts ...grpc.CallOption) (rsp *healthcheckPb.SelfCheckResponse, err error) {
+	c.recordUnary("SelfCheck", req, func() (proto.Message, error) {
+		rsp, err = c.client.SelfCheck(ctx, req, opts...)
+		return rsp, err
+	})
+	return
+}
+
+func (c *recordingClient) Tap(ctx context.Context, req *pb.TapRequest, opts ...grpc.CallOption) (pb.Api_TapClient, error) {
+	start := time.Now()
+	stream, err := c.client.Tap(ctx, req, opts...)
+	if err != nil {
+		c.record("Tap", true, start, req, nil, 0, 0, err)
+		return nil, err
+	}
+	return &recordingTapClient{Api_TapClient: stream, call: c.newStreamCall("Tap", start, req)}, nil
+}
+
+func (c *recordingClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, opts ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	start := time.Now()
+	stream, err := c.client.TapByResource(ctx, req, opts...)
+	if err != nil {
+		c.record("TapByResource", true, start, req, nil, 0, 0, err)
+		return nil, err
+	}
+	return &recordingTapClient{Api_TapClient: stream, call: c.newStreamCall("TapByResource", start, req)}, nil
+}
+
+func (c *recordingClient) SelfCheckStream(ctx context.Context, req *healthcheckPb.SelfCheckRequest, opts ...grpc.CallOption) (pb.Api_SelfCheckStreamClient, error) {
+	start := time.Now()
+	stream, err := c.client.SelfCheckStream(ctx, req, opts...)
+	if err != nil {
+		c.record("SelfCheckStream", true, start, req, nil, 0, 0, err)
+		return nil, err
+	}
+	return &recordingSelfCheckStreamClient{Api_SelfCheckStreamClient: stream, call: c.newStreamCall("SelfCheckStream", start, req)}, nil
+}
+
+// streamCall accumulates the messages received on a stream, and records the
+// call once the stream ends.
+type streamCall struct {
+	client       *recordingClient
+	method       string
+	start        time.Time
+	req          proto.Message
+	last         proto.Message
+	messages     int
+	responseSize int
+	ended        bool
+}
+
+func (c *recordingClient) newStreamCall(method string, start time.Time, req proto.Message) *streamCall {
+	return &streamCall{client: c, method: method, start: start, req: req}
+}
+
+// received accounts for a message received on the stream, or records the
+// call if the stream ended, with io.EOF once it has ended successfully.
+func (s *streamCall) received(msg proto.Message, err error) {
+	if s.ended {
+		return
+	}
+	if err == nil {
+		s.last = msg
+		s.messages++
+		s.responseSize += proto.Size(msg)
+		return
+	}
+
+	s.ended = true
+	if err == io.EOF {
+		err = nil
+	}
+	s.client.record(s.method, true, s.start, s.req, s.last, s.messages, s.responseSize, err)
+}
+
+type recordingTapClient struct {
+	pb.Api_TapClient
+	call *streamCall
+}
+
+func (c *recordingTapClient) Recv() (*pb.TapEvent, error) {
+	msg, err := c.Api_TapClient.Recv()
+	c.call.received(msg, err)
+	return msg, err
+}
+
+type recordingSelfCheckStreamClient struct {
+	pb.Api_SelfCheckStreamClient
+	call *streamCall
+}
+
+func (c *recordingSelfCheckStreamClient) Recv() (*healthcheckPb.CheckResult, error) {
+	msg, err := c.Api_SelfCheckStreamClient.Recv()
+	c.call.received(msg, err)
+	return msg, err
+}
+
+// Code returns the gRPC status code of an error returned by a public API
+// client: that of a gRPC status error, or the code matching the failure of a
+// call made over HTTP.
+func Code(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+
+	switch e := err.(type) {
+	case *attemptsError:
+		return Code(e.err)
+	case *ConnectionError:
+		if c := Code(e.Err); c != codes.Unknown {
+			return c
+		}
+		return codes.Unavailable
+	case *url.Error:
+		if c := Code(e.Err); c != codes.Unknown {
+			return c
+		}
+		return codes.Unavailable
+	case *UnexpectedResponseError:
+		switch e.StatusCode {
+		case http.StatusUnauthorized:
+			return codes.Unauthenticated
+		case http.StatusForbidden:
+			return codes.PermissionDenied
+		case http.StatusNotFound:
+			return codes.NotFound
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return codes.Unavailable
+		}
+	}
+
+	switch err {
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case context.Canceled:
+		return codes.Canceled
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,138 @@
+package public
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeCallRecorder struct {
+	mu      sync.Mutex
+	records []CallRecord
+}
+
+func (r *fakeCallRecorder) RecordCall(record CallRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+func TestRecordingClient(t *testing.T) {
+	versionInfo := &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}
+
+	t.Run("Records unary calls without their messages", func(t *testing.T) {
+		recorder := &fakeCallRecorder{}
+		client := NewRecordingClient(&MockApiClient{VersionInfoToReturn: versionInfo}, recorder, false)
+
+		if _, err := client.Version(context.Background(), &pb.Empty{}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if len(recorder.records) != 1 {
+			t.Fatalf("Expected 1 call to be recorded, got %d", len(recorder.records))
+		}
+		record := recorder.records[0]
+		if record.Method != "/linkerd2.public.Api/Version" || record.Stream || record.Code != codes.OK {
+			t.Fatalf("Unexpected record: %+v", record)
+		}
+		if record.Messages != 1 || record.ResponseSize != proto.Size(versionInfo) {
+			t.Fatalf("Expected a response of %d bytes to be recorded, got %+v", proto.Size(versionInfo), record)
+		}
+		if record.Request != nil || record.Response != nil {
+			t.Fatalf("Expected the messages not to be recorded, got %+v", record)
+		}
+	})
+
+	t.Run("Records the messages of calls if payloads are recorded", func(t *testing.T) {
+		recorder := &fakeCallRecorder{}
+		client := NewRecordingClient(&MockApiClient{VersionInfoToReturn: versionInfo}, recorder, true)
+
+		req := &pb.Empty{}
+		if _, err := client.Version(context.Background(), req); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		record := recorder.records[0]
+		if record.Request != req || !proto.Equal(record.Response, versionInfo) {
+			t.Fatalf("Expected the messages to be recorded, got %+v", record)
+		}
+	})
+
+	t.Run("Records the status of failed calls", func(t *testing.T) {
+		recorder := &fakeCallRecorder{}
+		failure := status.Error(codes.PermissionDenied, "denied")
+		client := NewRecordingClient(&MockApiClient{ErrorToReturn: failure}, recorder, false)
+
+		if _, err := client.ListPods(context.Background(), &pb.ListPodsRequest{}); err != failure {
+			t.Fatalf("Expected the client's error, got %v", err)
+		}
+
+		record := recorder.records[0]
+		if record.Code != codes.PermissionDenied || record.Err != failure || record.Messages != 0 {
+			t.Fatalf("Unexpected record: %+v", record)
+		}
+	})
+
+	t.Run("Records streams once they end", func(t *testing.T) {
+		recorder := &fakeCallRecorder{}
+		results := []*healthcheckPb.CheckResult{
+			{SubsystemName: K8sClientSubsystemName},
+			{SubsystemName: PromClientSubsystemName},
+		}
+		client := NewRecordingClient(&MockApiClient{SelfCheckStreamResultsToReturn: results}, recorder, false)
+
+		stream, err := client.SelfCheckStream(context.Background(), &healthcheckPb.SelfCheckRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for i := 0; i <= len(results); i++ {
+			if len(recorder.records) != 0 {
+				t.Fatalf("Expected the stream to be recorded once it ends, got %+v", recorder.records)
+			}
+			stream.Recv()
+		}
+		stream.Recv()
+
+		if len(recorder.records) != 1 {
+			t.Fatalf("Expected 1 call to be recorded, got %d", len(recorder.records))
+		}
+		record := recorder.records[0]
+		expectedSize := proto.Size(results[0]) + proto.Size(results[1])
+		if !record.Stream || record.Code != codes.OK || record.Messages != 2 || record.ResponseSize != expectedSize {
+			t.Fatalf("Unexpected record: %+v", record)
+		}
+	})
+}
+
+func TestCode(t *testing.T) {
+	testCases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{nil, codes.OK},
+		{status.Error(codes.Unimplemented, "unimplemented"), codes.Unimplemented},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{&url.Error{Op: "Post", URL: "http://127.0.0.1:8085", Err: errors.New("connection refused")}, codes.Unavailable},
+		{&url.Error{Op: "Post", URL: "http://127.0.0.1:8085", Err: context.DeadlineExceeded}, codes.DeadlineExceeded},
+		{&attemptsError{err: &UnexpectedResponseError{StatusCode: 503}, attempts: 3}, codes.Unavailable},
+		{&ConnectionError{Layer: TLSLayer, Err: errors.New("x509: certificate signed by unknown authority")}, codes.Unavailable},
+		{&UnexpectedResponseError{StatusCode: 401}, codes.Unauthenticated},
+		{&UnexpectedResponseError{StatusCode: 403}, codes.PermissionDenied},
+		{&UnexpectedResponseError{StatusCode: 404}, codes.NotFound},
+		{errors.New("Error calling Prometheus"), codes.Unknown},
+	}
+
+	for _, tc := range testCases {
+		if code := Code(tc.err); code != tc.code {
+			t.Fatalf("Expected the code of [%v] to be %s, got %s", tc.err, tc.code, code)
+		}
+	}
+}
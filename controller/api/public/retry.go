@@ -0,0 +1,107 @@
+package public
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMaxAttempts = 3
+	retryBaseBackoff   = 200 * time.Millisecond
+	retryMaxBackoff    = 2 * time.Second
+
+	// the keepalive period and idle timeout of the connections made directly
+	// to the public API
+	apiKeepAlive       = 15 * time.Second
+	apiIdleConnTimeout = 30 * time.Second
+)
+
+// idempotentEndpoints are the public API calls that are safe to retry.
+var idempotentEndpoints = map[string]bool{
+	"SelfCheck": true,
+	"Version":   true,
+	"ListPods":  true,
+}
+
+// retryPolicy retries the calls to idempotent endpoints that fail because the
+// public API is unavailable or the connection to it was lost, with capped
+// exponential backoff, until their context is done.
+type retryPolicy struct {
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts: defaultMaxAttempts,
+	baseBackoff: retryBaseBackoff,
+	maxBackoff:  retryMaxBackoff,
+}
+
+// do makes the call to the given endpoint, retrying it if it is idempotent.
+// Each retry is logged, and the error of a call that failed after retries
+// reports how many attempts were made.
+func (p retryPolicy) do(ctx context.Context, endpoint string, call func() error) error {
+	if !idempotentEndpoints[endpoint] {
+		return call()
+	}
+
+	backoff := p.baseBackoff
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil {
+			if attempt > 1 {
+				log.Debugf("Call to [%s] succeeded after %d attempts", endpoint, attempt)
+			}
+			return nil
+		}
+		if ctx.Err() != nil || !retryable(err) {
+			return err
+		}
+		if attempt >= p.maxAttempts {
+			return &attemptsError{err: err, attempts: attempt}
+		}
+
+		log.Debugf("Retrying call to [%s] in %s after attempt %d of %d failed: %s", endpoint, backoff, attempt, p.maxAttempts, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > p.maxBackoff {
+			backoff = p.maxBackoff
+		}
+	}
+}
+
+// attemptsError is the error of a call that failed after several attempts.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string {
+	return fmt.Sprintf("%s (after %d attempts)", e.err, e.attempts)
+}
+
+// retryable returns true if the error is a connection error, e.g. a reset
+// connection, or a response reporting the public API as unavailable.
+func retryable(err error) bool {
+	switch err := err.(type) {
+	case *url.Error:
+		return true
+	case *UnexpectedResponseError:
+		return err.StatusCode == http.StatusBadGateway ||
+			err.StatusCode == http.StatusServiceUnavailable ||
+			err.StatusCode == http.StatusGatewayTimeout
+	default:
+		return false
+	}
+}
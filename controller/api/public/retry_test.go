@@ -0,0 +1,121 @@
+package public
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	newTestClient := func(t *testing.T, handler http.HandlerFunc) (*grpcOverHttpClient, func()) {
+		server := httptest.NewServer(handler)
+		client, err := newDirectClient(server.Listener.Addr().String(), "linkerd", TLSOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		c := client.(*grpcOverHttpClient)
+		c.retry.baseBackoff = time.Millisecond
+		c.retry.maxBackoff = 10 * time.Millisecond
+		return c, server.Close
+	}
+
+	t.Run("Retries idempotent calls while the API is unavailable", func(t *testing.T) {
+		var requests int32
+		client, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			io.Copy(w, bufferedReader(t, &healthcheckPb.SelfCheckResponse{}))
+		})
+		defer done()
+
+		if _, err := client.SelfCheck(context.Background(), &healthcheckPb.SelfCheckRequest{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if requests != 3 {
+			t.Fatalf("Expected 3 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Reports the number of attempts made", func(t *testing.T) {
+		client, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer done()
+
+		_, err := client.Version(context.Background(), &pb.Empty{})
+		expected := "Unexpected API response: 503 Service Unavailable (after 3 attempts)"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	})
+
+	t.Run("Retries calls whose connection was reset", func(t *testing.T) {
+		var requests int32
+		client, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&requests, 1) == 1 {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			io.Copy(w, bufferedReader(t, &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}))
+		})
+		defer done()
+
+		rsp, err := client.Version(context.Background(), &pb.Empty{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rsp.GetReleaseVersion() != "stable-2.1.0" || requests != 2 {
+			t.Fatalf("Expected the version after 2 requests, got %s after %d", rsp.GetReleaseVersion(), requests)
+		}
+	})
+
+	t.Run("Doesn't retry calls that aren't idempotent or errors that aren't transient", func(t *testing.T) {
+		var requests int32
+		client, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if strings.HasSuffix(r.URL.Path, "/StatSummary") {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+		})
+		defer done()
+
+		client.StatSummary(context.Background(), &pb.StatSummaryRequest{})
+		client.ListPods(context.Background(), &pb.ListPodsRequest{})
+		if requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Stops retrying once the context is done", func(t *testing.T) {
+		client, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer done()
+		client.retry.maxAttempts = 1000
+		client.retry.baseBackoff = time.Second
+		client.retry.maxBackoff = time.Second
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := client.Version(ctx, &pb.Empty{}); err != context.DeadlineExceeded {
+			t.Fatalf("Expected the context's error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Fatalf("Expected to return once the context is done, took %s", elapsed)
+		}
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MockApiClient struct {
@@ -21,6 +23,24 @@ type MockApiClient struct {
 	SelfCheckResponseToReturn       *healthcheckPb.SelfCheckResponse
 	Api_TapClientToReturn           pb.Api_TapClient
 	Api_TapByResourceClientToReturn pb.Api_TapByResourceClient
+
+	// SelfCheckDelay delays the SelfCheck response, which is abandoned with
+	// the context's error if the context is done first
+	SelfCheckDelay time.Duration
+
+	// SelfCheckRequestReceived is the last SelfCheck request received. As with
+	// control planes predating subsystem filtering, every result is returned
+	// regardless of the subsystems requested.
+	SelfCheckRequestReceived *healthcheckPb.SelfCheckRequest
+
+	// SelfCheckStreamResultsToReturn are the results sent by SelfCheckStream,
+	// followed by SelfCheckStreamErrorToReturn or, if unset, by the end of
+	// the stream. If SelfCheckStreamHangs is set, the stream then hangs until
+	// its context is done instead. Unless any are set, SelfCheckStream is
+	// unimplemented, as on control planes predating it.
+	SelfCheckStreamResultsToReturn []*healthcheckPb.CheckResult
+	SelfCheckStreamErrorToReturn   error
+	SelfCheckStreamHangs           bool
 }
 
 func (c *MockApiClient) StatSummary(ctx context.Context, in *pb.StatSummaryRequest, opts ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
@@ -44,9 +64,47 @@ func (c *MockApiClient) TapByResource(ctx context.Context, in *pb.TapByResourceR
 }
 
 func (c *MockApiClient) SelfCheck(ctx context.Context, in *healthcheckPb.SelfCheckRequest, _ ...grpc.CallOption) (*healthcheckPb.SelfCheckResponse, error) {
+	c.SelfCheckRequestReceived = in
+	if c.SelfCheckDelay > 0 {
+		select {
+		case <-time.After(c.SelfCheckDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 	return c.SelfCheckResponseToReturn, c.ErrorToReturn
 }
 
+func (c *MockApiClient) SelfCheckStream(ctx context.Context, in *healthcheckPb.SelfCheckRequest, _ ...grpc.CallOption) (pb.Api_SelfCheckStreamClient, error) {
+	c.SelfCheckRequestReceived = in
+	if c.SelfCheckStreamResultsToReturn == nil && c.SelfCheckStreamErrorToReturn == nil && !c.SelfCheckStreamHangs {
+		return nil, status.Error(codes.Unimplemented, "SelfCheckStream is not implemented")
+	}
+	return &mockSelfCheckStreamClient{ctx: ctx, client: c}, nil
+}
+
+type mockSelfCheckStreamClient struct {
+	ctx    context.Context
+	client *MockApiClient
+	sent   int
+	grpc.ClientStream
+}
+
+func (s *mockSelfCheckStreamClient) Recv() (*healthcheckPb.CheckResult, error) {
+	if s.sent < len(s.client.SelfCheckStreamResultsToReturn) {
+		s.sent++
+		return s.client.SelfCheckStreamResultsToReturn[s.sent-1], nil
+	}
+	if s.client.SelfCheckStreamHangs {
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	if s.client.SelfCheckStreamErrorToReturn != nil {
+		return nil, s.client.SelfCheckStreamErrorToReturn
+	}
+	return nil, io.EOF
+}
+
 type MockApi_TapClient struct {
 	TapEventsToReturn []pb.TapEvent
 	ErrorsToReturn    []error
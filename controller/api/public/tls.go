@@ -0,0 +1,150 @@
+package public
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// TLSOptions configures TLS for the clients reaching the public API directly,
+// over a port-forward or at a given address. The zero value connects in
+// plaintext; setting any option connects over TLS, always verifying the
+// server's certificate.
+type TLSOptions struct {
+	// CAFile is the PEM-encoded CA bundle the server's certificate is
+	// verified with. Defaults to the system's roots.
+	CAFile string
+
+	// CertFile and KeyFile are the PEM-encoded client certificate and key
+	// presented to the server, for mutual TLS.
+	CertFile string
+	KeyFile  string
+
+	// ServerName overrides the name the server's certificate is verified
+	// against, e.g. when it is reached over a port-forward on 127.0.0.1.
+	ServerName string
+}
+
+func (o TLSOptions) enabled() bool {
+	return o.CAFile != "" || o.CertFile != "" || o.KeyFile != "" || o.ServerName != ""
+}
+
+func (o TLSOptions) scheme() string {
+	if o.enabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// config returns the TLS configuration of the options, or nil for plaintext.
+func (o TLSOptions) config() (*tls.Config, error) {
+	if !o.enabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{ServerName: o.ServerName}
+
+	if o.CAFile != "" {
+		pem, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, &ConnectionError{Layer: TLSLayer, Err: fmt.Errorf("failed to read the CA bundle: %s", err)}
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, &ConnectionError{Layer: TLSLayer, Err: fmt.Errorf("the CA bundle %s contains no PEM-encoded certificates", o.CAFile)}
+		}
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, &ConnectionError{Layer: TLSLayer, Err: errors.New("a client certificate and key must be given together")}
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, &ConnectionError{Layer: TLSLayer, Err: fmt.Errorf("failed to load the client certificate: %s", err)}
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// configFor returns the TLS configuration of the options for a server reached
+// at the given host, whose certificate is verified against the host's name
+// unless ServerName overrides it. Unlike config, it never returns nil.
+func (o TLSOptions) configFor(host string) (*tls.Config, error) {
+	if o.ServerName == "" {
+		o.ServerName = host
+	}
+	return o.config()
+}
+
+// The layers at which the connection to the public API may fail.
+const (
+	TCPLayer  = "TCP"
+	TLSLayer  = "TLS"
+	GRPCLayer = "gRPC"
+)
+
+// ConnectionError is returned when the public API cannot be reached, and
+// reports whether the TCP connection, the TLS handshake or the call itself
+// failed.
+type ConnectionError struct {
+	Layer string
+	Err   error
+}
+
+func (e *ConnectionError) Error() string {
+	switch e.Layer {
+	case TCPLayer:
+		return fmt.Sprintf("TCP connection to the public API failed: %s", e.Err)
+	case TLSLayer:
+		return fmt.Sprintf("TLS handshake with the public API failed: %s", e.Err)
+	default:
+		return fmt.Sprintf("the public API failed to serve the request: %s", e.Err)
+	}
+}
+
+// classifyConnectionError wraps the error of a call to the public API in a
+// ConnectionError naming the layer it failed at.
+func classifyConnectionError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*ConnectionError); ok {
+		return err
+	}
+
+	cause := err
+	if attempts, ok := cause.(*attemptsError); ok {
+		cause = attempts.err
+	}
+	urlErr, isURLErr := cause.(*url.Error)
+	if isURLErr {
+		cause = urlErr.Err
+	}
+
+	switch cause.(type) {
+	case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError, tls.RecordHeaderError:
+		return &ConnectionError{Layer: TLSLayer, Err: err}
+	}
+	if opErr, ok := cause.(*net.OpError); ok {
+		if opErr.Op == "remote error" {
+			return &ConnectionError{Layer: TLSLayer, Err: err}
+		}
+		return &ConnectionError{Layer: TCPLayer, Err: err}
+	}
+	if message := cause.Error(); strings.HasPrefix(message, "tls: ") || strings.Contains(message, "x509: ") ||
+		strings.Contains(message, "server gave HTTP response to HTTPS client") {
+		return &ConnectionError{Layer: TLSLayer, Err: err}
+	}
+	if isURLErr {
+		return &ConnectionError{Layer: TCPLayer, Err: err}
+	}
+	return &ConnectionError{Layer: GRPCLayer, Err: err}
+}
@@ -0,0 +1,89 @@
+package public
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+)
+
+func TestCheckConnection(t *testing.T) {
+	versionHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, bufferedReader(t, &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}))
+	})
+
+	tlsServer := httptest.NewTLSServer(versionHandler)
+	defer tlsServer.Close()
+	caFile, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+	caFile.Close()
+
+	plaintextServer := httptest.NewServer(versionHandler)
+	defer plaintextServer.Close()
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failingServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	testCases := []struct {
+		name          string
+		addr          string
+		tlsOptions    TLSOptions
+		expectedLayer string
+	}{
+		{"Connects in plaintext by default", plaintextServer.Listener.Addr().String(), TLSOptions{}, ""},
+		{"Verifies the server with the given CA", tlsServer.Listener.Addr().String(), TLSOptions{CAFile: caFile.Name()}, ""},
+		{"Verifies the server against the given server name", tlsServer.Listener.Addr().String(), TLSOptions{CAFile: caFile.Name(), ServerName: "example.com"}, ""},
+		{"Reports a refused connection as a TCP failure", closedAddr, TLSOptions{}, TCPLayer},
+		{"Reports an untrusted server as a TLS failure", tlsServer.Listener.Addr().String(), TLSOptions{ServerName: "example.com"}, TLSLayer},
+		{"Reports a server name mismatch as a TLS failure", tlsServer.Listener.Addr().String(), TLSOptions{CAFile: caFile.Name(), ServerName: "linkerd.io"}, TLSLayer},
+		{"Reports a plaintext server as a TLS failure", plaintextServer.Listener.Addr().String(), TLSOptions{CAFile: caFile.Name()}, TLSLayer},
+		{"Reports a client certificate without its key as a TLS failure", tlsServer.Listener.Addr().String(), TLSOptions{CertFile: caFile.Name()}, TLSLayer},
+		{"Reports a failed call as a gRPC failure", failingServer.Listener.Addr().String(), TLSOptions{}, GRPCLayer},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := NewInternalClientWithTLS("linkerd", tc.addr, tc.tlsOptions)
+			if err == nil {
+				defer conn.Close()
+				conn.ApiClient.(*grpcOverHttpClient).retry.maxAttempts = 1
+				err = CheckConnection(context.Background(), conn)
+			}
+
+			if tc.expectedLayer == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			connErr, ok := err.(*ConnectionError)
+			if !ok {
+				t.Fatalf("Expected a ConnectionError, got %v", err)
+			}
+			if connErr.Layer != tc.expectedLayer {
+				t.Fatalf("Expected a %s failure, got %s", tc.expectedLayer, connErr)
+			}
+		})
+	}
+}
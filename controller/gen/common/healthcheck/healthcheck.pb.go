@@ -41,7 +41,7 @@ func (x CheckStatus) String() string {
 	return proto.EnumName(CheckStatus_name, int32(x))
 }
 func (CheckStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_healthcheck_bb9bf49369fb327d, []int{0}
+	return fileDescriptor_healthcheck_6be7e23697dbd969, []int{0}
 }
 
 type CheckResult struct {
@@ -58,7 +58,7 @@ func (m *CheckResult) Reset()         { *m = CheckResult{} }
 func (m *CheckResult) String() string { return proto.CompactTextString(m) }
 func (*CheckResult) ProtoMessage()    {}
 func (*CheckResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_healthcheck_bb9bf49369fb327d, []int{0}
+	return fileDescriptor_healthcheck_6be7e23697dbd969, []int{0}
 }
 func (m *CheckResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CheckResult.Unmarshal(m, b)
@@ -107,6 +107,7 @@ func (m *CheckResult) GetFriendlyMessageToUser() string {
 }
 
 type SelfCheckRequest struct {
+	Subsystems           []string `protobuf:"bytes,1,rep,name=subsystems,proto3" json:"subsystems,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -116,7 +117,7 @@ func (m *SelfCheckRequest) Reset()         { *m = SelfCheckRequest{} }
 func (m *SelfCheckRequest) String() string { return proto.CompactTextString(m) }
 func (*SelfCheckRequest) ProtoMessage()    {}
 func (*SelfCheckRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_healthcheck_bb9bf49369fb327d, []int{1}
+	return fileDescriptor_healthcheck_6be7e23697dbd969, []int{1}
 }
 func (m *SelfCheckRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SelfCheckRequest.Unmarshal(m, b)
@@ -136,6 +137,13 @@ func (m *SelfCheckRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SelfCheckRequest proto.InternalMessageInfo
 
+func (m *SelfCheckRequest) GetSubsystems() []string {
+	if m != nil {
+		return m.Subsystems
+	}
+	return nil
+}
+
 type SelfCheckResponse struct {
 	Results              []*CheckResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
@@ -147,7 +155,7 @@ func (m *SelfCheckResponse) Reset()         { *m = SelfCheckResponse{} }
 func (m *SelfCheckResponse) String() string { return proto.CompactTextString(m) }
 func (*SelfCheckResponse) ProtoMessage()    {}
 func (*SelfCheckResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_healthcheck_bb9bf49369fb327d, []int{2}
+	return fileDescriptor_healthcheck_6be7e23697dbd969, []int{2}
 }
 func (m *SelfCheckResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SelfCheckResponse.Unmarshal(m, b)
@@ -182,29 +190,29 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("common/healthcheck.proto", fileDescriptor_healthcheck_bb9bf49369fb327d)
-}
-
-var fileDescriptor_healthcheck_bb9bf49369fb327d = []byte{
-	// 306 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
-	0x10, 0xc5, 0x4d, 0x5b, 0xab, 0x9d, 0xa2, 0xc4, 0x05, 0x61, 0xc1, 0x4b, 0x29, 0x1e, 0x42, 0x0f,
-	0x09, 0x54, 0xaf, 0xa2, 0x56, 0x2d, 0x88, 0x7f, 0x0a, 0x5b, 0x45, 0xf0, 0x96, 0xa6, 0x63, 0x13,
-	0xba, 0xd9, 0xad, 0x3b, 0x9b, 0x43, 0x3f, 0xa9, 0x5f, 0x47, 0xba, 0x49, 0xa1, 0x52, 0x11, 0x4f,
-	0xbb, 0xbc, 0x7d, 0xbf, 0x9d, 0x37, 0x33, 0xc0, 0x13, 0x9d, 0xe7, 0x5a, 0x45, 0x29, 0xc6, 0xd2,
-	0xa6, 0x49, 0x8a, 0xc9, 0x3c, 0x5c, 0x18, 0x6d, 0x35, 0x3b, 0x91, 0x99, 0x9a, 0xa3, 0x99, 0xf6,
-	0xc3, 0xd2, 0x12, 0x6e, 0x58, 0xba, 0x5f, 0x1e, 0xb4, 0x6f, 0x56, 0x37, 0x81, 0x54, 0x48, 0xcb,
-	0x4e, 0xe1, 0x60, 0x5c, 0x4c, 0x68, 0x49, 0x16, 0xf3, 0xe7, 0x38, 0x47, 0xee, 0x75, 0xbc, 0xa0,
-	0x25, 0x7e, 0x8a, 0xac, 0x07, 0xbe, 0x83, 0x6e, 0x91, 0x12, 0x93, 0x2d, 0x6c, 0xa6, 0x15, 0xaf,
-	0x39, 0xe3, 0x96, 0xce, 0xae, 0xa0, 0x39, 0xb6, 0xb1, 0x2d, 0x88, 0xd7, 0x3b, 0x5e, 0x70, 0xd8,
-	0x0f, 0xc2, 0x3f, 0xf2, 0x84, 0x0e, 0x2f, 0xfd, 0xa2, 0xe2, 0xd8, 0x39, 0x1c, 0x0f, 0x4d, 0x86,
-	0x6a, 0x2a, 0x97, 0x4f, 0x48, 0x14, 0xcf, 0xf0, 0x45, 0xbf, 0x12, 0x1a, 0xde, 0x70, 0x25, 0x7f,
-	0x7f, 0xec, 0x32, 0xf0, 0xc7, 0x28, 0x3f, 0xaa, 0xe6, 0x3e, 0x0b, 0x24, 0xdb, 0x7d, 0x83, 0xa3,
-	0x0d, 0x8d, 0x16, 0x5a, 0x11, 0xb2, 0x01, 0xec, 0x19, 0xd7, 0x3c, 0x71, 0xaf, 0x53, 0x0f, 0xda,
-	0xff, 0x49, 0x58, 0x4e, 0x4b, 0xac, 0xc1, 0x5e, 0xaf, 0x9a, 0x62, 0x95, 0xb8, 0x09, 0xb5, 0xd1,
-	0x83, 0xbf, 0xc3, 0xf6, 0xa1, 0x31, 0xbc, 0xbe, 0x7f, 0xf4, 0x3d, 0xd6, 0x82, 0xdd, 0x3b, 0x21,
-	0x46, 0xc2, 0xaf, 0x0d, 0x2e, 0xdf, 0x2f, 0x66, 0x99, 0x4d, 0x8b, 0xc9, 0xea, 0xf7, 0xa8, 0x2a,
-	0xb5, 0x3e, 0xfb, 0x51, 0xa2, 0x95, 0x35, 0x5a, 0x4a, 0x34, 0xd1, 0x0c, 0x55, 0xb4, 0xbd, 0xd6,
-	0x49, 0xd3, 0xed, 0xf5, 0xec, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x78, 0x7f, 0xc1, 0x03, 0xf3, 0x01,
-	0x00, 0x00,
+	proto.RegisterFile("common/healthcheck.proto", fileDescriptor_healthcheck_6be7e23697dbd969)
+}
+
+var fileDescriptor_healthcheck_6be7e23697dbd969 = []byte{
+	// 315 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xcf, 0x4a, 0xf3, 0x40,
+	0x14, 0xc5, 0xbf, 0xb4, 0xfd, 0xaa, 0xbd, 0x45, 0x89, 0x03, 0x42, 0x40, 0x90, 0x52, 0x5c, 0x84,
+	0x2e, 0x12, 0x88, 0x6e, 0x45, 0xad, 0x5a, 0x10, 0xff, 0x14, 0xa6, 0x8a, 0xe0, 0x2e, 0x4d, 0xaf,
+	0x4d, 0xe8, 0x64, 0xa6, 0xce, 0x9d, 0x2c, 0xfa, 0xa4, 0xbe, 0x8e, 0x74, 0x92, 0x42, 0xa4, 0x22,
+	0xae, 0x12, 0xce, 0x9c, 0xdf, 0xdc, 0x39, 0xe7, 0x82, 0x97, 0xa8, 0x3c, 0x57, 0x32, 0x4c, 0x31,
+	0x16, 0x26, 0x4d, 0x52, 0x4c, 0x16, 0xc1, 0x52, 0x2b, 0xa3, 0xd8, 0x91, 0xc8, 0xe4, 0x02, 0xf5,
+	0x2c, 0x0a, 0x4a, 0x4b, 0x50, 0xb3, 0xf4, 0x3f, 0x1d, 0xe8, 0x5e, 0xaf, 0xff, 0x38, 0x52, 0x21,
+	0x0c, 0x3b, 0x81, 0xbd, 0x49, 0x31, 0xa5, 0x15, 0x19, 0xcc, 0x9f, 0xe2, 0x1c, 0x3d, 0xa7, 0xe7,
+	0xf8, 0x1d, 0xfe, 0x5d, 0x64, 0x03, 0x70, 0x2d, 0x74, 0x83, 0x94, 0xe8, 0x6c, 0x69, 0x32, 0x25,
+	0xbd, 0x86, 0x35, 0x6e, 0xe9, 0xec, 0x12, 0xda, 0x13, 0x13, 0x9b, 0x82, 0xbc, 0x66, 0xcf, 0xf1,
+	0xf7, 0x23, 0x3f, 0xf8, 0xe5, 0x3d, 0x81, 0xc5, 0x4b, 0x3f, 0xaf, 0x38, 0x76, 0x06, 0x87, 0x23,
+	0x9d, 0xa1, 0x9c, 0x89, 0xd5, 0x23, 0x12, 0xc5, 0x73, 0x7c, 0x56, 0x2f, 0x84, 0xda, 0x6b, 0xd9,
+	0x91, 0x3f, 0x1f, 0xf6, 0x23, 0x70, 0x27, 0x28, 0xde, 0xab, 0x70, 0x1f, 0x05, 0x92, 0x61, 0xc7,
+	0x00, 0xb4, 0x09, 0x42, 0x9e, 0xd3, 0x6b, 0xfa, 0x1d, 0x5e, 0x53, 0xfa, 0xaf, 0x70, 0x50, 0x63,
+	0x68, 0xa9, 0x24, 0x21, 0x1b, 0xc2, 0x8e, 0xb6, 0xe5, 0x94, 0x44, 0xf7, 0x2f, 0x09, 0xca, 0x36,
+	0xf9, 0x06, 0x1c, 0x0c, 0xaa, 0x96, 0xab, 0x44, 0x6d, 0x68, 0x8c, 0xef, 0xdd, 0x7f, 0x6c, 0x17,
+	0x5a, 0xa3, 0xab, 0xbb, 0x07, 0xd7, 0x61, 0x1d, 0xf8, 0x7f, 0xcb, 0xf9, 0x98, 0xbb, 0x8d, 0xe1,
+	0xc5, 0xdb, 0xf9, 0x3c, 0x33, 0x69, 0x31, 0x5d, 0xdf, 0x1e, 0x56, 0xa3, 0x36, 0xdf, 0x28, 0x4c,
+	0x94, 0x34, 0x5a, 0x09, 0x81, 0x3a, 0x9c, 0xa3, 0x0c, 0xb7, 0xd7, 0x3e, 0x6d, 0xdb, 0xbd, 0x9f,
+	0x7e, 0x0d, 0x00, 0xbd, 0xad, 0x68, 0x6c, 0x13, 0x02, 0x00, 0x00,
 }
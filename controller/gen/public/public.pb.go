@@ -66,7 +66,7 @@ func (x HttpMethod_Registered) String() string {
 	return proto.EnumName(HttpMethod_Registered_name, int32(x))
 }
 func (HttpMethod_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{7, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{7, 0}
 }
 
 type Scheme_Registered int32
@@ -89,7 +89,7 @@ func (x Scheme_Registered) String() string {
 	return proto.EnumName(Scheme_Registered_name, int32(x))
 }
 func (Scheme_Registered) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{8, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{8, 0}
 }
 
 type TapEvent_ProxyDirection int32
@@ -115,7 +115,7 @@ func (x TapEvent_ProxyDirection) String() string {
 	return proto.EnumName(TapEvent_ProxyDirection_name, int32(x))
 }
 func (TapEvent_ProxyDirection) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 0}
 }
 
 type Empty struct {
@@ -128,7 +128,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{0}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -161,7 +161,7 @@ func (m *VersionInfo) Reset()         { *m = VersionInfo{} }
 func (m *VersionInfo) String() string { return proto.CompactTextString(m) }
 func (*VersionInfo) ProtoMessage()    {}
 func (*VersionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{1}
+	return fileDescriptor_public_b3b32936323b63b8, []int{1}
 }
 func (m *VersionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionInfo.Unmarshal(m, b)
@@ -213,7 +213,7 @@ func (m *ListPodsRequest) Reset()         { *m = ListPodsRequest{} }
 func (m *ListPodsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListPodsRequest) ProtoMessage()    {}
 func (*ListPodsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{2}
+	return fileDescriptor_public_b3b32936323b63b8, []int{2}
 }
 func (m *ListPodsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsRequest.Unmarshal(m, b)
@@ -251,7 +251,7 @@ func (m *ListPodsResponse) Reset()         { *m = ListPodsResponse{} }
 func (m *ListPodsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListPodsResponse) ProtoMessage()    {}
 func (*ListPodsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{3}
+	return fileDescriptor_public_b3b32936323b63b8, []int{3}
 }
 func (m *ListPodsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListPodsResponse.Unmarshal(m, b)
@@ -306,7 +306,7 @@ func (m *Pod) Reset()         { *m = Pod{} }
 func (m *Pod) String() string { return proto.CompactTextString(m) }
 func (*Pod) ProtoMessage()    {}
 func (*Pod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{4}
+	return fileDescriptor_public_b3b32936323b63b8, []int{4}
 }
 func (m *Pod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pod.Unmarshal(m, b)
@@ -636,7 +636,7 @@ func (m *TapRequest) Reset()         { *m = TapRequest{} }
 func (m *TapRequest) String() string { return proto.CompactTextString(m) }
 func (*TapRequest) ProtoMessage()    {}
 func (*TapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{5}
+	return fileDescriptor_public_b3b32936323b63b8, []int{5}
 }
 func (m *TapRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapRequest.Unmarshal(m, b)
@@ -839,7 +839,7 @@ func (m *TapByResourceRequest) Reset()         { *m = TapByResourceRequest{} }
 func (m *TapByResourceRequest) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest) ProtoMessage()    {}
 func (*TapByResourceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{6}
+	return fileDescriptor_public_b3b32936323b63b8, []int{6}
 }
 func (m *TapByResourceRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest.Unmarshal(m, b)
@@ -897,7 +897,7 @@ func (m *TapByResourceRequest_Match) Reset()         { *m = TapByResourceRequest
 func (m *TapByResourceRequest_Match) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match) ProtoMessage()    {}
 func (*TapByResourceRequest_Match) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{6, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{6, 0}
 }
 func (m *TapByResourceRequest_Match) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match.Unmarshal(m, b)
@@ -1135,7 +1135,7 @@ func (m *TapByResourceRequest_Match_Seq) Reset()         { *m = TapByResourceReq
 func (m *TapByResourceRequest_Match_Seq) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Seq) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Seq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{6, 0, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{6, 0, 0}
 }
 func (m *TapByResourceRequest_Match_Seq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Seq.Unmarshal(m, b)
@@ -1178,7 +1178,7 @@ func (m *TapByResourceRequest_Match_Http) Reset()         { *m = TapByResourceRe
 func (m *TapByResourceRequest_Match_Http) String() string { return proto.CompactTextString(m) }
 func (*TapByResourceRequest_Match_Http) ProtoMessage()    {}
 func (*TapByResourceRequest_Match_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{6, 0, 1}
+	return fileDescriptor_public_b3b32936323b63b8, []int{6, 0, 1}
 }
 func (m *TapByResourceRequest_Match_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapByResourceRequest_Match_Http.Unmarshal(m, b)
@@ -1371,7 +1371,7 @@ func (m *HttpMethod) Reset()         { *m = HttpMethod{} }
 func (m *HttpMethod) String() string { return proto.CompactTextString(m) }
 func (*HttpMethod) ProtoMessage()    {}
 func (*HttpMethod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{7}
+	return fileDescriptor_public_b3b32936323b63b8, []int{7}
 }
 func (m *HttpMethod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_HttpMethod.Unmarshal(m, b)
@@ -1507,7 +1507,7 @@ func (m *Scheme) Reset()         { *m = Scheme{} }
 func (m *Scheme) String() string { return proto.CompactTextString(m) }
 func (*Scheme) ProtoMessage()    {}
 func (*Scheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{8}
+	return fileDescriptor_public_b3b32936323b63b8, []int{8}
 }
 func (m *Scheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Scheme.Unmarshal(m, b)
@@ -1643,7 +1643,7 @@ func (m *IPAddress) Reset()         { *m = IPAddress{} }
 func (m *IPAddress) String() string { return proto.CompactTextString(m) }
 func (*IPAddress) ProtoMessage()    {}
 func (*IPAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{9}
+	return fileDescriptor_public_b3b32936323b63b8, []int{9}
 }
 func (m *IPAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPAddress.Unmarshal(m, b)
@@ -1781,7 +1781,7 @@ func (m *IPv6) Reset()         { *m = IPv6{} }
 func (m *IPv6) String() string { return proto.CompactTextString(m) }
 func (*IPv6) ProtoMessage()    {}
 func (*IPv6) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{10}
+	return fileDescriptor_public_b3b32936323b63b8, []int{10}
 }
 func (m *IPv6) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_IPv6.Unmarshal(m, b)
@@ -1827,7 +1827,7 @@ func (m *TcpAddress) Reset()         { *m = TcpAddress{} }
 func (m *TcpAddress) String() string { return proto.CompactTextString(m) }
 func (*TcpAddress) ProtoMessage()    {}
 func (*TcpAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{11}
+	return fileDescriptor_public_b3b32936323b63b8, []int{11}
 }
 func (m *TcpAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TcpAddress.Unmarshal(m, b)
@@ -1875,7 +1875,7 @@ func (m *Eos) Reset()         { *m = Eos{} }
 func (m *Eos) String() string { return proto.CompactTextString(m) }
 func (*Eos) ProtoMessage()    {}
 func (*Eos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{12}
+	return fileDescriptor_public_b3b32936323b63b8, []int{12}
 }
 func (m *Eos) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Eos.Unmarshal(m, b)
@@ -2014,7 +2014,7 @@ func (m *TapEvent) Reset()         { *m = TapEvent{} }
 func (m *TapEvent) String() string { return proto.CompactTextString(m) }
 func (*TapEvent) ProtoMessage()    {}
 func (*TapEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13}
 }
 func (m *TapEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent.Unmarshal(m, b)
@@ -2159,7 +2159,7 @@ func (m *TapEvent_EndpointMeta) Reset()         { *m = TapEvent_EndpointMeta{} }
 func (m *TapEvent_EndpointMeta) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_EndpointMeta) ProtoMessage()    {}
 func (*TapEvent_EndpointMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 0}
 }
 func (m *TapEvent_EndpointMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_EndpointMeta.Unmarshal(m, b)
@@ -2201,7 +2201,7 @@ func (m *TapEvent_Http) Reset()         { *m = TapEvent_Http{} }
 func (m *TapEvent_Http) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http) ProtoMessage()    {}
 func (*TapEvent_Http) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 1}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 1}
 }
 func (m *TapEvent_Http) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http.Unmarshal(m, b)
@@ -2378,7 +2378,7 @@ func (m *TapEvent_Http_StreamId) Reset()         { *m = TapEvent_Http_StreamId{}
 func (m *TapEvent_Http_StreamId) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_StreamId) ProtoMessage()    {}
 func (*TapEvent_Http_StreamId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 1, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 1, 0}
 }
 func (m *TapEvent_Http_StreamId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_StreamId.Unmarshal(m, b)
@@ -2427,7 +2427,7 @@ func (m *TapEvent_Http_RequestInit) Reset()         { *m = TapEvent_Http_Request
 func (m *TapEvent_Http_RequestInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_RequestInit) ProtoMessage()    {}
 func (*TapEvent_Http_RequestInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 1, 1}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 1, 1}
 }
 func (m *TapEvent_Http_RequestInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_RequestInit.Unmarshal(m, b)
@@ -2495,7 +2495,7 @@ func (m *TapEvent_Http_ResponseInit) Reset()         { *m = TapEvent_Http_Respon
 func (m *TapEvent_Http_ResponseInit) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseInit) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseInit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 1, 2}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 1, 2}
 }
 func (m *TapEvent_Http_ResponseInit) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseInit.Unmarshal(m, b)
@@ -2551,7 +2551,7 @@ func (m *TapEvent_Http_ResponseEnd) Reset()         { *m = TapEvent_Http_Respons
 func (m *TapEvent_Http_ResponseEnd) String() string { return proto.CompactTextString(m) }
 func (*TapEvent_Http_ResponseEnd) ProtoMessage()    {}
 func (*TapEvent_Http_ResponseEnd) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{13, 1, 3}
+	return fileDescriptor_public_b3b32936323b63b8, []int{13, 1, 3}
 }
 func (m *TapEvent_Http_ResponseEnd) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TapEvent_Http_ResponseEnd.Unmarshal(m, b)
@@ -2617,7 +2617,7 @@ func (m *ApiError) Reset()         { *m = ApiError{} }
 func (m *ApiError) String() string { return proto.CompactTextString(m) }
 func (*ApiError) ProtoMessage()    {}
 func (*ApiError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{14}
+	return fileDescriptor_public_b3b32936323b63b8, []int{14}
 }
 func (m *ApiError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ApiError.Unmarshal(m, b)
@@ -2655,7 +2655,7 @@ func (m *PodErrors) Reset()         { *m = PodErrors{} }
 func (m *PodErrors) String() string { return proto.CompactTextString(m) }
 func (*PodErrors) ProtoMessage()    {}
 func (*PodErrors) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{15}
+	return fileDescriptor_public_b3b32936323b63b8, []int{15}
 }
 func (m *PodErrors) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors.Unmarshal(m, b)
@@ -2695,7 +2695,7 @@ func (m *PodErrors_PodError) Reset()         { *m = PodErrors_PodError{} }
 func (m *PodErrors_PodError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError) ProtoMessage()    {}
 func (*PodErrors_PodError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{15, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{15, 0}
 }
 func (m *PodErrors_PodError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError.Unmarshal(m, b)
@@ -2809,7 +2809,7 @@ func (m *PodErrors_PodError_ContainerError) Reset()         { *m = PodErrors_Pod
 func (m *PodErrors_PodError_ContainerError) String() string { return proto.CompactTextString(m) }
 func (*PodErrors_PodError_ContainerError) ProtoMessage()    {}
 func (*PodErrors_PodError_ContainerError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{15, 0, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{15, 0, 0}
 }
 func (m *PodErrors_PodError_ContainerError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PodErrors_PodError_ContainerError.Unmarshal(m, b)
@@ -2880,7 +2880,7 @@ func (m *Resource) Reset()         { *m = Resource{} }
 func (m *Resource) String() string { return proto.CompactTextString(m) }
 func (*Resource) ProtoMessage()    {}
 func (*Resource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{16}
+	return fileDescriptor_public_b3b32936323b63b8, []int{16}
 }
 func (m *Resource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Resource.Unmarshal(m, b)
@@ -2939,7 +2939,7 @@ func (m *ResourceSelection) Reset()         { *m = ResourceSelection{} }
 func (m *ResourceSelection) String() string { return proto.CompactTextString(m) }
 func (*ResourceSelection) ProtoMessage()    {}
 func (*ResourceSelection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{17}
+	return fileDescriptor_public_b3b32936323b63b8, []int{17}
 }
 func (m *ResourceSelection) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceSelection.Unmarshal(m, b)
@@ -2985,7 +2985,7 @@ func (m *ResourceError) Reset()         { *m = ResourceError{} }
 func (m *ResourceError) String() string { return proto.CompactTextString(m) }
 func (*ResourceError) ProtoMessage()    {}
 func (*ResourceError) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{18}
+	return fileDescriptor_public_b3b32936323b63b8, []int{18}
 }
 func (m *ResourceError) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResourceError.Unmarshal(m, b)
@@ -3036,7 +3036,7 @@ func (m *StatSummaryRequest) Reset()         { *m = StatSummaryRequest{} }
 func (m *StatSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryRequest) ProtoMessage()    {}
 func (*StatSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{19}
+	return fileDescriptor_public_b3b32936323b63b8, []int{19}
 }
 func (m *StatSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryRequest.Unmarshal(m, b)
@@ -3227,7 +3227,7 @@ func (m *StatSummaryResponse) Reset()         { *m = StatSummaryResponse{} }
 func (m *StatSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse) ProtoMessage()    {}
 func (*StatSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{20}
+	return fileDescriptor_public_b3b32936323b63b8, []int{20}
 }
 func (m *StatSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse.Unmarshal(m, b)
@@ -3369,7 +3369,7 @@ func (m *StatSummaryResponse_Ok) Reset()         { *m = StatSummaryResponse_Ok{}
 func (m *StatSummaryResponse_Ok) String() string { return proto.CompactTextString(m) }
 func (*StatSummaryResponse_Ok) ProtoMessage()    {}
 func (*StatSummaryResponse_Ok) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{20, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{20, 0}
 }
 func (m *StatSummaryResponse_Ok) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummaryResponse_Ok.Unmarshal(m, b)
@@ -3412,7 +3412,7 @@ func (m *BasicStats) Reset()         { *m = BasicStats{} }
 func (m *BasicStats) String() string { return proto.CompactTextString(m) }
 func (*BasicStats) ProtoMessage()    {}
 func (*BasicStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{21}
+	return fileDescriptor_public_b3b32936323b63b8, []int{21}
 }
 func (m *BasicStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BasicStats.Unmarshal(m, b)
@@ -3487,7 +3487,7 @@ func (m *StatTable) Reset()         { *m = StatTable{} }
 func (m *StatTable) String() string { return proto.CompactTextString(m) }
 func (*StatTable) ProtoMessage()    {}
 func (*StatTable) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{22}
+	return fileDescriptor_public_b3b32936323b63b8, []int{22}
 }
 func (m *StatTable) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable.Unmarshal(m, b)
@@ -3597,7 +3597,7 @@ func (m *StatTable_PodGroup) Reset()         { *m = StatTable_PodGroup{} }
 func (m *StatTable_PodGroup) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup) ProtoMessage()    {}
 func (*StatTable_PodGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{22, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{22, 0}
 }
 func (m *StatTable_PodGroup) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup.Unmarshal(m, b)
@@ -3645,7 +3645,7 @@ func (m *StatTable_PodGroup_Row) Reset()         { *m = StatTable_PodGroup_Row{}
 func (m *StatTable_PodGroup_Row) String() string { return proto.CompactTextString(m) }
 func (*StatTable_PodGroup_Row) ProtoMessage()    {}
 func (*StatTable_PodGroup_Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_public_ff43b1a50eb4ceae, []int{22, 0, 0}
+	return fileDescriptor_public_b3b32936323b63b8, []int{22, 0, 0}
 }
 func (m *StatTable_PodGroup_Row) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatTable_PodGroup_Row.Unmarshal(m, b)
@@ -3779,6 +3779,8 @@ type ApiClient interface {
 	TapByResource(ctx context.Context, in *TapByResourceRequest, opts ...grpc.CallOption) (Api_TapByResourceClient, error)
 	Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionInfo, error)
 	SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheck.SelfCheckResponse, error)
+	// Reports the result of each subsystem's check as it completes.
+	SelfCheckStream(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (Api_SelfCheckStreamClient, error)
 }
 
 type apiClient struct {
@@ -3890,6 +3892,38 @@ func (c *apiClient) SelfCheck(ctx context.Context, in *healthcheck.SelfCheckRequ
 	return out, nil
 }
 
+func (c *apiClient) SelfCheckStream(ctx context.Context, in *healthcheck.SelfCheckRequest, opts ...grpc.CallOption) (Api_SelfCheckStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Api_serviceDesc.Streams[2], "/linkerd2.public.Api/SelfCheckStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiSelfCheckStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Api_SelfCheckStreamClient interface {
+	Recv() (*healthcheck.CheckResult, error)
+	grpc.ClientStream
+}
+
+type apiSelfCheckStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiSelfCheckStreamClient) Recv() (*healthcheck.CheckResult, error) {
+	m := new(healthcheck.CheckResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ApiServer is the server API for Api service.
 type ApiServer interface {
 	StatSummary(context.Context, *StatSummaryRequest) (*StatSummaryResponse, error)
@@ -3900,6 +3934,8 @@ type ApiServer interface {
 	TapByResource(*TapByResourceRequest, Api_TapByResourceServer) error
 	Version(context.Context, *Empty) (*VersionInfo, error)
 	SelfCheck(context.Context, *healthcheck.SelfCheckRequest) (*healthcheck.SelfCheckResponse, error)
+	// Reports the result of each subsystem's check as it completes.
+	SelfCheckStream(*healthcheck.SelfCheckRequest, Api_SelfCheckStreamServer) error
 }
 
 func RegisterApiServer(s *grpc.Server, srv ApiServer) {
@@ -4020,6 +4056,27 @@ func _Api_SelfCheck_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Api_SelfCheckStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(healthcheck.SelfCheckRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServer).SelfCheckStream(m, &apiSelfCheckStreamServer{stream})
+}
+
+type Api_SelfCheckStreamServer interface {
+	Send(*healthcheck.CheckResult) error
+	grpc.ServerStream
+}
+
+type apiSelfCheckStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiSelfCheckStreamServer) Send(m *healthcheck.CheckResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Api_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "linkerd2.public.Api",
 	HandlerType: (*ApiServer)(nil),
@@ -4052,169 +4109,175 @@ var _Api_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Api_TapByResource_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SelfCheckStream",
+			Handler:       _Api_SelfCheckStream_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "public.proto",
 }
 
-func init() { proto.RegisterFile("public.proto", fileDescriptor_public_ff43b1a50eb4ceae) }
-
-var fileDescriptor_public_ff43b1a50eb4ceae = []byte{
-	// 2506 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x3b, 0x73, 0x1b, 0xc9,
-	0xf1, 0xc7, 0x63, 0xf1, 0x6a, 0x00, 0x24, 0x34, 0xd2, 0xe9, 0x0f, 0xe1, 0xae, 0x74, 0xd4, 0x4a,
-	0xa7, 0x63, 0xe9, 0xfe, 0x06, 0x29, 0xe8, 0xa4, 0x13, 0xa5, 0xf3, 0x83, 0x20, 0x61, 0x81, 0x36,
-	0x45, 0x42, 0x03, 0xc8, 0x57, 0xa5, 0xba, 0x2a, 0xd4, 0x02, 0x3b, 0x24, 0xd6, 0x5c, 0xec, 0xac,
-	0x76, 0x07, 0xe2, 0x21, 0x75, 0xe4, 0x0f, 0x60, 0xc7, 0x8e, 0xed, 0xc4, 0xe5, 0xc4, 0x1f, 0xc2,
-	0x91, 0x33, 0x67, 0x76, 0xe6, 0xd4, 0x89, 0x63, 0xdb, 0x35, 0xaf, 0xc5, 0x82, 0x00, 0x1f, 0x92,
-	0x13, 0x47, 0x98, 0xee, 0xf9, 0x75, 0x6f, 0x4f, 0x77, 0x4f, 0xf7, 0xcc, 0x00, 0x4a, 0xfe, 0x64,
-	0xe0, 0x3a, 0xc3, 0xba, 0x1f, 0x50, 0x46, 0xd1, 0xaa, 0xeb, 0x78, 0x27, 0x24, 0xb0, 0x1b, 0x75,
-	0xc9, 0xae, 0xdd, 0x3e, 0xa6, 0xf4, 0xd8, 0x25, 0x1b, 0x62, 0x7a, 0x30, 0x39, 0xda, 0xb0, 0x27,
-	0x81, 0xc5, 0x1c, 0xea, 0x49, 0x81, 0x5a, 0x75, 0x48, 0xc7, 0x63, 0xea, 0x6d, 0x8c, 0x88, 0xe5,
-	0xb2, 0xd1, 0x70, 0x44, 0x86, 0x27, 0x72, 0xc6, 0xcc, 0x41, 0xa6, 0x35, 0xf6, 0xd9, 0xd4, 0x7c,
-	0x0b, 0xc5, 0x9f, 0x91, 0x20, 0x74, 0xa8, 0xb7, 0xe7, 0x1d, 0x51, 0xf4, 0x09, 0x14, 0x8e, 0xa9,
-	0x62, 0x54, 0x93, 0x6b, 0xc9, 0xf5, 0x02, 0x9e, 0x31, 0xf8, 0xec, 0x60, 0xe2, 0xb8, 0xf6, 0xae,
-	0xc5, 0x48, 0x35, 0x25, 0x67, 0x23, 0x06, 0xba, 0x0f, 0x2b, 0x01, 0x71, 0x89, 0x15, 0x12, 0xad,
-	0x20, 0x2d, 0x20, 0x67, 0xb8, 0xe6, 0x06, 0xac, 0xee, 0x3b, 0x21, 0xeb, 0x50, 0x3b, 0xc4, 0xe4,
-	0xed, 0x84, 0x84, 0x8c, 0x2b, 0xf6, 0xac, 0x31, 0x09, 0x7d, 0x6b, 0x48, 0xf4, 0x67, 0x23, 0x86,
-	0xf9, 0x35, 0x54, 0x66, 0x02, 0xa1, 0x4f, 0xbd, 0x90, 0xa0, 0x75, 0x30, 0x7c, 0x6a, 0x87, 0xd5,
-	0xe4, 0x5a, 0x7a, 0xbd, 0xd8, 0xb8, 0x51, 0x3f, 0xe3, 0x9a, 0x7a, 0x87, 0xda, 0x58, 0x20, 0xcc,
-	0x3f, 0x1b, 0x90, 0xee, 0x50, 0x1b, 0x21, 0x30, 0xb8, 0x4a, 0xa5, 0x5e, 0x8c, 0xd1, 0x0d, 0xc8,
-	0xf8, 0xd4, 0xde, 0xeb, 0xa8, 0xc5, 0x48, 0x02, 0xad, 0x01, 0xd8, 0xc4, 0x77, 0xe9, 0x74, 0x4c,
-	0x3c, 0x26, 0x17, 0xd1, 0x4e, 0xe0, 0x18, 0x0f, 0xdd, 0x81, 0x62, 0x40, 0x7c, 0xd7, 0x19, 0x5a,
-	0xfd, 0x90, 0xb0, 0x2a, 0x68, 0x88, 0x62, 0x76, 0x09, 0x43, 0x5f, 0xc1, 0x4d, 0x45, 0xf1, 0x80,
-	0xf4, 0x87, 0xd4, 0x63, 0x01, 0x75, 0x5d, 0x12, 0x54, 0x8b, 0x0a, 0xfd, 0x51, 0x6c, 0x7e, 0x27,
-	0x9a, 0x46, 0x77, 0xa1, 0x14, 0x32, 0x8b, 0x91, 0xa3, 0x89, 0x2b, 0x94, 0x97, 0x14, 0xbc, 0xa8,
-	0xb9, 0x5c, 0xfb, 0xa7, 0x00, 0xb6, 0x45, 0xc6, 0xd4, 0x13, 0x90, 0xb2, 0x82, 0x14, 0x24, 0x8f,
-	0x03, 0x10, 0xa4, 0x7f, 0x4e, 0x07, 0xd5, 0x15, 0x35, 0xc3, 0x09, 0x74, 0x13, 0xb2, 0x5c, 0xc7,
-	0x24, 0xac, 0x1a, 0x62, 0xb9, 0x8a, 0xe2, 0x5e, 0xb0, 0x6c, 0x9b, 0xd8, 0xd5, 0xcc, 0x5a, 0x72,
-	0x3d, 0x8f, 0x25, 0x81, 0x76, 0x60, 0x35, 0x74, 0xbc, 0x21, 0xd9, 0xb7, 0x42, 0x86, 0x89, 0x4f,
-	0x03, 0x56, 0xcd, 0xae, 0x25, 0xd7, 0x8b, 0x8d, 0x5b, 0x75, 0x99, 0x76, 0x75, 0x9d, 0x76, 0xf5,
-	0x5d, 0x95, 0x76, 0xf8, 0xac, 0x04, 0xda, 0x84, 0xeb, 0xb3, 0x95, 0x1f, 0x44, 0x21, 0xce, 0x89,
-	0xef, 0x2f, 0x9b, 0x42, 0x26, 0x94, 0x14, 0xbb, 0xe3, 0x5a, 0x1e, 0xa9, 0xe6, 0x85, 0x4d, 0x73,
-	0x3c, 0xf4, 0x10, 0xb2, 0x13, 0x9f, 0x39, 0x63, 0x52, 0x2d, 0x5c, 0x66, 0x91, 0x02, 0xa2, 0xdb,
-	0x00, 0x7e, 0x40, 0xbf, 0x9b, 0x62, 0x62, 0xd9, 0xd3, 0xea, 0xaa, 0x50, 0x1a, 0xe3, 0xf0, 0xcf,
-	0x0a, 0x4a, 0xa7, 0x6e, 0x45, 0x58, 0x38, 0xc7, 0x6b, 0xe6, 0x20, 0x43, 0x4f, 0x3d, 0x12, 0x98,
-	0xbf, 0x4b, 0x01, 0xf4, 0x2c, 0x5f, 0x67, 0x2f, 0x82, 0xb4, 0x4f, 0x6d, 0x99, 0x58, 0xdc, 0xd7,
-	0x3e, 0xb5, 0xcf, 0xe4, 0x50, 0x6a, 0x49, 0x0e, 0xdd, 0x84, 0xec, 0xd8, 0xfa, 0x0e, 0xfb, 0xa1,
-	0xc8, 0xb0, 0x14, 0x56, 0x14, 0xe7, 0x33, 0xda, 0xe1, 0xee, 0xe6, 0x51, 0x2a, 0x63, 0x45, 0xf1,
-	0xfc, 0x65, 0x74, 0xaf, 0x23, 0x82, 0x54, 0xc0, 0x62, 0x8c, 0x6a, 0x90, 0x3f, 0x0a, 0xe8, 0xb8,
-	0xa3, 0x83, 0x53, 0xc6, 0x11, 0xcd, 0xf5, 0xf0, 0xf1, 0x5e, 0x47, 0x79, 0x5b, 0x51, 0x22, 0x0b,
-	0x86, 0x23, 0x32, 0x96, 0xae, 0xe5, 0x59, 0x20, 0x28, 0x61, 0x0f, 0x61, 0x23, 0x6a, 0x0b, 0xa7,
-	0x16, 0xb0, 0xa2, 0xf8, 0xde, 0xb4, 0x26, 0x6c, 0x44, 0x03, 0x87, 0x4d, 0x65, 0xa6, 0xe3, 0x19,
-	0x83, 0x5b, 0xe5, 0x5b, 0x6c, 0x24, 0x93, 0x1a, 0x8b, 0xf1, 0xb3, 0x54, 0x35, 0xd9, 0xcc, 0x43,
-	0x96, 0x59, 0xc1, 0x31, 0x61, 0xe6, 0xdf, 0x33, 0x70, 0xa3, 0x67, 0xf9, 0xcd, 0x29, 0x26, 0x21,
-	0x9d, 0x04, 0x43, 0xa2, 0xdd, 0xf6, 0x4c, 0x43, 0x84, 0xe7, 0x8a, 0x0d, 0x73, 0x61, 0x13, 0x6b,
-	0x89, 0x2e, 0x71, 0xc9, 0x50, 0x86, 0x53, 0x4a, 0xa0, 0x6d, 0xc8, 0x8c, 0x2d, 0x36, 0x1c, 0x09,
-	0xcf, 0x16, 0x1b, 0x5f, 0x2c, 0x88, 0x2e, 0xfb, 0x62, 0xfd, 0x25, 0x17, 0xc1, 0x52, 0xf2, 0x3c,
-	0xff, 0xd7, 0xfe, 0x68, 0x40, 0x46, 0x00, 0xd1, 0x0e, 0xa4, 0x2d, 0xd7, 0x55, 0xd6, 0x6d, 0xbc,
-	0xc7, 0x27, 0xea, 0x5d, 0xf2, 0x96, 0x27, 0x82, 0xe5, 0xba, 0x42, 0x89, 0x37, 0x55, 0x76, 0x7e,
-	0x90, 0x12, 0x6f, 0x8a, 0x7e, 0x08, 0x69, 0x8f, 0xca, 0x52, 0xf4, 0x7e, 0x8b, 0xe5, 0x0a, 0x3c,
-	0xca, 0x50, 0x1b, 0x4a, 0x36, 0x09, 0x99, 0xe3, 0x89, 0x5d, 0x21, 0x0b, 0xc0, 0x95, 0x3c, 0xde,
-	0x4e, 0xe0, 0x39, 0x49, 0xf4, 0x63, 0x30, 0x46, 0x8c, 0xf9, 0x22, 0x0d, 0x8b, 0x8d, 0xcd, 0xf7,
-	0x59, 0x50, 0x9b, 0x31, 0xbf, 0x9d, 0xc0, 0x42, 0xbe, 0xb6, 0x0f, 0xe9, 0x2e, 0x79, 0x8b, 0x5a,
-	0x90, 0x13, 0xe1, 0x20, 0xba, 0x94, 0xbf, 0x57, 0x28, 0xb5, 0x6c, 0x6d, 0x0a, 0x06, 0xd7, 0x8e,
-	0xaa, 0x51, 0x72, 0xeb, 0xdd, 0xa8, 0xd3, 0xbb, 0x1a, 0xa5, 0xb7, 0xde, 0x8c, 0x3a, 0xc1, 0x6f,
-	0xc7, 0x13, 0x5c, 0x57, 0xfb, 0x58, 0x8a, 0xdf, 0x50, 0x29, 0x6e, 0xa8, 0x29, 0x41, 0xf1, 0x62,
-	0x20, 0x3e, 0x1e, 0x0d, 0xcc, 0x7f, 0x26, 0x01, 0xb8, 0x11, 0x2f, 0xa5, 0xda, 0x36, 0x40, 0x40,
-	0x8e, 0x9d, 0x90, 0x91, 0x80, 0xc8, 0xe2, 0xb0, 0xd2, 0xb8, 0xbf, 0xb0, 0xb8, 0x99, 0x40, 0x1d,
-	0x47, 0x68, 0xd9, 0x4a, 0x34, 0x85, 0xee, 0x41, 0x69, 0xe2, 0xc5, 0x74, 0xe9, 0x05, 0xcc, 0x71,
-	0x4d, 0x0f, 0x60, 0xa6, 0x01, 0xe5, 0x20, 0xfd, 0xa2, 0xd5, 0xab, 0x24, 0x50, 0x1e, 0x8c, 0xce,
-	0x61, 0xb7, 0x57, 0x49, 0x72, 0x56, 0xe7, 0x75, 0xaf, 0x92, 0x42, 0x00, 0xd9, 0xdd, 0xd6, 0x7e,
-	0xab, 0xd7, 0xaa, 0xa4, 0x51, 0x01, 0x32, 0x9d, 0xed, 0xde, 0x4e, 0xbb, 0x62, 0xa0, 0x22, 0xe4,
-	0x0e, 0x3b, 0xbd, 0xbd, 0xc3, 0x83, 0x6e, 0x25, 0xc3, 0x89, 0x9d, 0xc3, 0x83, 0x83, 0xd6, 0x4e,
-	0xaf, 0x92, 0xe5, 0x3a, 0xda, 0xad, 0xed, 0xdd, 0x4a, 0x8e, 0xc3, 0x7b, 0x78, 0x7b, 0xa7, 0x55,
-	0xc9, 0x37, 0xb3, 0x60, 0xb0, 0xa9, 0x4f, 0xcc, 0xdf, 0x24, 0x21, 0xdb, 0x95, 0x3e, 0xde, 0x5d,
-	0xb2, 0xe4, 0xc5, 0x1c, 0x93, 0xe0, 0xff, 0x76, 0xb9, 0x77, 0xe6, 0x96, 0xcb, 0x2d, 0xec, 0xf5,
-	0x3a, 0x95, 0x04, 0xb7, 0x90, 0x8f, 0xba, 0x95, 0x64, 0x64, 0x61, 0x0f, 0x0a, 0x7b, 0x9d, 0x6d,
-	0xdb, 0x0e, 0x48, 0xc8, 0x9b, 0x9d, 0xe1, 0xf8, 0xef, 0xbe, 0x14, 0xd6, 0xe5, 0x78, 0x34, 0x39,
-	0x85, 0xbe, 0x10, 0xdc, 0x27, 0x6a, 0x9b, 0x7e, 0xb4, 0x60, 0xf3, 0x5e, 0xe7, 0xdd, 0x13, 0x05,
-	0x7e, 0xd2, 0x34, 0x20, 0xe5, 0xf8, 0xe6, 0x26, 0x18, 0x9c, 0xcb, 0xbb, 0xe7, 0x91, 0x13, 0x84,
-	0xb2, 0x8a, 0x65, 0xb1, 0x24, 0x78, 0x5d, 0x74, 0xad, 0x50, 0x56, 0xfe, 0x2c, 0x16, 0x63, 0x73,
-	0x1f, 0xa0, 0x37, 0xf4, 0xb5, 0x21, 0x0f, 0xb8, 0x16, 0x55, 0x5c, 0x6a, 0x4b, 0x3e, 0xa8, 0x70,
-	0x38, 0xe5, 0xf8, 0xa2, 0xca, 0xf2, 0x1a, 0x9f, 0x12, 0x35, 0x5e, 0x8c, 0x4d, 0x1b, 0xd2, 0x2d,
-	0xca, 0xd5, 0x54, 0x8e, 0x03, 0x7f, 0xd8, 0x97, 0xbd, 0xbc, 0x3f, 0xa4, 0xb6, 0xcc, 0xfd, 0x72,
-	0x3b, 0x81, 0x57, 0xf8, 0x4c, 0x57, 0x4c, 0xec, 0x50, 0x9b, 0x70, 0x6c, 0x40, 0x42, 0xc2, 0xfa,
-	0x24, 0x08, 0x68, 0x20, 0xb1, 0x29, 0x8d, 0x15, 0x33, 0x2d, 0x3e, 0xc1, 0xb1, 0xcd, 0x0c, 0xa4,
-	0x89, 0x67, 0x9b, 0xff, 0x2e, 0x41, 0xbe, 0x67, 0xf9, 0xad, 0x77, 0xbc, 0x65, 0x3d, 0x82, 0xac,
-	0xdc, 0x85, 0xca, 0xec, 0x8f, 0x17, 0xf7, 0x6a, 0xb4, 0x3e, 0xac, 0xa0, 0xe8, 0x05, 0x14, 0xe5,
-	0xa8, 0x3f, 0x26, 0xcc, 0x52, 0x75, 0xe3, 0xfe, 0xb2, 0x5d, 0x2e, 0x3e, 0x52, 0x6f, 0x79, 0xb6,
-	0x4f, 0x1d, 0x8f, 0xbd, 0x24, 0xcc, 0xc2, 0x20, 0x45, 0xf9, 0x18, 0x7d, 0x1f, 0x8a, 0xb1, 0x4a,
-	0xa4, 0x42, 0x75, 0xa1, 0x09, 0x71, 0x3c, 0x7a, 0x05, 0x95, 0x18, 0x29, 0x8d, 0x31, 0xde, 0xcb,
-	0x98, 0xd5, 0x98, 0xbc, 0xb0, 0xe8, 0x15, 0xac, 0x8a, 0x03, 0x42, 0xdf, 0x76, 0x02, 0x59, 0x2e,
-	0x45, 0x17, 0x5e, 0x69, 0xac, 0x9f, 0xaf, 0xb1, 0xc3, 0x05, 0x76, 0x35, 0x1e, 0xaf, 0xf8, 0x73,
-	0x34, 0xfa, 0x52, 0x95, 0x57, 0x59, 0xea, 0x6f, 0x9f, 0xaf, 0x67, 0xae, 0x98, 0xfe, 0x3a, 0x09,
-	0xa5, 0xb8, 0xa9, 0xe8, 0x27, 0x90, 0x75, 0xad, 0x01, 0x71, 0x75, 0x55, 0x6d, 0x5c, 0x6d, 0x89,
-	0xf5, 0x7d, 0x21, 0xd4, 0xf2, 0x58, 0x30, 0xc5, 0x4a, 0x43, 0x6d, 0x0b, 0x8a, 0x31, 0x36, 0xaa,
-	0x40, 0xfa, 0x84, 0x4c, 0xd5, 0x31, 0x9a, 0x0f, 0xf9, 0x0e, 0x78, 0x67, 0xb9, 0x13, 0x7d, 0x25,
-	0x90, 0xc4, 0xb3, 0xd4, 0xd3, 0x64, 0xed, 0x5f, 0x39, 0x55, 0x97, 0x0f, 0xa1, 0x14, 0xc8, 0xca,
-	0xdd, 0x77, 0x3c, 0x47, 0x77, 0xfc, 0x07, 0x17, 0x2f, 0xaf, 0xae, 0x8a, 0xfd, 0x9e, 0xe7, 0x30,
-	0x7e, 0x00, 0x0e, 0x66, 0x24, 0xc2, 0x50, 0x0e, 0xd4, 0x5d, 0x40, 0x6a, 0xbc, 0xe0, 0x20, 0x30,
-	0xa7, 0x51, 0xca, 0x28, 0x95, 0xa5, 0x20, 0x46, 0x4b, 0x23, 0x95, 0x4e, 0xe2, 0xd9, 0x2a, 0x06,
-	0x0f, 0xae, 0xa8, 0xb2, 0xe5, 0xd9, 0xd2, 0xc8, 0x88, 0xac, 0x3d, 0x81, 0x7c, 0x97, 0x05, 0xc4,
-	0x1a, 0xef, 0x89, 0xeb, 0xc7, 0xc0, 0x0a, 0xd5, 0xde, 0xc4, 0x62, 0x2c, 0x0f, 0xe4, 0x7c, 0x5e,
-	0x58, 0x6f, 0x60, 0x45, 0xd5, 0xfe, 0x9a, 0x84, 0x62, 0x6c, 0xed, 0xe8, 0x2b, 0x48, 0x39, 0xb6,
-	0xf2, 0xd9, 0xe7, 0x97, 0x98, 0xa3, 0x3f, 0x88, 0x53, 0x8e, 0xcd, 0x37, 0x6c, 0xac, 0xe9, 0x2d,
-	0xdb, 0x2d, 0xb3, 0xfe, 0x13, 0xf5, 0xc3, 0x8d, 0xa8, 0x87, 0x4a, 0x07, 0xfc, 0xdf, 0x39, 0x15,
-	0x3c, 0x6a, 0xad, 0x73, 0x27, 0x44, 0xe3, 0xbc, 0x13, 0x62, 0x66, 0x76, 0x42, 0xac, 0xfd, 0x21,
-	0x09, 0xa5, 0x78, 0x28, 0x3e, 0x7c, 0x85, 0x2f, 0x00, 0x89, 0x3b, 0x47, 0x7f, 0x2e, 0xbd, 0x52,
-	0x97, 0x5d, 0x0b, 0x2a, 0x42, 0x28, 0xee, 0xe3, 0x4f, 0xa1, 0xc8, 0xb7, 0x92, 0xaa, 0xa3, 0x62,
-	0xe9, 0x65, 0x0c, 0x9c, 0x25, 0x0b, 0x68, 0xed, 0xb7, 0x29, 0x1e, 0x94, 0x28, 0xb8, 0xff, 0x03,
-	0x26, 0xef, 0xc1, 0x75, 0xad, 0x28, 0xbe, 0x13, 0xd2, 0x97, 0x69, 0xba, 0xa6, 0x34, 0xc5, 0xfc,
-	0xff, 0x19, 0xbf, 0xbb, 0x2b, 0x25, 0x83, 0x29, 0x23, 0xf2, 0x84, 0x68, 0xe0, 0x68, 0x93, 0x35,
-	0x39, 0x13, 0xdd, 0x87, 0x34, 0xa1, 0xa1, 0xaa, 0xe1, 0x8b, 0x97, 0xee, 0x16, 0x0d, 0x31, 0x07,
-	0xf0, 0x33, 0x11, 0xe1, 0xab, 0x37, 0x9f, 0xc2, 0xca, 0x7c, 0xc1, 0xe3, 0x07, 0x8b, 0xd7, 0x07,
-	0x3f, 0x3d, 0x38, 0xfc, 0xe6, 0xa0, 0x92, 0xe0, 0xc4, 0xde, 0x41, 0xf3, 0xf0, 0xf5, 0xc1, 0x6e,
-	0x25, 0x89, 0x4a, 0x90, 0x3f, 0x7c, 0xdd, 0x93, 0x54, 0x6a, 0xa6, 0x62, 0x0d, 0xf2, 0xdb, 0xbe,
-	0x23, 0x1a, 0x13, 0xaf, 0x34, 0xa2, 0x75, 0xa9, 0xea, 0x23, 0x09, 0x7e, 0x1d, 0x2b, 0x74, 0xa8,
-	0x2d, 0x20, 0x21, 0x7a, 0x0e, 0x59, 0xc1, 0xd6, 0xa5, 0xef, 0xee, 0xb2, 0xb7, 0x01, 0x89, 0x8d,
-	0x46, 0x58, 0x89, 0xd4, 0xfe, 0x96, 0x84, 0xbc, 0x66, 0x22, 0x0c, 0x05, 0x7e, 0xed, 0xb4, 0x1c,
-	0x8f, 0x04, 0x2a, 0xd0, 0x8d, 0x2b, 0x28, 0xab, 0xef, 0x68, 0x21, 0x41, 0xf2, 0xc3, 0x64, 0xa4,
-	0xa6, 0xf6, 0x0e, 0x56, 0xe6, 0xa7, 0x51, 0x15, 0x72, 0x63, 0x12, 0x86, 0xd6, 0xb1, 0x7e, 0x9a,
-	0xd0, 0x24, 0xdf, 0x57, 0xb3, 0xef, 0xab, 0xe7, 0x96, 0x88, 0xc1, 0x7d, 0xe1, 0x8c, 0xb9, 0x94,
-	0x7c, 0x65, 0x91, 0x04, 0x2f, 0x29, 0x01, 0xb1, 0x42, 0xea, 0xe9, 0x3b, 0xbe, 0xa4, 0x84, 0x3b,
-	0x85, 0xb3, 0x3a, 0x90, 0xd7, 0x67, 0xe9, 0x8b, 0x9f, 0x5d, 0xc4, 0x85, 0x73, 0xea, 0xeb, 0xaa,
-	0x2e, 0xc6, 0xd1, 0x23, 0x4a, 0x7a, 0xf6, 0x88, 0x62, 0xbe, 0x85, 0x6b, 0x0b, 0xd7, 0x06, 0xf4,
-	0x18, 0xf2, 0x01, 0x99, 0x3b, 0x2c, 0xdc, 0x3a, 0xf7, 0xb2, 0x81, 0x23, 0x28, 0xcf, 0x43, 0xd1,
-	0x75, 0xfa, 0xa1, 0xd0, 0x44, 0xf5, 0xba, 0xcb, 0x82, 0xdb, 0x55, 0x4c, 0xf3, 0x5b, 0x28, 0x6b,
-	0x61, 0xe9, 0xc4, 0x0f, 0xfc, 0x5c, 0x94, 0x4f, 0xa9, 0x78, 0x3e, 0xfd, 0x3e, 0x05, 0x88, 0x6f,
-	0xfa, 0xee, 0x64, 0x3c, 0xb6, 0x82, 0xa9, 0xbe, 0xaf, 0xfe, 0x00, 0xf2, 0x91, 0x55, 0x57, 0xbf,
-	0xb1, 0x46, 0x32, 0xbc, 0xc2, 0x30, 0x67, 0x4c, 0xfa, 0xa7, 0x8e, 0x67, 0xd3, 0x53, 0xf5, 0x49,
-	0xe0, 0xac, 0x6f, 0x04, 0x07, 0xfd, 0x3f, 0x18, 0x1e, 0xf5, 0x74, 0xd9, 0xbd, 0xb9, 0xb8, 0xbd,
-	0xc6, 0x3e, 0x9b, 0xf2, 0x9e, 0xcf, 0x51, 0xe8, 0x6b, 0x28, 0x32, 0xda, 0x8f, 0x56, 0x6d, 0x5c,
-	0xb2, 0x6a, 0x7e, 0xc8, 0x66, 0x34, 0x0a, 0xfd, 0x8f, 0xa0, 0x7c, 0x14, 0xd0, 0xf1, 0x4c, 0x3e,
-	0x73, 0xb9, 0x7c, 0x89, 0x4b, 0x68, 0xba, 0x09, 0x90, 0xa7, 0x13, 0x36, 0xa0, 0x13, 0xcf, 0x36,
-	0xff, 0x92, 0x84, 0xeb, 0x73, 0x1e, 0x53, 0xaf, 0x74, 0x5b, 0x90, 0xa2, 0x27, 0xe7, 0xd6, 0xc8,
-	0x25, 0x12, 0xf5, 0xc3, 0x93, 0x76, 0x02, 0xa7, 0xe8, 0x09, 0x7a, 0x12, 0x0f, 0xcd, 0xb2, 0x93,
-	0xd0, 0x5c, 0x02, 0xb4, 0x13, 0x2a, 0x78, 0xb5, 0x6d, 0x48, 0x1d, 0x9e, 0xa0, 0xe7, 0x20, 0x9e,
-	0xcb, 0xfa, 0xcc, 0x1a, 0xb8, 0xd1, 0xd5, 0xb2, 0xb6, 0xd4, 0x82, 0x1e, 0x87, 0x60, 0x08, 0xf5,
-	0x30, 0xe4, 0x2b, 0xd3, 0x65, 0x4f, 0x5c, 0xea, 0x9a, 0x56, 0xe8, 0x88, 0x63, 0x74, 0x88, 0xee,
-	0x42, 0x39, 0x9c, 0x0c, 0x87, 0x24, 0xe4, 0x27, 0xed, 0x89, 0x27, 0x0f, 0x32, 0x06, 0x2e, 0x29,
-	0xe6, 0x0e, 0xe7, 0x71, 0xd0, 0x91, 0xe5, 0xb8, 0x93, 0x80, 0x28, 0x90, 0xec, 0xee, 0x25, 0xc5,
-	0x94, 0xa0, 0x7b, 0x3c, 0xd3, 0x19, 0xf1, 0x86, 0xd3, 0xfe, 0x38, 0xec, 0xfb, 0x8f, 0x37, 0x45,
-	0xd8, 0x0d, 0x5c, 0x52, 0xdc, 0x97, 0x61, 0xe7, 0xf1, 0xe6, 0x59, 0xd4, 0xd6, 0x63, 0x55, 0x97,
-	0x63, 0xa8, 0xad, 0xc7, 0x0b, 0xa8, 0x2d, 0x11, 0xcd, 0x79, 0xd4, 0x16, 0x7a, 0x00, 0xd7, 0x98,
-	0x1b, 0x46, 0x5d, 0x47, 0x9a, 0x96, 0x15, 0xc0, 0x55, 0xe6, 0xea, 0xb7, 0x58, 0x61, 0x9d, 0xf9,
-	0x0f, 0x03, 0x0a, 0x91, 0x73, 0x50, 0x13, 0x0a, 0x3e, 0xb5, 0xfb, 0xc7, 0x01, 0x9d, 0xe8, 0x1b,
-	0xcb, 0xdd, 0xf3, 0x7d, 0xc9, 0x0b, 0xe1, 0x0b, 0x0e, 0x6d, 0x27, 0x70, 0xde, 0x57, 0xe3, 0xda,
-	0xaf, 0x0c, 0x51, 0x59, 0x05, 0x81, 0x9e, 0x83, 0x11, 0xd0, 0x53, 0x1d, 0x97, 0xcf, 0xaf, 0xa0,
-	0xab, 0x8e, 0xe9, 0x29, 0x16, 0x42, 0xb5, 0x3f, 0xa5, 0x21, 0x8d, 0xe9, 0xe9, 0x87, 0xee, 0xf9,
-	0x4b, 0xb7, 0xe1, 0x3a, 0x54, 0xc6, 0x24, 0x1c, 0x11, 0xbb, 0xcf, 0x17, 0x2d, 0xdd, 0x24, 0x63,
-	0xb3, 0x22, 0xf9, 0x1d, 0x6a, 0xcb, 0x18, 0x3e, 0x80, 0x6b, 0xc1, 0xc4, 0xf3, 0x1c, 0xef, 0x38,
-	0x06, 0x95, 0x01, 0x5a, 0x55, 0x13, 0x11, 0x76, 0x1d, 0x2a, 0x3c, 0xfe, 0x73, 0x5a, 0xa5, 0xf3,
-	0x57, 0x24, 0x3f, 0x42, 0x3e, 0x84, 0x0c, 0x4f, 0x46, 0xdd, 0x66, 0x17, 0xcf, 0x6c, 0xb3, 0x7c,
-	0xc4, 0x12, 0x89, 0xbe, 0x85, 0xb2, 0x6c, 0x60, 0xfd, 0xc1, 0x94, 0xeb, 0xaf, 0xe6, 0x84, 0x63,
-	0x9f, 0x5e, 0xd1, 0xb1, 0x75, 0xd9, 0xc1, 0x9a, 0x53, 0xde, 0xc2, 0xc4, 0xd9, 0xbf, 0x48, 0x66,
-	0x9c, 0xda, 0x1b, 0xa8, 0x9c, 0x05, 0x2c, 0xb9, 0x05, 0x6c, 0xc6, 0x6f, 0x01, 0xcb, 0x36, 0x5b,
-	0xd4, 0x29, 0x63, 0x37, 0x04, 0xde, 0x97, 0xc4, 0x1e, 0x6d, 0xfc, 0xc2, 0x80, 0xf4, 0xb6, 0xef,
-	0xa0, 0x37, 0x50, 0x8c, 0xd5, 0x05, 0x74, 0xf7, 0xe2, 0xaa, 0x21, 0x52, 0xb6, 0x76, 0xef, 0x2a,
-	0xa5, 0xc5, 0x4c, 0xa0, 0x57, 0x90, 0xd7, 0x7f, 0x24, 0xa0, 0xb5, 0x05, 0x99, 0x33, 0x7f, 0x4a,
-	0xd4, 0xee, 0x5c, 0x80, 0x88, 0x54, 0xee, 0x42, 0xba, 0x67, 0xf9, 0xe8, 0xe3, 0x65, 0x07, 0x40,
-	0xad, 0xe8, 0xd6, 0xb9, 0xa7, 0x43, 0x33, 0xfd, 0xcb, 0x54, 0x72, 0x33, 0x89, 0x5e, 0x43, 0x79,
-	0xee, 0x95, 0x0b, 0x7d, 0x76, 0xa5, 0x57, 0xb0, 0x8b, 0x34, 0x27, 0x36, 0x93, 0x68, 0x1b, 0x72,
-	0xfa, 0xaf, 0x9b, 0x73, 0xba, 0x49, 0xed, 0x93, 0x05, 0x7e, 0xec, 0xef, 0x20, 0x33, 0x81, 0x5c,
-	0x28, 0x74, 0x89, 0x7b, 0xb4, 0x33, 0x22, 0xc3, 0x13, 0xf4, 0xbd, 0x19, 0x58, 0xfe, 0xb3, 0x54,
-	0x8f, 0xff, 0xb3, 0x14, 0xe1, 0xb4, 0x75, 0xf5, 0xab, 0xc2, 0xb5, 0x37, 0x9b, 0x8f, 0xde, 0x3c,
-	0x3c, 0x76, 0xd8, 0x68, 0x32, 0xe0, 0x02, 0x1b, 0x4a, 0x5a, 0xff, 0x36, 0x36, 0x66, 0xff, 0x17,
-	0x6c, 0x1c, 0x13, 0x6f, 0x43, 0x1a, 0x3c, 0xc8, 0x8a, 0x13, 0xee, 0xa3, 0xff, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0xfd, 0xb6, 0x0b, 0x26, 0x2d, 0x1b, 0x00, 0x00,
+func init() { proto.RegisterFile("public.proto", fileDescriptor_public_b3b32936323b63b8) }
+
+var fileDescriptor_public_b3b32936323b63b8 = []byte{
+	// 2519 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x4b, 0x73, 0x1b, 0xc7,
+	0xf1, 0xc7, 0x63, 0x01, 0x02, 0x0d, 0x80, 0x84, 0xc6, 0xb2, 0xfe, 0x30, 0xec, 0x92, 0xe9, 0x95,
+	0x2d, 0xb3, 0xe4, 0x7f, 0x40, 0x9a, 0xb6, 0x64, 0xcb, 0x76, 0x1e, 0x04, 0x89, 0x88, 0x4c, 0x24,
+	0x12, 0x1e, 0x40, 0x71, 0x95, 0xca, 0x55, 0xa8, 0x05, 0x76, 0x48, 0x6e, 0xb8, 0xd8, 0x59, 0xed,
+	0x0e, 0x24, 0xe3, 0x1b, 0xe4, 0x03, 0x24, 0xe7, 0x9c, 0x93, 0x4b, 0x2a, 0x97, 0x7c, 0x88, 0x9c,
+	0x72, 0xcb, 0x2d, 0xb9, 0xe5, 0x9a, 0xaa, 0x54, 0xce, 0x49, 0xaa, 0xe7, 0xb1, 0x58, 0x10, 0xe0,
+	0x43, 0xca, 0x25, 0x27, 0x4c, 0xf7, 0xfc, 0xba, 0xb7, 0xa7, 0xa7, 0xa7, 0xbb, 0x67, 0x00, 0xd5,
+	0x70, 0x32, 0xf4, 0xbd, 0x51, 0x2b, 0x8c, 0xb8, 0xe0, 0x64, 0xcd, 0xf7, 0x82, 0x33, 0x16, 0xb9,
+	0xdb, 0x2d, 0xc5, 0x6e, 0xde, 0x3e, 0xe1, 0xfc, 0xc4, 0x67, 0x9b, 0x72, 0x7a, 0x38, 0x39, 0xde,
+	0x74, 0x27, 0x91, 0x23, 0x3c, 0x1e, 0x28, 0x81, 0x66, 0x63, 0xc4, 0xc7, 0x63, 0x1e, 0x6c, 0x9e,
+	0x32, 0xc7, 0x17, 0xa7, 0xa3, 0x53, 0x36, 0x3a, 0x53, 0x33, 0xf6, 0x0a, 0x14, 0x3a, 0xe3, 0x50,
+	0x4c, 0xed, 0xe7, 0x50, 0xf9, 0x19, 0x8b, 0x62, 0x8f, 0x07, 0x07, 0xc1, 0x31, 0x27, 0xef, 0x40,
+	0xf9, 0x84, 0x6b, 0x46, 0x23, 0xbb, 0x9e, 0xdd, 0x28, 0xd3, 0x19, 0x03, 0x67, 0x87, 0x13, 0xcf,
+	0x77, 0xf7, 0x1c, 0xc1, 0x1a, 0x39, 0x35, 0x9b, 0x30, 0xc8, 0x5d, 0x58, 0x8d, 0x98, 0xcf, 0x9c,
+	0x98, 0x19, 0x05, 0x79, 0x09, 0x39, 0xc7, 0xb5, 0x37, 0x61, 0xed, 0xb1, 0x17, 0x8b, 0x2e, 0x77,
+	0x63, 0xca, 0x9e, 0x4f, 0x58, 0x2c, 0x50, 0x71, 0xe0, 0x8c, 0x59, 0x1c, 0x3a, 0x23, 0x66, 0x3e,
+	0x9b, 0x30, 0xec, 0xaf, 0xa0, 0x3e, 0x13, 0x88, 0x43, 0x1e, 0xc4, 0x8c, 0x6c, 0x80, 0x15, 0x72,
+	0x37, 0x6e, 0x64, 0xd7, 0xf3, 0x1b, 0x95, 0xed, 0x9b, 0xad, 0x73, 0xae, 0x69, 0x75, 0xb9, 0x4b,
+	0x25, 0xc2, 0xfe, 0x93, 0x05, 0xf9, 0x2e, 0x77, 0x09, 0x01, 0x0b, 0x55, 0x6a, 0xf5, 0x72, 0x4c,
+	0x6e, 0x42, 0x21, 0xe4, 0xee, 0x41, 0x57, 0x2f, 0x46, 0x11, 0x64, 0x1d, 0xc0, 0x65, 0xa1, 0xcf,
+	0xa7, 0x63, 0x16, 0x08, 0xb5, 0x88, 0xfd, 0x0c, 0x4d, 0xf1, 0xc8, 0x7b, 0x50, 0x89, 0x58, 0xe8,
+	0x7b, 0x23, 0x67, 0x10, 0x33, 0xd1, 0x00, 0x03, 0xd1, 0xcc, 0x1e, 0x13, 0xe4, 0x33, 0xb8, 0xa5,
+	0x29, 0xdc, 0x90, 0xc1, 0x88, 0x07, 0x22, 0xe2, 0xbe, 0xcf, 0xa2, 0x46, 0x45, 0xa3, 0xdf, 0x4c,
+	0xcd, 0xef, 0x26, 0xd3, 0xe4, 0x0e, 0x54, 0x63, 0xe1, 0x08, 0x76, 0x3c, 0xf1, 0xa5, 0xf2, 0xaa,
+	0x86, 0x57, 0x0c, 0x17, 0xb5, 0xbf, 0x0b, 0xe0, 0x3a, 0x6c, 0xcc, 0x03, 0x09, 0xa9, 0x69, 0x48,
+	0x59, 0xf1, 0x10, 0x40, 0x20, 0xff, 0x73, 0x3e, 0x6c, 0xac, 0xea, 0x19, 0x24, 0xc8, 0x2d, 0x28,
+	0xa2, 0x8e, 0x49, 0xdc, 0xb0, 0xe4, 0x72, 0x35, 0x85, 0x5e, 0x70, 0x5c, 0x97, 0xb9, 0x8d, 0xc2,
+	0x7a, 0x76, 0xa3, 0x44, 0x15, 0x41, 0x76, 0x61, 0x2d, 0xf6, 0x82, 0x11, 0x7b, 0xec, 0xc4, 0x82,
+	0xb2, 0x90, 0x47, 0xa2, 0x51, 0x5c, 0xcf, 0x6e, 0x54, 0xb6, 0xdf, 0x6a, 0xa9, 0xb0, 0x6b, 0x99,
+	0xb0, 0x6b, 0xed, 0xe9, 0xb0, 0xa3, 0xe7, 0x25, 0xc8, 0x16, 0xbc, 0x31, 0x5b, 0xf9, 0x61, 0xb2,
+	0xc5, 0x2b, 0xf2, 0xfb, 0xcb, 0xa6, 0x88, 0x0d, 0x55, 0xcd, 0xee, 0xfa, 0x4e, 0xc0, 0x1a, 0x25,
+	0x69, 0xd3, 0x1c, 0x8f, 0x7c, 0x0c, 0xc5, 0x49, 0x28, 0xbc, 0x31, 0x6b, 0x94, 0xaf, 0xb2, 0x48,
+	0x03, 0xc9, 0x6d, 0x80, 0x30, 0xe2, 0xdf, 0x4d, 0x29, 0x73, 0xdc, 0x69, 0x63, 0x4d, 0x2a, 0x4d,
+	0x71, 0xf0, 0xb3, 0x92, 0x32, 0xa1, 0x5b, 0x97, 0x16, 0xce, 0xf1, 0xda, 0x2b, 0x50, 0xe0, 0x2f,
+	0x03, 0x16, 0xd9, 0xbf, 0xcd, 0x01, 0xf4, 0x9d, 0xd0, 0x44, 0x2f, 0x81, 0x7c, 0xc8, 0xdd, 0x46,
+	0xd6, 0xf8, 0x3a, 0xe4, 0xee, 0xb9, 0x18, 0xca, 0x2d, 0x89, 0xa1, 0x5b, 0x50, 0x1c, 0x3b, 0xdf,
+	0xd1, 0x30, 0x96, 0x11, 0x96, 0xa3, 0x9a, 0x42, 0xbe, 0xe0, 0x5d, 0x74, 0x37, 0xee, 0x52, 0x8d,
+	0x6a, 0x0a, 0xe3, 0x57, 0xf0, 0x83, 0xae, 0xdc, 0xa4, 0x32, 0x95, 0x63, 0xd2, 0x84, 0xd2, 0x71,
+	0xc4, 0xc7, 0x5d, 0xb3, 0x39, 0x35, 0x9a, 0xd0, 0xa8, 0x07, 0xc7, 0x07, 0x5d, 0xed, 0x6d, 0x4d,
+	0x21, 0x3f, 0x1e, 0x9d, 0xb2, 0xb1, 0x72, 0x6d, 0x99, 0x6a, 0x4a, 0xda, 0xc3, 0xc4, 0x29, 0x77,
+	0xa5, 0x53, 0xcb, 0x54, 0x53, 0x78, 0x36, 0x9d, 0x89, 0x38, 0xe5, 0x91, 0x27, 0xa6, 0x2a, 0xd2,
+	0xe9, 0x8c, 0x81, 0x56, 0x85, 0x8e, 0x38, 0x55, 0x41, 0x4d, 0xe5, 0xf8, 0x8b, 0x5c, 0x23, 0xdb,
+	0x2e, 0x41, 0x51, 0x38, 0xd1, 0x09, 0x13, 0xf6, 0xdf, 0x0a, 0x70, 0xb3, 0xef, 0x84, 0xed, 0x29,
+	0x65, 0x31, 0x9f, 0x44, 0x23, 0x66, 0xdc, 0xf6, 0x85, 0x81, 0x48, 0xcf, 0x55, 0xb6, 0xed, 0x85,
+	0x43, 0x6c, 0x24, 0x7a, 0xcc, 0x67, 0x23, 0xb5, 0x9d, 0x4a, 0x82, 0xec, 0x40, 0x61, 0xec, 0x88,
+	0xd1, 0xa9, 0xf4, 0x6c, 0x65, 0xfb, 0xa3, 0x05, 0xd1, 0x65, 0x5f, 0x6c, 0x3d, 0x41, 0x11, 0xaa,
+	0x24, 0x2f, 0xf2, 0x7f, 0xf3, 0x0f, 0x16, 0x14, 0x24, 0x90, 0xec, 0x42, 0xde, 0xf1, 0x7d, 0x6d,
+	0xdd, 0xe6, 0x2b, 0x7c, 0xa2, 0xd5, 0x63, 0xcf, 0x31, 0x10, 0x1c, 0xdf, 0x97, 0x4a, 0x82, 0x69,
+	0x23, 0xf7, 0xfa, 0x4a, 0x82, 0x29, 0xf9, 0x21, 0xe4, 0x03, 0xae, 0x52, 0xd1, 0xab, 0x2d, 0x16,
+	0x15, 0x04, 0x5c, 0x90, 0x7d, 0xa8, 0xba, 0x2c, 0x16, 0x5e, 0x20, 0x4f, 0x85, 0x4a, 0x00, 0xd7,
+	0xf2, 0xf8, 0x7e, 0x86, 0xce, 0x49, 0x92, 0x1f, 0x83, 0x75, 0x2a, 0x44, 0x28, 0xc3, 0xb0, 0xb2,
+	0xbd, 0xf5, 0x2a, 0x0b, 0xda, 0x17, 0x22, 0xdc, 0xcf, 0x50, 0x29, 0xdf, 0x7c, 0x0c, 0xf9, 0x1e,
+	0x7b, 0x4e, 0x3a, 0xb0, 0x22, 0xb7, 0x83, 0x99, 0x54, 0xfe, 0x4a, 0x5b, 0x69, 0x64, 0x9b, 0x53,
+	0xb0, 0x50, 0x3b, 0x69, 0x24, 0xc1, 0x6d, 0x4e, 0xa3, 0xa6, 0x71, 0x46, 0x87, 0xb7, 0x39, 0x8c,
+	0x9a, 0x26, 0xb7, 0xd3, 0x01, 0x6e, 0xb2, 0xfd, 0x8c, 0x45, 0x6e, 0xea, 0x10, 0xb7, 0xf4, 0x94,
+	0xa4, 0x30, 0x19, 0xc8, 0x8f, 0x27, 0x03, 0xfb, 0x9f, 0x59, 0x00, 0x34, 0xe2, 0x89, 0x52, 0xbb,
+	0x0f, 0x10, 0xb1, 0x13, 0x2f, 0x16, 0x2c, 0x62, 0x2a, 0x39, 0xac, 0x6e, 0xdf, 0x5d, 0x58, 0xdc,
+	0x4c, 0xa0, 0x45, 0x13, 0xb4, 0x2a, 0x25, 0x86, 0x22, 0xef, 0x43, 0x75, 0x12, 0xa4, 0x74, 0x99,
+	0x05, 0xcc, 0x71, 0xed, 0x00, 0x60, 0xa6, 0x81, 0xac, 0x40, 0xfe, 0x51, 0xa7, 0x5f, 0xcf, 0x90,
+	0x12, 0x58, 0xdd, 0xa3, 0x5e, 0xbf, 0x9e, 0x45, 0x56, 0xf7, 0x69, 0xbf, 0x9e, 0x23, 0x00, 0xc5,
+	0xbd, 0xce, 0xe3, 0x4e, 0xbf, 0x53, 0xcf, 0x93, 0x32, 0x14, 0xba, 0x3b, 0xfd, 0xdd, 0xfd, 0xba,
+	0x45, 0x2a, 0xb0, 0x72, 0xd4, 0xed, 0x1f, 0x1c, 0x1d, 0xf6, 0xea, 0x05, 0x24, 0x76, 0x8f, 0x0e,
+	0x0f, 0x3b, 0xbb, 0xfd, 0x7a, 0x11, 0x75, 0xec, 0x77, 0x76, 0xf6, 0xea, 0x2b, 0x08, 0xef, 0xd3,
+	0x9d, 0xdd, 0x4e, 0xbd, 0xd4, 0x2e, 0x82, 0x25, 0xa6, 0x21, 0xb3, 0x7f, 0x9d, 0x85, 0x62, 0x4f,
+	0xf9, 0x78, 0x6f, 0xc9, 0x92, 0x17, 0x63, 0x4c, 0x81, 0xff, 0xdb, 0xe5, 0xbe, 0x37, 0xb7, 0x5c,
+	0xb4, 0xb0, 0xdf, 0xef, 0xd6, 0x33, 0x68, 0x21, 0x8e, 0x7a, 0xf5, 0x6c, 0x62, 0x61, 0x1f, 0xca,
+	0x07, 0xdd, 0x1d, 0xd7, 0x8d, 0x58, 0x8c, 0xc5, 0xce, 0xf2, 0xc2, 0x17, 0x9f, 0x4a, 0xeb, 0x56,
+	0x70, 0x37, 0x91, 0x22, 0x1f, 0x49, 0xee, 0x03, 0x7d, 0x4c, 0xdf, 0x5c, 0xb0, 0xf9, 0xa0, 0xfb,
+	0xe2, 0x81, 0x06, 0x3f, 0x68, 0x5b, 0x90, 0xf3, 0x42, 0x7b, 0x0b, 0x2c, 0xe4, 0x62, 0xf5, 0x3c,
+	0xf6, 0xa2, 0x58, 0x65, 0xb1, 0x22, 0x55, 0x04, 0xe6, 0x45, 0xdf, 0x89, 0x55, 0xe6, 0x2f, 0x52,
+	0x39, 0xb6, 0x1f, 0x03, 0xf4, 0x47, 0xa1, 0x31, 0xe4, 0x1e, 0x6a, 0xd1, 0xc9, 0xa5, 0xb9, 0xe4,
+	0x83, 0x1a, 0x47, 0x73, 0x5e, 0x28, 0xb3, 0x2c, 0x8f, 0x94, 0xb6, 0x1a, 0x95, 0x63, 0xdb, 0x85,
+	0x7c, 0x87, 0xa3, 0x9a, 0xfa, 0x49, 0x14, 0x8e, 0x06, 0xaa, 0x96, 0x0f, 0x46, 0xdc, 0x55, 0xb1,
+	0x5f, 0xdb, 0xcf, 0xd0, 0x55, 0x9c, 0xe9, 0xc9, 0x89, 0x5d, 0xee, 0x32, 0xc4, 0x46, 0x2c, 0x66,
+	0x62, 0xc0, 0xa2, 0x88, 0x47, 0x0a, 0x9b, 0x33, 0x58, 0x39, 0xd3, 0xc1, 0x09, 0xc4, 0xb6, 0x0b,
+	0x90, 0x67, 0x81, 0x6b, 0xff, 0xbb, 0x0a, 0xa5, 0xbe, 0x13, 0x76, 0x5e, 0x60, 0xc9, 0xfa, 0x04,
+	0x8a, 0xea, 0x14, 0x6a, 0xb3, 0xdf, 0x5e, 0x3c, 0xab, 0xc9, 0xfa, 0xa8, 0x86, 0x92, 0x47, 0x50,
+	0x51, 0xa3, 0xc1, 0x98, 0x09, 0x47, 0xe7, 0x8d, 0xbb, 0xcb, 0x4e, 0xb9, 0xfc, 0x48, 0xab, 0x13,
+	0xb8, 0x21, 0xf7, 0x02, 0xf1, 0x84, 0x09, 0x87, 0x82, 0x12, 0xc5, 0x31, 0xf9, 0x3e, 0x54, 0x52,
+	0x99, 0xa8, 0x91, 0xbb, 0xda, 0x84, 0x34, 0x9e, 0x7c, 0x0d, 0xf5, 0x14, 0xa9, 0x8c, 0xb1, 0x5e,
+	0xc9, 0x98, 0xb5, 0x94, 0xbc, 0xb4, 0xe8, 0x6b, 0x58, 0x93, 0x0d, 0xc2, 0xc0, 0xf5, 0x22, 0x95,
+	0x2e, 0x65, 0x15, 0x5e, 0xdd, 0xde, 0xb8, 0x58, 0x63, 0x17, 0x05, 0xf6, 0x0c, 0x9e, 0xae, 0x86,
+	0x73, 0x34, 0xf9, 0x54, 0xa7, 0x57, 0x95, 0xea, 0x6f, 0x5f, 0xac, 0x67, 0x2e, 0x99, 0xfe, 0x2a,
+	0x0b, 0xd5, 0xb4, 0xa9, 0xe4, 0x27, 0x50, 0xf4, 0x9d, 0x21, 0xf3, 0x4d, 0x56, 0xdd, 0xbe, 0xde,
+	0x12, 0x5b, 0x8f, 0xa5, 0x50, 0x27, 0x10, 0xd1, 0x94, 0x6a, 0x0d, 0xcd, 0x87, 0x50, 0x49, 0xb1,
+	0x49, 0x1d, 0xf2, 0x67, 0x6c, 0xaa, 0xdb, 0x68, 0x1c, 0xe2, 0x09, 0x78, 0xe1, 0xf8, 0x13, 0x73,
+	0x25, 0x50, 0xc4, 0x17, 0xb9, 0xcf, 0xb3, 0xcd, 0x7f, 0xad, 0xe8, 0xbc, 0x7c, 0x04, 0xd5, 0x48,
+	0x65, 0xee, 0x81, 0x17, 0x78, 0xa6, 0xe2, 0xdf, 0xbb, 0x7c, 0x79, 0x2d, 0x9d, 0xec, 0x0f, 0x02,
+	0x4f, 0x60, 0x03, 0x1c, 0xcd, 0x48, 0x42, 0xa1, 0x16, 0xe9, 0xbb, 0x80, 0xd2, 0x78, 0x49, 0x23,
+	0x30, 0xa7, 0x51, 0xc9, 0x68, 0x95, 0xd5, 0x28, 0x45, 0x2b, 0x23, 0xb5, 0x4e, 0x16, 0xb8, 0x8d,
+	0xfc, 0x35, 0x8d, 0x54, 0x22, 0x9d, 0xc0, 0x55, 0x46, 0x26, 0x64, 0xf3, 0x01, 0x94, 0x7a, 0x22,
+	0x62, 0xce, 0xf8, 0x40, 0x5e, 0x3f, 0x86, 0x4e, 0xac, 0xcf, 0x26, 0x95, 0x63, 0xd5, 0x90, 0xe3,
+	0xbc, 0xb4, 0xde, 0xa2, 0x9a, 0x6a, 0xfe, 0x25, 0x0b, 0x95, 0xd4, 0xda, 0xc9, 0x67, 0x90, 0xf3,
+	0x5c, 0xed, 0xb3, 0x0f, 0xaf, 0x30, 0xc7, 0x7c, 0x90, 0xe6, 0x3c, 0x17, 0x0f, 0x6c, 0xaa, 0xe8,
+	0x2d, 0x3b, 0x2d, 0xb3, 0xfa, 0x93, 0xd4, 0xc3, 0xcd, 0xa4, 0x86, 0x2a, 0x07, 0xfc, 0xdf, 0x05,
+	0x19, 0x3c, 0x29, 0xad, 0x73, 0x1d, 0xa2, 0x75, 0x51, 0x87, 0x58, 0x98, 0x75, 0x88, 0xcd, 0xdf,
+	0x67, 0xa1, 0x9a, 0xde, 0x8a, 0xd7, 0x5f, 0xe1, 0x23, 0x20, 0xf2, 0xce, 0x31, 0x98, 0x0b, 0xaf,
+	0xdc, 0x55, 0xd7, 0x82, 0xba, 0x14, 0x4a, 0xfb, 0xf8, 0x5d, 0xa8, 0xe0, 0x51, 0xd2, 0x79, 0x54,
+	0x2e, 0xbd, 0x46, 0x01, 0x59, 0x2a, 0x81, 0x36, 0x7f, 0x93, 0x83, 0x8a, 0xb1, 0xb9, 0x13, 0xb8,
+	0xff, 0x03, 0x26, 0x1f, 0xc0, 0x1b, 0x46, 0x51, 0xfa, 0x24, 0xe4, 0xaf, 0xd2, 0x74, 0x43, 0x6b,
+	0x4a, 0xf9, 0xff, 0x03, 0xbc, 0xbb, 0x6b, 0x25, 0xc3, 0xa9, 0x60, 0xaa, 0x43, 0xb4, 0x68, 0x72,
+	0xc8, 0xda, 0xc8, 0x24, 0x77, 0x21, 0xcf, 0x78, 0xac, 0x73, 0xf8, 0xe2, 0xa5, 0xbb, 0xc3, 0x63,
+	0x8a, 0x00, 0xec, 0x89, 0x18, 0xae, 0xde, 0xfe, 0x1c, 0x56, 0xe7, 0x13, 0x1e, 0x36, 0x16, 0x4f,
+	0x0f, 0x7f, 0x7a, 0x78, 0xf4, 0xcd, 0x61, 0x3d, 0x83, 0xc4, 0xc1, 0x61, 0xfb, 0xe8, 0xe9, 0xe1,
+	0x5e, 0x3d, 0x4b, 0xaa, 0x50, 0x3a, 0x7a, 0xda, 0x57, 0x54, 0x6e, 0xa6, 0x62, 0x1d, 0x4a, 0x3b,
+	0xa1, 0x27, 0x0b, 0x13, 0x66, 0x1a, 0x59, 0xba, 0x74, 0xf6, 0x51, 0x04, 0x5e, 0xc7, 0xca, 0x5d,
+	0xee, 0x4a, 0x48, 0x4c, 0xbe, 0x84, 0xa2, 0x64, 0x9b, 0xd4, 0x77, 0x67, 0xd9, 0xdb, 0x80, 0xc2,
+	0x26, 0x23, 0xaa, 0x45, 0x9a, 0x7f, 0xcd, 0x42, 0xc9, 0x30, 0x09, 0x85, 0x32, 0x5e, 0x3b, 0x1d,
+	0x2f, 0x60, 0x91, 0xde, 0xe8, 0xed, 0x6b, 0x28, 0x6b, 0xed, 0x1a, 0x21, 0x49, 0x62, 0x33, 0x99,
+	0xa8, 0x69, 0xbe, 0x80, 0xd5, 0xf9, 0x69, 0xd2, 0x80, 0x95, 0x31, 0x8b, 0x63, 0xe7, 0xc4, 0x3c,
+	0x4d, 0x18, 0x12, 0xcf, 0xd5, 0xec, 0xfb, 0xfa, 0xb9, 0x25, 0x61, 0xa0, 0x2f, 0xbc, 0x31, 0x4a,
+	0xa9, 0x57, 0x16, 0x45, 0x60, 0x4a, 0x89, 0x98, 0x13, 0xf3, 0xc0, 0xdc, 0xf1, 0x15, 0x25, 0xdd,
+	0x29, 0x9d, 0xd5, 0x85, 0x92, 0xe9, 0xa5, 0x2f, 0x7f, 0x76, 0x91, 0x17, 0xce, 0x69, 0x68, 0xb2,
+	0xba, 0x1c, 0x27, 0x8f, 0x28, 0xf9, 0xd9, 0x23, 0x8a, 0xfd, 0x1c, 0x6e, 0x2c, 0x5c, 0x1b, 0xc8,
+	0x7d, 0x28, 0x45, 0x6c, 0xae, 0x59, 0x78, 0xeb, 0xc2, 0xcb, 0x06, 0x4d, 0xa0, 0x18, 0x87, 0xb2,
+	0xea, 0x0c, 0x62, 0xa9, 0x89, 0x9b, 0x75, 0xd7, 0x24, 0xb7, 0xa7, 0x99, 0xf6, 0xb7, 0x50, 0x33,
+	0xc2, 0xca, 0x89, 0xaf, 0xf9, 0xb9, 0x24, 0x9e, 0x72, 0xe9, 0x78, 0xfa, 0x5d, 0x0e, 0x08, 0x1e,
+	0xfa, 0xde, 0x64, 0x3c, 0x76, 0xa2, 0xa9, 0xb9, 0xaf, 0xfe, 0x00, 0x4a, 0x89, 0x55, 0xd7, 0xbf,
+	0xb1, 0x26, 0x32, 0x98, 0x61, 0xf0, 0x29, 0x62, 0xf0, 0xd2, 0x0b, 0x5c, 0xfe, 0x52, 0x7f, 0x12,
+	0x90, 0xf5, 0x8d, 0xe4, 0x90, 0xff, 0x07, 0x2b, 0xe0, 0x81, 0x49, 0xbb, 0xb7, 0x16, 0x8f, 0x17,
+	0xbe, 0xd8, 0x61, 0xcd, 0x47, 0x14, 0xf9, 0x0a, 0x2a, 0x82, 0x0f, 0x92, 0x55, 0x5b, 0x57, 0xac,
+	0x1a, 0x9b, 0x6c, 0xc1, 0x0d, 0x45, 0x7e, 0x04, 0x35, 0x7c, 0x0f, 0x98, 0xc9, 0x17, 0xae, 0x96,
+	0xaf, 0xa2, 0x84, 0xa1, 0xdb, 0x00, 0x25, 0x3e, 0x11, 0x43, 0x3e, 0x09, 0x5c, 0xfb, 0xcf, 0x59,
+	0x78, 0x63, 0xce, 0x63, 0xfa, 0x95, 0xee, 0x21, 0xe4, 0xf8, 0xd9, 0x85, 0x39, 0x72, 0x89, 0x44,
+	0xeb, 0xe8, 0x6c, 0x3f, 0x43, 0x73, 0xfc, 0x8c, 0x3c, 0x48, 0x6f, 0xcd, 0xb2, 0x4e, 0x68, 0x2e,
+	0x00, 0xf6, 0x33, 0x7a, 0xf3, 0x9a, 0x3b, 0x90, 0x3b, 0x3a, 0x23, 0x5f, 0x82, 0x7c, 0x2e, 0x1b,
+	0x08, 0x67, 0xe8, 0x27, 0x57, 0xcb, 0xe6, 0x52, 0x0b, 0xfa, 0x08, 0xa1, 0x10, 0x9b, 0x61, 0x8c,
+	0x2b, 0x33, 0x69, 0x4f, 0x5e, 0xea, 0xda, 0x4e, 0xec, 0xc9, 0x36, 0x3a, 0x26, 0x77, 0xa0, 0x16,
+	0x4f, 0x46, 0x23, 0x16, 0x63, 0xa7, 0x3d, 0x09, 0x54, 0x23, 0x63, 0xd1, 0xaa, 0x66, 0xee, 0x22,
+	0x0f, 0x41, 0xc7, 0x8e, 0xe7, 0x4f, 0x22, 0xa6, 0x41, 0xaa, 0xba, 0x57, 0x35, 0x53, 0x81, 0xde,
+	0xc7, 0x48, 0x17, 0x2c, 0x18, 0x4d, 0x07, 0xe3, 0x78, 0x10, 0xde, 0xdf, 0x92, 0xdb, 0x6e, 0xd1,
+	0xaa, 0xe6, 0x3e, 0x89, 0xbb, 0xf7, 0xb7, 0xce, 0xa3, 0x1e, 0xde, 0x6f, 0x58, 0xe7, 0x51, 0x0f,
+	0xef, 0x2f, 0xa0, 0x1e, 0x36, 0x0a, 0x0b, 0xa8, 0x87, 0xe4, 0x1e, 0xdc, 0x10, 0x7e, 0x9c, 0x54,
+	0x1d, 0x65, 0x5a, 0x51, 0x02, 0xd7, 0x84, 0x6f, 0xde, 0x62, 0xa5, 0x75, 0xf6, 0xdf, 0x2d, 0x28,
+	0x27, 0xce, 0x21, 0x6d, 0x28, 0x87, 0xdc, 0x1d, 0x9c, 0x44, 0x7c, 0x62, 0x6e, 0x2c, 0x77, 0x2e,
+	0xf6, 0x25, 0x26, 0xc2, 0x47, 0x08, 0xdd, 0xcf, 0xd0, 0x52, 0xa8, 0xc7, 0xcd, 0x5f, 0x5a, 0x32,
+	0xb3, 0x4a, 0x82, 0x7c, 0x09, 0x56, 0xc4, 0x5f, 0x9a, 0x7d, 0xf9, 0xf0, 0x1a, 0xba, 0x5a, 0x94,
+	0xbf, 0xa4, 0x52, 0xa8, 0xf9, 0xc7, 0x3c, 0xe4, 0x29, 0x7f, 0xf9, 0xba, 0x67, 0xfe, 0xca, 0x63,
+	0xb8, 0x01, 0xf5, 0x31, 0x8b, 0x4f, 0x99, 0x3b, 0xc0, 0x45, 0x2b, 0x37, 0xa9, 0xbd, 0x59, 0x55,
+	0xfc, 0x2e, 0x77, 0xd5, 0x1e, 0xde, 0x83, 0x1b, 0xd1, 0x24, 0x08, 0xbc, 0xe0, 0x24, 0x05, 0x55,
+	0x1b, 0xb4, 0xa6, 0x27, 0x12, 0xec, 0x06, 0xd4, 0x71, 0xff, 0xe7, 0xb4, 0x2a, 0xe7, 0xaf, 0x2a,
+	0x7e, 0x82, 0xfc, 0x18, 0x0a, 0x18, 0x8c, 0xa6, 0xcc, 0x2e, 0xf6, 0x6c, 0xb3, 0x78, 0xa4, 0x0a,
+	0x49, 0xbe, 0x85, 0x9a, 0x2a, 0x60, 0x83, 0xe1, 0x14, 0xf5, 0x37, 0x56, 0xa4, 0x63, 0x3f, 0xbf,
+	0xa6, 0x63, 0x5b, 0xaa, 0x82, 0xb5, 0xa7, 0x58, 0xc2, 0x64, 0xef, 0x5f, 0x61, 0x33, 0x4e, 0xf3,
+	0x19, 0xd4, 0xcf, 0x03, 0x96, 0xdc, 0x02, 0xb6, 0xd2, 0xb7, 0x80, 0x65, 0x87, 0x2d, 0xa9, 0x94,
+	0xa9, 0x1b, 0x02, 0xd6, 0x25, 0x79, 0x46, 0xb7, 0xff, 0x61, 0x41, 0x7e, 0x27, 0xf4, 0xc8, 0x33,
+	0xa8, 0xa4, 0xf2, 0x02, 0xb9, 0x73, 0x79, 0xd6, 0x90, 0x21, 0xdb, 0x7c, 0xff, 0x3a, 0xa9, 0xc5,
+	0xce, 0x90, 0xaf, 0xa1, 0x64, 0xfe, 0x48, 0x20, 0xeb, 0x0b, 0x32, 0xe7, 0xfe, 0x94, 0x68, 0xbe,
+	0x77, 0x09, 0x22, 0x51, 0xb9, 0x07, 0xf9, 0xbe, 0x13, 0x92, 0xb7, 0x97, 0x35, 0x80, 0x46, 0xd1,
+	0x5b, 0x17, 0x76, 0x87, 0x76, 0xfe, 0x17, 0xb9, 0xec, 0x56, 0x96, 0x3c, 0x85, 0xda, 0xdc, 0x2b,
+	0x17, 0xf9, 0xe0, 0x5a, 0xaf, 0x60, 0x97, 0x69, 0xce, 0x6c, 0x65, 0xc9, 0x0e, 0xac, 0x98, 0xbf,
+	0x6e, 0x2e, 0xa8, 0x26, 0xcd, 0x77, 0x16, 0xf8, 0xa9, 0xbf, 0x83, 0xec, 0x0c, 0xf1, 0xa1, 0xdc,
+	0x63, 0xfe, 0xf1, 0x2e, 0xfe, 0x77, 0x44, 0xbe, 0x37, 0x03, 0xab, 0x7f, 0x96, 0x5a, 0xe9, 0x7f,
+	0x96, 0x12, 0x9c, 0xb1, 0xae, 0x75, 0x5d, 0x78, 0xe2, 0xcd, 0x00, 0xd6, 0x12, 0xb6, 0xea, 0x99,
+	0x5f, 0xf5, 0x9b, 0x1b, 0x97, 0xc2, 0xcd, 0xf7, 0x26, 0xbe, 0x74, 0x50, 0xfb, 0x93, 0x67, 0x1f,
+	0x9f, 0x78, 0xe2, 0x74, 0x32, 0x44, 0xf0, 0xa6, 0x96, 0x34, 0xbf, 0xdb, 0x9b, 0xb3, 0xff, 0x27,
+	0x36, 0x4f, 0x58, 0xb0, 0xa9, 0x1c, 0x34, 0x2c, 0xca, 0x8e, 0xfa, 0x93, 0xff, 0x0c, 0x00, 0x67,
+	0x47, 0x15, 0x2b, 0x9d, 0x1b, 0x00, 0x00,
 }
@@ -5,6 +5,8 @@ apiVersion: admissionregistration.k8s.io/v1beta1
 kind: MutatingWebhookConfiguration
 metadata:
   name: {{ .WebhookConfigName }}
+  labels:
+    {{ .ControllerNSLabel }}: {{ .ControllerNamespace }}
 webhooks:
 - name: {{ .WebhookServiceName }}
   clientConfig:
@@ -29,6 +29,7 @@ const (
 // requests by injecting sidecar container spec into the pod spec during pod
 // creation.
 type Webhook struct {
+	client              kubernetes.Interface
 	deserializer        runtime.Decoder
 	controllerNamespace string
 	resources           *WebhookResources
@@ -42,6 +43,7 @@ func NewWebhook(client kubernetes.Interface, resources *WebhookResources, contro
 	)
 
 	return &Webhook{
+		client:              client,
 		deserializer:        codecs.UniversalDeserializer(),
 		controllerNamespace: controllerNamespace,
 		resources:           resources,
@@ -108,7 +110,7 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	}
 	log.Infof("resource namespace: %s", ns)
 
-	if w.ignore(&deployment) {
+	if w.ignore(&deployment, w.namespaceLabels(ns)) {
 		log.Infof("ignoring deployment %s", deployment.ObjectMeta.Name)
 		return &admissionv1beta1.AdmissionResponse{
 			UID:     request.UID,
@@ -202,14 +204,21 @@ func (w *Webhook) inject(request *admissionv1beta1.AdmissionRequest) (*admission
 	return admissionResponse, nil
 }
 
-func (w *Webhook) ignore(deployment *appsv1.Deployment) bool {
-	labels := deployment.Spec.Template.ObjectMeta.GetLabels()
-	status, defined := labels[k8sPkg.ProxyAutoInjectLabel]
-	if defined {
-		switch status {
-		case k8sPkg.ProxyAutoInjectDisabled, k8sPkg.ProxyAutoInjectCompleted:
-			return true
-		}
+// namespaceLabels returns the labels of the given namespace. Namespaces
+// labeled as disabled are already excluded by the webhook's
+// namespaceSelector, so nil is returned if the namespace can't be read.
+func (w *Webhook) namespaceLabels(name string) map[string]string {
+	namespace, err := w.client.CoreV1().Namespaces().Get(name, metav1.GetOptions{})
+	if err != nil {
+		log.Warnf("failed to read namespace %s: %s", name, err)
+		return nil
+	}
+	return namespace.GetLabels()
+}
+
+func (w *Webhook) ignore(deployment *appsv1.Deployment, namespaceLabels map[string]string) bool {
+	if !k8sPkg.AutoInjected(namespaceLabels, deployment.Spec.Template.ObjectMeta.GetLabels()) {
+		return true
 	}
 
 	return healthcheck.HasExistingSidecars(&deployment.Spec.Template.Spec)
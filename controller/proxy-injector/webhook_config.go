@@ -83,12 +83,14 @@ func (w *WebhookConfig) create() (*arv1beta1.MutatingWebhookConfiguration, error
 			ControllerNamespace  string
 			CABundle             string
 			ProxyAutoInjectLabel string
+			ControllerNSLabel    string
 		}{
 			WebhookConfigName:    k8sPkg.ProxyInjectorWebhookConfig,
 			WebhookServiceName:   w.webhookServiceName,
 			ControllerNamespace:  w.controllerNamespace,
 			CABundle:             base64.StdEncoding.EncodeToString(w.trustAnchor),
 			ProxyAutoInjectLabel: k8sPkg.ProxyAutoInjectLabel,
+			ControllerNSLabel:    k8sPkg.ControllerNSLabel,
 		}
 	)
 	if err := w.configTemplate.Execute(buf, spec); err != nil {
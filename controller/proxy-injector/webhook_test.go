@@ -80,7 +80,7 @@ func TestIgnore(t *testing.T) {
 					t.Fatal("Unexpected error: ", err)
 				}
 
-				if actual := webhook.ignore(deployment); actual != testCase.expected {
+				if actual := webhook.ignore(deployment, nil); actual != testCase.expected {
 					t.Errorf("Boolean mismatch. Expected: %t. Actual: %t", testCase.expected, actual)
 				}
 			})
@@ -93,10 +93,24 @@ func TestIgnore(t *testing.T) {
 			t.Fatal("Unexpected error: ", err)
 		}
 
-		if !webhook.ignore(deployment) {
+		if !webhook.ignore(deployment, nil) {
 			t.Errorf("Expected deployment with injected proxy to be ignored")
 		}
 	})
+
+	t.Run("by checking namespace labels", func(t *testing.T) {
+		deployment, err := factory.Deployment("deployment-inject-status-empty.yaml")
+		if err != nil {
+			t.Fatal("Unexpected error: ", err)
+		}
+
+		if !webhook.ignore(deployment, map[string]string{k8s.ProxyAutoInjectLabel: k8s.ProxyAutoInjectDisabled}) {
+			t.Errorf("Expected deployment in a namespace with auto-inject disabled to be ignored")
+		}
+		if webhook.ignore(deployment, map[string]string{k8s.ProxyAutoInjectLabel: k8s.ProxyAutoInjectEnabled}) {
+			t.Errorf("Expected deployment in a namespace with auto-inject enabled not to be ignored")
+		}
+	})
 }
 
 func TestContainersSpec(t *testing.T) {
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// LinkerdConfigMapName is the ConfigMap holding the control plane's
+	// configuration, as JSON documents under the GlobalKey, ProxyKey and
+	// InstallKey keys.
+	LinkerdConfigMapName = "linkerd-config"
+
+	// the keys of the linkerd-config ConfigMap
+	GlobalKey  = "global"
+	ProxyKey   = "proxy"
+	InstallKey = "install"
+)
+
+// LinkerdConfig is the decoded content of the linkerd-config ConfigMap. Keys
+// missing from the ConfigMap are left to their zero value.
+type LinkerdConfig struct {
+	Global  Global
+	Proxy   Proxy
+	Install Install
+}
+
+// Global holds the configuration shared by the control plane components.
+type Global struct {
+	LinkerdNamespace string           `json:"linkerdNamespace"`
+	CNIEnabled       bool             `json:"cniEnabled"`
+	Version          string           `json:"version"`
+	ClusterDomain    string           `json:"clusterDomain"`
+	IdentityContext  *IdentityContext `json:"identityContext"`
+}
+
+// IdentityContext holds the configuration of the identity service, if
+// identity is enabled.
+type IdentityContext struct {
+	TrustDomain        string `json:"trustDomain"`
+	TrustAnchorsPEM    string `json:"trustAnchorsPem"`
+	IssuanceLifetime   string `json:"issuanceLifetime"`
+	ClockSkewAllowance string `json:"clockSkewAllowance"`
+}
+
+// Proxy holds the configuration applied to proxies at injection time. Ports
+// left to 0 are not overridden.
+type Proxy struct {
+	Image        string `json:"image"`
+	Version      string `json:"version"`
+	InboundPort  int    `json:"inboundPort"`
+	OutboundPort int    `json:"outboundPort"`
+	ControlPort  int    `json:"controlPort"`
+	MetricsPort  int    `json:"metricsPort"`
+}
+
+// Install holds the options the control plane was installed with.
+type Install struct {
+	HighAvailability bool `json:"ha"`
+}
+
+// FieldError is returned when a key of the linkerd-config ConfigMap is
+// malformed. Field is empty if the key isn't a valid JSON document.
+type FieldError struct {
+	Key    string
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("The \"%s\" key of the \"%s\" ConfigMap is malformed: %s", e.Key, LinkerdConfigMapName, e.Reason)
+	}
+	return fmt.Sprintf("The \"%s\" field of the \"%s\" key of the \"%s\" ConfigMap is malformed: %s", e.Field, e.Key, LinkerdConfigMapName, e.Reason)
+}
+
+// ParseLinkerdConfig decodes and validates the data of the linkerd-config
+// ConfigMap, returning a FieldError naming the first malformed key and field.
+func ParseLinkerdConfig(data map[string]string) (*LinkerdConfig, error) {
+	config := &LinkerdConfig{}
+
+	if err := decode(data, GlobalKey, &config.Global); err != nil {
+		return nil, err
+	}
+	if err := decode(data, ProxyKey, &config.Proxy); err != nil {
+		return nil, err
+	}
+	if err := decode(data, InstallKey, &config.Install); err != nil {
+		return nil, err
+	}
+
+	if err := validateGlobal(config.Global); err != nil {
+		return nil, err
+	}
+	if err := validateProxy(config.Proxy); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func decode(data map[string]string, key string, v interface{}) error {
+	document := strings.TrimSpace(data[key])
+	if document == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(document), v); err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok && typeErr.Field != "" {
+			return &FieldError{Key: key, Field: typeErr.Field, Reason: fmt.Sprintf("expected a %s, got a JSON %s", typeErr.Type, typeErr.Value)}
+		}
+		return &FieldError{Key: key, Reason: err.Error()}
+	}
+	return nil
+}
+
+func validateGlobal(global Global) error {
+	if global.ClusterDomain != "" {
+		if errs := validation.IsDNS1123Subdomain(global.ClusterDomain); len(errs) > 0 {
+			return &FieldError{Key: GlobalKey, Field: "clusterDomain", Reason: strings.Join(errs, "; ")}
+		}
+	}
+
+	identity := global.IdentityContext
+	if identity == nil {
+		return nil
+	}
+
+	if identity.TrustDomain == "" {
+		return &FieldError{Key: GlobalKey, Field: "identityContext.trustDomain", Reason: "is required"}
+	}
+	if errs := validation.IsDNS1123Subdomain(identity.TrustDomain); len(errs) > 0 {
+		return &FieldError{Key: GlobalKey, Field: "identityContext.trustDomain", Reason: strings.Join(errs, "; ")}
+	}
+	if identity.TrustAnchorsPEM != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(identity.TrustAnchorsPEM)) {
+		return &FieldError{Key: GlobalKey, Field: "identityContext.trustAnchorsPem", Reason: "does not contain any PEM-encoded certificate"}
+	}
+
+	durations := []struct {
+		field string
+		value string
+	}{
+		{"identityContext.issuanceLifetime", identity.IssuanceLifetime},
+		{"identityContext.clockSkewAllowance", identity.ClockSkewAllowance},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d.value); err != nil {
+			return &FieldError{Key: GlobalKey, Field: d.field, Reason: fmt.Sprintf("must be a duration such as \"86400s\", got \"%s\"", d.value)}
+		}
+	}
+
+	return nil
+}
+
+func validateProxy(proxy Proxy) error {
+	ports := []struct {
+		field string
+		port  int
+	}{
+		{"inboundPort", proxy.InboundPort},
+		{"outboundPort", proxy.OutboundPort},
+		{"controlPort", proxy.ControlPort},
+		{"metricsPort", proxy.MetricsPort},
+	}
+	for _, p := range ports {
+		if p.port == 0 {
+			continue
+		}
+		if errs := validation.IsValidPortNum(p.port); len(errs) > 0 {
+			return &FieldError{Key: ProxyKey, Field: p.field, Reason: strings.Join(errs, "; ")}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestParseLinkerdConfig(t *testing.T) {
+	t.Run("Decodes the global, proxy and install keys", func(t *testing.T) {
+		config, err := ParseLinkerdConfig(map[string]string{
+			GlobalKey: `{"linkerdNamespace":"linkerd","cniEnabled":true,"clusterDomain":"cluster.local",
+				"identityContext":{"trustDomain":"cluster.local","issuanceLifetime":"86400s","clockSkewAllowance":"20s"}}`,
+			ProxyKey:   `{"image":"gcr.io/linkerd-io/proxy","inboundPort":5143}`,
+			InstallKey: `{"ha":true}`,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !config.Global.CNIEnabled || config.Global.ClusterDomain != "cluster.local" ||
+			config.Global.IdentityContext == nil || config.Global.IdentityContext.TrustDomain != "cluster.local" {
+			t.Fatalf("Unexpected global configuration: %+v", config.Global)
+		}
+		if config.Proxy.Image != "gcr.io/linkerd-io/proxy" || config.Proxy.InboundPort != 5143 || config.Proxy.OutboundPort != 0 {
+			t.Fatalf("Unexpected proxy configuration: %+v", config.Proxy)
+		}
+		if !config.Install.HighAvailability {
+			t.Fatalf("Unexpected install configuration: %+v", config.Install)
+		}
+	})
+
+	t.Run("Leaves missing keys to their zero value", func(t *testing.T) {
+		config, err := ParseLinkerdConfig(map[string]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if config.Global.IdentityContext != nil || config.Proxy != (Proxy{}) || config.Install.HighAvailability {
+			t.Fatalf("Unexpected configuration: %+v", config)
+		}
+	})
+
+	t.Run("Reports the malformed key and field", func(t *testing.T) {
+		testCases := []struct {
+			data     map[string]string
+			expected string
+		}{
+			{
+				map[string]string{ProxyKey: `{`},
+				"The \"proxy\" key of the \"linkerd-config\" ConfigMap is malformed: unexpected end of JSON input",
+			},
+			{
+				map[string]string{InstallKey: `{"ha":"yes"}`},
+				"The \"ha\" field of the \"install\" key of the \"linkerd-config\" ConfigMap is malformed: expected a bool, got a JSON string",
+			},
+			{
+				map[string]string{ProxyKey: `{"metricsPort":70000}`},
+				"The \"metricsPort\" field of the \"proxy\" key of the \"linkerd-config\" ConfigMap is malformed: must be between 1 and 65535, inclusive",
+			},
+			{
+				map[string]string{GlobalKey: `{"identityContext":{}}`},
+				"The \"identityContext.trustDomain\" field of the \"global\" key of the \"linkerd-config\" ConfigMap is malformed: is required",
+			},
+			{
+				map[string]string{GlobalKey: `{"identityContext":{"trustDomain":"cluster.local","issuanceLifetime":"one day"}}`},
+				"The \"identityContext.issuanceLifetime\" field of the \"global\" key of the \"linkerd-config\" ConfigMap is malformed: must be a duration such as \"86400s\", got \"one day\"",
+			},
+			{
+				map[string]string{GlobalKey: `{"identityContext":{"trustDomain":"cluster.local","trustAnchorsPem":"not a certificate"}}`},
+				"The \"identityContext.trustAnchorsPem\" field of the \"global\" key of the \"linkerd-config\" ConfigMap is malformed: does not contain any PEM-encoded certificate",
+			},
+		}
+
+		for _, tc := range testCases {
+			_, err := ParseLinkerdConfig(tc.data)
+			if err == nil {
+				t.Fatalf("Expected error for %v, got nothing", tc.data)
+			}
+			if _, ok := err.(*FieldError); !ok {
+				t.Fatalf("Expected a FieldError, got %T", err)
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("Unexpected error message: %s", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The classifications of the failed calls to the public API.
+const (
+	APIUnreachable  = "unreachable"
+	APITimeout      = "timeout"
+	APIUnauthorized = "unauthorized"
+	APIVersionSkew  = "version-skew"
+)
+
+// APIError is the error of a failed call to the public API, translated into a
+// message users can act on, and classified. Err is the call's original error,
+// for verbose output.
+type APIError struct {
+	Classification string
+	Message        string
+
+	// Retryable is set if the call may succeed once retried, e.g. once the
+	// controller is ready.
+	Retryable bool
+
+	Err error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Message, errorDetail(e.Err))
+}
+
+// Unwrap returns the call's original error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the gRPC status code of the call's original error.
+func (e *APIError) Code() codes.Code {
+	return public.Code(e.Err)
+}
+
+// Status returns the gRPC status of the call's original error, if it is a
+// status error.
+func (e *APIError) Status() (*status.Status, bool) {
+	return status.FromError(e.Err)
+}
+
+// apiErrors are the translations of the status codes of failed calls.
+var apiErrors = map[codes.Code]APIError{
+	codes.Unavailable: {
+		Classification: APIUnreachable,
+		Message:        "control plane API is not reachable",
+		Retryable:      true,
+	},
+	codes.DeadlineExceeded: {
+		Classification: APITimeout,
+		Message:        "control plane API timed out",
+		Retryable:      true,
+	},
+	codes.PermissionDenied: {
+		Classification: APIUnauthorized,
+		Message:        "not permitted to call the control plane API; check the RBAC permissions of your Kubernetes user, and those of the controller",
+	},
+	codes.Unauthenticated: {
+		Classification: APIUnauthorized,
+		Message:        "the control plane API rejected the credentials; check that your kubeconfig's credentials are current",
+	},
+	codes.Unimplemented: {
+		Classification: APIVersionSkew,
+		Message:        "the control plane API does not serve this call; the CLI and the control plane may be running different versions, compare them with `linkerd version`",
+	},
+}
+
+// apiCallError translates the error of a call to the public API into an
+// APIError if its status code is a common one, and returns it as is otherwise,
+// or if the call failed since the given context, the caller's, is done. The
+// errors of calls short-circuited by the circuit are already explicit.
+func apiCallError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	switch err.(type) {
+	case *APIError, *circuitOpenError:
+		return err
+	}
+
+	translation, ok := apiErrors[public.Code(err)]
+	if !ok {
+		return err
+	}
+	translation.Err = err
+	return &translation
+}
+
+// errorDetail returns the message of a status error, without its code, or the
+// error's own message.
+func errorDetail(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Message()
+	}
+	return err.Error()
+}
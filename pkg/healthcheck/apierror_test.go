@@ -0,0 +1,105 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPICallError(t *testing.T) {
+	testCases := []struct {
+		err            error
+		classification string
+		retryable      bool
+		message        string
+	}{
+		{
+			status.Error(codes.Unavailable, "connection refused"),
+			APIUnreachable,
+			true,
+			"control plane API is not reachable: connection refused",
+		},
+		{
+			&public.ConnectionError{Layer: public.TCPLayer, Err: errors.New("dial tcp 127.0.0.1:8085: connect: connection refused")},
+			APIUnreachable,
+			true,
+			"control plane API is not reachable: TCP connection to the public API failed: dial tcp 127.0.0.1:8085: connect: connection refused",
+		},
+		{
+			status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+			APITimeout,
+			true,
+			"control plane API timed out: context deadline exceeded",
+		},
+		{
+			status.Error(codes.PermissionDenied, "denied"),
+			APIUnauthorized,
+			false,
+			"not permitted to call the control plane API; check the RBAC permissions of your Kubernetes user, and those of the controller: denied",
+		},
+		{
+			&public.UnexpectedResponseError{StatusCode: http.StatusForbidden, Status: "403 Forbidden"},
+			APIUnauthorized,
+			false,
+			"not permitted to call the control plane API; check the RBAC permissions of your Kubernetes user, and those of the controller: Unexpected API response: 403 Forbidden",
+		},
+		{
+			status.Error(codes.Unauthenticated, "token expired"),
+			APIUnauthorized,
+			false,
+			"the control plane API rejected the credentials; check that your kubeconfig's credentials are current: token expired",
+		},
+		{
+			status.Error(codes.Unimplemented, "unknown method SelfCheckStream"),
+			APIVersionSkew,
+			false,
+			"the control plane API does not serve this call; the CLI and the control plane may be running different versions, compare them with `linkerd version`: unknown method SelfCheckStream",
+		},
+	}
+
+	for _, tc := range testCases {
+		err := apiCallError(context.Background(), tc.err)
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			t.Fatalf("Expected [%v] to be translated, got [%v]", tc.err, err)
+		}
+		if apiErr.Classification != tc.classification || apiErr.Retryable != tc.retryable || apiErr.Error() != tc.message {
+			t.Fatalf("Expected [%v] to be translated to [%s] (%s, retryable=%t), got [%s] (%s, retryable=%t)",
+				tc.err, tc.message, tc.classification, tc.retryable, apiErr, apiErr.Classification, apiErr.Retryable)
+		}
+		if apiErr.Err != tc.err || apiErr.Unwrap() != tc.err {
+			t.Fatalf("Expected the original error [%v] to be preserved, got [%v]", tc.err, apiErr.Err)
+		}
+		if s, ok := status.FromError(tc.err); ok {
+			if got, ok := apiErr.Status(); !ok || got.Code() != s.Code() {
+				t.Fatalf("Expected the status of [%v] to be accessible, got %v", tc.err, got)
+			}
+		}
+	}
+
+	t.Run("Leaves other errors as they are", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		testCases := []struct {
+			ctx context.Context
+			err error
+		}{
+			{context.Background(), errors.New("Error calling Prometheus")},
+			{context.Background(), status.Error(codes.Internal, "internal")},
+			{context.Background(), &circuitOpenError{err: status.Error(codes.Unavailable, "connection refused")}},
+			{canceled, context.Canceled},
+			{canceled, status.Error(codes.Unavailable, "connection refused")},
+		}
+		for _, tc := range testCases {
+			if err := apiCallError(tc.ctx, tc.err); err != tc.err {
+				t.Fatalf("Expected [%v] to be left as is, got [%v]", tc.err, err)
+			}
+		}
+	})
+}
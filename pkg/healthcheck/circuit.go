@@ -0,0 +1,230 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// apiCircuit counts the consecutive transport-level failures of the calls made
+// to the public API, and opens once the budget of failures is exhausted within
+// the window, so that the calls made while it is open fail without being made.
+// It half-opens on the next iteration of the checks, when a failed check is
+// retried, letting a single call through to find out whether the API is
+// reachable again.
+type apiCircuit struct {
+	budget        int
+	window        time.Duration
+	retryDeadline time.Time
+
+	mu           sync.Mutex
+	state        circuitState
+	failures     int
+	firstFailure time.Time
+	lastErr      error
+	retryAt      time.Time
+	probing      bool
+}
+
+func newAPICircuit(budget int, window time.Duration, retryDeadline time.Time) *apiCircuit {
+	return &apiCircuit{budget: budget, window: window, retryDeadline: retryDeadline}
+}
+
+// circuitOpenError is returned by the calls made while the circuit is open.
+type circuitOpenError struct {
+	retryAt time.Time
+	err     error
+}
+
+func (e *circuitOpenError) Error() string {
+	if e.retryAt.IsZero() {
+		return fmt.Sprintf("controller unreachable (circuit open): %s", e.err)
+	}
+	return fmt.Sprintf("controller unreachable (circuit open, retrying at %s): %s", e.retryAt.Format("15:04:05"), e.err)
+}
+
+// allow returns a circuitOpenError if the call about to be made must not be,
+// since the circuit is open, or it is half-open and already probing the API.
+func (c *apiCircuit) allow() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitOpen:
+		return &circuitOpenError{retryAt: c.retryAt, err: c.lastErr}
+	case circuitHalfOpen:
+		if c.probing {
+			return &circuitOpenError{retryAt: c.retryAt, err: c.lastErr}
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// done accounts for the outcome of a call allowed by the circuit. Only the
+// failures to reach the public API count towards the budget; any response
+// from it, even an error, closes the circuit.
+func (c *apiCircuit) done(ctx context.Context, err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == circuitHalfOpen {
+		c.probing = false
+	}
+	if err != nil && ctx.Err() != nil {
+		// the call was canceled, which tells nothing of the API
+		return
+	}
+	if public.Code(err) != codes.Unavailable {
+		c.state = circuitClosed
+		c.failures = 0
+		return
+	}
+
+	now := time.Now()
+	c.lastErr = err
+	if c.state == circuitHalfOpen {
+		c.open(now)
+		return
+	}
+	if c.failures == 0 || now.Sub(c.firstFailure) > c.window {
+		c.failures = 0
+		c.firstFailure = now
+	}
+	c.failures++
+	if c.failures >= c.budget {
+		c.open(now)
+	}
+}
+
+// open opens the circuit, until the checks are retried if the run waits for
+// them to succeed.
+func (c *apiCircuit) open(now time.Time) {
+	c.state = circuitOpen
+	c.retryAt = time.Time{}
+	if next := now.Add(retryWindow); next.Before(c.retryDeadline) {
+		c.retryAt = next
+	}
+}
+
+// halfOpen half-opens the circuit if it is open, on the next iteration of the
+// checks.
+func (c *apiCircuit) halfOpen() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == circuitOpen {
+		c.state = circuitHalfOpen
+		c.probing = false
+	}
+}
+
+// reset closes the circuit and forgets the failures counted, so that an
+// independent run starts afresh.
+func (c *apiCircuit) reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.state = circuitClosed
+	c.failures = 0
+	c.lastErr = nil
+	c.retryAt = time.Time{}
+	c.probing = false
+}
+
+// circuitBreakingClient makes its calls with the given client, unless the
+// circuit is open.
+type circuitBreakingClient struct {
+	client  pb.ApiClient
+	circuit *apiCircuit
+}
+
+func (c *circuitBreakingClient) StatSummary(ctx context.Context, req *pb.StatSummaryRequest, opts ...grpc.CallOption) (*pb.StatSummaryResponse, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.StatSummary(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return rsp, err
+}
+
+func (c *circuitBreakingClient) ListPods(ctx context.Context, req *pb.ListPodsRequest, opts ...grpc.CallOption) (*pb.ListPodsResponse, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.ListPods(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return rsp, err
+}
+
+func (c *circuitBreakingClient) Version(ctx context.Context, req *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.Version(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return rsp, err
+}
+
+func (c *circuitBreakingClient) SelfCheck(ctx context.Context, req *healthcheckPb.SelfCheckRequest, opts ...grpc.CallOption) (*healthcheckPb.SelfCheckResponse, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	rsp, err := c.client.SelfCheck(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return rsp, err
+}
+
+func (c *circuitBreakingClient) Tap(ctx context.Context, req *pb.TapRequest, opts ...grpc.CallOption) (pb.Api_TapClient, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	stream, err := c.client.Tap(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return stream, err
+}
+
+func (c *circuitBreakingClient) TapByResource(ctx context.Context, req *pb.TapByResourceRequest, opts ...grpc.CallOption) (pb.Api_TapByResourceClient, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	stream, err := c.client.TapByResource(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return stream, err
+}
+
+func (c *circuitBreakingClient) SelfCheckStream(ctx context.Context, req *healthcheckPb.SelfCheckRequest, opts ...grpc.CallOption) (pb.Api_SelfCheckStreamClient, error) {
+	if err := c.circuit.allow(); err != nil {
+		return nil, err
+	}
+	stream, err := c.client.SelfCheckStream(ctx, req, opts...)
+	c.circuit.done(ctx, err)
+	return stream, err
+}
@@ -0,0 +1,167 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// versionApiClient returns the given errors from its successive Version
+// calls, and then succeeds.
+type versionApiClient struct {
+	*public.MockApiClient
+	errs  []error
+	calls int
+}
+
+func (c *versionApiClient) Version(ctx context.Context, in *pb.Empty, opts ...grpc.CallOption) (*pb.VersionInfo, error) {
+	c.calls++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	return &pb.VersionInfo{ReleaseVersion: "stable-2.1.0"}, nil
+}
+
+func TestAPICircuit(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+
+	callVersion := func(client pb.ApiClient, n int) error {
+		var err error
+		for i := 0; i < n; i++ {
+			_, err = client.Version(context.Background(), &pb.Empty{})
+		}
+		return err
+	}
+
+	t.Run("Opens once the budget of failures is exhausted", func(t *testing.T) {
+		api := &versionApiClient{MockApiClient: &public.MockApiClient{}, errs: []error{unavailable, unavailable, unavailable}}
+		client := &circuitBreakingClient{client: api, circuit: newAPICircuit(2, time.Minute, time.Time{})}
+
+		err := callVersion(client, 3)
+		if api.calls != 2 {
+			t.Fatalf("Expected 2 calls to be made, got %d", api.calls)
+		}
+		expected := "controller unreachable (circuit open): rpc error: code = Unavailable desc = connection refused"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	})
+
+	t.Run("Only counts the failures within the window", func(t *testing.T) {
+		api := &versionApiClient{MockApiClient: &public.MockApiClient{}, errs: []error{unavailable, unavailable, unavailable}}
+		client := &circuitBreakingClient{client: api, circuit: newAPICircuit(2, time.Nanosecond, time.Time{})}
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(time.Millisecond)
+			callVersion(client, 1)
+		}
+		if api.calls != 3 {
+			t.Fatalf("Expected 3 calls to be made, got %d", api.calls)
+		}
+	})
+
+	t.Run("Does not count the errors returned by the public API", func(t *testing.T) {
+		denied := status.Error(codes.PermissionDenied, "denied")
+		api := &versionApiClient{MockApiClient: &public.MockApiClient{}, errs: []error{unavailable, denied, unavailable, denied}}
+		client := &circuitBreakingClient{client: api, circuit: newAPICircuit(2, time.Minute, time.Time{})}
+
+		callVersion(client, 4)
+		if api.calls != 4 {
+			t.Fatalf("Expected 4 calls to be made, got %d", api.calls)
+		}
+	})
+
+	t.Run("Lets a single call probe the public API once half-open", func(t *testing.T) {
+		api := &versionApiClient{MockApiClient: &public.MockApiClient{}, errs: []error{unavailable, unavailable}}
+		circuit := newAPICircuit(1, time.Minute, time.Time{})
+		client := &circuitBreakingClient{client: api, circuit: circuit}
+
+		callVersion(client, 2)
+		circuit.halfOpen()
+		if err := callVersion(client, 2); err == nil {
+			t.Fatal("Expected the circuit to open again after the failed probe")
+		}
+		if api.calls != 2 {
+			t.Fatalf("Expected 2 calls to be made, got %d", api.calls)
+		}
+
+		circuit.halfOpen()
+		if err := callVersion(client, 2); err != nil {
+			t.Fatalf("Expected the circuit to close after the successful probe, got %s", err)
+		}
+		if api.calls != 4 {
+			t.Fatalf("Expected 4 calls to be made, got %d", api.calls)
+		}
+	})
+
+	t.Run("Short-circuits the checks until they are retried, and resets between runs", func(t *testing.T) {
+		defer func(window time.Duration) { retryWindow = window }(retryWindow)
+		retryWindow = 0
+
+		retryDeadline := time.Now().Add(100 * time.Second)
+		api := &versionApiClient{MockApiClient: &public.MockApiClient{}, errs: []error{unavailable}}
+		circuit := newAPICircuit(1, time.Minute, retryDeadline)
+		client := &circuitBreakingClient{client: api, circuit: circuit}
+
+		versionCheck := func(category string, retryDeadline time.Time) *checker {
+			return &checker{
+				category:      category,
+				description:   "can query the version",
+				retryDeadline: retryDeadline,
+				check: func(ctx context.Context) error {
+					_, err := client.Version(ctx, &pb.Empty{})
+					return err
+				},
+			}
+		}
+		hc := HealthChecker{
+			HealthCheckOptions: &HealthCheckOptions{RetryDeadline: retryDeadline},
+			apiCircuit:         circuit,
+			checkers: []*checker{
+				versionCheck("cat1", time.Time{}),
+				versionCheck("cat2", time.Time{}),
+				versionCheck("cat3", retryDeadline),
+			},
+		}
+
+		var observedResults []string
+		hc.RunChecks(func(result *CheckResult) {
+			res := fmt.Sprintf("%s retry=%t", result.Category, result.Retry)
+			if result.Err != nil {
+				res += ": " + strings.SplitN(result.Err.Error(), " (circuit open, retrying at ", 2)[0]
+			}
+			observedResults = append(observedResults, res)
+		})
+
+		expectedResults := []string{
+			"cat1 retry=false: rpc error: code = Unavailable desc = connection refused",
+			"cat2 retry=false: controller unreachable",
+			"cat3 retry=true: controller unreachable",
+			"cat3 retry=false",
+		}
+		if !reflect.DeepEqual(observedResults, expectedResults) {
+			t.Fatalf("Expected results %v, but got %v", expectedResults, observedResults)
+		}
+		if api.calls != 2 {
+			t.Fatalf("Expected 2 calls to be made, got %d", api.calls)
+		}
+
+		api.errs = []error{unavailable}
+		callVersion(client, 1)
+		hc.checkers = []*checker{versionCheck("cat4", time.Time{})}
+		if !hc.RunChecks(func(*CheckResult) {}) || api.calls != 4 {
+			t.Fatalf("Expected the circuit to be closed by the next run, got %d calls", api.calls)
+		}
+	})
+}
@@ -0,0 +1,376 @@
+package healthcheck
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+)
+
+const (
+	cniDaemonSetName    = "linkerd-cni"
+	cniContainerName    = "install-cni"
+	defaultCNINamespace = "linkerd-cni"
+
+	// cniLogLines and cniLogBytes bound the logs fetched from each CNI pod
+	cniLogLines = 100
+	cniLogBytes = 64 * 1024
+
+	// proxies that export the traffic redirection mode do so as a gauge whose
+	// "mode" label is either "init" or "cni"; older proxies don't export it
+	iptablesModeMetricName = "proxy_iptables_mode"
+)
+
+func (hc *HealthChecker) addLinkerdCNIPluginChecks() {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdCNIPluginCategory,
+		description: "linkerd-cni DaemonSet exists",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			_, err := hc.getCNIDaemonSet(ctx)
+			return err
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdCNIPluginCategory,
+		description: "linkerd-cni DaemonSet is ready",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			ds, err := hc.getCNIDaemonSet(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateCNIDaemonSetReady(ds)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdCNIPluginCategory,
+		description: "linkerd-cni pods are running on all schedulable nodes",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			ds, err := hc.getCNIDaemonSet(ctx)
+			if err != nil {
+				return err
+			}
+
+			nodes, err := hc.kubeAPI.GetNodes(ctx)
+			if err != nil {
+				return err
+			}
+
+			pods, err := hc.kubeAPI.GetPodsByNamespace(ctx, ds.Namespace, "")
+			if err != nil {
+				return err
+			}
+
+			return validateCNINodeCoverage(ds, pods, nodes)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdCNIPluginCategory,
+		description: "linkerd-cni plugin config is installed on all nodes",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			ds, err := hc.getCNIDaemonSet(ctx)
+			if err != nil {
+				return err
+			}
+
+			pods, err := hc.kubeAPI.GetPodsByNamespace(ctx, ds.Namespace, "")
+			if err != nil {
+				return err
+			}
+
+			logs := make(map[string][]byte)
+			for _, pod := range pods {
+				if !isOwnedBy(pod, "DaemonSet", ds.Name) || !isPodReady(pod) {
+					continue
+				}
+
+				rsp, err := hc.kubeAPI.GetPodLogs(ctx, pod.Namespace, pod.Name, cniContainerName, k8s.PodLogOptions{TailLines: cniLogLines, LimitBytes: cniLogBytes})
+				if err != nil {
+					return fmt.Errorf("Failed to fetch logs from the \"%s/%s\" pod: %s", pod.Namespace, pod.Name, err)
+				}
+				logs[pod.Spec.NodeName] = rsp
+			}
+
+			return validateCNIConfigInstalled(logs)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdCNIPluginCategory,
+		description: "meshed pods are redirected by the CNI plugin",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			if _, err := hc.getCNIDaemonSet(ctx); err != nil {
+				return err
+			}
+
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			metrics := make(map[string]map[string]*dto.MetricFamily)
+			recent := recentMeshedPods(pods, hc.ControlPlaneNamespace, hc.maxSampledProxies())
+			for _, pod := range recent {
+				rsp, err := hc.kubeAPI.GetPodMetrics(ctx, pod.Namespace, pod.Name, proxyMetricsPort(pod))
+				if err != nil {
+					return fmt.Errorf("Failed to fetch metrics from the \"%s/%s\" pod: %s", pod.Namespace, pod.Name, err)
+				}
+
+				parsed, err := parseProxyMetrics(rsp)
+				if err != nil {
+					return fmt.Errorf("Failed to parse metrics from the \"%s/%s\" pod: %s", pod.Namespace, pod.Name, err)
+				}
+				metrics[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = parsed
+			}
+
+			return validateCNIRedirection(recent, metrics)
+		},
+	})
+}
+
+func (hc *HealthChecker) cniNamespace() string {
+	if hc.CNINamespace != "" {
+		return hc.CNINamespace
+	}
+	return defaultCNINamespace
+}
+
+// cniEnabled returns true if traffic redirection is set up by the CNI plugin,
+// which is the case once the linkerd-cni DaemonSet is installed.
+func (hc *HealthChecker) cniEnabled(ctx context.Context) (bool, error) {
+	ds, err := hc.findCNIDaemonSet(ctx)
+	return ds != nil, err
+}
+
+// findCNIDaemonSet returns the linkerd-cni DaemonSet, or nil if it is not
+// installed in the CNI namespace. The result is cached for the remainder of
+// the check run.
+func (hc *HealthChecker) findCNIDaemonSet(ctx context.Context) (*appsV1.DaemonSet, error) {
+	if hc.cniDaemonSetChecked {
+		return hc.cniDaemonSet, nil
+	}
+
+	daemonSets, err := hc.kubeAPI.GetDaemonSets(ctx, hc.cniNamespace(), "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range daemonSets {
+		if daemonSets[i].Name == cniDaemonSetName {
+			hc.cniDaemonSet = &daemonSets[i]
+			break
+		}
+	}
+
+	hc.cniDaemonSetChecked = true
+	return hc.cniDaemonSet, nil
+}
+
+// getCNIDaemonSet returns the linkerd-cni DaemonSet, or a SkipError if it is
+// not installed, meaning the control plane is not running in CNI mode.
+func (hc *HealthChecker) getCNIDaemonSet(ctx context.Context) (*appsV1.DaemonSet, error) {
+	ds, err := hc.findCNIDaemonSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ds == nil {
+		return nil, &SkipError{Reason: fmt.Sprintf("CNI mode is not enabled: the \"%s\" DaemonSet does not exist in the \"%s\" namespace", cniDaemonSetName, hc.cniNamespace())}
+	}
+	return ds, nil
+}
+
+func validateCNIDaemonSetReady(ds *appsV1.DaemonSet) error {
+	if ds.Status.NumberReady != ds.Status.DesiredNumberScheduled {
+		return fmt.Errorf("The \"%s\" DaemonSet has %d ready pods, expected %d",
+			ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+	}
+	return nil
+}
+
+// validateCNINodeCoverage returns an error listing the schedulable nodes that
+// are not running a ready pod of the given DaemonSet.
+func validateCNINodeCoverage(ds *appsV1.DaemonSet, pods []v1.Pod, nodes []k8s.NodeInfo) error {
+	covered := make(map[string]bool)
+	for _, pod := range pods {
+		if isOwnedBy(pod, "DaemonSet", ds.Name) && isPodReady(pod) {
+			covered[pod.Spec.NodeName] = true
+		}
+	}
+
+	missing := []string{}
+	for _, node := range nodes {
+		if !node.Unschedulable && !covered[node.Name] {
+			missing = append(missing, node.Name)
+		}
+	}
+	sort.Strings(missing)
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Some nodes are not running a ready \"%s\" pod: %s", ds.Name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func isOwnedBy(pod v1.Pod, kind, name string) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isOwnedByKind(pod v1.Pod, kind string) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isPodReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// validateCNIConfigInstalled returns an error naming the nodes whose CNI pod
+// logs most recently report an error, or lack the confirmation that the CNI
+// config was written. A confirmation supersedes earlier errors, and an error
+// supersedes earlier confirmations. logs is keyed by node name.
+func validateCNIConfigInstalled(logs map[string][]byte) error {
+	failed := []string{}
+	unconfirmed := []string{}
+
+	for node, log := range logs {
+		confirmed := false
+		var errLine string
+
+		scanner := bufio.NewScanner(bytes.NewReader(log))
+		for scanner.Scan() {
+			line := strings.ToLower(scanner.Text())
+			if strings.Contains(line, "error") {
+				errLine = truncateLogLine(scanner.Text())
+				confirmed = false
+			} else if strings.Contains(line, "wrote") && strings.Contains(line, "config") {
+				errLine = ""
+				confirmed = true
+			}
+		}
+
+		if errLine != "" {
+			failed = append(failed, fmt.Sprintf("%s (\"%s\")", node, errLine))
+		} else if !confirmed {
+			unconfirmed = append(unconfirmed, node)
+		}
+	}
+	sort.Strings(failed)
+	sort.Strings(unconfirmed)
+
+	problems := []string{}
+	if len(failed) > 0 {
+		problems = append(problems, fmt.Sprintf("CNI pods report errors on nodes: %s", strings.Join(failed, ", ")))
+	}
+	if len(unconfirmed) > 0 {
+		problems = append(problems, fmt.Sprintf("CNI pods have not confirmed writing the CNI config on nodes: %s", strings.Join(unconfirmed, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("The linkerd CNI config may be missing; %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// recentMeshedPods returns up to limit of the most recently created running,
+// meshed pods.
+func recentMeshedPods(pods []v1.Pod, controlPlaneNamespace string, limit int) []v1.Pod {
+	meshed := []v1.Pod{}
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning && hasProxyContainer(pod) && k8s.IsMeshed(&pod, controlPlaneNamespace) {
+			meshed = append(meshed, pod)
+		}
+	}
+
+	sort.Slice(meshed, func(i, j int) bool {
+		return meshed[j].CreationTimestamp.Before(&meshed[i].CreationTimestamp)
+	})
+	if len(meshed) > limit {
+		meshed = meshed[:limit]
+	}
+
+	return meshed
+}
+
+// validateCNIRedirection returns an error listing the pods that were injected
+// with an init container, or whose proxy reports that its traffic is not
+// redirected by the CNI plugin. Proxies that don't export the redirection mode
+// are only checked for the init container. metrics is keyed by
+// "namespace/name".
+func validateCNIRedirection(pods []v1.Pod, metrics map[string]map[string]*dto.MetricFamily) error {
+	withInit := []string{}
+	notRedirected := []string{}
+
+	for _, pod := range pods {
+		name := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+
+		for _, container := range pod.Spec.InitContainers {
+			if container.Name == k8s.InitContainerName {
+				withInit = append(withInit, name)
+				break
+			}
+		}
+
+		if mode, ok := iptablesMode(metrics[name]); ok && mode != "cni" {
+			notRedirected = append(notRedirected, name)
+		}
+	}
+
+	problems := []string{}
+	if len(withInit) > 0 {
+		problems = append(problems, fmt.Sprintf("pods have a \"%s\" init container: %s", k8s.InitContainerName, strings.Join(withInit, ", ")))
+	}
+	if len(notRedirected) > 0 {
+		problems = append(problems, fmt.Sprintf("proxies do not report CNI redirection: %s", strings.Join(notRedirected, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Some meshed pods are not redirected by the CNI plugin; %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// iptablesMode returns the traffic redirection mode reported by the proxy,
+// and false if the proxy does not export it.
+func iptablesMode(metrics map[string]*dto.MetricFamily) (string, bool) {
+	family, ok := metrics[iptablesModeMetricName]
+	if !ok {
+		return "", false
+	}
+	for _, metric := range family.GetMetric() {
+		if metric.GetGauge().GetValue() > 0 {
+			return labelValue(metric, "mode"), true
+		}
+	}
+	return "", false
+}
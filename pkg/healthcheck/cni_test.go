@@ -0,0 +1,216 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCNIDaemonSet(t *testing.T) {
+	ds := &appsV1.DaemonSet{
+		ObjectMeta: meta.ObjectMeta{Name: "linkerd-cni", Namespace: "linkerd-cni"},
+		Status: appsV1.DaemonSetStatus{
+			DesiredNumberScheduled: 3,
+			NumberReady:            2,
+		},
+	}
+
+	cniPod := func(name, node string, ready v1.ConditionStatus) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:            name,
+				Namespace:       "linkerd-cni",
+				OwnerReferences: []meta.OwnerReference{{Kind: "DaemonSet", Name: "linkerd-cni"}},
+			},
+			Spec: v1.PodSpec{NodeName: node},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	node := func(name string, unschedulable bool) k8s.NodeInfo {
+		return k8s.NodeInfo{Name: name, Unschedulable: unschedulable}
+	}
+
+	t.Run("Returns an error if the DaemonSet is not ready", func(t *testing.T) {
+		err := validateCNIDaemonSetReady(ds)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "The \"linkerd-cni\" DaemonSet has 2 ready pods, expected 3" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns nil if all schedulable nodes run a ready pod", func(t *testing.T) {
+		pods := []v1.Pod{
+			cniPod("linkerd-cni-a", "node-a", v1.ConditionTrue),
+			cniPod("linkerd-cni-b", "node-b", v1.ConditionTrue),
+		}
+		nodes := []k8s.NodeInfo{node("node-a", false), node("node-b", false), node("node-c", true)}
+
+		err := validateCNINodeCoverage(ds, pods, nodes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error listing nodes without a ready pod", func(t *testing.T) {
+		pods := []v1.Pod{
+			cniPod("linkerd-cni-a", "node-a", v1.ConditionTrue),
+			cniPod("linkerd-cni-b", "node-b", v1.ConditionFalse),
+		}
+		nodes := []k8s.NodeInfo{node("node-c", false), node("node-b", false), node("node-a", false)}
+
+		err := validateCNINodeCoverage(ds, pods, nodes)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some nodes are not running a ready \"linkerd-cni\" pod: node-b, node-c" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestCNIEnabled(t *testing.T) {
+	t.Run("Returns the linkerd-cni DaemonSet if it is installed", func(t *testing.T) {
+		hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{CNINamespace: "kube-system"}, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/apis/apps/v1/namespaces/kube-system/daemonsets" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"items":[{"metadata":{"name":"kube-proxy"}},{"metadata":{"name":"linkerd-cni"}}]}`))
+		})
+		defer done()
+
+		enabled, err := hc.cniEnabled(context.Background())
+		if err != nil || !enabled {
+			t.Fatalf("Expected CNI mode to be enabled, got %t, %v", enabled, err)
+		}
+		if ds, err := hc.getCNIDaemonSet(context.Background()); err != nil || ds.Name != "linkerd-cni" {
+			t.Fatalf("Unexpected result: %v, %v", ds, err)
+		}
+	})
+
+	t.Run("Skips the CNI checks if the linkerd-cni DaemonSet is not installed", func(t *testing.T) {
+		hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"items":[]}`))
+		})
+		defer done()
+
+		enabled, err := hc.cniEnabled(context.Background())
+		if err != nil || enabled {
+			t.Fatalf("Expected CNI mode to be disabled, got %t, %v", enabled, err)
+		}
+		if _, err := hc.getCNIDaemonSet(context.Background()); err == nil {
+			t.Fatal("Expected a SkipError")
+		} else if _, ok := err.(*SkipError); !ok {
+			t.Fatalf("Expected a SkipError, got %v", err)
+		}
+	})
+}
+
+func TestValidateCNIConfigInstalled(t *testing.T) {
+	t.Run("Returns nil if all CNI pods wrote the config", func(t *testing.T) {
+		logs := map[string][]byte{
+			"node-a": []byte("Wrote linkerd CNI binaries to /host/opt/cni/bin\nWrote CNI config: /host/etc/cni/net.d/10-calico.conflist\n"),
+		}
+
+		err := validateCNIConfigInstalled(logs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming nodes with errors or no confirmation", func(t *testing.T) {
+		logs := map[string][]byte{
+			"node-a": []byte("Wrote CNI config: /host/etc/cni/net.d/10-calico.conflist\n"),
+			"node-b": []byte("ERROR: /host/etc/cni/net.d is not writable\n"),
+			"node-c": []byte("Waiting for CNI config\n"),
+			"node-d": []byte("ERROR: /host/etc/cni/net.d is not writable\nWrote CNI config: /host/etc/cni/net.d/10-calico.conflist\n"),
+			"node-e": []byte("Wrote CNI config: /host/etc/cni/net.d/10-calico.conflist\nERROR: failed to watch /host/etc/cni/net.d\n"),
+		}
+
+		err := validateCNIConfigInstalled(logs)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The linkerd CNI config may be missing; " +
+			"CNI pods report errors on nodes: node-b (\"ERROR: /host/etc/cni/net.d is not writable\"), node-e (\"ERROR: failed to watch /host/etc/cni/net.d\"); " +
+			"CNI pods have not confirmed writing the CNI config on nodes: node-c"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateCNIRedirection(t *testing.T) {
+	gauge := func(mode string) map[string]*dto.MetricFamily {
+		name := "mode"
+		value := 1.0
+		return map[string]*dto.MetricFamily{
+			"proxy_iptables_mode": &dto.MetricFamily{
+				Metric: []*dto.Metric{
+					&dto.Metric{
+						Label: []*dto.LabelPair{&dto.LabelPair{Name: &name, Value: &mode}},
+						Gauge: &dto.Gauge{Value: &value},
+					},
+				},
+			},
+		}
+	}
+
+	pods := []v1.Pod{
+		v1.Pod{ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "emojivoto"}},
+		v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: "voting", Namespace: "emojivoto"},
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{{Name: "linkerd-init"}},
+			},
+		},
+	}
+
+	t.Run("Returns nil if pods are redirected by the CNI plugin", func(t *testing.T) {
+		err := validateCNIRedirection(pods[:1], map[string]map[string]*dto.MetricFamily{
+			"emojivoto/web": gauge("cni"),
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Only checks the init container of proxies without the redirection mode metric", func(t *testing.T) {
+		err := validateCNIRedirection(pods, map[string]map[string]*dto.MetricFamily{})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some meshed pods are not redirected by the CNI plugin; " +
+			"pods have a \"linkerd-init\" init container: emojivoto/voting"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if pods are not redirected by the CNI plugin", func(t *testing.T) {
+		err := validateCNIRedirection(pods, map[string]map[string]*dto.MetricFamily{
+			"emojivoto/web":    gauge("cni"),
+			"emojivoto/voting": gauge("init"),
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some meshed pods are not redirected by the CNI plugin; " +
+			"pods have a \"linkerd-init\" init container: emojivoto/voting; " +
+			"proxies do not report CNI redirection: emojivoto/voting"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
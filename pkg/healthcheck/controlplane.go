@@ -0,0 +1,145 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+)
+
+const proxyInjectorComponent = "proxy-injector"
+
+// checkControlPlaneLabels verifies that the control plane namespace and its
+// workloads carry the labels and annotations the install templates set.
+func (hc *HealthChecker) checkControlPlaneLabels(ctx context.Context) error {
+	namespaces, err := hc.kubeAPI.GetNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	var namespace *v1.Namespace
+	for i := range namespaces {
+		if namespaces[i].Name == hc.ControlPlaneNamespace {
+			namespace = &namespaces[i]
+			break
+		}
+	}
+	if namespace == nil {
+		return &k8s.NamespaceNotFoundError{Namespace: hc.ControlPlaneNamespace}
+	}
+
+	deployments, err := hc.kubeAPI.GetDeployments(ctx, hc.ControlPlaneNamespace, "")
+	if err != nil {
+		return err
+	}
+
+	pods, err := hc.kubeAPI.GetPodsByNamespace(ctx, hc.ControlPlaneNamespace, "")
+	if err != nil {
+		return err
+	}
+
+	return validateControlPlaneLabels(*namespace, deployments, pods)
+}
+
+// validateControlPlaneLabels returns an error listing the labels and
+// annotations that are missing or mismatched on the control plane namespace
+// and its workloads:
+//   - if the proxy injector is installed, the namespace must be excluded from
+//     auto-injection, or the injector would inject the control plane itself
+//   - Deployments and their pod templates must be labeled with their component
+//     and annotated with the CLI version that created them
+//   - injected control plane pods must be labeled with the control plane
+//     namespace
+func validateControlPlaneLabels(namespace v1.Namespace, deployments []appsV1.Deployment, pods []v1.Pod) error {
+	problems := []string{}
+
+	for _, d := range deployments {
+		if d.Labels[k8s.ControllerComponentLabel] == proxyInjectorComponent {
+			problems = append(problems, expectedValue("namespace/"+namespace.Name, "label", namespace.Labels, k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectDisabled)...)
+			break
+		}
+	}
+
+	for _, d := range deployments {
+		resource := "deployment/" + d.Name
+		component := d.Labels[k8s.ControllerComponentLabel]
+
+		problems = append(problems, expectedValue(resource, "label", d.Labels, k8s.ControllerComponentLabel, "")...)
+		problems = append(problems, expectedValue(resource, "annotation", d.Annotations, k8s.CreatedByAnnotation, "")...)
+		problems = append(problems, expectedValue(resource+" pod template", "label", d.Spec.Template.Labels, k8s.ControllerComponentLabel, component)...)
+		problems = append(problems, expectedValue(resource+" pod template", "annotation", d.Spec.Template.Annotations, k8s.CreatedByAnnotation, "")...)
+	}
+
+	for _, pod := range pods {
+		if isActivePod(pod) && hasProxyContainer(pod) {
+			problems = append(problems, expectedValue("pod/"+pod.Name, "label", pod.Labels, k8s.ControllerNSLabel, namespace.Name)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Some control plane resources are missing expected labels or annotations: %s", strings.Join(problems, ", "))
+	}
+
+	return nil
+}
+
+// expectedValue returns a problem if the key is missing from values, or if it
+// doesn't have the expected value. An empty expected value accepts any value.
+func expectedValue(resource, kind string, values map[string]string, key, expected string) []string {
+	value, ok := values[key]
+	switch {
+	case !ok && expected == "":
+		return []string{fmt.Sprintf("%s is missing the %s %s", resource, key, kind)}
+	case !ok:
+		return []string{fmt.Sprintf("%s is missing the %s %s (expected \"%s\")", resource, key, kind, expected)}
+	case expected != "" && value != expected:
+		return []string{fmt.Sprintf("%s has the %s %s set to \"%s\" (expected \"%s\")", resource, key, kind, value, expected)}
+	}
+	return nil
+}
+
+// controlPlaneComponentVersions returns the version of each control plane
+// Deployment, by name, as deployed. Nothing is returned if the Deployments
+// cannot be listed, so that the control plane version is then only compared
+// with the latest version.
+func (hc *HealthChecker) controlPlaneComponentVersions(ctx context.Context) (map[string]string, error) {
+	deployments, err := hc.kubeAPI.GetDeployments(ctx, hc.ControlPlaneNamespace, k8s.ControllerComponentLabel)
+	if k8s.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return componentVersions(deployments), nil
+}
+
+// componentVersions returns the version of each of the given Deployments, by
+// name, omitting those whose version cannot be determined.
+func componentVersions(deployments []appsV1.Deployment) map[string]string {
+	versions := make(map[string]string)
+	for _, d := range deployments {
+		if version := componentVersion(d); version != "" {
+			versions[d.Name] = version
+		}
+	}
+	return versions
+}
+
+// componentVersion returns the version of a control plane component as
+// deployed: the image tag of the first container of its pod template other
+// than the proxy, falling back to the version of the CLI that created it.
+func componentVersion(d appsV1.Deployment) string {
+	for _, container := range d.Spec.Template.Spec.Containers {
+		if container.Name == k8s.ProxyContainerName {
+			continue
+		}
+		if tag := imageTag(container.Image); tag != "" {
+			return tag
+		}
+		break
+	}
+	return strings.TrimPrefix(d.Annotations[k8s.CreatedByAnnotation], "linkerd/cli ")
+}
@@ -0,0 +1,112 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateControlPlaneLabels(t *testing.T) {
+	deployment := func(name, component string) appsV1.Deployment {
+		d := appsV1.Deployment{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:   "linkerd",
+				Name:        name,
+				Labels:      map[string]string{"linkerd.io/control-plane-component": component},
+				Annotations: map[string]string{"linkerd.io/created-by": "linkerd/cli dev"},
+			},
+		}
+		d.Spec.Template.Labels = map[string]string{"linkerd.io/control-plane-component": component}
+		d.Spec.Template.Annotations = map[string]string{"linkerd.io/created-by": "linkerd/cli dev"}
+		return d
+	}
+
+	pod := func(name string, labels map[string]string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Namespace: "linkerd", Name: name, Labels: labels},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "linkerd-proxy"}}},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+
+	t.Run("Returns nil if all resources have the expected labels", func(t *testing.T) {
+		namespace := v1.Namespace{ObjectMeta: meta.ObjectMeta{
+			Name:   "linkerd",
+			Labels: map[string]string{"linkerd.io/auto-inject": "disabled"},
+		}}
+		deployments := []appsV1.Deployment{
+			deployment("controller", "controller"),
+			deployment("proxy-injector", "proxy-injector"),
+		}
+		pods := []v1.Pod{
+			pod("controller-6f78cbd47-bc557", map[string]string{"linkerd.io/control-plane-ns": "linkerd"}),
+		}
+
+		err := validateControlPlaneLabels(namespace, deployments, pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming the missing and mismatched labels", func(t *testing.T) {
+		namespace := v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "linkerd"}}
+		web := deployment("web", "web")
+		web.Spec.Template.Labels["linkerd.io/control-plane-component"] = "controller"
+		delete(web.Annotations, "linkerd.io/created-by")
+		deployments := []appsV1.Deployment{
+			web,
+			deployment("proxy-injector", "proxy-injector"),
+		}
+		pods := []v1.Pod{
+			pod("web-5f9d7b6c9-xvkwm", map[string]string{"linkerd.io/control-plane-ns": "other"}),
+		}
+
+		err := validateControlPlaneLabels(namespace, deployments, pods)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some control plane resources are missing expected labels or annotations: " +
+			"namespace/linkerd is missing the linkerd.io/auto-inject label (expected \"disabled\"), " +
+			"deployment/web is missing the linkerd.io/created-by annotation, " +
+			"deployment/web pod template has the linkerd.io/control-plane-component label set to \"controller\" (expected \"web\"), " +
+			"pod/web-5f9d7b6c9-xvkwm has the linkerd.io/control-plane-ns label set to \"other\" (expected \"linkerd\")"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestComponentVersion(t *testing.T) {
+	deployment := func(annotations map[string]string, images ...string) appsV1.Deployment {
+		d := appsV1.Deployment{ObjectMeta: meta.ObjectMeta{Name: "linkerd-controller", Annotations: annotations}}
+		for i, image := range images {
+			name := fmt.Sprintf("container-%d", i)
+			if strings.Contains(image, "/proxy:") {
+				name = k8s.ProxyContainerName
+			}
+			d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, v1.Container{Name: name, Image: image})
+		}
+		return d
+	}
+	createdBy := map[string]string{k8s.CreatedByAnnotation: "linkerd/cli stable-2.0.0"}
+
+	testCases := []struct {
+		deployment appsV1.Deployment
+		expected   string
+	}{
+		{deployment(nil, "gcr.io/linkerd-io/controller:stable-2.1.0"), "stable-2.1.0"},
+		{deployment(createdBy, "gcr.io/linkerd-io/proxy:stable-2.0.0", "gcr.io/linkerd-io/controller:stable-2.1.0"), "stable-2.1.0"},
+		{deployment(createdBy, "gcr.io/linkerd-io/controller@sha256:0123"), "stable-2.0.0"},
+		{deployment(nil, "gcr.io/linkerd-io/controller"), ""},
+	}
+	for i, tc := range testCases {
+		if version := componentVersion(tc.deployment); version != tc.expected {
+			t.Fatalf("Test case %d: expected version %q, got %q", i, tc.expected, version)
+		}
+	}
+}
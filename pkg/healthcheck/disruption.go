@@ -0,0 +1,101 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	policyV1beta1 "k8s.io/api/policy/v1beta1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// checkControlPlaneDisruptionBudgets verifies that none of the
+// PodDisruptionBudgets in the control plane namespace would block the eviction
+// of the control plane pods they select.
+func (hc *HealthChecker) checkControlPlaneDisruptionBudgets(ctx context.Context) error {
+	pdbs, err := hc.kubeAPI.GetPodDisruptionBudgets(ctx, hc.ControlPlaneNamespace)
+	if err != nil {
+		return err
+	}
+
+	pods, err := hc.kubeAPI.GetPodsByNamespace(ctx, hc.ControlPlaneNamespace, "")
+	if err != nil {
+		return err
+	}
+
+	return validateDisruptionBudgets(pdbs, pods)
+}
+
+// validateDisruptionBudgets returns an error naming the PodDisruptionBudgets
+// that currently allow no voluntary disruption of the pods they select. A
+// budget is considered blocking only if both its reported status and the
+// budget computed from the pods' current readiness allow no disruption, so
+// that a stale status does not trigger false positives.
+func validateDisruptionBudgets(pdbs []policyV1beta1.PodDisruptionBudget, pods []v1.Pod) error {
+	blocking := []string{}
+
+	for _, pdb := range pdbs {
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := meta_v1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("The \"%s\" PodDisruptionBudget has an invalid selector: %s", pdb.Name, err)
+		}
+
+		expected, healthy := 0, 0
+		for _, pod := range pods {
+			if pod.Namespace != pdb.Namespace || !selector.Matches(labels.Set(pod.Labels)) || !isActivePod(pod) {
+				continue
+			}
+			expected++
+			if isPodReady(pod) {
+				healthy++
+			}
+		}
+		if expected == 0 {
+			continue
+		}
+
+		allowed, err := disruptionsAllowed(pdb.Spec, expected, healthy)
+		if err != nil {
+			return fmt.Errorf("The \"%s\" PodDisruptionBudget is invalid: %s", pdb.Name, err)
+		}
+
+		if allowed <= 0 && pdb.Status.PodDisruptionsAllowed <= 0 {
+			blocking = append(blocking, fmt.Sprintf("%s (%d/%d pods healthy)", pdb.Name, healthy, expected))
+		}
+	}
+
+	if len(blocking) > 0 {
+		return fmt.Errorf("Some PodDisruptionBudgets allow no disruptions, which blocks rolling upgrades and node drains: %s", strings.Join(blocking, ", "))
+	}
+
+	return nil
+}
+
+// disruptionsAllowed mirrors the disruption controller's computation of the
+// number of pods that may be evicted.
+func disruptionsAllowed(spec policyV1beta1.PodDisruptionBudgetSpec, expected, healthy int) (int, error) {
+	desiredHealthy := 0
+
+	switch {
+	case spec.MaxUnavailable != nil:
+		maxUnavailable, err := intstr.GetValueFromIntOrPercent(spec.MaxUnavailable, expected, true)
+		if err != nil {
+			return 0, err
+		}
+		desiredHealthy = expected - maxUnavailable
+	case spec.MinAvailable != nil:
+		minAvailable, err := intstr.GetValueFromIntOrPercent(spec.MinAvailable, expected, true)
+		if err != nil {
+			return 0, err
+		}
+		desiredHealthy = minAvailable
+	}
+
+	return healthy - desiredHealthy, nil
+}
@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	policyV1beta1 "k8s.io/api/policy/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidateDisruptionBudgets(t *testing.T) {
+	pod := func(name string, ready bool) v1.Pod {
+		status := v1.ConditionFalse
+		if ready {
+			status = v1.ConditionTrue
+		}
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "linkerd",
+				Name:      name,
+				Labels:    map[string]string{"linkerd.io/control-plane-component": "controller"},
+			},
+			Status: v1.PodStatus{
+				Phase:      v1.PodRunning,
+				Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	pdb := func(name string, minAvailable, maxUnavailable *intstr.IntOrString, allowed int32) policyV1beta1.PodDisruptionBudget {
+		return policyV1beta1.PodDisruptionBudget{
+			ObjectMeta: meta.ObjectMeta{Namespace: "linkerd", Name: name},
+			Spec: policyV1beta1.PodDisruptionBudgetSpec{
+				Selector: &meta.LabelSelector{
+					MatchLabels: map[string]string{"linkerd.io/control-plane-component": "controller"},
+				},
+				MinAvailable:   minAvailable,
+				MaxUnavailable: maxUnavailable,
+			},
+			Status: policyV1beta1.PodDisruptionBudgetStatus{PodDisruptionsAllowed: allowed},
+		}
+	}
+
+	two := intstr.FromInt(2)
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	none := intstr.FromInt(0)
+
+	pods := []v1.Pod{pod("controller-1", true), pod("controller-2", true)}
+
+	t.Run("Returns nil if all budgets allow disruptions", func(t *testing.T) {
+		pdbs := []policyV1beta1.PodDisruptionBudget{
+			pdb("min-one", &one, nil, 1),
+			pdb("half", &half, nil, 0),
+			pdb("max-one", nil, &one, 1),
+		}
+
+		err := validateDisruptionBudgets(pdbs, pods)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming the blocking budgets", func(t *testing.T) {
+		pdbs := []policyV1beta1.PodDisruptionBudget{
+			pdb("min-all", &two, nil, 0),
+			pdb("max-none", nil, &none, 0),
+			pdb("min-one", &one, nil, 0),
+		}
+
+		err := validateDisruptionBudgets(pdbs, []v1.Pod{pod("controller-1", true), pod("controller-2", false)})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some PodDisruptionBudgets allow no disruptions, which blocks rolling upgrades and node drains: min-all (1/2 pods healthy), max-none (1/2 pods healthy), min-one (1/2 pods healthy)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
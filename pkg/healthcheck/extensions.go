@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+)
+
+// ExtensionCheck is a single check contributed by a Linkerd extension.
+type ExtensionCheck struct {
+	Description string
+	Fatal       bool
+	Warning     bool
+	Check       func(ctx context.Context) error
+}
+
+// ExtensionCheckSuite returns the checks for an extension installed in the
+// given namespace. It is invoked once the extension has been discovered, after
+// the KubernetesAPIChecks have run.
+type ExtensionCheckSuite func(hc *HealthChecker, namespace string) []ExtensionCheck
+
+// AddChecker registers the check suite for the named extension. The suite is
+// run by the LinkerdExtensionChecks if a namespace labeled as belonging to
+// that extension is found.
+func (hc *HealthChecker) AddChecker(extension string, suite ExtensionCheckSuite) {
+	if hc.extensionSuites == nil {
+		hc.extensionSuites = make(map[string]ExtensionCheckSuite)
+	}
+	hc.extensionSuites[extension] = suite
+}
+
+func (hc *HealthChecker) addLinkerdExtensionChecks() {
+	hc.AddChecker(vizExtensionName, vizCheckSuite)
+	hc.AddChecker(jaegerExtensionName, jaegerCheckSuite)
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdExtensionsCategory,
+		description: "can discover installed extensions",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			namespaces, err := hc.kubeAPI.GetNamespaces(ctx)
+			if err != nil {
+				return err
+			}
+
+			// The discovered checks are appended after all the core checks,
+			// which RunChecks picks up as it iterates.
+			hc.checkers = append(hc.checkers, hc.extensionCheckers(discoverExtensions(namespaces))...)
+			return nil
+		},
+	})
+}
+
+// discoverExtensions returns the namespace of each installed extension, keyed
+// by extension name.
+func discoverExtensions(namespaces []v1.Namespace) map[string]string {
+	extensions := make(map[string]string)
+	for _, ns := range namespaces {
+		if name := ns.Labels[k8s.ExtensionLabel]; name != "" {
+			extensions[name] = ns.Name
+		}
+	}
+	return extensions
+}
+
+// extensionCheckers builds the checkers for the discovered extensions, in
+// alphabetical order of extension name. Extensions without a registered check
+// suite get a single skipped checker noting their presence, and extensions
+// with a registered check suite that are not installed get a single skipped
+// checker noting their absence.
+func (hc *HealthChecker) extensionCheckers(extensions map[string]string) []*checker {
+	names := []string{}
+	for name := range extensions {
+		names = append(names, name)
+	}
+	for name := range hc.extensionSuites {
+		if _, ok := extensions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	checkers := []*checker{}
+	for _, name := range names {
+		category := fmt.Sprintf("linkerd-%s", name)
+		namespace, installed := extensions[name]
+
+		if !installed {
+			checkers = append(checkers, &checker{
+				category:    category,
+				description: "extension is installed",
+				extension:   name,
+				check: func(ctx context.Context) error {
+					return &SkipError{Reason: "The extension is not installed"}
+				},
+			})
+			continue
+		}
+
+		suite, ok := hc.extensionSuites[name]
+		if !ok {
+			checkers = append(checkers, &checker{
+				category:    category,
+				description: "extension is installed",
+				extension:   name,
+				check: func(ctx context.Context) error {
+					return &SkipError{Reason: fmt.Sprintf("Found in the \"%s\" namespace, but no checks are registered for this extension", namespace)}
+				},
+			})
+			continue
+		}
+
+		for _, c := range suite(hc, namespace) {
+			checkers = append(checkers, &checker{
+				category:    category,
+				description: c.Description,
+				fatal:       c.Fatal,
+				warning:     c.Warning,
+				extension:   name,
+				check:       c.Check,
+			})
+		}
+	}
+
+	return checkers
+}
@@ -0,0 +1,82 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExtensionChecks(t *testing.T) {
+	namespaces := []v1.Namespace{
+		v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "linkerd-viz", Labels: map[string]string{"linkerd.io/extension": "viz"}}},
+		v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "tracing", Labels: map[string]string{"linkerd.io/extension": "jaeger"}}},
+		v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "buoyant-cloud", Labels: map[string]string{"linkerd.io/extension": "buoyant"}}},
+		v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "emojivoto"}},
+	}
+
+	extensions := discoverExtensions(namespaces)
+	expectedExtensions := map[string]string{"viz": "linkerd-viz", "jaeger": "tracing", "buoyant": "buoyant-cloud"}
+	if !reflect.DeepEqual(extensions, expectedExtensions) {
+		t.Fatalf("Expected extensions %v, got %v", expectedExtensions, extensions)
+	}
+
+	t.Run("Runs extensions in order after core checks, isolating fatal failures", func(t *testing.T) {
+		hc := HealthChecker{}
+		hc.AddChecker("viz", func(_ *HealthChecker, namespace string) []ExtensionCheck {
+			return []ExtensionCheck{
+				{Description: "fatal in " + namespace, Fatal: true, Check: func(ctx context.Context) error { return fmt.Errorf("fatal") }},
+				{Description: "never runs", Check: func(ctx context.Context) error { return nil }},
+			}
+		})
+		hc.AddChecker("jaeger", func(_ *HealthChecker, namespace string) []ExtensionCheck {
+			return []ExtensionCheck{
+				{Description: "passes in " + namespace, Check: func(ctx context.Context) error { return nil }},
+			}
+		})
+
+		hc.checkers = []*checker{
+			&checker{
+				category:    "core",
+				description: "discovers extensions",
+				check: func(ctx context.Context) error {
+					hc.checkers = append(hc.checkers, hc.extensionCheckers(extensions)...)
+					return nil
+				},
+			},
+			&checker{
+				category:    "core",
+				description: "runs before extensions",
+				check:       func(ctx context.Context) error { return nil },
+			},
+		}
+
+		observedResults := make([]string, 0)
+		observer := func(result *CheckResult) {
+			res := fmt.Sprintf("%s %s", result.Category, result.Description)
+			if result.Err != nil {
+				res += fmt.Sprintf(": %s", result.Err)
+			}
+			observedResults = append(observedResults, res)
+		}
+
+		success := hc.RunChecks(observer)
+
+		expectedResults := []string{
+			"core discovers extensions",
+			"core runs before extensions",
+			"linkerd-buoyant extension is installed: Found in the \"buoyant-cloud\" namespace, but no checks are registered for this extension",
+			"linkerd-jaeger passes in tracing",
+			"linkerd-viz fatal in linkerd-viz: fatal",
+		}
+		if !reflect.DeepEqual(observedResults, expectedResults) {
+			t.Fatalf("Expected results %v, but got %v", expectedResults, observedResults)
+		}
+		if success {
+			t.Fatalf("Expecting checks to not be successful, but got [%t]", success)
+		}
+	})
+}
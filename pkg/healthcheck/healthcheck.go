@@ -2,22 +2,31 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
+	spv1alpha1 "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
 	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
+	spscheme "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned/scheme"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/config"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
 	"github.com/linkerd/linkerd2/pkg/version"
+	appsV1 "k8s.io/api/apps/v1"
 	authorizationapi "k8s.io/api/authorization/v1beta1"
 	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sVersion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -49,6 +58,15 @@ const (
 	// checks must be added first.
 	LinkerdAPIChecks
 
+	// LinkerdControlPlaneChecks adds a series of checks to validate the
+	// configuration of the control plane and of the resources it serves. They
+	// are reported in the LinkerdAPICategory, but are only run by `linkerd
+	// check`, rather than before each command reaching the public API, since
+	// they are too slow or too strict to gate it.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdControlPlaneChecks
+
 	// LinkerdVersionChecks adds a series of checks to validate that the CLI,
 	// control plane, and data plane are running the latest available version.
 	// These checks are dependent on the output of AddLinkerdAPIChecks, so those
@@ -56,27 +74,111 @@ const (
 	// and ShouldCheckDataPlaneVersion options are false.
 	LinkerdVersionChecks
 
-	KubernetesAPICategory     = "kubernetes-api"
-	LinkerdPreInstallCategory = "kubernetes-setup"
-	LinkerdDataPlaneCategory  = "linkerd-data-plane"
-	LinkerdAPICategory        = "linkerd-api"
-	LinkerdVersionCategory    = "linkerd-version"
+	// LinkerdCNIPluginChecks adds a series of checks to validate that the
+	// linkerd-cni DaemonSet is healthy, when the control plane is configured to
+	// rely on the CNI plugin rather than on init containers to set up traffic
+	// redirection. When CNI mode is off, these checks are reported as skipped.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdCNIPluginChecks
+
+	// LinkerdMulticlusterChecks adds a series of checks to validate the
+	// gateways of the remote clusters linked to this one. When no Link
+	// resources exist, these checks are reported as skipped.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdMulticlusterChecks
+
+	// LinkerdExtensionChecks adds a check that discovers the installed Linkerd
+	// extensions, from the namespaces labeled with the extension's name, and
+	// then runs the check suite registered for each via AddChecker, after all
+	// the other checks. A fatal failure in an extension's checks only stops
+	// the remaining checks of that extension.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdExtensionChecks
+
+	// LinkerdPreUpgradeChecks adds a series of checks to validate that the
+	// control plane can be upgraded in place, without its rollout or the
+	// draining of its nodes being blocked.
+	// These checks are dependent on the output of KubernetesAPIChecks, so those
+	// checks must be added first.
+	LinkerdPreUpgradeChecks
+
+	KubernetesAPICategory       = "kubernetes-api"
+	LinkerdPreInstallCategory   = "kubernetes-setup"
+	LinkerdDataPlaneCategory    = "linkerd-data-plane"
+	LinkerdAPICategory          = "linkerd-api"
+	LinkerdVersionCategory      = "linkerd-version"
+	LinkerdCNIPluginCategory    = "linkerd-cni-plugin"
+	LinkerdMulticlusterCategory = "linkerd-multicluster"
+	LinkerdExtensionsCategory   = "linkerd-extensions"
+	LinkerdPreUpgradeCategory   = "linkerd-pre-upgrade"
 )
 
 var (
-	maxRetries        = 60
-	retryWindow       = 5 * time.Second
-	clusterZoneSuffix = []string{"svc", "cluster", "local"}
+	maxRetries  = 60
+	retryWindow = 5 * time.Second
+
+	// maxRetryWatch bounds how long a retried check waits for a change to the
+	// resources it checks before running again anyway, and minRetryWatch
+	// spaces out retries of resources that change in quick succession
+	maxRetryWatch = time.Minute
+	minRetryWatch = time.Second
+
+	serviceProfilesPageSize  = int64(100)
+	maxServiceProfileDetails = 20
+
+	defaultCertExpiryWarningWindow = time.Hour
+	defaultMaxSampledProxies       = 10
+	defaultStuckTerminatingTimeout = 5 * time.Minute
+	defaultSelfCheckTimeout        = 5 * time.Second
+	defaultVersionRPCTimeout       = 2 * time.Second
+	defaultAPIFailureBudget        = 3
+	defaultAPIFailureWindow        = 30 * time.Second
+
+	portListAnnotations = []string{
+		k8s.ProxySkipInboundPortsAnnotation,
+		k8s.ProxySkipOutboundPortsAnnotation,
+		k8s.ProxyOpaquePortsAnnotation,
+	}
 )
 
+// PreInstallMinKubeVersion is the oldest Kubernetes version the control plane
+// can be installed on, to be passed as MinKubeVersion by pre-installation
+// checks. The proxy injector's MutatingWebhookConfiguration is served from
+// admissionregistration.k8s.io/v1beta1 as of Kubernetes 1.9.
+var PreInstallMinKubeVersion = [3]int{1, 9, 0}
+
 type checker struct {
 	category      string
 	description   string
 	fatal         bool
 	warning       bool
 	retryDeadline time.Time
-	check         func() error
-	checkRPC      func() (*healthcheckPb.SelfCheckResponse, error)
+	check         func(ctx context.Context) error
+
+	// checkStream, if set, opens a stream of subsystem results, each reported
+	// as its own check as soon as it is received
+	checkStream func(ctx context.Context) (*selfCheckStream, error)
+
+	// retryWatch, if set, is invoked instead of waiting for the retry window
+	// before retrying a failed check, and returns once the resources the check
+	// depends on may have changed
+	retryWatch func(ctx context.Context) error
+
+	// payload, if set, is invoked after check to attach structured data to the
+	// check's result
+	payload func() interface{}
+
+	// extension is the name of the extension that contributed this check, if
+	// any
+	extension string
+
+	// kubeOptional is set for the checks that run without the Kubernetes API,
+	// as they do when the public API is reached at a URL and no kubeconfig
+	// was loaded
+	kubeOptional bool
 }
 
 type CheckResult struct {
@@ -84,11 +186,63 @@ type CheckResult struct {
 	Description string
 	Retry       bool
 	Warning     bool
+	Skipped     bool
 	Err         error
+
+	// Payload holds structured data describing the check's findings, for
+	// consumers that render results as JSON.
+	Payload interface{}
+
+	// HintAnchor is the anchor of the section of the checks' documentation
+	// describing the failure, if the check's error is a HintError.
+	HintAnchor string
+}
+
+// HintURL returns the URL of the documentation describing the failure, or ""
+// if the result has no HintAnchor.
+func (r *CheckResult) HintURL() string {
+	if r.HintAnchor == "" {
+		return ""
+	}
+	return HintBaseURL + r.HintAnchor
 }
 
 type checkObserver func(*CheckResult)
 
+// SkipError is returned by checks that do not apply to the current cluster.
+// It is reported as a skipped result with the given reason, rather than as a
+// failure.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return e.Reason
+}
+
+// HintBaseURL is the URL of the checks' documentation, to which the anchors of
+// HintErrors are appended.
+const HintBaseURL = "https://linkerd.io/checks/#"
+
+// HintError is returned by checks whose failure is documented, and is reported
+// with the anchor of the section of the documentation describing it.
+type HintError struct {
+	Anchor string
+	Err    error
+}
+
+func (e *HintError) Error() string {
+	return e.Err.Error()
+}
+
+// hintAnchor returns the anchor of the error, if it is a HintError.
+func hintAnchor(err error) string {
+	if hintErr, ok := err.(*HintError); ok {
+		return hintErr.Anchor
+	}
+	return ""
+}
+
 type HealthCheckOptions struct {
 	ControlPlaneNamespace          string
 	DataPlaneNamespace             string
@@ -101,6 +255,140 @@ type HealthCheckOptions struct {
 	ShouldCheckControlPlaneVersion bool
 	ShouldCheckDataPlaneVersion    bool
 	SingleNamespace                bool
+
+	// CertExpiryWarningWindow is how far ahead of a data plane proxy's
+	// certificate expiry a warning is emitted. Defaults to one hour.
+	CertExpiryWarningWindow time.Duration
+
+	// MaxSampledProxies caps the number of proxies per namespace whose metrics
+	// are scraped by the data plane checks. Defaults to 10.
+	MaxSampledProxies int
+
+	// ProxyLogLines is the number of trailing proxy log lines to scan for known
+	// error signatures. The scan only runs when a DataPlaneNamespace is set
+	// and ProxyLogLines is greater than zero.
+	ProxyLogLines int
+
+	// StuckTerminatingTimeout is how long a meshed pod may be Terminating with
+	// only its proxy still running before it is reported as stuck. Defaults to
+	// five minutes.
+	StuckTerminatingTimeout time.Duration
+
+	// CNINamespace is the namespace the linkerd-cni DaemonSet is installed
+	// in. Defaults to "linkerd-cni".
+	CNINamespace string
+
+	// MulticlusterNamespace is the namespace the multicluster gateway is
+	// installed in. Defaults to "linkerd-multicluster".
+	MulticlusterNamespace string
+
+	// SkipChecks lists the checks that should not be run, each given either as
+	// a category name (e.g. "linkerd-data-plane") or as a category and
+	// description, as printed by the CLI (e.g. "linkerd-api: can query the
+	// control plane API"). Skipped checks are reported as such.
+	SkipChecks []string
+
+	// ImpersonateUser, if set, is the user every Kubernetes API request is
+	// made as, so that the checks evaluate that user's permissions.
+	ImpersonateUser string
+
+	// ImpersonateGroups are the groups to impersonate, along with
+	// ImpersonateUser.
+	ImpersonateGroups []string
+
+	// KubeRequestTimeout bounds each Kubernetes API request. Defaults to
+	// k8s.DefaultRequestTimeout.
+	KubeRequestTimeout time.Duration
+
+	// KubeQPS and KubeBurst limit the rate of Kubernetes API requests made by
+	// the checks. They default to k8s.DefaultQPS and k8s.DefaultBurst; a
+	// negative KubeQPS disables the limit.
+	KubeQPS   float32
+	KubeBurst int
+
+	// KubeCAFile, if set, is the PEM-encoded CA bundle the Kubernetes API
+	// server's certificate is verified with, instead of the kubeconfig's.
+	KubeCAFile string
+
+	// KubeInsecureSkipTLSVerify disables the verification of the Kubernetes
+	// API server's certificate. A warning is reported when it is set.
+	KubeInsecureSkipTLSVerify bool
+
+	// KubeRequestRecorder, if set, is invoked with each request sent to the
+	// Kubernetes API, e.g. to report the requests made by a run.
+	KubeRequestRecorder k8s.RequestRecorder
+
+	// CacheKubeResponses, if set, serves repeated Kubernetes API GET requests
+	// made during a run from memory. The cache is emptied at the start of
+	// each run, and before a check is retried.
+	CacheKubeResponses bool
+
+	// LatestVersionTimeout bounds each request made to the version endpoint to
+	// determine the latest versions. Defaults to
+	// version.DefaultLatestVersionTimeout.
+	LatestVersionTimeout time.Duration
+
+	// LatestVersionURL, if set, is the base URL of an alternate version
+	// endpoint, such as an internal mirror, used instead of
+	// version.DefaultLatestVersionURL. Its use is reported as a warning.
+	LatestVersionURL string
+
+	// LatestVersionCAFile, if set, is the PEM-encoded CA bundle the version
+	// endpoint's certificate is verified with.
+	LatestVersionCAFile string
+
+	// LatestVersionCacheTTL is how long the latest versions are served from
+	// the on-disk cache. Defaults to version.DefaultLatestVersionCacheTTL; a
+	// negative TTL disables the cache.
+	LatestVersionCacheTTL time.Duration
+
+	// AnonymousVersionCheck, if set, strips the identifying parameters, such
+	// as the install's UUID, from the request made to the version endpoint.
+	AnonymousVersionCheck bool
+
+	// APITLS configures TLS for the connections made directly to the public
+	// API, either at APIAddr or over a port-forward. Defaults to plaintext,
+	// unless APIAddr is a URL, which is always reached over TLS.
+	APITLS public.TLSOptions
+
+	// APIProxyPath overrides the namespace, service and port names of the
+	// path the public API is reached at through the Kubernetes API server's
+	// service proxy, unless APIAddr is set. Defaults to the control plane's
+	// "api" service.
+	APIProxyPath public.ProxyPathOptions
+
+	// APICallRecorder, if set, is invoked with each call made to the public
+	// API. The calls' messages are only recorded if RecordAPIPayloads is set,
+	// since they may hold the names of workloads.
+	APICallRecorder   public.CallRecorder
+	RecordAPIPayloads bool
+
+	// SelfCheckTimeout bounds the control plane API's self-check, which may
+	// be slow to respond when the controller probes a slow Prometheus.
+	// Defaults to five seconds.
+	SelfCheckTimeout time.Duration
+
+	// SelfCheckSubsystems lists the control plane API's subsystems its
+	// self-check runs, e.g. only "prometheus" when debugging metrics. Defaults
+	// to all of them, less those excluded by SkipChecks, e.g. with
+	// "linkerd-api[kubernetes]".
+	SelfCheckSubsystems []string
+
+	// APIFailureBudget is the number of consecutive failures to reach the
+	// public API, within the APIFailureWindow, after which the circuit opens:
+	// the calls made to the API fail without being made, until a failed check
+	// is retried. Defaults to three; a negative budget disables the circuit.
+	APIFailureBudget int
+
+	// APIFailureWindow is the period the failures counted towards the
+	// APIFailureBudget must occur within. Defaults to 30 seconds.
+	APIFailureWindow time.Duration
+
+	// MinKubeVersion is the oldest Kubernetes version accepted by the
+	// KubernetesAPIChecks, as major, minor and patch versions. Defaults to
+	// the oldest version supported by the control plane; pre-installation
+	// checks should use PreInstallMinKubeVersion.
+	MinKubeVersion [3]int
 }
 
 type HealthChecker struct {
@@ -109,13 +397,65 @@ type HealthChecker struct {
 
 	// these fields are set in the process of running checks
 	kubeAPI          *k8s.KubernetesAPI
-	httpClient       *http.Client
-	clientset        *kubernetes.Clientset
-	spClientset      *spclient.Clientset
+	clientset        kubernetes.Interface
+	spClientset      spclient.Interface
 	kubeVersion      *k8sVersion.Info
+	openShift        bool
 	controlPlanePods []v1.Pod
 	apiClient        pb.ApiClient
-	latestVersion    string
+	latestVersions   version.Channels
+
+	// apiConn is the connection apiClient was made from, unless apiClient
+	// was given
+	apiConn *public.Conn
+
+	// apiCircuit, if set, short-circuits the calls made with apiClient once
+	// the public API repeatedly fails to be reached
+	apiCircuit *apiCircuit
+
+	// runCtx is the context of the current run, which the resources outliving
+	// a single check, such as the public API connection's port-forward, are
+	// tied to
+	runCtx context.Context
+
+	// controlPlaneVersion is the control plane's version, as reported by the
+	// public API or, failing that, as deployed
+	controlPlaneVersion *controlPlaneVersion
+
+	// minimumSupportedVersions is set if the version endpoint publishes the
+	// minimum supported version of its release channels
+	minimumSupportedVersions version.Channels
+
+	// staleLatestVersions is set if the latest versions are cached ones that
+	// could not be refreshed
+	staleLatestVersions error
+
+	// latestVersionRequest is the URL the latest versions are requested from
+	latestVersionRequest string
+
+	// the data plane resources shared by several checks are fetched once and
+	// cached for the remainder of the check run
+	dataPlaneKubePods   []v1.Pod
+	dataPlaneNamespaces map[string]v1.Namespace
+	dataPlaneWorkloads  *dataPlaneWorkloads
+	allKubePods         []v1.Pod
+	services            []v1.Service
+	cniDaemonSet        *appsV1.DaemonSet
+	cniDaemonSetChecked bool
+	injectorInstalled   *bool
+	linkerdConfig       *config.LinkerdConfig
+	linkerdConfigErr    error
+	linkerdConfigRead   bool
+
+	sampledProxyMetrics []*proxyMetrics
+	unsampledProxies    []string
+	proxyVersions       map[string]map[string]int
+	multiclusterLinks   []multiclusterLink
+	gatewayProbeResults map[string]string
+	remoteClusterAPIs   map[string]*k8s.KubernetesAPI
+
+	extensionSuites        map[string]ExtensionCheckSuite
+	invalidServiceProfiles []invalidServiceProfile
 }
 
 func NewHealthChecker(checks []Checks, options *HealthCheckOptions) *HealthChecker {
@@ -134,8 +474,18 @@ func NewHealthChecker(checks []Checks, options *HealthCheckOptions) *HealthCheck
 			hc.addLinkerdDataPlaneChecks()
 		case LinkerdAPIChecks:
 			hc.addLinkerdAPIChecks()
+		case LinkerdControlPlaneChecks:
+			hc.addLinkerdControlPlaneChecks()
 		case LinkerdVersionChecks:
 			hc.addLinkerdVersionChecks()
+		case LinkerdCNIPluginChecks:
+			hc.addLinkerdCNIPluginChecks()
+		case LinkerdMulticlusterChecks:
+			hc.addLinkerdMulticlusterChecks()
+		case LinkerdExtensionChecks:
+			hc.addLinkerdExtensionChecks()
+		case LinkerdPreUpgradeChecks:
+			hc.addLinkerdPreUpgradeChecks()
 		}
 	}
 
@@ -147,22 +497,80 @@ func (hc *HealthChecker) addKubernetesAPIChecks() {
 		category:    KubernetesAPICategory,
 		description: "can initialize the client",
 		fatal:       true,
-		check: func() (err error) {
+		check: func(ctx context.Context) (err error) {
 			hc.kubeAPI, err = k8s.NewAPI(hc.KubeConfig, hc.KubeContext)
+			if err != nil {
+				// the public API reached at a URL needs no kubeconfig, and
+				// the checks of the cluster are skipped without one
+				if hc.reachesAPIAtURL() {
+					hc.kubeAPI = nil
+					return &SkipError{Reason: fmt.Sprintf("No Kubernetes API configured, the public API is reached at %s: %s", hc.APIAddr, err)}
+				}
+				return
+			}
+			if hc.ImpersonateUser != "" || len(hc.ImpersonateGroups) > 0 {
+				hc.kubeAPI.Impersonate(hc.ImpersonateUser, hc.ImpersonateGroups)
+			}
+			if hc.KubeInsecureSkipTLSVerify {
+				hc.kubeAPI.SkipTLSVerify()
+			}
+			if hc.KubeCAFile != "" {
+				if err = hc.kubeAPI.UseCertificateAuthority(hc.KubeCAFile, nil); err != nil {
+					return
+				}
+			}
+			hc.kubeAPI.RequestTimeout = hc.KubeRequestTimeout
+			hc.kubeAPI.QPS = hc.KubeQPS
+			hc.kubeAPI.Burst = hc.KubeBurst
+			hc.kubeAPI.Recorder = hc.KubeRequestRecorder
+			if hc.CacheKubeResponses {
+				hc.kubeAPI.EnableResponseCache(k8s.DefaultResponseCacheBytes)
+			}
+			hc.kubeAPI.MinVersion = hc.MinKubeVersion
+			_, err = hc.kubeAPI.Client()
 			return
 		},
+		payload: func() interface{} {
+			if hc.kubeAPI == nil {
+				return nil
+			}
+			return map[string]string{"source": hc.kubeAPI.Source()}
+		},
+		kubeOptional: true,
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    KubernetesAPICategory,
+		description: "the Kubernetes API server is healthy",
+		fatal:       true,
+		check: func(ctx context.Context) error {
+			return hc.checkAPIServerHealth(ctx)
+		},
 	})
 
+	if hc.KubeInsecureSkipTLSVerify {
+		hc.checkers = append(hc.checkers, &checker{
+			category:    KubernetesAPICategory,
+			description: "verifies the Kubernetes API server's certificate",
+			warning:     true,
+			check: func(ctx context.Context) error {
+				if hc.kubeAPI.TLSVerifySkipped() {
+					return fmt.Errorf("TLS verification is disabled: the Kubernetes API server's certificate is not checked, so its connections may be intercepted")
+				}
+				return nil
+			},
+		})
+	}
+
 	hc.checkers = append(hc.checkers, &checker{
 		category:    KubernetesAPICategory,
 		description: "can query the Kubernetes API",
 		fatal:       true,
-		check: func() (err error) {
-			hc.httpClient, err = hc.kubeAPI.NewClient()
-			if err != nil {
-				return
+		check: func(ctx context.Context) (err error) {
+			hc.kubeVersion, err = hc.kubeAPI.GetVersionInfo(ctx)
+			if err == nil {
+				hc.detectOpenShift(ctx)
 			}
-			hc.kubeVersion, err = hc.kubeAPI.GetVersionInfo(hc.httpClient)
 			return
 		},
 	})
@@ -172,8 +580,12 @@ func (hc *HealthChecker) addKubernetesAPIChecks() {
 			category:    KubernetesAPICategory,
 			description: "is running the minimum Kubernetes API version",
 			fatal:       false,
-			check: func() error {
-				return hc.kubeAPI.CheckVersion(hc.kubeVersion)
+			check: func(ctx context.Context) error {
+				err := hc.kubeAPI.CheckVersion(hc.kubeVersion)
+				if unknown, ok := err.(*k8s.UnknownVersionError); ok {
+					return &SkipError{Reason: fmt.Sprintf("%s; the minimum version requirement could not be verified", unknown)}
+				}
+				return err
 			},
 		})
 	}
@@ -184,15 +596,24 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "control plane namespace does not already exist",
 		fatal:       false,
-		check: func() error {
-			exists, err := hc.kubeAPI.NamespaceExists(hc.httpClient, hc.ControlPlaneNamespace)
+		check: func(ctx context.Context) error {
+			err := hc.kubeAPI.CheckNamespaceExists(ctx, hc.ControlPlaneNamespace)
+			if k8s.IsNamespaceNotFound(err) {
+				return nil
+			}
 			if err != nil {
 				return err
 			}
-			if exists {
-				return fmt.Errorf("The \"%s\" namespace already exists", hc.ControlPlaneNamespace)
-			}
-			return nil
+			return fmt.Errorf("The \"%s\" namespace already exists", hc.ControlPlaneNamespace)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdPreInstallCategory,
+		description: "no resources are left over from a previous install",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkLeftoverResources(ctx)
 		},
 	})
 
@@ -200,8 +621,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "can create Namespaces",
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate("", "", "v1", "Namespace")
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, "", "", "v1", "Namespace")
 		},
 	})
 
@@ -216,8 +637,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: fmt.Sprintf("can create %ss", roleType),
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate("", "rbac.authorization.k8s.io", "v1beta1", roleType)
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, "", "rbac.authorization.k8s.io", "v1beta1", roleType)
 		},
 	})
 
@@ -225,8 +646,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: fmt.Sprintf("can create %ss", roleBindingType),
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate("", "rbac.authorization.k8s.io", "v1beta1", roleBindingType)
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, "", "rbac.authorization.k8s.io", "v1beta1", roleBindingType)
 		},
 	})
 
@@ -234,8 +655,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "can create ServiceAccounts",
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate(hc.ControlPlaneNamespace, "", "v1", "ServiceAccount")
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "", "v1", "ServiceAccount")
 		},
 	})
 
@@ -243,8 +664,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "can create Services",
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate(hc.ControlPlaneNamespace, "", "v1", "Service")
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "", "v1", "Service")
 		},
 	})
 
@@ -252,8 +673,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "can create Deployments",
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate(hc.ControlPlaneNamespace, "extensions", "v1beta1", "Deployments")
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "extensions", "v1beta1", "Deployments")
 		},
 	})
 
@@ -261,8 +682,8 @@ func (hc *HealthChecker) addLinkerdPreInstallChecks() {
 		category:    LinkerdPreInstallCategory,
 		description: "can create ConfigMaps",
 		fatal:       true,
-		check: func() error {
-			return hc.checkCanCreate(hc.ControlPlaneNamespace, "", "v1", "ConfigMap")
+		check: func(ctx context.Context) error {
+			return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "", "v1", "ConfigMap")
 		},
 	})
 }
@@ -272,8 +693,8 @@ func (hc *HealthChecker) addLinkerdAPIChecks() {
 		category:    LinkerdAPICategory,
 		description: "control plane namespace exists",
 		fatal:       true,
-		check: func() error {
-			return hc.checkNamespace(hc.ControlPlaneNamespace)
+		check: func(ctx context.Context) error {
+			return hc.checkNamespace(ctx, hc.ControlPlaneNamespace)
 		},
 	})
 
@@ -282,13 +703,39 @@ func (hc *HealthChecker) addLinkerdAPIChecks() {
 		description:   "control plane pods are ready",
 		retryDeadline: hc.RetryDeadline,
 		fatal:         true,
-		check: func() error {
+		check: func(ctx context.Context) error {
 			var err error
-			hc.controlPlanePods, err = hc.kubeAPI.GetPodsByNamespace(hc.httpClient, hc.ControlPlaneNamespace)
+			hc.controlPlanePods, err = hc.kubeAPI.GetPodsByNamespace(ctx, hc.ControlPlaneNamespace, "")
 			if err != nil {
 				return err
 			}
-			return validateControlPlanePods(hc.controlPlanePods)
+			if err = validateControlPlanePods(hc.controlPlanePods); err != nil {
+				return withSchedulingFailures(err, hc.controlPlanePods, hc.getPendingPodEvents(ctx, hc.controlPlanePods))
+			}
+			return nil
+		},
+		retryWatch: func(ctx context.Context) error {
+			return hc.waitForPodChange(ctx, hc.ControlPlaneNamespace, hc.controlPlanePods)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "control plane pods can use an SCC permitting NET_ADMIN",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			return hc.checkSecurityContextConstraints(ctx)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "control plane PodDisruptionBudgets allow disruptions",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			return hc.checkControlPlaneDisruptionBudgets(ctx)
 		},
 	})
 
@@ -296,34 +743,187 @@ func (hc *HealthChecker) addLinkerdAPIChecks() {
 		category:    LinkerdAPICategory,
 		description: "can initialize the client",
 		fatal:       true,
-		check: func() (err error) {
-			if hc.APIAddr != "" {
-				hc.apiClient, err = public.NewInternalClient(hc.ControlPlaneNamespace, hc.APIAddr)
-			} else {
-				hc.apiClient, err = public.NewExternalClient(hc.ControlPlaneNamespace, hc.kubeAPI)
+		check: func(ctx context.Context) (err error) {
+			// the connection is made once per run, and is then shared by the
+			// checks calling the public API
+			hc.ClosePublicAPIClient()
+			hc.apiConn, hc.apiClient = nil, nil
+
+			var conn *public.Conn
+			var pingErr error
+			switch {
+			case public.IsAPIURL(hc.APIAddr):
+				conn, err = public.NewExternalClientForURL(hc.ControlPlaneNamespace, hc.APIAddr, hc.APITLS)
+			case hc.APIAddr != "":
+				conn, err = public.NewInternalClientWithTLS(hc.ControlPlaneNamespace, hc.APIAddr, hc.APITLS)
+			default:
+				// the public API is reached with the client of the Kubernetes
+				// checks, so that its requests go through the same transport,
+				// recorded and rate limited alike
+				httpClient, clientErr := hc.kubeAPI.Client()
+				if clientErr != nil {
+					return clientErr
+				}
+
+				// a plain HTTP ping of the controller through the service
+				// proxy tells whether the proxy path, the service or gRPC is
+				// at fault if the client fails
+				pingErr = public.PingProxy(ctx, hc.ControlPlaneNamespace, hc.kubeAPI, hc.APIProxyPath, httpClient)
+				conn, err = public.NewExternalClientWithFallback(hc.runCtx, hc.ControlPlaneNamespace, hc.kubeAPI, hc.APIProxyPath, httpClient, hc.APITLS)
+				if err != nil {
+					// name the URL composed from the proxy path options, so
+					// that a misconfigured path can be told apart
+					if proxyURL, urlErr := public.ProxyURL(hc.ControlPlaneNamespace, hc.kubeAPI, hc.APIProxyPath); urlErr == nil {
+						err = fmt.Errorf("%s (public API proxy URL: %s)", err, proxyURL)
+					}
+					return withPingHint(err, pingErr)
+				}
 			}
-			return
+			if err != nil {
+				return
+			}
+			hc.apiConn, hc.apiClient = conn, conn
+			if hc.APICallRecorder != nil {
+				hc.apiClient = public.NewRecordingClient(conn, hc.APICallRecorder, hc.RecordAPIPayloads)
+			}
+			if hc.apiCircuit = hc.newAPICircuit(); hc.apiCircuit != nil {
+				hc.apiClient = &circuitBreakingClient{client: hc.apiClient, circuit: hc.apiCircuit}
+			}
+
+			// the connections made directly to the public API are checked,
+			// so that a failure is reported as a TCP, TLS or gRPC failure
+			if conn.Transport != public.KubernetesProxyTransport {
+				return public.CheckConnection(ctx, hc.apiClient)
+			}
+
+			// a failed ping tells whether the proxy path or the service is at
+			// fault, and else a failure of the API is one of gRPC itself
+			if ping, ok := pingErr.(*public.PingError); ok && ping.Reason != public.PingFailed {
+				return withPingHint(pingErr, pingErr)
+			}
+			if err := public.CheckConnection(ctx, hc.apiClient); err != nil {
+				if pingErr != nil {
+					return err
+				}
+				return &HintError{
+					Anchor: hintAnchorAPIGRPC,
+					Err:    fmt.Errorf("the controller answered a ping through the Kubernetes API server's service proxy, but its gRPC API failed: %s", err),
+				}
+			}
+			return nil
+		},
+		payload: func() interface{} {
+			if hc.apiConn == nil {
+				return nil
+			}
+			return map[string]interface{}{"transport": hc.apiConn.Transport}
 		},
+		kubeOptional: true,
 	})
 
 	hc.checkers = append(hc.checkers, &checker{
 		category:    LinkerdAPICategory,
 		description: "can query the control plane API",
 		fatal:       true,
-		checkRPC: func() (*healthcheckPb.SelfCheckResponse, error) {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			return hc.apiClient.SelfCheck(ctx, &healthcheckPb.SelfCheckRequest{})
+		checkStream: func(ctx context.Context) (*selfCheckStream, error) {
+			return hc.openSelfCheckStream(ctx)
 		},
+		kubeOptional: true,
 	})
 
 	hc.checkers = append(hc.checkers, &checker{
 		category:    LinkerdAPICategory,
-		description: "no invalid service profiles",
+		description: "can query the control plane version",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			return hc.checkControlPlaneVersion(ctx)
+		},
+		payload: func() interface{} {
+			if hc.controlPlaneVersion == nil {
+				return nil
+			}
+			return hc.controlPlaneVersion
+		},
+		kubeOptional: true,
+	})
+}
+
+func (hc *HealthChecker) addLinkerdControlPlaneChecks() {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "control plane resources have the expected labels",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkControlPlaneLabels(ctx)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "control plane storage is provisioned",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkControlPlaneClaims(ctx)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "controller has the permissions it needs",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkControllerPermissions(ctx)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "proxy injector responds quickly",
 		fatal:       false,
 		warning:     true,
-		check: func() error {
-			return hc.validateServiceProfiles()
+		check: func(ctx context.Context) error {
+			return hc.probeInjector(ctx)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "service profile CRD is established",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkCRDEstablished(ctx, serviceProfileCRDName)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "no invalid service profiles",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.validateServiceProfiles(ctx)
+		},
+		payload: func() interface{} {
+			return serviceProfilesPayload(hc.invalidServiceProfiles)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdAPICategory,
+		description: "no invalid traffic splits",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			splits, err := hc.getTrafficSplits(ctx)
+			if err != nil {
+				return err
+			}
+
+			services, err := hc.kubeAPI.GetServices(ctx, "", "")
+			if err != nil {
+				return err
+			}
+
+			return validateTrafficSplits(splits, services)
 		},
 	})
 }
@@ -334,8 +934,8 @@ func (hc *HealthChecker) addLinkerdDataPlaneChecks() {
 			category:    LinkerdDataPlaneCategory,
 			description: "data plane namespace exists",
 			fatal:       true,
-			check: func() error {
-				return hc.checkNamespace(hc.DataPlaneNamespace)
+			check: func(ctx context.Context) error {
+				return hc.checkNamespace(ctx, hc.DataPlaneNamespace)
 			},
 		})
 	}
@@ -345,8 +945,8 @@ func (hc *HealthChecker) addLinkerdDataPlaneChecks() {
 		description:   "data plane proxies are ready",
 		retryDeadline: hc.RetryDeadline,
 		fatal:         true,
-		check: func() error {
-			pods, err := hc.getDataPlanePods()
+		check: func(ctx context.Context) error {
+			pods, err := hc.getDataPlanePods(ctx)
 			if err != nil {
 				return err
 			}
@@ -360,8 +960,8 @@ func (hc *HealthChecker) addLinkerdDataPlaneChecks() {
 		description:   "data plane proxy metrics are present in Prometheus",
 		retryDeadline: hc.RetryDeadline,
 		fatal:         false,
-		check: func() error {
-			pods, err := hc.getDataPlanePods()
+		check: func(ctx context.Context) error {
+			pods, err := hc.getDataPlanePods(ctx)
 			if err != nil {
 				return err
 			}
@@ -369,86 +969,621 @@ func (hc *HealthChecker) addLinkerdDataPlaneChecks() {
 			return validateDataPlanePodReporting(pods)
 		},
 	})
-}
 
-func (hc *HealthChecker) addLinkerdVersionChecks() {
 	hc.checkers = append(hc.checkers, &checker{
-		category:    LinkerdVersionCategory,
-		description: "can determine the latest version",
-		fatal:       true,
-		check: func() (err error) {
-			if hc.VersionOverride != "" {
-				hc.latestVersion = hc.VersionOverride
-			} else {
-				// The UUID is only known to the web process. At some point we may want
-				// to consider providing it in the Public API.
-				uuid := "unknown"
-				for _, pod := range hc.controlPlanePods {
-					if strings.Split(pod.Name, "-")[0] == "web" {
-						for _, container := range pod.Spec.Containers {
-							if container.Name == "web" {
-								for _, arg := range container.Args {
-									if strings.HasPrefix(arg, "-uuid=") {
-										uuid = strings.TrimPrefix(arg, "-uuid=")
-									}
-								}
-							}
-						}
-					}
-				}
-				hc.latestVersion, err = version.GetLatestVersion(uuid, "cli")
+		category:    LinkerdDataPlaneCategory,
+		description: "pods in auto-inject namespaces are injected",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
 			}
-			return
+
+			policy, err := hc.getAutoInjectPolicy(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateAutoInjectedPods(pods, policy)
 		},
 	})
 
 	hc.checkers = append(hc.checkers, &checker{
-		category:    LinkerdVersionCategory,
-		description: "cli is up-to-date",
+		category:    LinkerdDataPlaneCategory,
+		description: "no injected pods in namespaces with auto-inject disabled",
 		fatal:       false,
-		check: func() error {
-			return version.CheckClientVersion(hc.latestVersion)
+		warning:     true,
+		check: func(ctx context.Context) error {
+			pods, namespaces, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateAutoInjectDisabledPods(pods, namespaces, hc.ControlPlaneNamespace)
 		},
 	})
 
-	if hc.ShouldCheckControlPlaneVersion {
-		hc.checkers = append(hc.checkers, &checker{
-			category:    LinkerdVersionCategory,
-			description: "control plane is up-to-date",
-			fatal:       false,
-			check: func() error {
-				return version.CheckServerVersion(hc.apiClient, hc.latestVersion)
-			},
-		})
-	}
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "auto-inject labels do not contradict their namespace",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			namespaces, err := hc.getNamespacesByName(ctx)
+			if err != nil {
+				return err
+			}
 
-	if hc.ShouldCheckDataPlaneVersion {
-		hc.checkers = append(hc.checkers, &checker{
-			category:    LinkerdVersionCategory,
-			description: "data plane is up-to-date",
-			fatal:       false,
-			check: func() error {
-				pods, err := hc.getDataPlanePods()
-				if err != nil {
-					return err
-				}
+			workloads, err := hc.getDataPlaneWorkloads(ctx)
+			if err != nil {
+				return err
+			}
 
-				for _, pod := range pods {
-					if pod.ProxyVersion != hc.latestVersion {
-						return fmt.Errorf("%s is running version %s but the latest version is %s",
-							pod.Name, pod.ProxyVersion, hc.latestVersion)
-					}
-				}
-				return nil
-			},
-		})
-	}
-}
+			return validateAutoInjectLabels(workloads.templates(), namespaces)
+		},
+	})
 
-// Add adds an arbitrary checker. This should only be used for testing. For
-// production code, pass in the desired set of checks when calling
-// NewHeathChecker.
-func (hc *HealthChecker) Add(category, description string, check func() error) {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "no injected pods use the host network",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			workloads, err := hc.getDataPlaneWorkloads(ctx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := hc.getAutoInjectPolicy(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateHostNetworkPods(pods, workloads.templates(), policy)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "data plane proxy certificates are not expired",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkSampledProxyMetrics(ctx, func(sampled []*proxyMetrics) error {
+				return validateProxyCertsNotExpired(sampled, time.Now())
+			})
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "data plane proxy certificates are not about to expire",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			return hc.checkSampledProxyMetrics(ctx, func(sampled []*proxyMetrics) error {
+				return validateProxyCertsNotExpiringSoon(sampled, time.Now(), hc.certExpiryWarningWindow())
+			})
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "data plane proxies can reach the control plane",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkSampledProxyMetrics(ctx, validateProxyControlPlaneConnectivity)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "data plane and control plane versions are compatible",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			pods, err := hc.getDataPlanePods(ctx)
+			if err != nil {
+				return err
+			}
+			hc.proxyVersions = proxyVersionsByNamespace(pods)
+
+			rpcCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			rsp, err := hc.apiClient.Version(rpcCtx, &pb.Empty{})
+			if err != nil {
+				return apiCallError(ctx, err)
+			}
+
+			return validateProxyVersionSkew(hc.proxyVersions, rsp.GetReleaseVersion())
+		},
+		payload: func() interface{} {
+			return hc.proxyVersions
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "data plane proxies report to an existing control plane",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			allPods, err := hc.getAllKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateNoOrphanedProxies(pods, controlPlaneNamespaces(allPods))
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "port annotations are valid",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			resources, err := hc.getAnnotatedResources(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validatePortAnnotations(resources)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "skipped inbound ports do not include proxy ports",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			resources, err := hc.getAnnotatedResources(ctx)
+			if err != nil {
+				return err
+			}
+
+			proxyPorts, err := hc.getProxyPortConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateInboundSkipPorts(resources, proxyPorts.list())
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "application ports do not collide with proxy ports",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			workloads, err := hc.getDataPlaneWorkloads(ctx)
+			if err != nil {
+				return err
+			}
+
+			policy, err := hc.getAutoInjectPolicy(ctx)
+			if err != nil {
+				return err
+			}
+
+			services, err := hc.getServices(ctx)
+			if err != nil {
+				return err
+			}
+
+			proxyPorts, err := hc.getProxyPortConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateProxyPortCollisions(pods, workloads.templates(), services, policy, hc.ControlPlaneNamespace, proxyPorts)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "service ports are not likely to be misclassified by protocol detection",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			pods, namespaces, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			services, err := hc.getServices(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateServicePortProtocols(services, namespaces, meshedNamespaces(pods, hc.ControlPlaneNamespace))
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "no pods are kept running by their proxy",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateNoProxyOnlyPods(pods, time.Now(), hc.stuckTerminatingTimeout())
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdDataPlaneCategory,
+		description: "proxy-init and proxy versions match",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			cniEnabled, err := hc.cniEnabled(ctx)
+			if err != nil {
+				return err
+			}
+			if cniEnabled {
+				return &SkipError{Reason: "CNI mode is enabled, so pods have no proxy-init container"}
+			}
+
+			pods, _, err := hc.getDataPlaneKubePods(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateProxyInitVersions(pods, hc.ControlPlaneNamespace)
+		},
+	})
+
+	if hc.DataPlaneNamespace != "" && hc.ProxyLogLines > 0 {
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdDataPlaneCategory,
+			description: "data plane proxy logs are free of known errors",
+			fatal:       false,
+			warning:     true,
+			check: func(ctx context.Context) error {
+				logs, err := hc.getSampledProxyLogs(ctx)
+				if err != nil {
+					return err
+				}
+
+				return validateProxyLogs(logs)
+			},
+		})
+	}
+}
+
+func (hc *HealthChecker) addLinkerdPreUpgradeChecks() {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdPreUpgradeCategory,
+		description: "control plane PodDisruptionBudgets allow a rolling upgrade",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			return hc.checkControlPlaneDisruptionBudgets(ctx)
+		},
+	})
+}
+
+func (hc *HealthChecker) addLinkerdVersionChecks() {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdVersionCategory,
+		description: "can determine the latest version",
+		fatal:       true,
+		check: func(ctx context.Context) (err error) {
+			hc.minimumSupportedVersions = nil
+			if hc.VersionOverride != "" {
+				hc.latestVersions = version.NewChannels(hc.VersionOverride)
+			} else {
+				// The UUID is only known to the web process. At some point we may want
+				// to consider providing it in the Public API.
+				uuid := "unknown"
+				for _, pod := range hc.controlPlanePods {
+					if strings.Split(pod.Name, "-")[0] == "web" {
+						for _, container := range pod.Spec.Containers {
+							if container.Name == "web" {
+								for _, arg := range container.Args {
+									if strings.HasPrefix(arg, "-uuid=") {
+										uuid = strings.TrimPrefix(arg, "-uuid=")
+									}
+								}
+							}
+						}
+					}
+				}
+				options := version.LatestVersionOptions{
+					Timeout:   hc.LatestVersionTimeout,
+					URL:       hc.LatestVersionURL,
+					CAFile:    hc.LatestVersionCAFile,
+					CacheTTL:  hc.LatestVersionCacheTTL,
+					Anonymous: hc.AnonymousVersionCheck,
+				}
+				hc.latestVersionRequest = version.LatestVersionRequestURL(uuid, "cli", options)
+				hc.staleLatestVersions = nil
+				var published version.PublishedVersions
+				published, err = version.GetPublishedVersions(ctx, uuid, "cli", options)
+				hc.latestVersions = published.Latest
+				hc.minimumSupportedVersions = published.MinimumSupported
+				// stale latest versions are still compared with, and reported
+				// by the following check
+				if version.IsStale(err) {
+					hc.staleLatestVersions = err
+					err = nil
+				}
+			}
+			return
+		},
+		payload: func() interface{} {
+			if hc.latestVersions == nil {
+				return nil
+			}
+			payload := map[string]interface{}{"latestVersions": hc.latestVersions}
+			if hc.minimumSupportedVersions != nil {
+				payload["minimumSupportedVersions"] = hc.minimumSupportedVersions
+			}
+			return payload
+		},
+		kubeOptional: true,
+	})
+
+	if hc.VersionOverride == "" {
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "latest version information is current",
+			fatal:       false,
+			warning:     true,
+			check: func(ctx context.Context) error {
+				return hc.staleLatestVersions
+			},
+			kubeOptional: true,
+		})
+	}
+
+	if hc.LatestVersionURL != "" && hc.VersionOverride == "" {
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "uses the default version endpoint",
+			fatal:       false,
+			warning:     true,
+			check: func(ctx context.Context) error {
+				return fmt.Errorf("the latest versions are served by %s rather than %s", hc.LatestVersionURL, version.DefaultLatestVersionURL)
+			},
+			kubeOptional: true,
+		})
+	}
+
+	var cliChannel string
+	var cliErr error
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdVersionCategory,
+		description: "cli is up-to-date",
+		fatal:       false,
+		warning:     true,
+		check: func(ctx context.Context) error {
+			cliChannel, cliErr = version.CheckClientVersion(hc.latestVersions)
+			return versionCheckError(cliErr)
+		},
+		payload: func() interface{} {
+			return versionPayload(cliChannel, cliErr)
+		},
+		kubeOptional: true,
+	})
+
+	if hc.ShouldCheckControlPlaneVersion {
+		var controlPlaneChannel string
+		var controlPlaneErr error
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "control plane is up-to-date",
+			fatal:       false,
+			warning:     true,
+			check: func(ctx context.Context) error {
+				components, err := hc.controlPlaneComponentVersions(ctx)
+				if err != nil {
+					return err
+				}
+
+				serverVersion, err := hc.serverVersion(ctx)
+				if err != nil {
+					return err
+				}
+
+				controlPlaneChannel, controlPlaneErr = version.MatchServerVersion(serverVersion, hc.latestVersions, components)
+				return versionCheckError(controlPlaneErr)
+			},
+			payload: func() interface{} {
+				return versionPayload(controlPlaneChannel, controlPlaneErr)
+			},
+		})
+
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "control plane version is supported",
+			fatal:       false,
+			check: func(ctx context.Context) error {
+				// without a published minimum, only the up-to-date warning
+				// above applies
+				if hc.minimumSupportedVersions == nil {
+					return nil
+				}
+
+				serverVersion, err := hc.serverVersion(ctx)
+				if err != nil {
+					return err
+				}
+				return version.CheckMinimumVersion(hc.minimumSupportedVersions, serverVersion)
+			},
+			kubeOptional: true,
+		})
+	}
+
+	if hc.ShouldCheckControlPlaneVersion {
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "cli and control plane are on the same release channel",
+			fatal:       false,
+			warning:     true,
+			check: func(ctx context.Context) error {
+				serverVersion, err := hc.serverVersion(ctx)
+				if err != nil {
+					return err
+				}
+
+				err = version.CheckChannels(version.Version, serverVersion)
+				if unreleased, ok := err.(*version.UnreleasedVersionError); ok {
+					return &SkipError{Reason: fmt.Sprintf("running development build %s, skipping release channel comparison", unreleased.Version)}
+				}
+				return err
+			},
+			kubeOptional: true,
+		})
+	}
+
+	if hc.ShouldCheckDataPlaneVersion {
+		var summaries []version.Summary
+		var dataPlaneErr error
+		hc.checkers = append(hc.checkers, &checker{
+			category:    LinkerdVersionCategory,
+			description: "data plane is up-to-date",
+			fatal:       false,
+			check: func(ctx context.Context) error {
+				pods, err := hc.getDataPlanePods(ctx)
+				if err != nil {
+					return err
+				}
+
+				observed := make(map[string]int)
+				for _, pod := range pods {
+					observed[pod.ProxyVersion]++
+				}
+				summaries, dataPlaneErr = hc.checkDataPlaneVersions(observed)
+				return dataPlaneErr
+			},
+			payload: func() interface{} {
+				if len(summaries) == 0 {
+					return nil
+				}
+				payload := map[string]interface{}{"versions": summaries}
+				if mismatch, ok := versionPayload("", dataPlaneErr).(map[string]interface{}); ok {
+					for k, v := range mismatch {
+						payload[k] = v
+					}
+				}
+				return payload
+			},
+		})
+	}
+}
+
+// checkDataPlaneVersions compares the observed proxy versions with the
+// expected version, if one is given, or else with the latest version of each
+// release channel the proxies run.
+func (hc *HealthChecker) checkDataPlaneVersions(observed map[string]int) ([]version.Summary, error) {
+	expected := []string{}
+	if hc.VersionOverride != "" {
+		expected = append(expected, hc.VersionOverride)
+	} else {
+		found := make(map[string]bool)
+		for v := range observed {
+			if channel := version.Channel(v); channel != "" {
+				found[channel] = true
+			}
+		}
+		channels := []string{}
+		for channel := range found {
+			channels = append(channels, channel)
+		}
+		sort.Strings(channels)
+		for _, channel := range channels {
+			if latest, ok := hc.latestVersions[channel]; ok {
+				expected = append(expected, latest)
+			}
+		}
+	}
+
+	summaries := []version.Summary{}
+	errs := []string{}
+	mismatches := []error{}
+	for _, v := range expected {
+		summary, err := version.CheckDataPlaneVersions(v, observed)
+		summaries = append(summaries, summary)
+		if err != nil {
+			errs = append(errs, err.Error())
+			mismatches = append(mismatches, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return summaries, nil
+	case 1:
+		return summaries, mismatches[0]
+	default:
+		return summaries, errors.New(strings.Join(errs, "; "))
+	}
+}
+
+// LatestVersionRequest returns the URL, including any identifying parameters,
+// the LinkerdVersionChecks request the latest versions from, or "" if they
+// haven't run. It is reported even if the latest versions were served from the
+// on-disk cache instead.
+func (hc *HealthChecker) LatestVersionRequest() string {
+	return hc.latestVersionRequest
+}
+
+// versionCheckError reports a development build as a skipped check, since
+// there is no latest version to compare it with, and a version ahead of the
+// latest one as informational.
+func versionCheckError(err error) error {
+	if unreleased, ok := err.(*version.UnreleasedVersionError); ok {
+		return &SkipError{Reason: fmt.Sprintf("running development build %s, skipping version comparison", unreleased.Version)}
+	}
+	if version.IsAheadOfLatest(err) {
+		return &SkipError{Reason: err.Error()}
+	}
+	return err
+}
+
+// versionPayload reports the release channel whose latest version a version
+// was compared with and, if they differ, both versions.
+func versionPayload(channel string, err error) interface{} {
+	payload := make(map[string]interface{})
+	if channel != "" {
+		payload["channel"] = channel
+	}
+	if mismatch, ok := err.(*version.VersionMismatchError); ok {
+		payload["component"] = mismatch.Component
+		payload["current"] = mismatch.Current
+		payload["latest"] = mismatch.Latest
+		payload["channel"] = mismatch.Channel
+	}
+
+	if len(payload) == 0 {
+		return nil
+	}
+	return payload
+}
+
+// Add adds an arbitrary checker. This should only be used for testing. For
+// production code, pass in the desired set of checks when calling
+// NewHeathChecker.
+func (hc *HealthChecker) Add(category, description string, check func(ctx context.Context) error) {
 	hc.checkers = append(hc.checkers, &checker{
 		category:    category,
 		description: description,
@@ -456,308 +1591,1668 @@ func (hc *HealthChecker) Add(category, description string, check func() error) {
 	})
 }
 
-// RunChecks runs all configured checkers, and passes the results of each
-// check to the observer. If a check fails and is marked as fatal, then all
-// remaining checks are skipped. If at least one check fails, RunChecks returns
-// false; if all checks passed, RunChecks returns true.  Checks which are
-// designated as warnings will not cause RunCheck to return false, however.
-func (hc *HealthChecker) RunChecks(observer checkObserver) bool {
-	success := true
+// RunChecks runs all configured checkers, and passes the results of each
+// check to the observer. If a check fails and is marked as fatal, then all
+// remaining checks are skipped. If at least one check fails, RunChecks returns
+// false; if all checks passed, RunChecks returns true.  Checks which are
+// designated as warnings will not cause RunCheck to return false, however.
+func (hc *HealthChecker) RunChecks(observer checkObserver) bool {
+	return hc.RunChecksContext(context.Background(), observer)
+}
+
+// RunChecksContext is RunChecks, with each check's requests canceled once the
+// given context is done. The remaining checks are then not run, and false is
+// returned. A port-forward the public API client reaches the API through runs
+// until the context is done.
+func (hc *HealthChecker) RunChecksContext(ctx context.Context, observer checkObserver) bool {
+	success := true
+	abortedExtensions := make(map[string]bool)
+	hc.runCtx = ctx
+	hc.apiCircuit.reset()
+
+	// the served APIs are discovered, and the cached responses fetched,
+	// again on each run
+	if hc.kubeAPI != nil {
+		hc.kubeAPI.ResetDiscovery()
+		hc.kubeAPI.ResetResponseCache()
+	}
+
+	// checks may append more checkers while running, so the length of
+	// hc.checkers is re-evaluated on each iteration
+	for i := 0; i < len(hc.checkers); i++ {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		checker := hc.checkers[i]
+		if abortedExtensions[checker.extension] {
+			continue
+		}
+
+		if hc.isSkipped(checker) {
+			observer(&CheckResult{
+				Category:    checker.category,
+				Description: checker.description,
+				Warning:     checker.warning,
+				Skipped:     true,
+				Err:         &SkipError{Reason: "Skipped by request"},
+			})
+			continue
+		}
+
+		// without a kubeconfig, only the checks of the public API reached at
+		// a URL, and of the versions, can run
+		if hc.kubeAPI == nil && hc.reachesAPIAtURL() && !checker.kubeOptional {
+			observer(&CheckResult{
+				Category:    checker.category,
+				Description: checker.description,
+				Warning:     checker.warning,
+				Skipped:     true,
+				Err:         &SkipError{Reason: "Skipped as no Kubernetes API is configured"},
+			})
+			continue
+		}
+
+		if checker.check != nil {
+			if !hc.runCheck(ctx, checker, observer) {
+				if !checker.warning {
+					success = false
+				}
+				if checker.fatal {
+					if checker.extension != "" {
+						abortedExtensions[checker.extension] = true
+						continue
+					}
+					break
+				}
+			}
+		}
+
+		if checker.checkStream != nil {
+			if !hc.runCheckStream(ctx, checker, observer) {
+				if !checker.warning {
+					success = false
+				}
+				if checker.fatal {
+					break
+				}
+			}
+		}
+	}
+
+	return success
+}
+
+// reachesAPIAtURL returns true if the public API is reached at the URL given
+// by the APIAddr option.
+func (hc *HealthChecker) reachesAPIAtURL() bool {
+	return hc.HealthCheckOptions != nil && public.IsAPIURL(hc.APIAddr)
+}
+
+// isSkipped returns true if the checker was excluded by the SkipChecks
+// option.
+func (hc *HealthChecker) isSkipped(c *checker) bool {
+	if hc.HealthCheckOptions == nil {
+		return false
+	}
+
+	label := fmt.Sprintf("%s: %s", c.category, c.description)
+	for _, skip := range hc.SkipChecks {
+		if skip == c.category || skip == label {
+			return true
+		}
+	}
+	return false
+}
+
+func (hc *HealthChecker) runCheck(ctx context.Context, c *checker, observer checkObserver) bool {
+	for {
+		err := c.runOnce(ctx)
+		checkResult := &CheckResult{
+			Category:    c.category,
+			Description: c.description,
+			Warning:     c.warning,
+			Err:         err,
+			HintAnchor:  hintAnchor(err),
+		}
+		if c.payload != nil {
+			checkResult.Payload = c.payload()
+		}
+
+		if _, ok := err.(*SkipError); ok {
+			checkResult.Skipped = true
+			observer(checkResult)
+			return true
+		}
+
+		if err != nil && time.Now().Before(c.retryDeadline) && ctx.Err() == nil {
+			checkResult.Retry = true
+			observer(checkResult)
+			c.waitToRetry(ctx)
+
+			// the retried check must see the resources' current state, and
+			// may probe the public API again
+			if hc.kubeAPI != nil {
+				hc.kubeAPI.ResetResponseCache()
+			}
+			hc.apiCircuit.halfOpen()
+			continue
+		}
+
+		observer(checkResult)
+		return err == nil
+	}
+}
+
+// runOnce runs the check with a context of its own, so that anything the
+// check leaves running, such as port-forwards, stops once it returns.
+func (c *checker) runOnce(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	return c.check(ctx)
+}
+
+// waitToRetry waits for the checker's retryWatch to report a change, if set,
+// or else for the retry window to elapse. It returns early once the context
+// is done.
+func (c *checker) waitToRetry(ctx context.Context) {
+	if c.retryWatch != nil {
+		start := time.Now()
+		deadline := start.Add(maxRetryWatch)
+		if c.retryDeadline.Before(deadline) {
+			deadline = c.retryDeadline
+		}
+		watchCtx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+
+		// fall back to the retry window if the resources cannot be watched
+		if err := c.retryWatch(watchCtx); err == nil || watchCtx.Err() != nil {
+			if elapsed := time.Since(start); elapsed < minRetryWatch {
+				sleep(ctx, minRetryWatch-elapsed)
+			}
+			return
+		}
+	}
+
+	sleep(ctx, retryWindow)
+}
+
+// sleep waits for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// KubeAPIClient returns the Kubernetes API the checks were run against, from
+// which commands can make clients and clientsets sharing its configuration.
+// It is only configured if the KubernetesAPIChecks are configured and run
+// first, and is nil if the client could not be initialized.
+func (hc *HealthChecker) KubeAPIClient() *k8s.KubernetesAPI {
+	return hc.kubeAPI
+}
+
+// PublicAPIClient returns a fully configured public API client. This client is
+// only configured if the KubernetesAPIChecks and LinkerdAPIChecks are
+// configured and run first. It is the connection the checks were run with,
+// which remains open for the caller to keep using, and may be released with
+// ClosePublicAPIClient; it is otherwise closed by the next run, or once the
+// run's context is done.
+func (hc *HealthChecker) PublicAPIClient() pb.ApiClient {
+	return hc.apiClient
+}
+
+// ClosePublicAPIClient closes the connection of the public API client, if
+// any. It is safe to call more than once.
+func (hc *HealthChecker) ClosePublicAPIClient() {
+	hc.apiConn.Close()
+}
+
+// APITransport returns the transport the public API client reaches the public
+// API with, i.e. public.KubernetesProxyTransport, public.PortForwardTransport
+// or public.DirectTransport, or "" if the client isn't configured.
+func (hc *HealthChecker) APITransport() string {
+	if hc.apiConn == nil {
+		return ""
+	}
+	return hc.apiConn.Transport
+}
+
+// The hint anchors of the failures to reach the public API through the
+// Kubernetes API server's service proxy.
+const (
+	hintAnchorAPIProxyPath           = "l5d-api-proxy-path"
+	hintAnchorAPIControllerListening = "l5d-api-controller-listening"
+	hintAnchorAPIGRPC                = "l5d-api-grpc"
+)
+
+// withPingHint returns the error of the public API client made through the
+// service proxy with the hint matching the reason the controller could not be
+// pinged for, or the error as is if the ping tells nothing of the failure.
+func withPingHint(err error, pingErr error) error {
+	ping, ok := pingErr.(*public.PingError)
+	if !ok {
+		return err
+	}
+
+	switch ping.Reason {
+	case public.PingProxyPathNotFound:
+		return &HintError{
+			Anchor: hintAnchorAPIProxyPath,
+			Err:    fmt.Errorf("%s; the Kubernetes API server found no service at the public API's proxy path, check its namespace, service and port names", err),
+		}
+	case public.PingControllerNotListening:
+		return &HintError{
+			Anchor: hintAnchorAPIControllerListening,
+			Err:    fmt.Errorf("%s; the controller is not listening, check that its pods are running and ready", err),
+		}
+	}
+	return err
+}
+
+// newAPICircuit returns the circuit of the calls made to the public API, or
+// nil if disabled by a negative APIFailureBudget.
+func (hc *HealthChecker) newAPICircuit() *apiCircuit {
+	budget := hc.APIFailureBudget
+	if budget < 0 {
+		return nil
+	}
+	if budget == 0 {
+		budget = defaultAPIFailureBudget
+	}
+	window := hc.APIFailureWindow
+	if window <= 0 {
+		window = defaultAPIFailureWindow
+	}
+	return newAPICircuit(budget, window, hc.RetryDeadline)
+}
+
+// selfCheck requests the control plane API's self-check, bounded by the
+// SelfCheckTimeout as well as by the run's context.
+func (hc *HealthChecker) selfCheck(ctx context.Context) (*healthcheckPb.SelfCheckResponse, error) {
+	timeout := hc.SelfCheckTimeout
+	if timeout <= 0 {
+		timeout = defaultSelfCheckTimeout
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	subsystems := hc.selfCheckSubsystems()
+	rsp, err := hc.apiClient.SelfCheck(rpcCtx, &healthcheckPb.SelfCheckRequest{Subsystems: subsystems})
+	if err != nil && ctx.Err() == nil && rpcCtx.Err() == context.DeadlineExceeded {
+		// the subsystems' results are all reported in the one response, so
+		// there are none to report for those that did respond
+		return nil, fmt.Errorf("the control plane API did not respond to the self-check within %s", timeout)
+	}
+	if err != nil || subsystems == nil {
+		return rsp, apiCallError(ctx, err)
+	}
+
+	// control planes predating subsystem filtering check all of them
+	results := []*healthcheckPb.CheckResult{}
+	for _, result := range rsp.Results {
+		if containsSubsystem(subsystems, result.SubsystemName) {
+			results = append(results, result)
+		}
+	}
+	rsp.Results = results
+	return rsp, nil
+}
+
+// selfCheckSubsystems returns the subsystems the self-check is requested to
+// run: the SelfCheckSubsystems, or all known subsystems, less those excluded
+// by SkipChecks. It returns nil, requesting all of them, if none are
+// filtered out.
+func (hc *HealthChecker) selfCheckSubsystems() []string {
+	if hc.HealthCheckOptions == nil {
+		return nil
+	}
+
+	subsystems := hc.SelfCheckSubsystems
+	if subsystems == nil {
+		subsystems = knownSelfCheckSubsystems
+	}
+
+	requested := []string{}
+	for _, subsystem := range subsystems {
+		if !hc.isSubsystemSkipped(subsystem) {
+			requested = append(requested, subsystem)
+		}
+	}
+	if hc.SelfCheckSubsystems == nil && len(requested) == len(subsystems) {
+		return nil
+	}
+	return requested
+}
+
+// isSubsystemSkipped returns true if the self-check results of the subsystem
+// were excluded by the SkipChecks option, either as a whole or by
+// description.
+func (hc *HealthChecker) isSubsystemSkipped(subsystem string) bool {
+	category := fmt.Sprintf("%s[%s]", LinkerdAPICategory, subsystem)
+	for _, skip := range hc.SkipChecks {
+		if skip == category || strings.HasPrefix(skip, category+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+// The sources the control plane's version is determined from.
+const (
+	versionSourcePublicAPI  = "public-api"
+	versionSourceDeployment = "controller-deployment"
+)
+
+// controlPlaneVersion is the control plane's version, and where it was
+// determined from.
+type controlPlaneVersion struct {
+	ReleaseVersion string `json:"releaseVersion"`
+	GoVersion      string `json:"goVersion,omitempty"`
+	BuildDate      string `json:"buildDate,omitempty"`
+	Source         string `json:"source"`
+}
+
+// checkControlPlaneVersion requests the control plane's version from the
+// public API, with a short timeout. If the request fails, the version is read
+// from the image tag of the controller Deployment instead, so that the version
+// checks can still compare it, and the check warns that this version is less
+// authoritative.
+func (hc *HealthChecker) checkControlPlaneVersion(ctx context.Context) error {
+	hc.controlPlaneVersion = nil
+
+	rpcCtx, cancel := context.WithTimeout(ctx, defaultVersionRPCTimeout)
+	defer cancel()
+
+	info, err := hc.apiClient.Version(rpcCtx, &pb.Empty{})
+	err = apiCallError(ctx, err)
+	if err == nil {
+		hc.controlPlaneVersion = &controlPlaneVersion{
+			ReleaseVersion: info.GetReleaseVersion(),
+			GoVersion:      info.GetGoVersion(),
+			BuildDate:      info.GetBuildDate(),
+			Source:         versionSourcePublicAPI,
+		}
+		return nil
+	}
+
+	deployed, deployedErr := hc.controllerDeploymentVersion(ctx)
+	if deployedErr != nil {
+		return fmt.Errorf("the control plane API failed to report its version, which could not be read from the controller Deployment either: %s; %s", err, deployedErr)
+	}
+	hc.controlPlaneVersion = &controlPlaneVersion{ReleaseVersion: deployed, Source: versionSourceDeployment}
+	return fmt.Errorf("the control plane API failed to report its version: %s; falling back to the controller Deployment's image tag %s, which may not be the version running", err, deployed)
+}
+
+// controllerDeploymentVersion returns the version of the controller
+// Deployment, as deployed.
+func (hc *HealthChecker) controllerDeploymentVersion(ctx context.Context) (string, error) {
+	if hc.kubeAPI == nil {
+		return "", fmt.Errorf("the Kubernetes API is not configured")
+	}
+	deployments, err := hc.kubeAPI.GetDeployments(ctx, hc.ControlPlaneNamespace, fmt.Sprintf("%s=controller", k8s.ControllerComponentLabel))
+	if err != nil {
+		return "", err
+	}
+	for _, d := range deployments {
+		if v := componentVersion(d); v != "" {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("the version of the controller Deployment cannot be determined")
+}
+
+// serverVersion returns the control plane's version, as determined by the
+// LinkerdAPIChecks, or else as reported by the public API.
+func (hc *HealthChecker) serverVersion(ctx context.Context) (string, error) {
+	if hc.controlPlaneVersion != nil {
+		return hc.controlPlaneVersion.ReleaseVersion, nil
+	}
+	return version.GetServerVersion(ctx, hc.apiClient)
+}
+
+// checkAPIServerHealth verifies that the API server reports itself healthy.
+// It is skipped if the health endpoints may not be read, as some managed
+// clusters restrict them.
+func (hc *HealthChecker) checkAPIServerHealth(ctx context.Context) error {
+	err := hc.kubeAPI.CheckAPIServerHealth(ctx)
+	if k8s.IsForbidden(err) {
+		return &SkipError{Reason: fmt.Sprintf("The health of the Kubernetes API server cannot be read: %s", err)}
+	}
+	return err
+}
+
+// checkNamespace verifies that the namespace exists and is not being deleted.
+func (hc *HealthChecker) checkNamespace(ctx context.Context, namespace string) error {
+	ns, err := hc.kubeAPI.GetNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return validateNamespace(namespace, ns)
+}
+
+// validateNamespace returns a NamespaceNotFoundError if ns is nil, or an
+// error if it is Terminating, as its resources are being deleted with it.
+func validateNamespace(name string, ns *v1.Namespace) error {
+	if ns == nil {
+		return &k8s.NamespaceNotFoundError{Namespace: name}
+	}
+	if ns.Status.Phase == v1.NamespaceTerminating {
+		return fmt.Errorf("The \"%s\" namespace is Terminating: it is being deleted along with its resources", name)
+	}
+	return nil
+}
+
+func (hc *HealthChecker) getDataPlanePods(ctx context.Context) ([]*pb.Pod, error) {
+	req := &pb.ListPodsRequest{}
+	if hc.DataPlaneNamespace != "" {
+		req.Namespace = hc.DataPlaneNamespace
+	}
+
+	resp, err := hc.apiClient.ListPods(ctx, req)
+	if err != nil {
+		return nil, apiCallError(ctx, err)
+	}
+
+	pods := make([]*pb.Pod, 0)
+	for _, pod := range resp.GetPods() {
+		if pod.ControllerNamespace == hc.ControlPlaneNamespace {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+func (hc *HealthChecker) certExpiryWarningWindow() time.Duration {
+	if hc.CertExpiryWarningWindow > 0 {
+		return hc.CertExpiryWarningWindow
+	}
+	return defaultCertExpiryWarningWindow
+}
+
+func (hc *HealthChecker) stuckTerminatingTimeout() time.Duration {
+	if hc.StuckTerminatingTimeout > 0 {
+		return hc.StuckTerminatingTimeout
+	}
+	return defaultStuckTerminatingTimeout
+}
+
+// getDataPlaneKubePods returns the Kubernetes pods in the data plane namespace
+// (or in all namespaces, if no data plane namespace is configured), along with
+// the namespaces those pods belong to, indexed by name. The results are cached
+// for the remainder of the check run.
+func (hc *HealthChecker) getDataPlaneKubePods(ctx context.Context) ([]v1.Pod, map[string]v1.Namespace, error) {
+	if hc.dataPlaneKubePods == nil {
+		var pods []v1.Pod
+		var err error
+		if hc.DataPlaneNamespace != "" {
+			pods, err = hc.kubeAPI.GetPodsByNamespace(ctx, hc.DataPlaneNamespace, "")
+		} else {
+			pods, err = hc.getAllKubePods(ctx)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		hc.dataPlaneKubePods = pods
+	}
+
+	namespaces, err := hc.getNamespacesByName(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hc.dataPlaneKubePods, namespaces, nil
+}
+
+// getAllKubePods returns the Kubernetes pods in all namespaces. The results
+// are cached for the remainder of the check run.
+func (hc *HealthChecker) getAllKubePods(ctx context.Context) ([]v1.Pod, error) {
+	if hc.allKubePods == nil {
+		pods, err := hc.kubeAPI.GetAllPods(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hc.allKubePods = pods
+	}
+	return hc.allKubePods, nil
+}
+
+// getNamespacesByName returns all namespaces, indexed by name. The results are
+// cached for the remainder of the check run.
+func (hc *HealthChecker) getNamespacesByName(ctx context.Context) (map[string]v1.Namespace, error) {
+	if hc.dataPlaneNamespaces == nil {
+		namespaceList, err := hc.kubeAPI.GetNamespaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces := make(map[string]v1.Namespace)
+		for _, ns := range namespaceList {
+			namespaces[ns.Name] = ns
+		}
+		hc.dataPlaneNamespaces = namespaces
+	}
+	return hc.dataPlaneNamespaces, nil
+}
+
+// dataPlaneWorkloads holds the workloads in the data plane namespace, or in
+// all namespaces if no data plane namespace is configured.
+type dataPlaneWorkloads struct {
+	deployments  []appsV1.Deployment
+	daemonSets   []appsV1.DaemonSet
+	statefulSets []appsV1.StatefulSet
+}
+
+// getDataPlaneWorkloads returns the workloads in the data plane namespace, or
+// in all namespaces if no data plane namespace is configured. The results are
+// cached for the remainder of the check run.
+func (hc *HealthChecker) getDataPlaneWorkloads(ctx context.Context) (*dataPlaneWorkloads, error) {
+	if hc.dataPlaneWorkloads != nil {
+		return hc.dataPlaneWorkloads, nil
+	}
+
+	deployments, err := hc.kubeAPI.GetDeployments(ctx, hc.DataPlaneNamespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	daemonSets, err := hc.kubeAPI.GetDaemonSets(ctx, hc.DataPlaneNamespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := hc.kubeAPI.GetStatefulSets(ctx, hc.DataPlaneNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	hc.dataPlaneWorkloads = &dataPlaneWorkloads{
+		deployments:  deployments,
+		daemonSets:   daemonSets,
+		statefulSets: statefulSets,
+	}
+	return hc.dataPlaneWorkloads, nil
+}
+
+// workloadTemplate is the pod template of a workload.
+type workloadTemplate struct {
+	kind      string
+	namespace string
+	name      string
+	template  v1.PodTemplateSpec
+}
+
+func (w workloadTemplate) String() string {
+	return fmt.Sprintf("%s %s/%s", w.kind, w.namespace, w.name)
+}
+
+// owns returns true if the pod was created by the workload. The pods of a
+// Deployment are owned by one of its ReplicaSets, which are named after it.
+func (w workloadTemplate) owns(pod v1.Pod) bool {
+	if pod.Namespace != w.namespace {
+		return false
+	}
+	for _, ref := range pod.OwnerReferences {
+		switch w.kind {
+		case "deployment":
+			if ref.Kind == "ReplicaSet" && strings.HasPrefix(ref.Name, w.name+"-") {
+				return true
+			}
+		case "daemonset":
+			if ref.Kind == "DaemonSet" && ref.Name == w.name {
+				return true
+			}
+		case "statefulset":
+			if ref.Kind == "StatefulSet" && ref.Name == w.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// templates returns the pod templates of the Deployments, DaemonSets and
+// StatefulSets, in that order.
+func (w *dataPlaneWorkloads) templates() []workloadTemplate {
+	templates := []workloadTemplate{}
+	for _, d := range w.deployments {
+		templates = append(templates, workloadTemplate{"deployment", d.Namespace, d.Name, d.Spec.Template})
+	}
+	for _, ds := range w.daemonSets {
+		templates = append(templates, workloadTemplate{"daemonset", ds.Namespace, ds.Name, ds.Spec.Template})
+	}
+	for _, ss := range w.statefulSets {
+		templates = append(templates, workloadTemplate{"statefulset", ss.Namespace, ss.Name, ss.Spec.Template})
+	}
+	return templates
+}
+
+// autoInjectPolicy determines which workloads the proxy injector injects.
+type autoInjectPolicy struct {
+	namespaces map[string]v1.Namespace
+
+	// injectorInstalled is true if the proxy injector's webhook is configured
+	injectorInstalled bool
+}
+
+// injects returns true if pods with the given labels are meant to be injected.
+// Workloads labeled as enabled, directly or through their namespace, ask for
+// it. Once the proxy injector is installed, it also injects the Deployments
+// of all namespaces not labeled as disabled, as its webhook's
+// namespaceSelector does not require the namespace to be labeled as enabled.
+func (p autoInjectPolicy) injects(namespace string, labels map[string]string, deployment bool) bool {
+	nsLabels := p.namespaces[namespace].Labels
+	if setting, _ := k8s.AutoInjectSetting(nsLabels, labels); setting == k8s.ProxyAutoInjectEnabled {
+		return true
+	}
+	return deployment && p.injectorInstalled && k8s.AutoInjected(nsLabels, labels)
+}
+
+// injectsPod returns true if the pod is meant to be injected. Pods created
+// by a Deployment are owned by one of its ReplicaSets.
+func (p autoInjectPolicy) injectsPod(pod v1.Pod) bool {
+	return p.injects(pod.Namespace, pod.Labels, isOwnedByKind(pod, "ReplicaSet"))
+}
+
+// injectsTemplate returns true if the workload's pods are meant to be
+// injected once created. The proxy injector's webhook only mutates
+// Deployments.
+func (p autoInjectPolicy) injectsTemplate(w workloadTemplate) bool {
+	return w.kind == "deployment" && p.injects(w.namespace, w.template.Labels, true)
+}
+
+// getAutoInjectPolicy returns the policy the proxy injector applies to the
+// data plane workloads.
+func (hc *HealthChecker) getAutoInjectPolicy(ctx context.Context) (autoInjectPolicy, error) {
+	namespaces, err := hc.getNamespacesByName(ctx)
+	if err != nil {
+		return autoInjectPolicy{}, err
+	}
+
+	if hc.injectorInstalled == nil {
+		_, found, err := hc.getInjectorWebhookTimeout(ctx)
+		if err != nil {
+			return autoInjectPolicy{}, err
+		}
+		hc.injectorInstalled = &found
+	}
+
+	return autoInjectPolicy{namespaces: namespaces, injectorInstalled: *hc.injectorInstalled}, nil
+}
+
+// getServices returns the Services in all namespaces. The results are cached
+// for the remainder of the check run.
+func (hc *HealthChecker) getServices(ctx context.Context) ([]v1.Service, error) {
+	if hc.services == nil {
+		services, err := hc.kubeAPI.GetServices(ctx, "", "")
+		if err != nil {
+			return nil, err
+		}
+		hc.services = services
+	}
+	return hc.services, nil
+}
+
+// getLinkerdConfig returns the parsed linkerd-config ConfigMap of the control
+// plane namespace, or nil if it does not exist. It is fetched and parsed once,
+// and the result, or the parse error, is shared by the remainder of the check
+// run.
+func (hc *HealthChecker) getLinkerdConfig(ctx context.Context) (*config.LinkerdConfig, error) {
+	if hc.linkerdConfigRead {
+		return hc.linkerdConfig, hc.linkerdConfigErr
+	}
+
+	configMap, err := hc.kubeAPI.GetConfigMap(ctx, hc.ControlPlaneNamespace, config.LinkerdConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+
+	hc.linkerdConfigRead = true
+	if configMap != nil {
+		hc.linkerdConfig, hc.linkerdConfigErr = config.ParseLinkerdConfig(configMap.Data)
+	}
+	return hc.linkerdConfig, hc.linkerdConfigErr
+}
+
+// annotatedResource is a namespace or workload whose annotations configure
+// the proxies injected into its pods.
+type annotatedResource struct {
+	kind        string
+	namespace   string
+	name        string
+	annotations map[string]string
+}
+
+func (r annotatedResource) String() string {
+	if r.kind == "namespace" {
+		return fmt.Sprintf("namespace/%s", r.name)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.namespace, r.kind, r.name)
+}
+
+// getAnnotatedResources returns the namespaces and the pod templates of the
+// workloads in the data plane namespace, or in all namespaces if no data plane
+// namespace is configured.
+func (hc *HealthChecker) getAnnotatedResources(ctx context.Context) ([]annotatedResource, error) {
+	resources := []annotatedResource{}
+
+	namespaces, err := hc.getNamespacesByName(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if hc.DataPlaneNamespace == "" || name == hc.DataPlaneNamespace {
+			resources = append(resources, annotatedResource{"namespace", name, name, namespaces[name].Annotations})
+		}
+	}
+
+	workloads, err := hc.getDataPlaneWorkloads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workloads.templates() {
+		resources = append(resources, annotatedResource{w.kind, w.namespace, w.name, w.template.Annotations})
+	}
+
+	return resources, nil
+}
+
+func (hc *HealthChecker) checkCanCreate(ctx context.Context, namespace, group, version, resource string) error {
+	allowed, reason, err := hc.kubeAPI.CheckAccess(ctx, authorizationapi.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "create",
+		Group:     group,
+		Version:   version,
+		Resource:  resource,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		if len(reason) > 0 {
+			return fmt.Errorf("Missing permissions to create %s: %v", resource, reason)
+		}
+		return fmt.Errorf("Missing permissions to create %s", resource)
+	}
+	return nil
+}
+
+// validateServiceProfiles validates the ServiceProfiles of the control plane
+// namespace, and that the services they apply to exist. Its requests are sent
+// with the clientsets' REST clients, so that they are canceled once the
+// context is done, since the typed clients' requests take no context.
+func (hc *HealthChecker) validateServiceProfiles(ctx context.Context) error {
+	if err := hc.initClientset(); err != nil {
+		return err
+	}
+
+	if hc.spClientset == nil {
+		var err error
+		hc.spClientset, err = hc.kubeAPI.NewServiceProfileClientset()
+		if err != nil {
+			return err
+		}
+	}
+
+	invalid := []invalidServiceProfile{}
+	opts := meta_v1.ListOptions{Limit: serviceProfilesPageSize}
+	for {
+		svcProfiles := &spv1alpha1.ServiceProfileList{}
+		err := hc.spClientset.LinkerdV1alpha1().RESTClient().Get().
+			Namespace(hc.ControlPlaneNamespace).
+			Resource("serviceprofiles").
+			VersionedParams(&opts, spscheme.ParameterCodec).
+			Context(ctx).
+			Do().
+			Into(svcProfiles)
+		if err != nil {
+			return err
+		}
+
+		for i := range svcProfiles.Items {
+			p := &svcProfiles.Items[i]
+			reasons := []string{}
+			for _, err := range profiles.ValidateServiceProfile(p) {
+				reasons = append(reasons, err.Error())
+			}
+
+			if service, namespace, ok := profiles.ServiceProfileService(p.Name); ok {
+				err := hc.clientset.CoreV1().RESTClient().Get().
+					Namespace(namespace).
+					Resource("services").
+					Name(service).
+					Context(ctx).
+					Do().
+					Error()
+				if kerrors.IsNotFound(err) {
+					reasons = append(reasons, fmt.Sprintf("metadata.name: unknown service: %s", err))
+				} else if err != nil {
+					return err
+				}
+			}
+
+			if len(reasons) > 0 {
+				invalid = append(invalid, invalidServiceProfile{
+					Name:    fmt.Sprintf("%s/%s", p.Namespace, p.Name),
+					Reasons: reasons,
+				})
+			}
+		}
+
+		if svcProfiles.Continue == "" {
+			break
+		}
+		opts.Continue = svcProfiles.Continue
+	}
+
+	hc.invalidServiceProfiles = invalid
+	return validateServiceProfileReasons(invalid)
+}
+
+// checkCRDEstablished verifies that the named CustomResourceDefinition exists
+// and that its resources are being served.
+func (hc *HealthChecker) checkCRDEstablished(ctx context.Context, name string) error {
+	crd, err := hc.kubeAPI.GetCRD(ctx, name)
+	if err != nil {
+		return err
+	}
+	if established, reason := k8s.CRDIsEstablished(crd); !established {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// invalidServiceProfile describes the problems found in a ServiceProfile.
+type invalidServiceProfile struct {
+	Name    string   `json:"name"`
+	Reasons []string `json:"reasons"`
+}
+
+func validateServiceProfileReasons(invalid []invalidServiceProfile) error {
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	details := []string{}
+	for _, p := range invalid {
+		details = append(details, fmt.Sprintf("%s (%s)", p.Name, strings.Join(p.Reasons, "; ")))
+	}
+	return fmt.Errorf("Some ServiceProfiles are invalid: %s", strings.Join(details, ", "))
+}
+
+// serviceProfilesPayload caps the number of invalid ServiceProfiles detailed
+// in the check's payload.
+func serviceProfilesPayload(invalid []invalidServiceProfile) interface{} {
+	if len(invalid) == 0 {
+		return nil
+	}
+
+	payload := map[string]interface{}{"invalidCount": len(invalid)}
+	if len(invalid) > maxServiceProfileDetails {
+		invalid = invalid[:maxServiceProfileDetails]
+	}
+	payload["invalid"] = invalid
+	return payload
+}
+
+func validateControlPlanePods(pods []v1.Pod) error {
+	statuses := make(map[string][]v1.ContainerStatus)
+
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning {
+			name := strings.Split(pod.Name, "-")[0]
+			if _, found := statuses[name]; !found {
+				statuses[name] = make([]v1.ContainerStatus, 0)
+			}
+			statuses[name] = append(statuses[name], pod.Status.ContainerStatuses...)
+		}
+	}
+
+	names := []string{"controller", "grafana", "prometheus", "web"}
+	if _, found := statuses["ca"]; found {
+		names = append(names, "ca")
+	}
+
+	for _, name := range names {
+		containers, found := statuses[name]
+		if !found {
+			return fmt.Errorf("No running pods for \"%s\"", name)
+		}
+		for _, container := range containers {
+			if !container.Ready {
+				return fmt.Errorf("The \"%s\" pod's \"%s\" container is not ready", name,
+					container.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getPendingPodEvents returns the Events of the Pending pods, keyed by pod
+// name. Pods whose Events cannot be read are left out, as the Events only
+// explain failures.
+func (hc *HealthChecker) getPendingPodEvents(ctx context.Context, pods []v1.Pod) map[string][]v1.Event {
+	events := make(map[string][]v1.Event)
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodPending {
+			continue
+		}
+		if podEvents, err := hc.kubeAPI.GetEventsFor(ctx, pod.Namespace, "Pod", pod.Name); err == nil {
+			events[pod.Name] = podEvents
+		}
+	}
+	return events
+}
+
+// withSchedulingFailures appends to the error the message of the latest
+// FailedScheduling event of each Pending pod. events is keyed by pod name and
+// sorted newest first.
+func withSchedulingFailures(err error, pods []v1.Pod, events map[string][]v1.Event) error {
+	failures := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodPending {
+			continue
+		}
+		for _, event := range events[pod.Name] {
+			if event.Reason == "FailedScheduling" {
+				failures = append(failures, fmt.Sprintf("%s is Pending: %s", pod.Name, event.Message))
+				break
+			}
+		}
+	}
+
+	if len(failures) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s (%s)", err, strings.Join(failures, "; "))
+}
+
+// waitForPodChange returns once a pod in the namespace is added, updated or
+// deleted, compared to the given pods, or the context is done. It returns an
+// error if there are no pods to compare against.
+func (hc *HealthChecker) waitForPodChange(ctx context.Context, namespace string, pods []v1.Pod) error {
+	if len(pods) == 0 {
+		return fmt.Errorf("No pods to watch for changes in the \"%s\" namespace", namespace)
+	}
+
+	events, err := hc.kubeAPI.Watch(ctx, k8s.PodsPath(namespace), "")
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]string)
+	for _, pod := range pods {
+		known[pod.Name] = pod.ResourceVersion
+	}
+
+	// pods deleted before the watch was established produce no event, so
+	// the pods are listed again once it is
+	current, err := hc.kubeAPI.GetPodsByNamespace(ctx, namespace, "")
+	if err != nil {
+		return err
+	}
+	if podsChanged(current, known) {
+		return nil
+	}
+
+	for event := range events {
+		changed, err := podChanged(event, known)
+		if err != nil || changed {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// podsChanged returns true if the pods differ from the known pods, keyed by
+// name to resource version.
+func podsChanged(pods []v1.Pod, known map[string]string) bool {
+	if len(pods) != len(known) {
+		return true
+	}
+	for _, pod := range pods {
+		if version, ok := known[pod.Name]; !ok || version != pod.ResourceVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// podChanged returns true if the watch event is not merely the initial
+// listing of one of the known pods, keyed by name to resource version.
+func podChanged(event k8s.WatchEvent, known map[string]string) (bool, error) {
+	if event.Type == watch.Error {
+		var status meta_v1.Status
+		if err := json.Unmarshal(event.Object, &status); err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("Failed to watch pods: %s", status.Message)
+	}
+	if event.Type != watch.Added {
+		return true, nil
+	}
+
+	var pod v1.Pod
+	if err := json.Unmarshal(event.Object, &pod); err != nil {
+		return false, err
+	}
+	version, ok := known[pod.Name]
+	return !ok || version != pod.ResourceVersion, nil
+}
+
+func validateDataPlanePods(pods []*pb.Pod, targetNamespace string) error {
+	if len(pods) == 0 {
+		msg := fmt.Sprintf("No \"%s\" containers found", k8s.ProxyContainerName)
+		if targetNamespace != "" {
+			msg += fmt.Sprintf(" in the \"%s\" namespace", targetNamespace)
+		}
+		return fmt.Errorf(msg)
+	}
+
+	for _, pod := range pods {
+		if pod.Status != "Running" {
+			return fmt.Errorf("The \"%s\" pod is not running",
+				pod.Name)
+		}
+
+		if !pod.ProxyReady {
+			return fmt.Errorf("The \"%s\" container in the \"%s\" pod is not ready",
+				k8s.ProxyContainerName, pod.Name)
+		}
+	}
+
+	return nil
+}
+
+func validateDataPlanePodReporting(pods []*pb.Pod) error {
+	notInPrometheus := []string{}
+
+	for _, p := range pods {
+		// the `Added` field indicates the pod was found in Prometheus
+		if !p.Added {
+			notInPrometheus = append(notInPrometheus, p.Name)
+		}
+	}
+
+	errMsg := ""
+	if len(notInPrometheus) > 0 {
+		errMsg = fmt.Sprintf("Data plane metrics not found for %s.", strings.Join(notInPrometheus, ", "))
+	}
+
+	if errMsg != "" {
+		return fmt.Errorf(errMsg)
+	}
 
-	for _, checker := range hc.checkers {
-		if checker.check != nil {
-			if !hc.runCheck(checker, observer) {
-				if !checker.warning {
-					success = false
-				}
-				if checker.fatal {
-					break
-				}
-			}
+	return nil
+}
+
+// validateAutoInjectLabels returns an error listing the workloads whose pod
+// template auto-inject label contradicts the label on their namespace, grouped
+// by namespace, along with the effective outcome and which label wins.
+func validateAutoInjectLabels(workloads []workloadTemplate, namespaces map[string]v1.Namespace) error {
+	contradictions := make(map[string][]string)
+	namespaceNames := []string{}
+
+	for _, w := range workloads {
+		nsLabels := namespaces[w.namespace].Labels
+		nsSetting := nsLabels[k8s.ProxyAutoInjectLabel]
+		workloadSetting := w.template.Labels[k8s.ProxyAutoInjectLabel]
+
+		// "completed" marks an already injected template, which doesn't
+		// contradict anything
+		if !isAutoInjectToggle(nsSetting) || !isAutoInjectToggle(workloadSetting) || nsSetting == workloadSetting {
+			continue
 		}
 
-		if checker.checkRPC != nil {
-			if !hc.runCheckRPC(checker, observer) {
-				if !checker.warning {
-					success = false
-				}
-				if checker.fatal {
-					break
-				}
-			}
+		_, source := k8s.AutoInjectSetting(nsLabels, w.template.Labels)
+		outcome := fmt.Sprintf("not injected, the %s label wins", source)
+		if w.kind != "deployment" {
+			outcome = "not injected, only Deployments are auto-injected"
+		} else if k8s.AutoInjected(nsLabels, w.template.Labels) {
+			outcome = fmt.Sprintf("injected, the %s label wins", source)
+		}
+
+		if _, ok := contradictions[w.namespace]; !ok {
+			namespaceNames = append(namespaceNames, w.namespace)
 		}
+		contradictions[w.namespace] = append(contradictions[w.namespace],
+			fmt.Sprintf("%s/%s is %s (%s)", w.kind, w.name, workloadSetting, outcome))
 	}
 
-	return success
+	if len(namespaceNames) == 0 {
+		return nil
+	}
+
+	sort.Strings(namespaceNames)
+	groups := []string{}
+	for _, ns := range namespaceNames {
+		groups = append(groups, fmt.Sprintf("namespace %s is %s: %s",
+			ns, namespaces[ns].Labels[k8s.ProxyAutoInjectLabel], strings.Join(contradictions[ns], ", ")))
+	}
+
+	return fmt.Errorf("Some workloads have auto-inject labels that contradict their namespace; %s", strings.Join(groups, "; "))
 }
 
-func (hc *HealthChecker) runCheck(c *checker, observer checkObserver) bool {
-	for {
-		err := c.check()
-		checkResult := &CheckResult{
-			Category:    c.category,
-			Description: c.description,
-			Warning:     c.warning,
-			Err:         err,
+// validateHostNetworkPods returns an error listing the workloads of all kinds
+// whose pod template uses the host network and has a proxy, or will be
+// injected the next time their pods are created, along with the other
+// injected pods that use the host network.
+func validateHostNetworkPods(pods []v1.Pod, workloads []workloadTemplate, policy autoInjectPolicy) error {
+	reported := []workloadTemplate{}
+	offendingWorkloads := []string{}
+
+	for _, w := range workloads {
+		if !w.template.Spec.HostNetwork {
+			continue
 		}
+		if k8s.GetProxyContainer(&w.template.Spec) != nil {
+			reported = append(reported, w)
+			offendingWorkloads = append(offendingWorkloads, w.String())
+		} else if policy.injectsTemplate(w) {
+			reported = append(reported, w)
+			offendingWorkloads = append(offendingWorkloads, fmt.Sprintf("%s (not yet injected)", w))
+		}
+	}
 
-		if err != nil && time.Now().Before(c.retryDeadline) {
-			checkResult.Retry = true
-			observer(checkResult)
-			time.Sleep(retryWindow)
+	offenders := []string{}
+	for _, pod := range pods {
+		if !isActivePod(pod) || !pod.Spec.HostNetwork || !hasProxyContainer(pod) {
 			continue
 		}
+		// pods of a reported workload are covered by it
+		if !ownedByAny(pod, reported) {
+			offenders = append(offenders, fmt.Sprintf("pod %s/%s", pod.Namespace, pod.Name))
+		}
+	}
+	offenders = append(offenders, offendingWorkloads...)
 
-		observer(checkResult)
-		return err == nil
+	if len(offenders) > 0 {
+		return fmt.Errorf("Some injected workloads use the host network, so their proxy rewrites the node's iptables rules and may break node networking: %s; label their pod templates with %s: %s to exclude them",
+			strings.Join(offenders, ", "), k8s.ProxyAutoInjectLabel, k8s.ProxyAutoInjectDisabled)
 	}
+
+	return nil
 }
 
-func (hc *HealthChecker) runCheckRPC(c *checker, observer checkObserver) bool {
-	checkRsp, err := c.checkRPC()
-	observer(&CheckResult{
-		Category:    c.category,
-		Description: c.description,
-		Warning:     c.warning,
-		Err:         err,
-	})
-	if err != nil {
-		return false
+func ownedByAny(pod v1.Pod, workloads []workloadTemplate) bool {
+	for _, w := range workloads {
+		if w.owns(pod) {
+			return true
+		}
 	}
+	return false
+}
 
-	for _, check := range checkRsp.Results {
-		var err error
-		if check.Status != healthcheckPb.CheckStatus_OK {
-			err = fmt.Errorf(check.FriendlyMessageToUser)
+func isAutoInjectToggle(setting string) bool {
+	return setting == k8s.ProxyAutoInjectEnabled || setting == k8s.ProxyAutoInjectDisabled
+}
+
+func hasProxyContainer(pod v1.Pod) bool {
+	return k8s.GetProxyContainer(&pod.Spec) != nil
+}
+
+func isActivePod(pod v1.Pod) bool {
+	return pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed
+}
+
+// validateAutoInjectedPods returns an error listing the active pods that are
+// meant to be injected according to the auto-inject policy, but have no proxy.
+func validateAutoInjectedPods(pods []v1.Pod, policy autoInjectPolicy) error {
+	notInjected := []string{}
+
+	for _, pod := range pods {
+		if !isActivePod(pod) || hasProxyContainer(pod) {
+			continue
 		}
-		observer(&CheckResult{
-			Category:    fmt.Sprintf("%s[%s]", c.category, check.SubsystemName),
-			Description: check.CheckDescription,
-			Warning:     c.warning,
-			Err:         err,
-		})
-		if err != nil {
-			return false
+		if policy.injectsPod(pod) {
+			notInjected = append(notInjected, fmt.Sprintf("%s/%s (created %s)",
+				pod.Namespace, pod.Name, pod.CreationTimestamp.UTC().Format(time.RFC3339)))
 		}
 	}
 
-	return true
-}
+	if len(notInjected) > 0 {
+		return fmt.Errorf("Pods with auto-inject enabled are missing the \"%s\" container: %s",
+			k8s.ProxyContainerName, strings.Join(notInjected, ", "))
+	}
 
-// PublicAPIClient returns a fully configured public API client. This client is
-// only configured if the KubernetesAPIChecks and LinkerdAPIChecks are
-// configured and run first.
-func (hc *HealthChecker) PublicAPIClient() pb.ApiClient {
-	return hc.apiClient
+	return nil
 }
 
-func (hc *HealthChecker) checkNamespace(namespace string) error {
-	exists, err := hc.kubeAPI.NamespaceExists(hc.httpClient, namespace)
-	if err != nil {
-		return err
+func validateAutoInjectDisabledPods(pods []v1.Pod, namespaces map[string]v1.Namespace, controlPlaneNamespace string) error {
+	injected := []string{}
+
+	for _, pod := range pods {
+		// the control plane namespace is labeled as disabled so that the control
+		// plane components are not injected by the webhook
+		if pod.Namespace == controlPlaneNamespace || !isActivePod(pod) || !hasProxyContainer(pod) {
+			continue
+		}
+		if namespaces[pod.Namespace].Labels[k8s.ProxyAutoInjectLabel] == k8s.ProxyAutoInjectDisabled {
+			injected = append(injected, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
 	}
-	if !exists {
-		return fmt.Errorf("The \"%s\" namespace does not exist", namespace)
+
+	if len(injected) > 0 {
+		return fmt.Errorf("Pods in namespaces with auto-inject disabled have the \"%s\" container: %s",
+			k8s.ProxyContainerName, strings.Join(injected, ", "))
 	}
+
 	return nil
 }
 
-func (hc *HealthChecker) getDataPlanePods() ([]*pb.Pod, error) {
-	req := &pb.ListPodsRequest{}
-	if hc.DataPlaneNamespace != "" {
-		req.Namespace = hc.DataPlaneNamespace
+// proxyCertExpiry returns the certificate expiry time reported by a proxy.
+// Proxies which don't report a certificate expiry (e.g. because TLS is not
+// enabled) return false.
+func proxyCertExpiry(proxy *proxyMetrics) (time.Time, bool) {
+	expiry, ok := gaugeValue(proxy.metrics, certExpiryMetricName)
+	if !ok {
+		return time.Time{}, false
 	}
+	return time.Unix(int64(expiry), 0), true
+}
 
-	resp, err := hc.apiClient.ListPods(context.Background(), req)
-	if err != nil {
-		return nil, err
-	}
+func validateProxyCertsNotExpired(sampled []*proxyMetrics, now time.Time) error {
+	expired := []string{}
 
-	pods := make([]*pb.Pod, 0)
-	for _, pod := range resp.GetPods() {
-		if pod.ControllerNamespace == hc.ControlPlaneNamespace {
-			pods = append(pods, pod)
+	for _, proxy := range sampled {
+		if expiry, ok := proxyCertExpiry(proxy); ok && !now.Before(expiry) {
+			expired = append(expired, fmt.Sprintf("%s (expired %s)", proxy.pod, expiry.UTC().Format(time.RFC3339)))
 		}
 	}
 
-	return pods, nil
+	if len(expired) > 0 {
+		return fmt.Errorf("Proxy certificates have expired for %s", strings.Join(expired, ", "))
+	}
+
+	return nil
 }
 
-func (hc *HealthChecker) checkCanCreate(namespace, group, version, resource string) error {
-	if hc.clientset == nil {
-		var err error
-		hc.clientset, err = kubernetes.NewForConfig(hc.kubeAPI.Config)
-		if err != nil {
-			return err
+func validateProxyCertsNotExpiringSoon(sampled []*proxyMetrics, now time.Time, window time.Duration) error {
+	expiring := []string{}
+
+	for _, proxy := range sampled {
+		expiry, ok := proxyCertExpiry(proxy)
+		if ok && now.Before(expiry) && expiry.Before(now.Add(window)) {
+			expiring = append(expiring, fmt.Sprintf("%s (expires %s)", proxy.pod, expiry.UTC().Format(time.RFC3339)))
 		}
 	}
 
-	auth := hc.clientset.AuthorizationV1beta1()
-
-	sar := &authorizationapi.SelfSubjectAccessReview{
-		Spec: authorizationapi.SelfSubjectAccessReviewSpec{
-			ResourceAttributes: &authorizationapi.ResourceAttributes{
-				Namespace: namespace,
-				Verb:      "create",
-				Group:     group,
-				Version:   version,
-				Resource:  resource,
-			},
-		},
+	if len(expiring) > 0 {
+		return fmt.Errorf("Proxy certificates will expire within %s for %s", window, strings.Join(expiring, ", "))
 	}
 
-	response, err := auth.SelfSubjectAccessReviews().Create(sar)
-	if err != nil {
-		return err
+	return nil
+}
+
+// controlPlaneComponent returns a friendly name for the control plane
+// component served at the given address, based on the service name.
+func controlPlaneComponent(addr string) string {
+	service := strings.Split(addr, ".")[0]
+	switch service {
+	case "proxy-api":
+		return "destination"
+	case "linkerd-identity", "identity":
+		return "identity"
+	default:
+		return service
 	}
+}
+
+// validateProxyControlPlaneConnectivity returns an error if any of the sampled
+// proxies report more failed than successful responses from a control plane
+// component. A handful of failures is expected during rollouts, so a proxy is
+// only flagged when failures dominate.
+func validateProxyControlPlaneConnectivity(sampled []*proxyMetrics) error {
+	unreachable := []string{}
 
-	if !response.Status.Allowed {
-		if len(response.Status.Reason) > 0 {
-			return fmt.Errorf("Missing permissions to create %s: %v", resource, response.Status.Reason)
+	for _, proxy := range sampled {
+		family, ok := proxy.metrics[controlResponseMetricName]
+		if !ok {
+			continue
+		}
+
+		successes := make(map[string]float64)
+		failures := make(map[string]float64)
+		seen := make(map[string]bool)
+		addrs := []string{}
+		for _, metric := range family.GetMetric() {
+			addr := labelValue(metric, "addr")
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+			if labelValue(metric, "classification") == "failure" {
+				failures[addr] += metric.GetCounter().GetValue()
+			} else {
+				successes[addr] += metric.GetCounter().GetValue()
+			}
+		}
+
+		for _, addr := range addrs {
+			if failures[addr] > successes[addr] {
+				unreachable = append(unreachable, fmt.Sprintf("%s cannot reach %s (%s)",
+					proxy.pod, controlPlaneComponent(addr), addr))
+			}
 		}
-		return fmt.Errorf("Missing permissions to create %s", resource)
 	}
+
+	if len(unreachable) > 0 {
+		return fmt.Errorf("Data plane proxies are failing to reach the control plane: %s", strings.Join(unreachable, ", "))
+	}
+
 	return nil
 }
 
-func (hc *HealthChecker) validateServiceProfiles() error {
-	if hc.clientset == nil {
-		var err error
-		hc.clientset, err = kubernetes.NewForConfig(hc.kubeAPI.Config)
-		if err != nil {
-			return err
+// proxyVersionsByNamespace aggregates the proxy versions of the given pods into
+// a matrix of namespace to version to pod count.
+func proxyVersionsByNamespace(pods []*pb.Pod) map[string]map[string]int {
+	versions := make(map[string]map[string]int)
+	for _, pod := range pods {
+		namespace := strings.Split(pod.Name, "/")[0]
+		if _, ok := versions[namespace]; !ok {
+			versions[namespace] = make(map[string]int)
 		}
+		versions[namespace][pod.ProxyVersion]++
+	}
+	return versions
+}
+
+// parseReleaseVersion splits a version such as "stable-2.0.0" or
+// "edge-18.10.2" into its channel and numeric components.
+func parseReleaseVersion(v string) (string, [3]int, bool) {
+	var parsed [3]int
+
+	parts := strings.SplitN(v, "-", 2)
+	if len(parts) != 2 {
+		return "", parsed, false
 	}
 
-	if hc.spClientset == nil {
+	numbers := strings.Split(parts[1], ".")
+	if len(numbers) != 3 {
+		return "", parsed, false
+	}
+	for i, n := range numbers {
 		var err error
-		hc.spClientset, err = spclient.NewForConfig(hc.kubeAPI.Config)
+		parsed[i], err = strconv.Atoi(n)
 		if err != nil {
-			return err
+			return "", parsed, false
 		}
 	}
 
-	svcProfiles, err := hc.spClientset.LinkerdV1alpha1().ServiceProfiles(hc.ControlPlaneNamespace).List(meta_v1.ListOptions{})
-	if err != nil {
-		return err
+	return parts[0], parsed, true
+}
+
+// validateProxyVersionSkew returns an error listing the namespaces that run
+// proxies more than one minor version behind the control plane, or proxies
+// newer than the control plane. Versions that can't be parsed, or that belong
+// to a different release channel, are not compared.
+func validateProxyVersionSkew(versions map[string]map[string]int, controlPlaneVersion string) error {
+	cpChannel, cpVersion, ok := parseReleaseVersion(controlPlaneVersion)
+	if !ok {
+		return nil
 	}
 
-	for _, p := range svcProfiles.Items {
-		nameParts := strings.Split(p.Name, ".")
-		if len(nameParts) != 2+len(clusterZoneSuffix) {
-			return fmt.Errorf("ServiceProfile \"%s\" has invalid name (must be \"<service>.<namespace>.svc.cluster.local\")", p.Name)
-		}
-		for i, part := range nameParts[2:] {
-			if part != clusterZoneSuffix[i] {
-				return fmt.Errorf("ServiceProfile \"%s\" has invalid name (must be \"<service>.<namespace>.svc.cluster.local\")", p.Name)
-			}
-		}
-		service := nameParts[0]
-		namespace := nameParts[1]
-		_, err := hc.clientset.Core().Services(namespace).Get(service, meta_v1.GetOptions{})
-		if err != nil {
-			return fmt.Errorf("ServiceProfile \"%s\" has unknown service: %s", p.Name, err)
+	namespaces := []string{}
+	for namespace := range versions {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	skewed := []string{}
+	for _, namespace := range namespaces {
+		proxyVersions := []string{}
+		for v := range versions[namespace] {
+			proxyVersions = append(proxyVersions, v)
 		}
-		for _, route := range p.Spec.Routes {
-			if route.Name == "" {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with no name", p.Name)
-			}
-			if route.Condition == nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with no condition", p.Name)
-			}
-			err = profiles.ValidateRequestMatch(route.Condition)
-			if err != nil {
-				return fmt.Errorf("ServiceProfile \"%s\" has a route with an invalid condition: %s", p.Name, err)
+		sort.Strings(proxyVersions)
+
+		for _, v := range proxyVersions {
+			channel, proxyVersion, ok := parseReleaseVersion(v)
+			if !ok || channel != cpChannel {
+				continue
 			}
-			for _, rc := range route.ResponseClasses {
-				if rc.Condition == nil {
-					return fmt.Errorf("ServiceProfile \"%s\" has a response class with no condition", p.Name)
-				}
-				err = profiles.ValidateResponseMatch(rc.Condition)
-				if err != nil {
-					return fmt.Errorf("ServiceProfile \"%s\" has a response class with an invalid condition: %s", p.Name, err)
-				}
+
+			behind := proxyVersion[0] < cpVersion[0] ||
+				(proxyVersion[0] == cpVersion[0] && cpVersion[1]-proxyVersion[1] > 1)
+			ahead := proxyVersion[0] > cpVersion[0] ||
+				(proxyVersion[0] == cpVersion[0] && proxyVersion[1] > cpVersion[1]) ||
+				(proxyVersion[0] == cpVersion[0] && proxyVersion[1] == cpVersion[1] && proxyVersion[2] > cpVersion[2])
+
+			if behind {
+				skewed = append(skewed, fmt.Sprintf("%s has %d proxies running %s, more than one minor version behind", namespace, versions[namespace][v], v))
+			} else if ahead {
+				skewed = append(skewed, fmt.Sprintf("%s has %d proxies running %s, which is newer than the control plane", namespace, versions[namespace][v], v))
 			}
 		}
 	}
+
+	if len(skewed) > 0 {
+		return fmt.Errorf("The control plane is running %s, but %s", controlPlaneVersion, strings.Join(skewed, "; "))
+	}
+
 	return nil
 }
 
-func validateControlPlanePods(pods []v1.Pod) error {
-	statuses := make(map[string][]v1.ContainerStatus)
+// controlPlaneNamespaces returns the set of namespaces running a Linkerd
+// controller.
+func controlPlaneNamespaces(pods []v1.Pod) map[string]bool {
+	namespaces := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Labels[k8s.ControllerComponentLabel] == "controller" {
+			namespaces[pod.Namespace] = true
+		}
+	}
+	return namespaces
+}
+
+// validateNoOrphanedProxies returns an error listing the meshed pods whose
+// proxies report to a control plane namespace that has no running controller,
+// e.g. because that control plane has since been uninstalled.
+func validateNoOrphanedProxies(pods []v1.Pod, controlPlanes map[string]bool) error {
+	orphaned := []string{}
 
 	for _, pod := range pods {
-		if pod.Status.Phase == v1.PodRunning {
-			name := strings.Split(pod.Name, "-")[0]
-			if _, found := statuses[name]; !found {
-				statuses[name] = make([]v1.ContainerStatus, 0)
+		if !isActivePod(pod) || pod.Labels[k8s.ControllerComponentLabel] != "" {
+			continue
+		}
+
+		target := k8s.GetProxyControlPlaneNamespace(&pod)
+		if target != "" && !controlPlanes[target] {
+			orphaned = append(orphaned, fmt.Sprintf("%s/%s (control plane namespace \"%s\")", pod.Namespace, pod.Name, target))
+		}
+	}
+
+	if len(orphaned) > 0 {
+		return fmt.Errorf("Data plane proxies reference a control plane that does not exist: %s", strings.Join(orphaned, ", "))
+	}
+
+	return nil
+}
+
+// validatePortAnnotations returns an error listing every port list annotation
+// that proxy-init would fail to parse.
+func validatePortAnnotations(resources []annotatedResource) error {
+	invalid := []string{}
+
+	for _, r := range resources {
+		for _, key := range portListAnnotations {
+			value, ok := r.annotations[key]
+			if !ok {
+				continue
+			}
+
+			if _, err := k8s.ParsePortList(value); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s %s=\"%s\" (%s)", r, key, value, err))
 			}
-			statuses[name] = append(statuses[name], pod.Status.ContainerStatuses...)
 		}
 	}
 
-	names := []string{"controller", "grafana", "prometheus", "web"}
-	if _, found := statuses["ca"]; found {
-		names = append(names, "ca")
+	if len(invalid) > 0 {
+		return fmt.Errorf("Some port annotations are invalid: %s", strings.Join(invalid, ", "))
 	}
 
-	for _, name := range names {
-		containers, found := statuses[name]
-		if !found {
-			return fmt.Errorf("No running pods for \"%s\"", name)
+	return nil
+}
+
+// validateInboundSkipPorts returns an error listing the resources whose
+// skipped inbound ports include one of the proxy's own ports. Unparseable
+// annotations are reported by validatePortAnnotations and ignored here.
+func validateInboundSkipPorts(resources []annotatedResource, ports []int) error {
+	overlaps := []string{}
+
+	for _, r := range resources {
+		value, ok := r.annotations[k8s.ProxySkipInboundPortsAnnotation]
+		if !ok {
+			continue
 		}
-		for _, container := range containers {
-			if !container.Ready {
-				return fmt.Errorf("The \"%s\" pod's \"%s\" container is not ready", name,
-					container.Name)
+
+		ranges, err := k8s.ParsePortList(value)
+		if err != nil {
+			continue
+		}
+
+		skipped := []string{}
+		for _, port := range ports {
+			for _, pr := range ranges {
+				if pr.Contains(port) {
+					skipped = append(skipped, strconv.Itoa(port))
+					break
+				}
 			}
 		}
+
+		if len(skipped) > 0 {
+			overlaps = append(overlaps, fmt.Sprintf("%s (%s)", r, strings.Join(skipped, ", ")))
+		}
+	}
+
+	if len(overlaps) > 0 {
+		return fmt.Errorf("Some skipped inbound ports are used by the proxy: %s", strings.Join(overlaps, ", "))
 	}
 
 	return nil
 }
 
-func validateDataPlanePods(pods []*pb.Pod, targetNamespace string) error {
-	if len(pods) == 0 {
-		msg := fmt.Sprintf("No \"%s\" containers found", k8s.ProxyContainerName)
-		if targetNamespace != "" {
-			msg += fmt.Sprintf(" in the \"%s\" namespace", targetNamespace)
+// proxyOnlyRunning returns true if the pod's proxy container is running while
+// all of its other containers have terminated.
+func proxyOnlyRunning(pod v1.Pod) bool {
+	proxyRunning := false
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == k8s.ProxyContainerName {
+			proxyRunning = status.State.Running != nil
+		} else if status.State.Terminated == nil {
+			return false
 		}
-		return fmt.Errorf(msg)
 	}
+	return proxyRunning
+}
+
+// validateNoProxyOnlyPods returns an error listing the meshed pods whose
+// application containers have all exited while the proxy keeps running, which
+// prevents Jobs from completing, as well as the pods that have been
+// Terminating for longer than timeout with only the proxy still alive.
+func validateNoProxyOnlyPods(pods []v1.Pod, now time.Time, timeout time.Duration) error {
+	completed := []string{}
+	terminating := []string{}
 
 	for _, pod := range pods {
-		if pod.Status != "Running" {
-			return fmt.Errorf("The \"%s\" pod is not running",
-				pod.Name)
+		if !isActivePod(pod) || !hasProxyContainer(pod) || !proxyOnlyRunning(pod) {
+			continue
 		}
 
-		if !pod.ProxyReady {
-			return fmt.Errorf("The \"%s\" container in the \"%s\" pod is not ready",
-				k8s.ProxyContainerName, pod.Name)
+		name := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		if pod.DeletionTimestamp == nil {
+			completed = append(completed, name)
+		} else if now.Sub(pod.DeletionTimestamp.Time) > timeout {
+			terminating = append(terminating, fmt.Sprintf("%s (terminating since %s)",
+				name, pod.DeletionTimestamp.UTC().Format(time.RFC3339)))
 		}
 	}
 
+	problems := []string{}
+	if len(completed) > 0 {
+		problems = append(problems, fmt.Sprintf("application containers have exited but the proxy is still running: %s",
+			strings.Join(completed, ", ")))
+	}
+	if len(terminating) > 0 {
+		problems = append(problems, fmt.Sprintf("pods are stuck Terminating with only the proxy running: %s",
+			strings.Join(terminating, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("Some pods are kept running by the \"%s\" container; %s. Jobs and other run-to-completion workloads should be left uninjected, or stop the proxy once the main container exits",
+			k8s.ProxyContainerName, strings.Join(problems, "; "))
+	}
+
 	return nil
 }
 
-func validateDataPlanePodReporting(pods []*pb.Pod) error {
-	notInPrometheus := []string{}
+// imageTag returns the tag of a container image reference, or "" if the
+// reference is pinned by digest or has no tag.
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
 
-	for _, p := range pods {
-		// the `Added` field indicates the pod was found in Prometheus
-		if !p.Added {
-			notInPrometheus = append(notInPrometheus, p.Name)
-		}
+// podProxyVersion returns the version of the pod's proxy, as recorded by the
+// injector, falling back to the proxy image tag.
+func podProxyVersion(pod v1.Pod) string {
+	if version := pod.Annotations[k8s.ProxyVersionAnnotation]; version != "" {
+		return version
 	}
+	if proxy := k8s.GetProxyContainer(&pod.Spec); proxy != nil {
+		return imageTag(proxy.Image)
+	}
+	return ""
+}
 
-	errMsg := ""
-	if len(notInPrometheus) > 0 {
-		errMsg = fmt.Sprintf("Data plane metrics not found for %s.", strings.Join(notInPrometheus, ", "))
+// validateProxyInitVersions returns an error listing the meshed pods whose
+// proxy-init container image tag differs from their proxy version. Pods
+// without a proxy-init container, or whose versions can't be determined, are
+// ignored.
+func validateProxyInitVersions(pods []v1.Pod, controlPlaneNamespace string) error {
+	mismatched := []string{}
+
+	for _, pod := range pods {
+		if !isActivePod(pod) || !hasProxyContainer(pod) || !k8s.IsMeshed(&pod, controlPlaneNamespace) {
+			continue
+		}
+
+		for _, container := range pod.Spec.InitContainers {
+			if container.Name != k8s.InitContainerName {
+				continue
+			}
+
+			initVersion := imageTag(container.Image)
+			proxyVersion := podProxyVersion(pod)
+			if initVersion != "" && proxyVersion != "" && initVersion != proxyVersion {
+				mismatched = append(mismatched, fmt.Sprintf("%s/%s (proxy-init %s, proxy %s)", pod.Namespace, pod.Name, initVersion, proxyVersion))
+			}
+			break
+		}
 	}
 
-	if errMsg != "" {
-		return fmt.Errorf(errMsg)
+	if len(mismatched) > 0 {
+		return fmt.Errorf("Some pods run a proxy-init version that does not match their proxy version: %s", strings.Join(mismatched, ", "))
 	}
 
 	return nil
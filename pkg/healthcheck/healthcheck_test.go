@@ -2,17 +2,28 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/api/public"
 	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
 	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/version"
+	dto "github.com/prometheus/client_model/go"
 	"k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
 )
 
 func TestHealthChecker(t *testing.T) {
@@ -21,7 +32,7 @@ func TestHealthChecker(t *testing.T) {
 	passingCheck1 := &checker{
 		category:    "cat1",
 		description: "desc1",
-		check: func() error {
+		check: func(ctx context.Context) error {
 			return nil
 		},
 		retryDeadline: time.Time{},
@@ -30,7 +41,7 @@ func TestHealthChecker(t *testing.T) {
 	passingCheck2 := &checker{
 		category:    "cat2",
 		description: "desc2",
-		check: func() error {
+		check: func(ctx context.Context) error {
 			return nil
 		},
 		retryDeadline: time.Time{},
@@ -39,7 +50,7 @@ func TestHealthChecker(t *testing.T) {
 	failingCheck := &checker{
 		category:    "cat3",
 		description: "desc3",
-		check: func() error {
+		check: func(ctx context.Context) error {
 			return fmt.Errorf("error")
 		},
 		retryDeadline: time.Time{},
@@ -57,13 +68,13 @@ func TestHealthChecker(t *testing.T) {
 		},
 	}
 
+	passingRPCChecker := NewHealthChecker([]Checks{}, &HealthCheckOptions{})
+	passingRPCChecker.apiClient = &passingRPCClient
+
 	passingRPCCheck := &checker{
-		category:    "cat4",
-		description: "desc4",
-		checkRPC: func() (*healthcheckPb.SelfCheckResponse, error) {
-			return passingRPCClient.SelfCheck(context.Background(),
-				&healthcheckPb.SelfCheckRequest{})
-		},
+		category:      "cat4",
+		description:   "desc4",
+		checkStream:   passingRPCChecker.openSelfCheckStream,
 		retryDeadline: time.Time{},
 	}
 
@@ -80,21 +91,30 @@ func TestHealthChecker(t *testing.T) {
 		},
 	}
 
+	failingRPCChecker := NewHealthChecker([]Checks{}, &HealthCheckOptions{})
+	failingRPCChecker.apiClient = &failingRPCClient
+
 	failingRPCCheck := &checker{
-		category:    "cat5",
-		description: "desc5",
-		checkRPC: func() (*healthcheckPb.SelfCheckResponse, error) {
-			return failingRPCClient.SelfCheck(context.Background(),
-				&healthcheckPb.SelfCheckRequest{})
-		},
+		category:      "cat5",
+		description:   "desc5",
+		checkStream:   failingRPCChecker.openSelfCheckStream,
 		retryDeadline: time.Time{},
 	}
 
+	skippedCheck := &checker{
+		category:    "cat7",
+		description: "desc7",
+		check: func(ctx context.Context) error {
+			return &SkipError{Reason: "not applicable"}
+		},
+		retryDeadline: time.Now().Add(time.Minute),
+	}
+
 	fatalCheck := &checker{
 		category:    "cat6",
 		description: "desc6",
 		fatal:       true,
-		check: func() error {
+		check: func(ctx context.Context) error {
 			return fmt.Errorf("fatal")
 		},
 		retryDeadline: time.Time{},
@@ -185,6 +205,113 @@ func TestHealthChecker(t *testing.T) {
 		}
 	})
 
+	t.Run("Is successful if a check is skipped", func(t *testing.T) {
+		hc := HealthChecker{
+			checkers: []*checker{
+				passingCheck1,
+				skippedCheck,
+			},
+		}
+
+		observedResults := make([]*CheckResult, 0)
+		observer := func(result *CheckResult) {
+			observedResults = append(observedResults, result)
+		}
+
+		success := hc.RunChecks(observer)
+
+		if !success {
+			t.Fatalf("Expecting checks to be successful, but got [%t]", success)
+		}
+		if len(observedResults) != 2 {
+			t.Fatalf("Expected skipped check to be reported once without retries, got %d results", len(observedResults))
+		}
+		if !observedResults[1].Skipped || observedResults[1].Err.Error() != "not applicable" {
+			t.Fatalf("Expected skipped result with reason, got %+v", observedResults[1])
+		}
+	})
+
+	t.Run("Skips checks excluded by the SkipChecks option", func(t *testing.T) {
+		hc := HealthChecker{
+			HealthCheckOptions: &HealthCheckOptions{
+				SkipChecks: []string{"cat2: desc2", "cat3"},
+			},
+			checkers: []*checker{
+				passingCheck1,
+				passingCheck2,
+				failingCheck,
+			},
+		}
+
+		observedResults := make([]string, 0)
+		observer := func(result *CheckResult) {
+			res := fmt.Sprintf("%s %s skipped=%t", result.Category, result.Description, result.Skipped)
+			if result.Err != nil {
+				res += fmt.Sprintf(": %s", result.Err)
+			}
+			observedResults = append(observedResults, res)
+		}
+
+		expectedResults := []string{
+			"cat1 desc1 skipped=false",
+			"cat2 desc2 skipped=true: Skipped by request",
+			"cat3 desc3 skipped=true: Skipped by request",
+		}
+
+		success := hc.RunChecks(observer)
+
+		if !success {
+			t.Fatalf("Expecting checks to be successful, but got [%t]", success)
+		}
+		if !reflect.DeepEqual(observedResults, expectedResults) {
+			t.Fatalf("Expected results %v, but got %v", expectedResults, observedResults)
+		}
+	})
+
+	t.Run("Stops once the run context is canceled", func(t *testing.T) {
+		blockingCheck := &checker{
+			category:      "cat8",
+			description:   "desc8",
+			retryDeadline: time.Now().Add(time.Minute),
+			check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}
+		hc := HealthChecker{
+			checkers: []*checker{
+				passingCheck1,
+				blockingCheck,
+				passingCheck2,
+			},
+		}
+
+		observedResults := make([]string, 0)
+		observer := func(result *CheckResult) {
+			observedResults = append(observedResults, fmt.Sprintf("%s %s retry=%t", result.Category, result.Description, result.Retry))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		success := hc.RunChecksContext(ctx, observer)
+
+		if success {
+			t.Fatalf("Expecting checks to not be successful, but got [%t]", success)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("Expected the checks to stop once the context was canceled, took %s", elapsed)
+		}
+		expectedResults := []string{
+			"cat1 desc1 retry=false",
+			"cat8 desc8 retry=false",
+		}
+		if !reflect.DeepEqual(observedResults, expectedResults) {
+			t.Fatalf("Expected results %v, but got %v", expectedResults, observedResults)
+		}
+	})
+
 	t.Run("Does not run remaining check if fatal check fails", func(t *testing.T) {
 		hc := HealthChecker{
 			checkers: []*checker{
@@ -223,7 +350,7 @@ func TestHealthChecker(t *testing.T) {
 			category:      "cat7",
 			description:   "desc7",
 			retryDeadline: time.Now().Add(100 * time.Second),
-			check: func() error {
+			check: func(ctx context.Context) error {
 				if returnError {
 					returnError = false
 					return fmt.Errorf("retry")
@@ -262,6 +389,150 @@ func TestHealthChecker(t *testing.T) {
 	})
 }
 
+func TestPodChanged(t *testing.T) {
+	known := map[string]string{"linkerd-controller-1": "10"}
+
+	testCases := []struct {
+		event    k8s.WatchEvent
+		expected bool
+		err      bool
+	}{
+		{k8s.WatchEvent{Type: watch.Added, Object: []byte(`{"metadata":{"name":"linkerd-controller-1","resourceVersion":"10"}}`)}, false, false},
+		{k8s.WatchEvent{Type: watch.Added, Object: []byte(`{"metadata":{"name":"linkerd-controller-1","resourceVersion":"11"}}`)}, true, false},
+		{k8s.WatchEvent{Type: watch.Added, Object: []byte(`{"metadata":{"name":"linkerd-controller-2","resourceVersion":"10"}}`)}, true, false},
+		{k8s.WatchEvent{Type: watch.Modified, Object: []byte(`{"metadata":{"name":"linkerd-controller-1","resourceVersion":"10"}}`)}, true, false},
+		{k8s.WatchEvent{Type: watch.Deleted, Object: []byte(`{"metadata":{"name":"linkerd-controller-1","resourceVersion":"12"}}`)}, true, false},
+		{k8s.WatchEvent{Type: watch.Error, Object: []byte(`{"status":"Failure","message":"too old resource version"}`)}, false, true},
+	}
+
+	for i, tc := range testCases {
+		changed, err := podChanged(tc.event, known)
+		if tc.err != (err != nil) {
+			t.Fatalf("test case %d: unexpected error: %v", i, err)
+		}
+		if changed != tc.expected {
+			t.Fatalf("test case %d: expected %t, got %t", i, tc.expected, changed)
+		}
+	}
+}
+
+func TestPodsChanged(t *testing.T) {
+	known := map[string]string{"linkerd-controller-1": "10"}
+	pod := func(name, version string) v1.Pod {
+		return v1.Pod{ObjectMeta: meta.ObjectMeta{Name: name, ResourceVersion: version}}
+	}
+
+	if podsChanged([]v1.Pod{pod("linkerd-controller-1", "10")}, known) {
+		t.Fatal("Expected unchanged pods")
+	}
+	if !podsChanged([]v1.Pod{pod("linkerd-controller-1", "11")}, known) {
+		t.Fatal("Expected an updated pod to be a change")
+	}
+	if !podsChanged([]v1.Pod{}, known) {
+		t.Fatal("Expected a deleted pod to be a change")
+	}
+	if !podsChanged([]v1.Pod{pod("linkerd-controller-1", "10"), pod("linkerd-controller-2", "12")}, known) {
+		t.Fatal("Expected an added pod to be a change")
+	}
+}
+
+func TestWaitForPodChangeWithoutPods(t *testing.T) {
+	hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{})
+	if err := hc.waitForPodChange(context.Background(), "linkerd", nil); err == nil {
+		t.Fatal("Expected an error without pods to compare against")
+	}
+}
+
+// newTestHealthChecker returns a HealthChecker running the given checks, whose
+// Kubernetes API requests are served by the given handler.
+func newTestHealthChecker(t *testing.T, checks []Checks, options *HealthCheckOptions, handler http.HandlerFunc) (*HealthChecker, func()) {
+	server := httptest.NewServer(handler)
+
+	hc := NewHealthChecker(checks, options)
+	hc.kubeAPI = &k8s.KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	return hc, server.Close
+}
+
+func TestDataPlaneResourcesAreCached(t *testing.T) {
+	requests := make(map[string]int)
+	hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Write([]byte(`{"items":[{"metadata":{"name":"emojivoto","namespace":"emojivoto"}}]}`))
+	})
+	defer done()
+
+	for i := 0; i < 3; i++ {
+		if _, _, err := hc.getDataPlaneKubePods(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if _, err := hc.getAllKubePods(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	expected := map[string]int{"/api/v1/pods": 1, "/api/v1/namespaces": 1}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestLinkerdConfigIsShared(t *testing.T) {
+	requests := 0
+	hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/v1/namespaces/linkerd/configmaps/linkerd-config" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"metadata":{"name":"linkerd-config"},"data":{"proxy":"{\"inboundPort\":5143}"}}`))
+	})
+	defer done()
+
+	for i := 0; i < 3; i++ {
+		ports, err := hc.getProxyPortConfig(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if ports.InboundPort != 5143 || ports.OutboundPort != defaultProxyPorts.OutboundPort {
+			t.Fatalf("Unexpected proxy ports: %+v", ports)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("Expected linkerd-config to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestCheckAPIServerHealth(t *testing.T) {
+	hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer done()
+
+	if _, ok := hc.checkAPIServerHealth(context.Background()).(*SkipError); !ok {
+		t.Fatal("Expected the check to be skipped when the health endpoints are forbidden")
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	if err := validateNamespace("linkerd", nil); !k8s.IsNamespaceNotFound(err) {
+		t.Fatalf("Expected a NamespaceNotFoundError, got %v", err)
+	}
+
+	active := &v1.Namespace{Status: v1.NamespaceStatus{Phase: v1.NamespaceActive}}
+	if err := validateNamespace("linkerd", active); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	terminating := &v1.Namespace{Status: v1.NamespaceStatus{Phase: v1.NamespaceTerminating}}
+	err := validateNamespace("linkerd", terminating)
+	expected := "The \"linkerd\" namespace is Terminating: it is being deleted along with its resources"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error [%s], got %v", expected, err)
+	}
+}
+
 func TestValidateControlPlanePods(t *testing.T) {
 	pod := func(name string, phase v1.PodPhase, ready bool) v1.Pod {
 		return v1.Pod{
@@ -327,6 +598,34 @@ func TestValidateControlPlanePods(t *testing.T) {
 	})
 }
 
+func TestWithSchedulingFailures(t *testing.T) {
+	pods := []v1.Pod{
+		{ObjectMeta: meta.ObjectMeta{Name: "controller-6f78cbd47-bc557"}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+		{ObjectMeta: meta.ObjectMeta{Name: "prometheus-74d6879cd6-bbdk6"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+		{ObjectMeta: meta.ObjectMeta{Name: "grafana-5b7d796646-hh46d"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+	}
+	events := map[string][]v1.Event{
+		"prometheus-74d6879cd6-bbdk6": {
+			{Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 Insufficient memory."},
+			{Reason: "FailedScheduling", Message: "0/3 nodes are available: 3 node(s) had taints that the pod didn't tolerate."},
+		},
+		"grafana-5b7d796646-hh46d": {
+			{Reason: "Scheduled", Message: "Successfully assigned linkerd/grafana-5b7d796646-hh46d to node-1"},
+		},
+	}
+
+	err := withSchedulingFailures(errors.New("No running pods for \"prometheus\""), pods, events)
+	expected := "No running pods for \"prometheus\" (prometheus-74d6879cd6-bbdk6 is Pending: 0/3 nodes are available: 3 Insufficient memory.)"
+	if err.Error() != expected {
+		t.Fatalf("Expected error [%s], got [%s]", expected, err)
+	}
+
+	original := errors.New("The \"web\" pod's \"web\" container is not ready")
+	if err := withSchedulingFailures(original, pods, map[string][]v1.Event{}); err != original {
+		t.Fatalf("Expected the error to be unchanged, got %s", err)
+	}
+}
+
 func TestValidateDataPlanePods(t *testing.T) {
 
 	t.Run("Returns an error if no inject pods were found", func(t *testing.T) {
@@ -423,3 +722,1357 @@ func TestValidateDataPlanePodReporting(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateAutoInjectedPods(t *testing.T) {
+	created := meta.NewTime(time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC))
+
+	pod := func(namespace, name, autoInject string, injected bool) v1.Pod {
+		p := v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Name:              name,
+				Namespace:         namespace,
+				Labels:            map[string]string{},
+				CreationTimestamp: created,
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{v1.Container{Name: "app"}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if autoInject != "" {
+			p.Labels["linkerd.io/auto-inject"] = autoInject
+		}
+		if injected {
+			p.Spec.Containers = append(p.Spec.Containers, v1.Container{Name: "linkerd-proxy"})
+		}
+		return p
+	}
+
+	namespaces := map[string]v1.Namespace{
+		"enabled": v1.Namespace{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   "enabled",
+				Labels: map[string]string{"linkerd.io/auto-inject": "enabled"},
+			},
+		},
+		"disabled": v1.Namespace{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   "disabled",
+				Labels: map[string]string{"linkerd.io/auto-inject": "disabled"},
+			},
+		},
+		"linkerd": v1.Namespace{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   "linkerd",
+				Labels: map[string]string{"linkerd.io/auto-inject": "disabled"},
+			},
+		},
+		"default": v1.Namespace{
+			ObjectMeta: meta.ObjectMeta{Name: "default"},
+		},
+	}
+
+	t.Run("Returns nil if all pods in auto-inject namespaces are injected", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("enabled", "web-6cfbccc48-5g8px", "", true),
+			pod("enabled", "vote-bot-644b8cb6b4-g8nlr", "disabled", false),
+			pod("default", "emoji-d9c7866bb-7v74n", "", false),
+		}
+
+		err := validateAutoInjectedPods(pods, autoInjectPolicy{namespaces: namespaces})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a pod with auto-inject enabled is not injected", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("enabled", "web-6cfbccc48-5g8px", "", false),
+			pod("default", "emoji-d9c7866bb-7v74n", "enabled", false),
+			pod("default", "voting-65b9fffd77-rlwsd", "", false),
+		}
+
+		err := validateAutoInjectedPods(pods, autoInjectPolicy{namespaces: namespaces})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Pods with auto-inject enabled are missing the \"linkerd-proxy\" container: enabled/web-6cfbccc48-5g8px (created 2018-10-01T12:00:00Z), default/emoji-d9c7866bb-7v74n (created 2018-10-01T12:00:00Z)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if a Deployment pod in an unlabeled namespace is not injected by the proxy injector", func(t *testing.T) {
+		owned := func(p v1.Pod) v1.Pod {
+			p.OwnerReferences = []meta.OwnerReference{{Kind: "ReplicaSet", Name: "voting-65b9fffd77"}}
+			return p
+		}
+		pods := []v1.Pod{
+			owned(pod("default", "voting-65b9fffd77-rlwsd", "", false)),
+			owned(pod("default", "web-6cfbccc48-5g8px", "disabled", false)),
+			owned(pod("disabled", "emoji-d9c7866bb-7v74n", "", false)),
+			pod("default", "vote-bot", "", false),
+		}
+
+		err := validateAutoInjectedPods(pods, autoInjectPolicy{namespaces: namespaces, injectorInstalled: true})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Pods with auto-inject enabled are missing the \"linkerd-proxy\" container: default/voting-65b9fffd77-rlwsd (created 2018-10-01T12:00:00Z)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if a pod in an auto-inject disabled namespace is injected", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("disabled", "web-6cfbccc48-5g8px", "", true),
+			pod("disabled", "emoji-d9c7866bb-7v74n", "", false),
+			pod("linkerd", "controller-6f78cbd47-bc557", "", true),
+		}
+
+		err := validateAutoInjectDisabledPods(pods, namespaces, "linkerd")
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Pods in namespaces with auto-inject disabled have the \"linkerd-proxy\" container: disabled/web-6cfbccc48-5g8px" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateAutoInjectLabels(t *testing.T) {
+	workload := func(kind, namespace, name, autoInject string) workloadTemplate {
+		w := workloadTemplate{kind: kind, namespace: namespace, name: name}
+		if autoInject != "" {
+			w.template.Labels = map[string]string{"linkerd.io/auto-inject": autoInject}
+		}
+		return w
+	}
+	deployment := func(namespace, name, autoInject string) workloadTemplate {
+		return workload("deployment", namespace, name, autoInject)
+	}
+
+	namespaces := map[string]v1.Namespace{
+		"enabled": {ObjectMeta: meta.ObjectMeta{
+			Name:   "enabled",
+			Labels: map[string]string{"linkerd.io/auto-inject": "enabled"},
+		}},
+		"disabled": {ObjectMeta: meta.ObjectMeta{
+			Name:   "disabled",
+			Labels: map[string]string{"linkerd.io/auto-inject": "disabled"},
+		}},
+		"default": {ObjectMeta: meta.ObjectMeta{Name: "default"}},
+	}
+
+	t.Run("Returns nil if no labels contradict their namespace", func(t *testing.T) {
+		workloads := []workloadTemplate{
+			deployment("enabled", "web", ""),
+			deployment("enabled", "emoji", "enabled"),
+			deployment("enabled", "voting", "completed"),
+			deployment("disabled", "vote-bot", "disabled"),
+			deployment("default", "web", "disabled"),
+		}
+
+		err := validateAutoInjectLabels(workloads, namespaces)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error grouped by namespace if labels contradict their namespace", func(t *testing.T) {
+		workloads := []workloadTemplate{
+			deployment("enabled", "web", "disabled"),
+			deployment("disabled", "emoji", "enabled"),
+			deployment("enabled", "voting", "disabled"),
+			workload("statefulset", "disabled", "redis", "enabled"),
+		}
+
+		err := validateAutoInjectLabels(workloads, namespaces)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some workloads have auto-inject labels that contradict their namespace; " +
+			"namespace disabled is disabled: deployment/emoji is enabled (not injected, the namespace label wins), statefulset/redis is enabled (not injected, only Deployments are auto-injected); " +
+			"namespace enabled is enabled: deployment/web is disabled (not injected, the workload label wins), deployment/voting is disabled (not injected, the workload label wins)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateHostNetworkPods(t *testing.T) {
+	namespaces := map[string]v1.Namespace{
+		"enabled": {ObjectMeta: meta.ObjectMeta{
+			Name:   "enabled",
+			Labels: map[string]string{"linkerd.io/auto-inject": "enabled"},
+		}},
+	}
+
+	pod := func(name string, hostNetwork, injected bool, owners ...meta.OwnerReference) v1.Pod {
+		p := v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Namespace: "enabled", Name: name, OwnerReferences: owners},
+			Spec: v1.PodSpec{
+				HostNetwork: hostNetwork,
+				Containers:  []v1.Container{{Name: "app"}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if injected {
+			p.Spec.Containers = append(p.Spec.Containers, v1.Container{Name: "linkerd-proxy"})
+		}
+		return p
+	}
+
+	workload := func(kind, name string, hostNetwork, injected bool, autoInject string) workloadTemplate {
+		w := workloadTemplate{kind: kind, namespace: "enabled", name: name}
+		w.template.Spec.HostNetwork = hostNetwork
+		if injected {
+			w.template.Spec.Containers = []v1.Container{{Name: "linkerd-proxy"}}
+		}
+		if autoInject != "" {
+			w.template.Labels = map[string]string{"linkerd.io/auto-inject": autoInject}
+		}
+		return w
+	}
+
+	policy := autoInjectPolicy{namespaces: namespaces}
+
+	t.Run("Returns nil if no injected workloads use the host network", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", false, true),
+			pod("node-exporter", true, false),
+		}
+		workloads := []workloadTemplate{
+			workload("deployment", "web", false, true, ""),
+			workload("deployment", "node-exporter", true, false, "disabled"),
+			workload("daemonset", "fluentd", true, false, ""),
+		}
+
+		err := validateHostNetworkPods(pods, workloads, policy)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if injected workloads use the host network", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", true, true),
+			pod("node-agent-x7k2p", true, true, meta.OwnerReference{Kind: "DaemonSet", Name: "node-agent"}),
+			pod("redis-0", true, true, meta.OwnerReference{Kind: "StatefulSet", Name: "redis"}),
+		}
+		workloads := []workloadTemplate{
+			workload("deployment", "agent", true, false, ""),
+			workload("daemonset", "node-agent", true, true, ""),
+			workload("statefulset", "redis", true, true, ""),
+		}
+
+		err := validateHostNetworkPods(pods, workloads, policy)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some injected workloads use the host network, so their proxy rewrites the node's iptables rules and may break node networking: pod enabled/web, deployment enabled/agent (not yet injected), daemonset enabled/node-agent, statefulset enabled/redis; label their pod templates with linkerd.io/auto-inject: disabled to exclude them"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestCheckCRDEstablished(t *testing.T) {
+	hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{}, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis":
+			w.Write([]byte(`{"kind":"APIGroupList","groups":[{"name":"apiextensions.k8s.io","versions":[{"groupVersion":"apiextensions.k8s.io/v1beta1"}]}]}`))
+		case "/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/serviceprofiles.linkerd.io":
+			w.Write([]byte(`{"metadata":{"name":"serviceprofiles.linkerd.io"},"status":{"conditions":[{"type":"NamesAccepted","status":"True"},{"type":"Established","status":"False"}]}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer done()
+
+	err := hc.checkCRDEstablished(context.Background(), serviceProfileCRDName)
+	if err == nil || err.Error() != "The \"serviceprofiles.linkerd.io\" CustomResourceDefinition's Established condition is False" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := hc.checkCRDEstablished(context.Background(), "trafficsplits.split.smi-spec.io"); !k8s.IsCRDNotFound(err) {
+		t.Fatalf("Expected a CRDNotFoundError, got %v", err)
+	}
+}
+
+func TestValidateProxyCerts(t *testing.T) {
+	now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	proxy := func(pod string, expiry time.Time) *proxyMetrics {
+		metrics, err := parseProxyMetrics([]byte(fmt.Sprintf(
+			"# TYPE identity_cert_expiration_timestamp_seconds gauge\nidentity_cert_expiration_timestamp_seconds %d\n",
+			expiry.Unix())))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return &proxyMetrics{pod: pod, metrics: metrics}
+	}
+
+	noTLS := &proxyMetrics{pod: "emojivoto/vote-bot-644b8cb6b4-g8nlr", metrics: map[string]*dto.MetricFamily{}}
+
+	t.Run("Returns nil if no certificates are expired", func(t *testing.T) {
+		sampled := []*proxyMetrics{
+			proxy("emojivoto/web-6cfbccc48-5g8px", now.Add(24*time.Hour)),
+			noTLS,
+		}
+
+		if err := validateProxyCertsNotExpired(sampled, now); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := validateProxyCertsNotExpiringSoon(sampled, now, time.Hour); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a certificate is expired", func(t *testing.T) {
+		sampled := []*proxyMetrics{
+			proxy("emojivoto/web-6cfbccc48-5g8px", now.Add(-time.Minute)),
+			proxy("emojivoto/emoji-d9c7866bb-7v74n", now.Add(24*time.Hour)),
+		}
+
+		err := validateProxyCertsNotExpired(sampled, now)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Proxy certificates have expired for emojivoto/web-6cfbccc48-5g8px (expired 2018-10-01T11:59:00Z)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if a certificate expires within the window", func(t *testing.T) {
+		sampled := []*proxyMetrics{
+			proxy("emojivoto/web-6cfbccc48-5g8px", now.Add(-time.Minute)),
+			proxy("emojivoto/emoji-d9c7866bb-7v74n", now.Add(30*time.Minute)),
+		}
+
+		err := validateProxyCertsNotExpiringSoon(sampled, now, time.Hour)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Proxy certificates will expire within 1h0m0s for emojivoto/emoji-d9c7866bb-7v74n (expires 2018-10-01T12:30:00Z)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateProxyControlPlaneConnectivity(t *testing.T) {
+	proxy := func(pod, metrics string) *proxyMetrics {
+		parsed, err := parseProxyMetrics([]byte("# TYPE control_response_total counter\n" + metrics))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return &proxyMetrics{pod: pod, metrics: parsed}
+	}
+
+	t.Run("Returns nil if proxies are reaching the control plane", func(t *testing.T) {
+		sampled := []*proxyMetrics{
+			proxy("emojivoto/web-6cfbccc48-5g8px", `control_response_total{addr="proxy-api.linkerd.svc.cluster.local:8086",classification="success"} 10
+control_response_total{addr="proxy-api.linkerd.svc.cluster.local:8086",classification="failure"} 2
+`),
+		}
+
+		err := validateProxyControlPlaneConnectivity(sampled)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a proxy is failing to reach the control plane", func(t *testing.T) {
+		sampled := []*proxyMetrics{
+			proxy("emojivoto/web-6cfbccc48-5g8px", `control_response_total{addr="proxy-api.linkerd.svc.cluster.local:8086",classification="success"} 10
+`),
+			proxy("emojivoto/emoji-d9c7866bb-7v74n", `control_response_total{addr="proxy-api.linkerd.svc.cluster.local:8086",classification="success"} 1
+control_response_total{addr="proxy-api.linkerd.svc.cluster.local:8086",classification="failure"} 20
+`),
+		}
+
+		err := validateProxyControlPlaneConnectivity(sampled)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Data plane proxies are failing to reach the control plane: emojivoto/emoji-d9c7866bb-7v74n cannot reach destination (proxy-api.linkerd.svc.cluster.local:8086)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateProxyVersionSkew(t *testing.T) {
+	pods := []*pb.Pod{
+		&pb.Pod{Name: "emojivoto/emoji-d9c7866bb-7v74n", ProxyVersion: "edge-18.10.2"},
+		&pb.Pod{Name: "emojivoto/vote-bot-644b8cb6b4-g8nlr", ProxyVersion: "edge-18.10.2"},
+		&pb.Pod{Name: "emojivoto/web-6cfbccc48-5g8px", ProxyVersion: "edge-18.9.3"},
+		&pb.Pod{Name: "books/webapp-5b7d796646-hh46d", ProxyVersion: "edge-18.7.1"},
+	}
+
+	t.Run("Aggregates proxy versions by namespace", func(t *testing.T) {
+		expected := map[string]map[string]int{
+			"emojivoto": {"edge-18.10.2": 2, "edge-18.9.3": 1},
+			"books":     {"edge-18.7.1": 1},
+		}
+
+		versions := proxyVersionsByNamespace(pods)
+		if !reflect.DeepEqual(versions, expected) {
+			t.Fatalf("Expected versions %v, but got %v", expected, versions)
+		}
+	})
+
+	t.Run("Returns nil if proxies are at most one minor version behind", func(t *testing.T) {
+		err := validateProxyVersionSkew(proxyVersionsByNamespace(pods[:3]), "edge-18.10.2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if proxies are too old or newer than the control plane", func(t *testing.T) {
+		err := validateProxyVersionSkew(proxyVersionsByNamespace(pods), "edge-18.9.3")
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The control plane is running edge-18.9.3, but books has 1 proxies running edge-18.7.1, more than one minor version behind; emojivoto has 2 proxies running edge-18.10.2, which is newer than the control plane"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Ignores versions that can't be compared", func(t *testing.T) {
+		versions := map[string]map[string]int{
+			"emojivoto": {"stable-2.0.0": 1, "undefined": 1},
+		}
+		err := validateProxyVersionSkew(versions, "edge-18.10.2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestValidateNoOrphanedProxies(t *testing.T) {
+	pod := func(namespace, name string, labels map[string]string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					v1.Container{Name: "app"},
+					v1.Container{Name: "linkerd-proxy"},
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+
+	pods := []v1.Pod{
+		pod("linkerd", "controller-6f78cbd47-bc557", map[string]string{
+			"linkerd.io/control-plane-component": "controller",
+			"linkerd.io/control-plane-ns":        "linkerd",
+		}),
+		pod("emojivoto", "web-6cfbccc48-5g8px", map[string]string{"linkerd.io/control-plane-ns": "linkerd"}),
+		pod("emojivoto", "emoji-d9c7866bb-7v74n", map[string]string{"linkerd.io/control-plane-ns": "linkerd-old"}),
+	}
+
+	controlPlanes := controlPlaneNamespaces(pods)
+	if !reflect.DeepEqual(controlPlanes, map[string]bool{"linkerd": true}) {
+		t.Fatalf("Unexpected control plane namespaces: %v", controlPlanes)
+	}
+
+	t.Run("Returns nil if all proxies report to an existing control plane", func(t *testing.T) {
+		err := validateNoOrphanedProxies(pods[:2], controlPlanes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a proxy reports to a missing control plane", func(t *testing.T) {
+		err := validateNoOrphanedProxies(pods, controlPlanes)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Data plane proxies reference a control plane that does not exist: emojivoto/emoji-d9c7866bb-7v74n (control plane namespace \"linkerd-old\")" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidatePortAnnotations(t *testing.T) {
+	resources := []annotatedResource{
+		{"namespace", "emojivoto", "emojivoto", map[string]string{
+			"config.linkerd.io/skip-outbound-ports": "3306",
+		}},
+		{"deployment", "emojivoto", "web", map[string]string{
+			"config.linkerd.io/skip-inbound-ports": "8000-8080",
+			"config.linkerd.io/opaque-ports":       "25,443",
+		}},
+	}
+
+	t.Run("Returns nil if all port annotations are valid", func(t *testing.T) {
+		err := validatePortAnnotations(resources)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a port annotation is invalid", func(t *testing.T) {
+		invalid := append(resources,
+			annotatedResource{"namespace", "books", "books", map[string]string{
+				"config.linkerd.io/skip-outbound-ports": "80,abc",
+			}},
+			annotatedResource{"statefulset", "books", "db", map[string]string{
+				"config.linkerd.io/skip-inbound-ports": "9090-9000",
+			}},
+		)
+
+		err := validatePortAnnotations(invalid)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some port annotations are invalid: " +
+			"namespace/books config.linkerd.io/skip-outbound-ports=\"80,abc\" (Invalid port \"abc\": must be a number between 1 and 65535), " +
+			"books/statefulset/db config.linkerd.io/skip-inbound-ports=\"9090-9000\" (Invalid port range \"9090-9000\": upper bound is lower than lower bound)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateInboundSkipPorts(t *testing.T) {
+	t.Run("Returns nil if no proxy ports are skipped", func(t *testing.T) {
+		resources := []annotatedResource{
+			{"deployment", "emojivoto", "web", map[string]string{
+				"config.linkerd.io/skip-inbound-ports": "8000-8080",
+			}},
+		}
+
+		err := validateInboundSkipPorts(resources, defaultProxyPorts.list())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if proxy ports are skipped", func(t *testing.T) {
+		resources := []annotatedResource{
+			{"deployment", "emojivoto", "web", map[string]string{
+				"config.linkerd.io/skip-inbound-ports": "4000-4150",
+			}},
+			{"daemonset", "emojivoto", "agent", map[string]string{
+				"config.linkerd.io/skip-inbound-ports": "abc",
+			}},
+		}
+
+		err := validateInboundSkipPorts(resources, defaultProxyPorts.list())
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some skipped inbound ports are used by the proxy: emojivoto/deployment/web (4143, 4140)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateProxyInitVersions(t *testing.T) {
+	pod := func(name, initImage, proxyImage string, annotations map[string]string) v1.Pod {
+		p := v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:   "emojivoto",
+				Name:        name,
+				Labels:      map[string]string{"linkerd.io/control-plane-ns": "linkerd"},
+				Annotations: annotations,
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "linkerd-proxy", Image: proxyImage}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if initImage != "" {
+			p.Spec.InitContainers = []v1.Container{{Name: "linkerd-init", Image: initImage}}
+		}
+		return p
+	}
+
+	t.Run("Returns nil if all proxy-init versions match", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", "gcr.io/linkerd-io/proxy-init:v18.8.1", "gcr.io/linkerd-io/proxy:v18.8.1", nil),
+			pod("voting", "", "gcr.io/linkerd-io/proxy:v18.8.1", nil),
+			pod("emoji", "gcr.io/linkerd-io/proxy-init@sha256:abc", "gcr.io/linkerd-io/proxy:v18.8.1", nil),
+			pod("vote-bot", "localhost:5000/proxy-init:dev", "localhost:5000/proxy:other",
+				map[string]string{"linkerd.io/proxy-version": "dev"}),
+		}
+
+		err := validateProxyInitVersions(pods, "linkerd")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if a proxy-init version does not match", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", "gcr.io/linkerd-io/proxy-init:v18.7.3", "gcr.io/linkerd-io/proxy:v18.8.1", nil),
+			pod("voting", "gcr.io/linkerd-io/proxy-init:v18.8.1", "gcr.io/linkerd-io/proxy:v18.8.1", nil),
+		}
+
+		err := validateProxyInitVersions(pods, "linkerd")
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some pods run a proxy-init version that does not match their proxy version: emojivoto/web (proxy-init v18.7.3, proxy v18.8.1)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateNoProxyOnlyPods(t *testing.T) {
+	now := time.Date(2018, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	running := v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	terminated := v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}
+
+	pod := func(name string, appState v1.ContainerState, deletedAt *time.Time) v1.Pod {
+		p := v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "emojivoto"},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "app"}, {Name: "linkerd-proxy"}},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "app", State: appState},
+					{Name: "linkerd-proxy", State: running},
+				},
+			},
+		}
+		if deletedAt != nil {
+			ts := meta.NewTime(*deletedAt)
+			p.DeletionTimestamp = &ts
+		}
+		return p
+	}
+
+	recently := now.Add(-time.Minute)
+	longAgo := now.Add(-time.Hour)
+
+	t.Run("Returns nil if application containers are running", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", running, nil),
+			pod("voting", terminated, &recently),
+		}
+
+		err := validateNoProxyOnlyPods(pods, now, 5*time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if only the proxy is running", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", running, nil),
+			pod("migrate-job", terminated, nil),
+			pod("voting", terminated, &longAgo),
+		}
+
+		err := validateNoProxyOnlyPods(pods, now, 5*time.Minute)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some pods are kept running by the \"linkerd-proxy\" container; " +
+			"application containers have exited but the proxy is still running: emojivoto/migrate-job; " +
+			"pods are stuck Terminating with only the proxy running: emojivoto/voting (terminating since 2018-10-01T11:00:00Z). " +
+			"Jobs and other run-to-completion workloads should be left uninjected, or stop the proxy once the main container exits"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateServiceProfileReasons(t *testing.T) {
+	t.Run("Returns nil if no ServiceProfiles are invalid", func(t *testing.T) {
+		if err := validateServiceProfileReasons(nil); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if payload := serviceProfilesPayload(nil); payload != nil {
+			t.Fatalf("Unexpected payload: %v", payload)
+		}
+	})
+
+	t.Run("Lists every invalid ServiceProfile and caps the payload", func(t *testing.T) {
+		invalid := []invalidServiceProfile{}
+		for i := 0; i < maxServiceProfileDetails+5; i++ {
+			invalid = append(invalid, invalidServiceProfile{
+				Name:    fmt.Sprintf("linkerd/svc-%d.default.svc.cluster.local", i),
+				Reasons: []string{"spec.routes[0].condition: must be set", "spec.routes[1].name: must not be empty"},
+			})
+		}
+
+		err := validateServiceProfileReasons(invalid[:2])
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some ServiceProfiles are invalid: " +
+			"linkerd/svc-0.default.svc.cluster.local (spec.routes[0].condition: must be set; spec.routes[1].name: must not be empty), " +
+			"linkerd/svc-1.default.svc.cluster.local (spec.routes[0].condition: must be set; spec.routes[1].name: must not be empty)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+
+		payload := serviceProfilesPayload(invalid).(map[string]interface{})
+		if payload["invalidCount"] != maxServiceProfileDetails+5 {
+			t.Fatalf("Unexpected invalid count: %v", payload["invalidCount"])
+		}
+		if len(payload["invalid"].([]invalidServiceProfile)) != maxServiceProfileDetails {
+			t.Fatalf("Expected payload to be capped at %d profiles", maxServiceProfileDetails)
+		}
+	})
+}
+
+func TestControlPlaneChecksDoNotGateCommands(t *testing.T) {
+	checkOnly := []string{
+		"service profile CRD is established",
+		"proxy injector responds quickly",
+		"control plane resources have the expected labels",
+		"control plane storage is provisioned",
+		"controller has the permissions it needs",
+		"no invalid service profiles",
+		"no invalid traffic splits",
+	}
+
+	descriptions := func(checks Checks) map[string]bool {
+		found := make(map[string]bool)
+		for _, c := range NewHealthChecker([]Checks{checks}, &HealthCheckOptions{}).checkers {
+			found[c.description] = true
+		}
+		return found
+	}
+	apiChecks := descriptions(LinkerdAPIChecks)
+	controlPlaneChecks := descriptions(LinkerdControlPlaneChecks)
+
+	for _, description := range checkOnly {
+		if apiChecks[description] {
+			t.Fatalf("Expected [%s] not to be run before the commands calling the public API", description)
+		}
+		if !controlPlaneChecks[description] {
+			t.Fatalf("Expected [%s] to be one of the control plane checks", description)
+		}
+	}
+}
+
+func TestValidateServiceProfilesServiceLookup(t *testing.T) {
+	profileList := `{"items":[{"metadata":{"name":"web.emojivoto.svc.cluster.local","namespace":"linkerd"},` +
+		`"spec":{"routes":[{"name":"GET /","condition":{"method":"GET","pathRegex":"/"}}]}}]}`
+
+	testCases := []struct {
+		description string
+		status      int
+		reason      meta.StatusReason
+		invalid     bool
+		err         string
+	}{
+		{"accepts a profile of an existing service", http.StatusOK, "", false, ""},
+		{"reports a profile of an unknown service", http.StatusNotFound, meta.StatusReasonNotFound, true, ""},
+		{"fails if the service cannot be read", http.StatusForbidden, meta.StatusReasonForbidden, false, "Forbidden"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/apis/linkerd.io/v1alpha1/namespaces/linkerd/serviceprofiles":
+					w.Write([]byte(profileList))
+				case "/api/v1/namespaces/emojivoto/services/web":
+					if tc.status != http.StatusOK {
+						w.WriteHeader(tc.status)
+						fmt.Fprintf(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"%s","reason":%q,"code":%d}`, tc.reason, tc.reason, tc.status)
+						return
+					}
+					w.Write([]byte(`{"metadata":{"name":"web","namespace":"emojivoto"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			defer done()
+
+			err := hc.validateServiceProfiles(context.Background())
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("Expected an error containing [%s], got [%v]", tc.err, err)
+				}
+				if len(hc.invalidServiceProfiles) != 0 {
+					t.Fatalf("Expected no profile to be reported as invalid, got %v", hc.invalidServiceProfiles)
+				}
+				return
+			}
+			if tc.invalid != (err != nil) {
+				t.Fatalf("Unexpected result [%v]", err)
+			}
+			if tc.invalid && !strings.Contains(err.Error(), "metadata.name: unknown service") {
+				t.Fatalf("Expected the service to be reported as unknown, got [%s]", err)
+			}
+		})
+	}
+
+	t.Run("cancels its requests with the context", func(t *testing.T) {
+		hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(profileList))
+		})
+		defer done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := hc.validateServiceProfiles(ctx); err == nil {
+			t.Fatal("Expected the requests to be canceled with the context")
+		}
+	})
+}
+
+func TestSkippedTLSVerificationIsReported(t *testing.T) {
+	for _, insecure := range []bool{false, true} {
+		hc := NewHealthChecker([]Checks{KubernetesAPIChecks}, &HealthCheckOptions{KubeInsecureSkipTLSVerify: insecure})
+
+		var found *checker
+		for _, c := range hc.checkers {
+			if c.description == "verifies the Kubernetes API server's certificate" {
+				found = c
+			}
+		}
+		if !insecure {
+			if found != nil {
+				t.Fatal("Unexpected TLS verification check")
+			}
+			continue
+		}
+
+		if found == nil || !found.warning {
+			t.Fatalf("Expected a TLS verification warning, got %+v", found)
+		}
+		hc.kubeAPI = &k8s.KubernetesAPI{Config: &rest.Config{}}
+		hc.kubeAPI.SkipTLSVerify()
+		if err := found.check(context.Background()); err == nil {
+			t.Fatal("Expected the skipped verification to be reported")
+		}
+	}
+}
+
+func TestLinkerdVersionChecks(t *testing.T) {
+	cliVersion := version.Version
+	defer func() { version.Version = cliVersion }()
+
+	testCases := []struct {
+		name          string
+		cliVersion    string
+		serverVersion string
+		deployed      string
+		expected      []string
+	}{
+		{
+			"Passes when the CLI and control plane run the expected version",
+			"stable-2.1.0",
+			"stable-2.1.0",
+			"stable-2.1.0",
+			[]string{
+				"linkerd-version can determine the latest version",
+				"linkerd-version cli is up-to-date",
+				"linkerd-version control plane is up-to-date",
+				"linkerd-version control plane version is supported",
+				"linkerd-version cli and control plane are on the same release channel",
+			},
+		},
+		{
+			"Compares each version within its own channel",
+			"edge-18.12.1",
+			"stable-2.0.0",
+			"stable-2.0.0",
+			[]string{
+				"linkerd-version can determine the latest version",
+				"linkerd-version cli is up-to-date: is running version 18.12.1 but the latest edge version is unknown",
+				"linkerd-version control plane is up-to-date: is running version 2.0.0 but the latest stable version is 2.1.0 (1 release behind)",
+				"linkerd-version control plane version is supported",
+				"linkerd-version cli and control plane are on the same release channel: the CLI runs the edge version 18.12.1 but the control plane runs the stable version 2.0.0; install the matching CLI with \"curl -sL https://run.linkerd.io/install | sh\"",
+			},
+		},
+		{
+			"Skips the comparison of development builds",
+			"dev-0123abcd-jane",
+			"stable-2.1.0",
+			"stable-2.1.0",
+			[]string{
+				"linkerd-version can determine the latest version",
+				"linkerd-version cli is up-to-date (skipped): running development build dev-0123abcd-jane, skipping version comparison",
+				"linkerd-version control plane is up-to-date",
+				"linkerd-version control plane version is supported",
+				"linkerd-version cli and control plane are on the same release channel (skipped): running development build dev-0123abcd-jane, skipping release channel comparison",
+			},
+		},
+		{
+			"Fails when the control plane components run different versions",
+			"stable-2.1.0",
+			"stable-2.1.0",
+			"stable-2.0.0",
+			[]string{
+				"linkerd-version can determine the latest version",
+				"linkerd-version cli is up-to-date",
+				"linkerd-version control plane is up-to-date: the control plane components run different versions, and the public API reports stable-2.1.0: " +
+					"linkerd-controller runs stable-2.1.0, linkerd-web runs stable-2.0.0 (1 release behind the latest stable version)",
+				"linkerd-version control plane version is supported",
+				"linkerd-version cli and control plane are on the same release channel",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			version.Version = tc.cliVersion
+			hc, done := newTestHealthChecker(t, []Checks{LinkerdVersionChecks}, &HealthCheckOptions{
+				ControlPlaneNamespace:          "linkerd",
+				VersionOverride:                "stable-2.1.0",
+				ShouldCheckControlPlaneVersion: true,
+			}, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"items":[
+					{"metadata":{"name":"linkerd-controller"},"spec":{"template":{"spec":{"containers":[
+						{"name":"public-api","image":"gcr.io/linkerd-io/controller:` + tc.serverVersion + `"}]}}}},
+					{"metadata":{"name":"linkerd-web"},"spec":{"template":{"spec":{"containers":[
+						{"name":"linkerd-proxy","image":"gcr.io/linkerd-io/proxy:` + tc.serverVersion + `"},
+						{"name":"web","image":"gcr.io/linkerd-io/web:` + tc.deployed + `"}]}}}}]}`))
+			})
+			defer done()
+
+			hc.apiClient = &public.MockApiClient{
+				VersionInfoToReturn: &pb.VersionInfo{ReleaseVersion: tc.serverVersion},
+			}
+
+			observed := []string{}
+			hc.RunChecks(func(result *CheckResult) {
+				entry := fmt.Sprintf("%s %s", result.Category, result.Description)
+				if result.Skipped {
+					entry += " (skipped)"
+				}
+				if result.Err != nil {
+					entry += fmt.Sprintf(": %s", result.Err)
+				}
+				observed = append(observed, entry)
+			})
+
+			if !reflect.DeepEqual(observed, tc.expected) {
+				t.Fatalf("Expected results %v, but got %v", tc.expected, observed)
+			}
+		})
+	}
+}
+
+func TestLinkerdVersionChecksWithCustomEndpoint(t *testing.T) {
+	cliVersion := version.Version
+	defer func() { version.Version = cliVersion }()
+	version.Version = "stable-2.1.0"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"stable":"stable-2.1.0","edge":"edge-18.12.1"}`))
+	}))
+	defer server.Close()
+
+	hc := NewHealthChecker([]Checks{LinkerdVersionChecks}, &HealthCheckOptions{
+		LatestVersionURL:      server.URL,
+		LatestVersionCacheTTL: -1,
+	})
+
+	observed := []string{}
+	hc.RunChecks(func(result *CheckResult) {
+		entry := fmt.Sprintf("%s %s", result.Category, result.Description)
+		if result.Err != nil {
+			entry += fmt.Sprintf(": %s", result.Err)
+		}
+		observed = append(observed, entry)
+	})
+
+	expected := []string{
+		"linkerd-version can determine the latest version",
+		"linkerd-version latest version information is current",
+		fmt.Sprintf("linkerd-version uses the default version endpoint: the latest versions are served by %s rather than %s", server.URL, version.DefaultLatestVersionURL),
+		"linkerd-version cli is up-to-date",
+	}
+	if !reflect.DeepEqual(observed, expected) {
+		t.Fatalf("Expected results %v, but got %v", expected, observed)
+	}
+
+	expectedRequest := server.URL + "/version.json?source=cli&uuid=unknown&version=stable-2.1.0"
+	if request := hc.LatestVersionRequest(); request != expectedRequest {
+		t.Fatalf("Expected the latest versions to be requested from %s, got %s", expectedRequest, request)
+	}
+
+	hc.AnonymousVersionCheck = true
+	hc.RunChecks(func(*CheckResult) {})
+	if request := hc.LatestVersionRequest(); request != server.URL+"/version.json" {
+		t.Fatalf("Expected an anonymous request, got %s", request)
+	}
+}
+
+func TestMinimumSupportedVersionCheck(t *testing.T) {
+	cliVersion := version.Version
+	defer func() { version.Version = cliVersion }()
+	version.Version = "stable-2.2.0"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version.json" {
+			w.Write([]byte(`{"stable":"stable-2.2.0","edge":"edge-19.2.3","minimumSupported":{"stable":"stable-2.1.0"}}`))
+			return
+		}
+		w.Write([]byte(`{"items":[
+			{"metadata":{"name":"linkerd-controller"},"spec":{"template":{"spec":{"containers":[
+				{"name":"public-api","image":"gcr.io/linkerd-io/controller:stable-2.0.0"}]}}}}]}`))
+	}))
+	defer server.Close()
+
+	hc := NewHealthChecker([]Checks{LinkerdVersionChecks}, &HealthCheckOptions{
+		ControlPlaneNamespace:          "linkerd",
+		LatestVersionURL:               server.URL,
+		LatestVersionCacheTTL:          -1,
+		ShouldCheckControlPlaneVersion: true,
+	})
+	hc.kubeAPI = &k8s.KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+	hc.apiClient = &public.MockApiClient{
+		VersionInfoToReturn: &pb.VersionInfo{ReleaseVersion: "stable-2.0.0"},
+	}
+
+	var supported *CheckResult
+	hc.RunChecks(func(result *CheckResult) {
+		if result.Description == "control plane version is supported" {
+			supported = result
+		}
+	})
+
+	if supported == nil {
+		t.Fatal("Expected the control plane's version to be checked against the minimum supported version")
+	}
+	if supported.Warning {
+		t.Fatal("Expected an unsupported control plane to fail the check, not warn")
+	}
+	expected := "is running version 2.0.0, which is below the minimum supported stable version 2.1.0"
+	if supported.Err == nil || supported.Err.Error() != expected {
+		t.Fatalf("Expected error [%s], got [%v]", expected, supported.Err)
+	}
+}
+
+func TestCheckDataPlaneVersions(t *testing.T) {
+	observed := map[string]int{"stable-2.1.0": 2, "stable-2.0.0": 1, "edge-18.12.1": 1, "dev-0123abcd-jane": 1}
+
+	t.Run("Compares each channel's proxies with its latest version", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{})
+		hc.latestVersions = version.Channels{version.StableChannel: "stable-2.1.0", version.EdgeChannel: "edge-18.12.1"}
+
+		summaries, err := hc.checkDataPlaneVersions(observed)
+		expected := "1 proxy is not running the latest stable version 2.1.0: 1 running 2.0.0 (1 release behind)"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+		if len(summaries) != 2 || summaries[0].Expected != "edge-18.12.1" || summaries[1].Expected != "stable-2.1.0" {
+			t.Fatalf("Expected a summary per channel, got %+v", summaries)
+		}
+	})
+
+	t.Run("Compares every proxy with the expected version", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{VersionOverride: "stable-2.1.0"})
+
+		summaries, err := hc.checkDataPlaneVersions(observed)
+		if err == nil {
+			t.Fatal("Expected the outdated proxy to be reported")
+		}
+		if len(summaries) != 1 || summaries[0].OtherChannel["edge-18.12.1"] != 1 {
+			t.Fatalf("Expected a single summary, got %+v", summaries)
+		}
+	})
+}
+
+func TestVersionPayload(t *testing.T) {
+	if payload := versionPayload("", nil); payload != nil {
+		t.Fatalf("Unexpected payload: %v", payload)
+	}
+
+	if payload := versionPayload("edge", nil); !reflect.DeepEqual(payload, map[string]interface{}{"channel": "edge"}) {
+		t.Fatalf("Unexpected payload: %v", payload)
+	}
+
+	mismatch := &version.VersionMismatchError{Component: "cli", Current: "stable-2.0.0", Latest: "stable-2.1.0", Channel: "stable"}
+	expected := map[string]interface{}{"component": "cli", "current": "stable-2.0.0", "latest": "stable-2.1.0", "channel": "stable"}
+	if payload := versionPayload("stable", mismatch); !reflect.DeepEqual(payload, expected) {
+		t.Fatalf("Expected payload %v, got %v", expected, payload)
+	}
+}
+
+func TestControlPlaneVersionCheck(t *testing.T) {
+	deployments := `{"items":[{"metadata":{"name":"linkerd-controller"},"spec":{"template":{"spec":{"containers":[
+		{"name":"public-api","image":"gcr.io/linkerd-io/controller:stable-2.1.0"}]}}}}]}`
+
+	testCases := []struct {
+		description string
+		client      *public.MockApiClient
+		deployments string
+		err         string
+		version     *controlPlaneVersion
+	}{
+		{
+			"reports the version returned by the public API",
+			&public.MockApiClient{VersionInfoToReturn: &pb.VersionInfo{ReleaseVersion: "stable-2.2.0", GoVersion: "go1.10", BuildDate: "2019-01-01"}},
+			deployments,
+			"",
+			&controlPlaneVersion{ReleaseVersion: "stable-2.2.0", GoVersion: "go1.10", BuildDate: "2019-01-01", Source: versionSourcePublicAPI},
+		},
+		{
+			"falls back to the controller Deployment's image tag",
+			&public.MockApiClient{ErrorToReturn: errors.New("unavailable")},
+			deployments,
+			"the control plane API failed to report its version: unavailable; falling back to the controller Deployment's image tag stable-2.1.0, which may not be the version running",
+			&controlPlaneVersion{ReleaseVersion: "stable-2.1.0", Source: versionSourceDeployment},
+		},
+		{
+			"fails without a controller Deployment to fall back to",
+			&public.MockApiClient{ErrorToReturn: errors.New("unavailable")},
+			`{"items":[]}`,
+			"the control plane API failed to report its version, which could not be read from the controller Deployment either: unavailable; the version of the controller Deployment cannot be determined",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			var selector string
+			hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+				selector = r.URL.Query().Get("labelSelector")
+				w.Write([]byte(tc.deployments))
+			})
+			defer done()
+			hc.apiClient = tc.client
+
+			err := hc.checkControlPlaneVersion(context.Background())
+			if tc.err == "" && err != nil || tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.err, err)
+			}
+			if !reflect.DeepEqual(hc.controlPlaneVersion, tc.version) {
+				t.Fatalf("Expected version %+v, got %+v", tc.version, hc.controlPlaneVersion)
+			}
+			if tc.client.ErrorToReturn != nil && selector != k8s.ControllerComponentLabel+"=controller" {
+				t.Fatalf("Expected the controller Deployment to be selected, got selector %q", selector)
+			}
+
+			if tc.version != nil {
+				serverVersion, err := hc.serverVersion(context.Background())
+				if err != nil || serverVersion != tc.version.ReleaseVersion {
+					t.Fatalf("Expected the version checks to compare %s, got %s (%v)", tc.version.ReleaseVersion, serverVersion, err)
+				}
+			}
+		})
+	}
+}
+
+func TestInitClientCheckReportsProxyURL(t *testing.T) {
+	testCases := []struct {
+		description string
+		proxyPath   public.ProxyPathOptions
+		err         string
+	}{
+		{
+			"reports the URL composed from the proxy path options",
+			public.ProxyPathOptions{ServiceName: "mesh-api", PortName: "admin-http"},
+			"No running controller pod to port-forward to in the linkerd namespace (public API proxy URL: %s/api/v1/namespaces/linkerd/services/http:mesh-api:admin-http/proxy/)",
+		},
+		{
+			"rejects proxy path options containing a slash",
+			public.ProxyPathOptions{ServiceName: "api/proxy/../../secrets"},
+			"invalid public API proxy service name [api/proxy/../../secrets]: must not contain a slash",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			hc, done := newTestHealthChecker(t, []Checks{LinkerdAPIChecks}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd", APIProxyPath: tc.proxyPath}, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v1/namespaces/linkerd/pods" {
+					w.Write([]byte(`{"items":[]}`))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			})
+			defer done()
+			hc.runCtx = context.Background()
+
+			var initClient *checker
+			for _, c := range hc.checkers {
+				if c.description == "can initialize the client" {
+					initClient = c
+				}
+			}
+			if initClient == nil {
+				t.Fatal("Expected a check initializing the client")
+			}
+
+			err := initClient.check(context.Background())
+			expected := tc.err
+			if strings.Contains(expected, "%s") {
+				expected = fmt.Sprintf(expected, hc.kubeAPI.Config.Host)
+			}
+			if err == nil || err.Error() != expected {
+				t.Fatalf("Expected error [%s], got [%v]", expected, err)
+			}
+		})
+	}
+}
+
+type fakeRequestRecorder struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (r *fakeRequestRecorder) RecordRequest(record k8s.RequestRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.urls = append(r.urls, record.URL)
+}
+
+func TestInitClientCheckSharesKubernetesTransport(t *testing.T) {
+	hc, done := newTestHealthChecker(t, []Checks{LinkerdAPIChecks}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/linkerd/services/http:api:http/proxy/api/v1/Version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		msg, err := proto.Marshal(&pb.VersionInfo{ReleaseVersion: "stable-2.1.0"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		size := make([]byte, 4)
+		binary.LittleEndian.PutUint32(size, uint32(len(msg)))
+		w.Write(append(size, msg...))
+	})
+	defer done()
+
+	recorder := &fakeRequestRecorder{}
+	hc.kubeAPI.Recorder = recorder
+	hc.runCtx = context.Background()
+	defer hc.ClosePublicAPIClient()
+
+	for _, c := range hc.checkers {
+		if c.description == "can initialize the client" {
+			if err := c.check(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+	}
+
+	if hc.apiConn == nil || hc.apiConn.Transport != public.KubernetesProxyTransport {
+		t.Fatalf("Expected a client using the service proxy, got %+v", hc.apiConn)
+	}
+	expected := []string{
+		"/api/v1/namespaces/linkerd/services/http:api:http/proxy/ping",
+		"/api/v1/namespaces/linkerd/services/http:api:http/proxy/api/v1/Version",
+		"/api/v1/namespaces/linkerd/services/http:api:http/proxy/api/v1/Version",
+	}
+	if !reflect.DeepEqual(recorder.urls, expected) {
+		t.Fatalf("Expected the Kubernetes request recorder to observe %v, got %v", expected, recorder.urls)
+	}
+}
+
+func TestInitClientCheckHints(t *testing.T) {
+	proxyPath := "/api/v1/namespaces/linkerd/services/http:api:http/proxy/"
+	kubeStatus := func(w http.ResponseWriter, code int, message string) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":%q,"code":%d}`, message, code)
+	}
+
+	testCases := []struct {
+		description string
+		proxy       func(w http.ResponseWriter, r *http.Request)
+		anchor      string
+		err         string
+	}{
+		{
+			"hints at the proxy path if the service is not found",
+			func(w http.ResponseWriter, r *http.Request) {
+				kubeStatus(w, http.StatusNotFound, `services "api" not found`)
+			},
+			hintAnchorAPIProxyPath,
+			"the Kubernetes API server found no service at the public API's proxy path",
+		},
+		{
+			"hints at the controller if it refuses the connection",
+			func(w http.ResponseWriter, r *http.Request) {
+				kubeStatus(w, http.StatusServiceUnavailable, "error trying to reach service: dial tcp 10.1.2.3:8085: connect: connection refused")
+			},
+			hintAnchorAPIControllerListening,
+			"the controller is not listening",
+		},
+		{
+			"hints at gRPC if the controller answers the ping but not the API",
+			func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == proxyPath+"ping" {
+					w.Write([]byte("pong\n"))
+					return
+				}
+				w.Write([]byte("<html>upgrade required</html>"))
+			},
+			hintAnchorAPIGRPC,
+			"the controller answered a ping through the Kubernetes API server's service proxy, but its gRPC API failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			hc, done := newTestHealthChecker(t, []Checks{LinkerdAPIChecks}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, proxyPath):
+					tc.proxy(w, r)
+				case r.URL.Path == "/api/v1/namespaces/linkerd/pods":
+					w.Write([]byte(`{"items":[]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			defer done()
+
+			hc.runCtx = context.Background()
+			defer hc.ClosePublicAPIClient()
+
+			var err error
+			for _, c := range hc.checkers {
+				if c.description == "can initialize the client" {
+					err = c.check(context.Background())
+				}
+			}
+			if err == nil || hintAnchor(err) != tc.anchor || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("Expected an error with the hint anchor [%s] containing [%s], got [%v]", tc.anchor, tc.err, err)
+			}
+		})
+	}
+}
+
+func TestChecksWithoutKubeconfigForAPIURL(t *testing.T) {
+	options := &HealthCheckOptions{
+		KubeConfig:      "/nonexistent/kubeconfig",
+		APIAddr:         "https://linkerd-api.example.com",
+		VersionOverride: "stable-2.1.0",
+	}
+	hc := NewHealthChecker([]Checks{KubernetesAPIChecks, LinkerdVersionChecks}, options)
+
+	results := make(map[string]*CheckResult)
+	hc.RunChecks(func(result *CheckResult) {
+		results[result.Description] = result
+	})
+
+	if result := results["can initialize the client"]; result == nil || !result.Skipped {
+		t.Fatalf("Expected the Kubernetes client check to be skipped, got %+v", result)
+	}
+	if result := results["the Kubernetes API server is healthy"]; result == nil || !result.Skipped || result.Err.Error() != "Skipped as no Kubernetes API is configured" {
+		t.Fatalf("Expected the Kubernetes API server check to be skipped for lack of a Kubernetes API, got %+v", result)
+	}
+	if result := results["can determine the latest version"]; result == nil || result.Skipped || result.Err != nil {
+		t.Fatalf("Expected the latest version check to run, got %+v", result)
+	}
+
+	options.APIAddr = "linkerd-api.linkerd.svc.cluster.local:8085"
+	if NewHealthChecker([]Checks{KubernetesAPIChecks}, options).RunChecks(func(*CheckResult) {}) {
+		t.Fatal("Expected the Kubernetes client check to fail without a kubeconfig")
+	}
+}
@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// injectorLatencyThreshold is the injection round trip above which pod
+	// creation is noticeably delayed.
+	injectorLatencyThreshold = 2 * time.Second
+
+	// defaultWebhookTimeout is the time the API server waits for a webhook
+	// whose configuration doesn't set timeoutSeconds.
+	defaultWebhookTimeout = 30 * time.Second
+
+	injectorProbeImage = "gcr.io/google_containers/pause:3.1"
+)
+
+// injectorProbeTimeout bounds the dry-run Deployment creation, rather than the
+// webhook's timeout, so that a stalled injector doesn't hold up the checks; an
+// injector that slow delays pod creation anyway.
+var injectorProbeTimeout = 5 * time.Second
+
+// probeInjector measures the round trip of a dry-run Deployment creation in a
+// namespace in which auto-injection is enabled, bounded by the
+// injectorProbeTimeout. The Deployment is never persisted: the request is only
+// sent to API servers supporting dry-run. The probe is skipped if the caller
+// may not create Deployments.
+func (hc *HealthChecker) probeInjector(ctx context.Context) error {
+	webhookTimeout, found, err := hc.getInjectorWebhookTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return &SkipError{Reason: "The proxy injector is not installed"}
+	}
+
+	if hc.kubeVersion == nil {
+		hc.kubeVersion, err = hc.kubeAPI.GetVersionInfo(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if !hc.kubeAPI.SupportsDryRun(hc.kubeVersion) {
+		return &SkipError{Reason: k8s.ErrDryRunUnsupported.Error()}
+	}
+
+	namespaces, err := hc.kubeAPI.GetNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	namespace := injectorProbeNamespace(namespaces, hc.DataPlaneNamespace, hc.ControlPlaneNamespace)
+	if namespace == "" {
+		return &SkipError{Reason: "No namespace has auto-injection enabled"}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, injectorProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	created, err := hc.kubeAPI.DryRunCreateDeployment(probeCtx, hc.kubeVersion, injectorProbeDeployment(namespace))
+	latency := time.Since(start)
+	if err == k8s.ErrDryRunUnsupported {
+		return &SkipError{Reason: err.Error()}
+	}
+	if k8s.IsForbidden(err) {
+		return &SkipError{Reason: fmt.Sprintf("Not permitted to create Deployments in the \"%s\" namespace: %s", namespace, err)}
+	}
+	if err != nil {
+		if probeCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+			return fmt.Errorf("The proxy injector did not respond within %s, which delays pod creation", injectorProbeTimeout)
+		}
+		return err
+	}
+
+	return validateInjectorLatency(latency, webhookTimeout, k8s.GetProxyContainer(&created.Spec.Template.Spec) != nil)
+}
+
+// getInjectorWebhookTimeout returns the timeout of the proxy injector's
+// webhook, and false if its configuration doesn't exist. The configuration is
+// read unstructured, as timeoutSeconds is not part of the typed API.
+func (hc *HealthChecker) getInjectorWebhookTimeout(ctx context.Context) (time.Duration, bool, error) {
+	path := k8s.PathWithQuery("/apis/admissionregistration.k8s.io/v1beta1/mutatingwebhookconfigurations",
+		url.Values{"fieldSelector": {"metadata.name=" + k8s.ProxyInjectorWebhookConfig}})
+	list, err := hc.kubeAPI.GetUnstructuredList(ctx, path)
+	if err != nil {
+		return 0, false, err
+	}
+	if list == nil || len(list.Items) == 0 {
+		return 0, false, nil
+	}
+
+	return webhookTimeout(list.Items[0].Object), true, nil
+}
+
+// webhookTimeout returns the shortest timeout of the webhooks in the given
+// unstructured webhook configuration.
+func webhookTimeout(config map[string]interface{}) time.Duration {
+	timeout := defaultWebhookTimeout
+
+	webhooks, _, _ := unstructured.NestedSlice(config, "webhooks")
+	for _, w := range webhooks {
+		webhook, ok := w.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if seconds, ok, _ := unstructured.NestedInt64(webhook, "timeoutSeconds"); ok {
+			if t := time.Duration(seconds) * time.Second; t < timeout {
+				timeout = t
+			}
+		}
+	}
+
+	return timeout
+}
+
+// injectorProbeNamespace returns the namespace to probe the injector in: the
+// data plane namespace if set, or else the first namespace, alphabetically,
+// that isn't excluded from auto-injection. It returns "" if no namespace
+// qualifies.
+func injectorProbeNamespace(namespaces []v1.Namespace, dataPlaneNamespace, controlPlaneNamespace string) string {
+	candidates := []string{}
+	for _, ns := range namespaces {
+		if ns.Name == controlPlaneNamespace || ns.Labels[k8s.ProxyAutoInjectLabel] == k8s.ProxyAutoInjectDisabled {
+			continue
+		}
+		if ns.Name == dataPlaneNamespace {
+			return ns.Name
+		}
+		candidates = append(candidates, ns.Name)
+	}
+
+	if dataPlaneNamespace != "" || len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[0]
+}
+
+func injectorProbeDeployment(namespace string) *appsV1.Deployment {
+	replicas := int32(0)
+	labels := map[string]string{"app": "linkerd-check-injector-probe"}
+
+	return &appsV1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			GenerateName: "linkerd-check-injector-probe-",
+			Namespace:    namespace,
+		},
+		Spec: appsV1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &meta_v1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: meta_v1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "pause", Image: injectorProbeImage}},
+				},
+			},
+		},
+	}
+}
+
+// validateInjectorLatency returns an error if the injection round trip is
+// slow, or uses more than half of the webhook's timeout, or if the probe was
+// not injected at all.
+func validateInjectorLatency(latency, timeout time.Duration, injected bool) error {
+	if !injected {
+		return fmt.Errorf("The dry-run Deployment was not injected by the proxy injector")
+	}
+
+	latency = latency.Round(time.Millisecond)
+	if latency > timeout/2 {
+		return fmt.Errorf("The proxy injector took %s to respond, leaving little headroom under the webhook's %s timeout", latency, timeout)
+	}
+	if latency > injectorLatencyThreshold {
+		return fmt.Errorf("The proxy injector took %s to respond, which delays pod creation", latency)
+	}
+
+	return nil
+}
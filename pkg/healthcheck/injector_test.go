@@ -0,0 +1,144 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateInjectorLatency(t *testing.T) {
+	t.Run("Returns nil if the injector responds quickly", func(t *testing.T) {
+		err := validateInjectorLatency(300*time.Millisecond, 30*time.Second, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if the probe was not injected", func(t *testing.T) {
+		err := validateInjectorLatency(300*time.Millisecond, 30*time.Second, false)
+		if err == nil || err.Error() != "The dry-run Deployment was not injected by the proxy injector" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Returns an error if the injector is slow", func(t *testing.T) {
+		err := validateInjectorLatency(3*time.Second, 30*time.Second, true)
+		if err == nil || err.Error() != "The proxy injector took 3s to respond, which delays pod creation" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Returns an error if the webhook timeout leaves little headroom", func(t *testing.T) {
+		err := validateInjectorLatency(1500*time.Millisecond, 2*time.Second, true)
+		if err == nil || err.Error() != "The proxy injector took 1.5s to respond, leaving little headroom under the webhook's 2s timeout" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	config := map[string]interface{}{
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "a"},
+			map[string]interface{}{"name": "b", "timeoutSeconds": int64(10)},
+		},
+	}
+	if timeout := webhookTimeout(config); timeout != 10*time.Second {
+		t.Fatalf("Expected 10s, got %s", timeout)
+	}
+	if timeout := webhookTimeout(map[string]interface{}{}); timeout != 30*time.Second {
+		t.Fatalf("Expected 30s, got %s", timeout)
+	}
+}
+
+func TestInjectorProbeNamespace(t *testing.T) {
+	namespaces := []v1.Namespace{
+		{ObjectMeta: meta.ObjectMeta{Name: "linkerd"}},
+		{ObjectMeta: meta.ObjectMeta{Name: "kube-system", Labels: map[string]string{"linkerd.io/auto-inject": "disabled"}}},
+		{ObjectMeta: meta.ObjectMeta{Name: "emojivoto"}},
+		{ObjectMeta: meta.ObjectMeta{Name: "default"}},
+	}
+
+	testCases := []struct {
+		dataPlaneNamespace string
+		expected           string
+	}{
+		{"", "default"},
+		{"emojivoto", "emojivoto"},
+		{"kube-system", ""},
+		{"missing", ""},
+	}
+
+	for _, tc := range testCases {
+		if ns := injectorProbeNamespace(namespaces, tc.dataPlaneNamespace, "linkerd"); ns != tc.expected {
+			t.Fatalf("Expected %q for data plane namespace %q, got %q", tc.expected, tc.dataPlaneNamespace, ns)
+		}
+	}
+}
+
+func TestProbeInjector(t *testing.T) {
+	defer func(timeout time.Duration) { injectorProbeTimeout = timeout }(injectorProbeTimeout)
+	injectorProbeTimeout = 50 * time.Millisecond
+
+	testCases := []struct {
+		description string
+		create      http.HandlerFunc
+		skipped     bool
+		err         string
+	}{
+		{
+			"skips the probe if Deployments may not be created",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			true,
+			"",
+		},
+		{
+			"reports an injector that does not respond in time",
+			func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(200 * time.Millisecond):
+				}
+			},
+			false,
+			"The proxy injector did not respond within 50ms, which delays pod creation",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/apis/admissionregistration.k8s.io/v1beta1/mutatingwebhookconfigurations":
+					w.Write([]byte(`{"apiVersion":"admissionregistration.k8s.io/v1beta1","kind":"MutatingWebhookConfigurationList","items":[` +
+						`{"apiVersion":"admissionregistration.k8s.io/v1beta1","kind":"MutatingWebhookConfiguration","metadata":{"name":"linkerd-proxy-injector-webhook-config"},"webhooks":[{"name":"linkerd-proxy-injector.linkerd.io"}]}]}`))
+				case "/version":
+					w.Write([]byte(`{"major":"1","minor":"14","gitVersion":"v1.14.0"}`))
+				case "/api/v1/namespaces":
+					w.Write([]byte(`{"items":[{"metadata":{"name":"emojivoto"}}]}`))
+				case "/apis/apps/v1/namespaces/emojivoto/deployments":
+					tc.create(w, r)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			defer done()
+
+			err := hc.probeInjector(context.Background())
+			if _, ok := err.(*SkipError); ok != tc.skipped {
+				t.Fatalf("Unexpected result [%v]", err)
+			}
+			if tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Fatalf("Expected the error [%s], got [%v]", tc.err, err)
+			}
+		})
+	}
+}
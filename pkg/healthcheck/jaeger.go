@@ -0,0 +1,124 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+)
+
+const (
+	jaegerExtensionName = "jaeger"
+
+	jaegerCollectorName = "collector"
+	jaegerDeployment    = "jaeger"
+
+	// the jaeger injector sets this environment variable on the proxy
+	// containers of pods in traced namespaces
+	proxyTraceCollectorEnvVarName = "LINKERD2_PROXY_TRACE_COLLECTOR_SVC_ADDR"
+)
+
+// jaegerCheckSuite returns the checks for the jaeger extension installed in
+// the given namespace. Failures of the collector checks are reported as the
+// collector being down, whereas failures of the pod check are reported as
+// injection not happening, since remediation differs.
+func jaegerCheckSuite(hc *HealthChecker, namespace string) []ExtensionCheck {
+	deploymentReady := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			deployments, err := hc.kubeAPI.GetDeployments(ctx, namespace, "")
+			if err != nil {
+				return err
+			}
+			return validateDeploymentReady(deployments, namespace, name)
+		}
+	}
+
+	return []ExtensionCheck{
+		{
+			Description: "collector deployment is ready",
+			Check: func(ctx context.Context) error {
+				if err := deploymentReady(jaegerCollectorName)(ctx); err != nil {
+					return fmt.Errorf("The trace collector is down: %s", err)
+				}
+				return nil
+			},
+		},
+		{
+			Description: "jaeger deployment is ready",
+			Check:       deploymentReady(jaegerDeployment),
+		},
+		{
+			Description: "collector Service has endpoints",
+			Check: func(ctx context.Context) error {
+				endpoints, err := hc.kubeAPI.GetEndpoints(ctx, namespace, jaegerCollectorName)
+				if err != nil {
+					return err
+				}
+				return validateCollectorEndpoints(endpoints, namespace)
+			},
+		},
+		{
+			Description: "meshed pods in traced namespaces have tracing enabled",
+			Check: func(ctx context.Context) error {
+				pods, namespaces, err := hc.getDataPlaneKubePods(ctx)
+				if err != nil {
+					return err
+				}
+				return validateTracedPods(pods, namespaces, hc.ControlPlaneNamespace, hc.maxSampledProxies())
+			},
+		},
+	}
+}
+
+func validateCollectorEndpoints(endpoints *v1.Endpoints, namespace string) error {
+	if endpoints != nil {
+		for _, subset := range endpoints.Subsets {
+			if len(subset.Addresses) > 0 {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("The trace collector is down: the \"%s/%s\" Service has no ready endpoints", namespace, jaegerCollectorName)
+}
+
+// validateTracedPods returns an error listing a sample of the meshed pods in
+// namespaces annotated with a trace collector whose proxy was not configured
+// for tracing by the jaeger injector.
+func validateTracedPods(pods []v1.Pod, namespaces map[string]v1.Namespace, controlPlaneNamespace string, limit int) error {
+	traced := []v1.Pod{}
+	for _, pod := range pods {
+		if _, ok := namespaces[pod.Namespace].Annotations[k8s.ProxyTraceCollectorAnnotation]; ok {
+			traced = append(traced, pod)
+		}
+	}
+
+	untraced := []string{}
+	for _, pod := range sampleMeshedPods(traced, controlPlaneNamespace, limit) {
+		if !hasTracingEnabled(pod) {
+			untraced = append(untraced, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	if len(untraced) > 0 {
+		return fmt.Errorf("Tracing injection is not happening: pods in traced namespaces have no trace collector configured: %s",
+			strings.Join(untraced, ", "))
+	}
+
+	return nil
+}
+
+func hasTracingEnabled(pod v1.Pod) bool {
+	proxy := k8s.GetProxyContainer(&pod.Spec)
+	if proxy == nil {
+		return false
+	}
+	for _, env := range proxy.Env {
+		if env.Name == proxyTraceCollectorEnvVarName && env.Value != "" {
+			return true
+		}
+	}
+	return false
+}
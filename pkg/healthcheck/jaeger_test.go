@@ -0,0 +1,82 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJaegerChecks(t *testing.T) {
+	t.Run("Reports the collector as down if it has no endpoints", func(t *testing.T) {
+		err := validateCollectorEndpoints(&v1.Endpoints{}, "linkerd-jaeger")
+		if err == nil || err.Error() != "The trace collector is down: the \"linkerd-jaeger/collector\" Service has no ready endpoints" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = validateCollectorEndpoints(&v1.Endpoints{
+			Subsets: []v1.EndpointSubset{{Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}}}},
+		}, "linkerd-jaeger")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Reports meshed pods in traced namespaces without tracing", func(t *testing.T) {
+		namespaces := map[string]v1.Namespace{
+			"emojivoto": v1.Namespace{ObjectMeta: meta.ObjectMeta{
+				Name:        "emojivoto",
+				Annotations: map[string]string{"config.linkerd.io/trace-collector": "collector.linkerd-jaeger:55678"},
+			}},
+			"books": v1.Namespace{ObjectMeta: meta.ObjectMeta{Name: "books"}},
+		}
+
+		pod := func(namespace, name string, env []v1.EnvVar) v1.Pod {
+			return v1.Pod{
+				ObjectMeta: meta.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Labels:    map[string]string{"linkerd.io/control-plane-ns": "linkerd"},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Name: "linkerd-proxy", Env: env}},
+				},
+				Status: v1.PodStatus{Phase: v1.PodRunning},
+			}
+		}
+		traceEnv := []v1.EnvVar{{Name: "LINKERD2_PROXY_TRACE_COLLECTOR_SVC_ADDR", Value: "collector.linkerd-jaeger:55678"}}
+		pods := []v1.Pod{
+			pod("emojivoto", "web", traceEnv),
+			pod("emojivoto", "voting", nil),
+			pod("books", "authors", nil),
+		}
+
+		err := validateTracedPods(pods[:1], namespaces, "linkerd", 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err = validateTracedPods(pods, namespaces, "linkerd", 10)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Tracing injection is not happening: pods in traced namespaces have no trace collector configured: emojivoto/voting" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Reports registered extensions that are not installed as skipped", func(t *testing.T) {
+		hc := HealthChecker{}
+		hc.AddChecker("jaeger", jaegerCheckSuite)
+
+		checkers := hc.extensionCheckers(map[string]string{})
+		if len(checkers) != 1 {
+			t.Fatalf("Expected a single checker, got %d", len(checkers))
+		}
+		err := checkers[0].check(context.Background())
+		if _, ok := err.(*SkipError); !ok || checkers[0].category != "linkerd-jaeger" {
+			t.Fatalf("Expected a skipped linkerd-jaeger check, got %s: %v", checkers[0].category, err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+// serviceProfileCRDName is the CustomResourceDefinition created by the
+// install for ServiceProfiles.
+const serviceProfileCRDName = "serviceprofiles.linkerd.io"
+
+// clusterResourceType is a type of cluster-scoped resource created by the
+// install, which outlives the control plane namespace. The group version of
+// CustomResourceDefinitions is left empty, to be resolved by
+// CRDGroupVersion.
+type clusterResourceType struct {
+	groupVersion string
+	resource     string
+	kind         string
+}
+
+var linkerdClusterResourceTypes = []clusterResourceType{
+	{"rbac.authorization.k8s.io/v1", "clusterroles", "ClusterRole"},
+	{"rbac.authorization.k8s.io/v1", "clusterrolebindings", "ClusterRoleBinding"},
+	{"admissionregistration.k8s.io/v1beta1", "mutatingwebhookconfigurations", "MutatingWebhookConfiguration"},
+	{"admissionregistration.k8s.io/v1beta1", "validatingwebhookconfigurations", "ValidatingWebhookConfiguration"},
+	{"", "customresourcedefinitions", "CustomResourceDefinition"},
+	{"apiregistration.k8s.io/v1", "apiservices", "APIService"},
+}
+
+// clusterResource is a cluster-scoped resource labeled with the namespace of
+// the control plane that created it.
+type clusterResource struct {
+	kind                  string
+	name                  string
+	controlPlaneNamespace string
+}
+
+// checkLeftoverResources verifies that no cluster-scoped resources remain from
+// a control plane whose namespace was deleted.
+func (hc *HealthChecker) checkLeftoverResources(ctx context.Context) error {
+	resources := []clusterResource{}
+	for _, t := range linkerdClusterResourceTypes {
+		if t.groupVersion == "" {
+			groupVersion, err := hc.kubeAPI.CRDGroupVersion(ctx)
+			if err == k8s.ErrCRDAPIUnavailable {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			t.groupVersion = groupVersion
+		}
+
+		list, err := hc.kubeAPI.GetClusterResourcesBySelector(ctx, t.groupVersion, t.resource, k8s.ControllerNSLabel)
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			continue
+		}
+
+		for _, item := range list.Items {
+			resources = append(resources, clusterResource{
+				kind:                  t.kind,
+				name:                  item.GetName(),
+				controlPlaneNamespace: item.GetLabels()[k8s.ControllerNSLabel],
+			})
+		}
+	}
+
+	namespaceList, err := hc.kubeAPI.GetNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	namespaces := make(map[string]bool)
+	for _, ns := range namespaceList {
+		namespaces[ns.Name] = true
+	}
+
+	return validateLeftoverResources(resources, namespaces)
+}
+
+// validateLeftoverResources returns an error listing the resources whose
+// control plane namespace doesn't exist, with the commands to delete them.
+func validateLeftoverResources(resources []clusterResource, namespaces map[string]bool) error {
+	leftovers := []string{}
+	orphanedNamespaces := map[string]bool{}
+
+	for _, r := range resources {
+		if namespaces[r.controlPlaneNamespace] {
+			continue
+		}
+		leftovers = append(leftovers, fmt.Sprintf("%s/%s", r.kind, r.name))
+		orphanedNamespaces[r.controlPlaneNamespace] = true
+	}
+
+	if len(leftovers) == 0 {
+		return nil
+	}
+
+	kinds := []string{}
+	for _, t := range linkerdClusterResourceTypes {
+		kinds = append(kinds, t.resource)
+	}
+	cleanup := []string{}
+	for ns := range orphanedNamespaces {
+		cleanup = append(cleanup, fmt.Sprintf("kubectl delete %s -l %s=%s", strings.Join(kinds, ","), k8s.ControllerNSLabel, ns))
+	}
+	sort.Strings(cleanup)
+
+	return fmt.Errorf("Found resources left over from a previous install, whose control plane namespace no longer exists: %s; remove them with: %s",
+		strings.Join(leftovers, ", "), strings.Join(cleanup, "; "))
+}
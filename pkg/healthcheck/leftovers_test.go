@@ -0,0 +1,37 @@
+package healthcheck
+
+import (
+	"testing"
+)
+
+func TestValidateLeftoverResources(t *testing.T) {
+	t.Run("Returns nil if all resources belong to an existing control plane", func(t *testing.T) {
+		resources := []clusterResource{
+			{"ClusterRole", "linkerd-linkerd-controller", "linkerd"},
+		}
+
+		err := validateLeftoverResources(resources, map[string]bool{"linkerd": true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error listing the leftover resources", func(t *testing.T) {
+		resources := []clusterResource{
+			{"ClusterRole", "linkerd-linkerd-controller", "linkerd"},
+			{"MutatingWebhookConfiguration", "linkerd-proxy-injector-webhook-config", "linkerd"},
+			{"ClusterRole", "linkerd-other-controller", "other"},
+		}
+
+		err := validateLeftoverResources(resources, map[string]bool{"other": true})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Found resources left over from a previous install, whose control plane namespace no longer exists: " +
+			"ClusterRole/linkerd-linkerd-controller, MutatingWebhookConfiguration/linkerd-proxy-injector-webhook-config; " +
+			"remove them with: kubectl delete clusterroles,clusterrolebindings,mutatingwebhookconfigurations,validatingwebhookconfigurations,customresourcedefinitions,apiservices -l linkerd.io/control-plane-ns=linkerd"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package healthcheck
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+)
+
+const (
+	// maxProxyLogBytes caps the total size of the proxy logs fetched by a
+	// single check run, across all sampled pods.
+	maxProxyLogBytes = 1024 * 1024
+
+	// maxProxyLogExampleLength caps the length of the example line reported
+	// for each error signature.
+	maxProxyLogExampleLength = 200
+)
+
+// proxyLogSignature classifies proxy log lines that are symptomatic of a known
+// failure mode.
+type proxyLogSignature struct {
+	name  string
+	match func(line string) bool
+}
+
+// proxyLogSignatures are matched against lowercased WARN and ERR level proxy
+// log lines, in order; a line is attributed to the first signature it matches.
+var proxyLogSignatures = []proxyLogSignature{
+	{
+		name: "identity failures",
+		match: func(line string) bool {
+			return strings.Contains(line, "tls handshake failed") ||
+				strings.Contains(line, "tls handshake error") ||
+				strings.Contains(line, "failed to certify identity")
+		},
+	},
+	{
+		name: "connect timeouts to destination",
+		match: func(line string) bool {
+			return (strings.Contains(line, "destination") || strings.Contains(line, "proxy-api")) &&
+				(strings.Contains(line, "timed out") || strings.Contains(line, "timeout"))
+		},
+	},
+	{
+		name: "protocol detection timeouts",
+		match: func(line string) bool {
+			return strings.Contains(line, "detect") &&
+				(strings.Contains(line, "timed out") || strings.Contains(line, "timeout"))
+		},
+	},
+}
+
+// proxyLogMatches counts the log lines matching a signature, and keeps the
+// first matching line as an example.
+type proxyLogMatches struct {
+	count   int
+	example string
+}
+
+// getSampledProxyLogs fetches the last ProxyLogLines lines of the proxy
+// container logs of a sample of the meshed pods in the data plane namespace.
+// The total number of bytes fetched is capped at maxProxyLogBytes.
+func (hc *HealthChecker) getSampledProxyLogs(ctx context.Context) ([][]byte, error) {
+	pods, _, err := hc.getDataPlaneKubePods(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := [][]byte{}
+	remaining := int64(maxProxyLogBytes)
+	for _, pod := range sampleMeshedPods(pods, hc.ControlPlaneNamespace, hc.maxSampledProxies()) {
+		if remaining <= 0 {
+			break
+		}
+
+		rsp, err := hc.kubeAPI.GetPodLogs(ctx, pod.Namespace, pod.Name, k8s.ProxyContainerName, k8s.PodLogOptions{TailLines: int64(hc.ProxyLogLines), LimitBytes: remaining})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch proxy logs from the \"%s/%s\" pod: %s", pod.Namespace, pod.Name, err)
+		}
+
+		remaining -= int64(len(rsp))
+		logs = append(logs, rsp)
+	}
+
+	return logs, nil
+}
+
+// scanProxyLogs classifies each log line against proxyLogSignatures, returning
+// the matches keyed by signature name.
+func scanProxyLogs(logs [][]byte) map[string]*proxyLogMatches {
+	matches := make(map[string]*proxyLogMatches)
+
+	for _, log := range logs {
+		scanner := bufio.NewScanner(bytes.NewReader(log))
+		for scanner.Scan() {
+			line := scanner.Text()
+			lower := strings.ToLower(line)
+			if !isProxyLogWarning(lower) {
+				continue
+			}
+
+			for _, sig := range proxyLogSignatures {
+				if !sig.match(lower) {
+					continue
+				}
+
+				m, ok := matches[sig.name]
+				if !ok {
+					m = &proxyLogMatches{example: truncateLogLine(line)}
+					matches[sig.name] = m
+				}
+				m.count++
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// isProxyLogWarning returns true if the lowercased proxy log line is logged at
+// the WARN or ERR level, optionally preceded by the proxy's uptime, e.g.
+// "[    12.345s]  WARN ...".
+func isProxyLogWarning(line string) bool {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "[") {
+		if end := strings.Index(line, "]"); end >= 0 {
+			line = strings.TrimSpace(line[end+1:])
+		}
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "warn", "err!", "error":
+		return true
+	}
+	return false
+}
+
+func truncateLogLine(line string) string {
+	line = strings.TrimSpace(line)
+	if len(line) > maxProxyLogExampleLength {
+		return line[:maxProxyLogExampleLength] + "..."
+	}
+	return line
+}
+
+// validateProxyLogs returns an error summarizing the known error signatures
+// found in the given proxy logs, with a count and an example line for each.
+func validateProxyLogs(logs [][]byte) error {
+	matches := scanProxyLogs(logs)
+
+	found := []string{}
+	for _, sig := range proxyLogSignatures {
+		if m, ok := matches[sig.name]; ok {
+			found = append(found, fmt.Sprintf("%s: %d (e.g. \"%s\")", sig.name, m.count, m.example))
+		}
+	}
+
+	if len(found) > 0 {
+		return fmt.Errorf("Proxy logs contain known error signatures: %s", strings.Join(found, ", "))
+	}
+
+	return nil
+}
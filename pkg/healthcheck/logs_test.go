@@ -0,0 +1,72 @@
+package healthcheck
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateProxyLogs(t *testing.T) {
+	t.Run("Returns nil if the logs contain no known errors", func(t *testing.T) {
+		logs := [][]byte{
+			[]byte("INFO linkerd2_proxy::app::main using controller at Some(Name(NameAddr { name: \"proxy-api.linkerd.svc.cluster.local\", port: 8086 }))\n"),
+		}
+
+		err := validateProxyLogs(logs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error with counts and examples per signature", func(t *testing.T) {
+		logs := [][]byte{
+			[]byte("WARN proxy={server=in listen=0.0.0.0:4143} TLS handshake failed: invalid certificate\n" +
+				"WARN proxy={server=in listen=0.0.0.0:4143} TLS handshake failed: unknown issuer\n"),
+			[]byte("ERR! proxy={bg=destination} connect to proxy-api.linkerd.svc.cluster.local:8086 timed out\n" +
+				"INFO proxy={server=in} accepted connection\n"),
+		}
+
+		err := validateProxyLogs(logs)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Proxy logs contain known error signatures: " +
+			"identity failures: 2 (e.g. \"WARN proxy={server=in listen=0.0.0.0:4143} TLS handshake failed: invalid certificate\"), " +
+			"connect timeouts to destination: 1 (e.g. \"ERR! proxy={bg=destination} connect to proxy-api.linkerd.svc.cluster.local:8086 timed out\")"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Ignores lines below the WARN level", func(t *testing.T) {
+		logs := [][]byte{
+			[]byte("INFO linkerd2_proxy::app::identity Certified identity: linkerd-controller.linkerd.serviceaccount.identity.linkerd.cluster.local\n" +
+				"DBUG proxy={server=in} TLS handshake failed: connection reset\n"),
+		}
+
+		if err := validateProxyLogs(logs); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Matches lines prefixed with the proxy's uptime", func(t *testing.T) {
+		line := "[    12.345s]  WARN linkerd2_proxy::app::identity Failed to certify identity: grpc-status: Unavailable"
+		matches := scanProxyLogs([][]byte{[]byte(line)})
+
+		if m, ok := matches["identity failures"]; !ok || m.count != 1 {
+			t.Fatalf("Expected an identity failure match, got %v", matches)
+		}
+	})
+
+	t.Run("Truncates long example lines", func(t *testing.T) {
+		line := "WARN protocol detection timed out " + strings.Repeat("x", 300)
+		matches := scanProxyLogs([][]byte{[]byte(line)})
+
+		m, ok := matches["protocol detection timeouts"]
+		if !ok {
+			t.Fatalf("Expected a protocol detection match, got %v", matches)
+		}
+		if len(m.example) != maxProxyLogExampleLength+len("...") {
+			t.Fatalf("Expected example to be truncated, got %d bytes", len(m.example))
+		}
+	})
+}
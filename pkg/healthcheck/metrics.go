@@ -0,0 +1,185 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"k8s.io/api/core/v1"
+)
+
+const (
+	proxyMetricsPortName    = "linkerd-metrics"
+	defaultProxyMetricsPort = 4191
+
+	// the identity certificate expiry is exported by the proxy as a unix
+	// timestamp
+	certExpiryMetricName = "identity_cert_expiration_timestamp_seconds"
+
+	// responses to the proxy's requests to the control plane, labeled with the
+	// control plane address and the response classification
+	controlResponseMetricName = "control_response_total"
+)
+
+// proxyMetrics holds the metrics scraped from a single proxy, keyed by
+// metric family name.
+type proxyMetrics struct {
+	pod     string
+	metrics map[string]*dto.MetricFamily
+}
+
+// getSampledProxyMetrics scrapes the metrics endpoint of a sample of the
+// meshed pods in the data plane namespace. The number of pods probed in each
+// namespace is capped by the MaxSampledProxies option, to bound the runtime
+// of the checks that depend on it. The results are cached for the remainder
+// of the check run. The pods that could not be scraped are returned
+// separately, so that a single unreachable proxy doesn't prevent evaluating
+// the others.
+func (hc *HealthChecker) getSampledProxyMetrics(ctx context.Context) ([]*proxyMetrics, []string, error) {
+	if hc.sampledProxyMetrics != nil {
+		return hc.sampledProxyMetrics, hc.unsampledProxies, nil
+	}
+
+	pods, _, err := hc.getDataPlaneKubePods(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sampled := make([]*proxyMetrics, 0)
+	failures := []string{}
+	for _, pod := range sampleMeshedPods(pods, hc.ControlPlaneNamespace, hc.maxSampledProxies()) {
+		rsp, err := hc.kubeAPI.GetPodMetrics(ctx, pod.Namespace, pod.Name, proxyMetricsPort(pod))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s (%s)", pod.Namespace, pod.Name, err))
+			continue
+		}
+
+		metrics, err := parseProxyMetrics(rsp)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s/%s (failed to parse metrics: %s)", pod.Namespace, pod.Name, err))
+			continue
+		}
+
+		sampled = append(sampled, &proxyMetrics{
+			pod:     fmt.Sprintf("%s/%s", pod.Namespace, pod.Name),
+			metrics: metrics,
+		})
+	}
+
+	hc.sampledProxyMetrics = sampled
+	hc.unsampledProxies = failures
+	return sampled, failures, nil
+}
+
+// checkSampledProxyMetrics runs validate against the metrics of the sampled
+// proxies that answered.
+func (hc *HealthChecker) checkSampledProxyMetrics(ctx context.Context, validate func([]*proxyMetrics) error) error {
+	sampled, failures, err := hc.getSampledProxyMetrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	return validateSampledProxyMetrics(sampled, failures, validate)
+}
+
+// validateSampledProxyMetrics runs validate against the metrics of the proxies
+// that answered, and also reports the pods whose metrics could not be
+// fetched, by name, so that they are not mistaken for healthy proxies.
+func validateSampledProxyMetrics(sampled []*proxyMetrics, failures []string, validate func([]*proxyMetrics) error) error {
+	err := validate(sampled)
+	if len(failures) == 0 {
+		return err
+	}
+
+	unreachable := fmt.Sprintf("Failed to fetch metrics from some pods: %s", strings.Join(failures, ", "))
+	if err != nil {
+		return fmt.Errorf("%s; %s", err, unreachable)
+	}
+	return errors.New(unreachable)
+}
+
+func (hc *HealthChecker) maxSampledProxies() int {
+	if hc.MaxSampledProxies > 0 {
+		return hc.MaxSampledProxies
+	}
+	return defaultMaxSampledProxies
+}
+
+// sampleMeshedPods returns up to limit running, meshed pods from each
+// namespace. Pods are sorted by name so that repeated runs probe the same
+// pods.
+func sampleMeshedPods(pods []v1.Pod, controlPlaneNamespace string, limit int) []v1.Pod {
+	byNamespace := make(map[string][]v1.Pod)
+	namespaces := []string{}
+
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning || !hasProxyContainer(pod) || !k8s.IsMeshed(&pod, controlPlaneNamespace) {
+			continue
+		}
+		if _, ok := byNamespace[pod.Namespace]; !ok {
+			namespaces = append(namespaces, pod.Namespace)
+		}
+		byNamespace[pod.Namespace] = append(byNamespace[pod.Namespace], pod)
+	}
+
+	sort.Strings(namespaces)
+
+	sampled := make([]v1.Pod, 0)
+	for _, ns := range namespaces {
+		nsPods := byNamespace[ns]
+		sort.Slice(nsPods, func(i, j int) bool { return nsPods[i].Name < nsPods[j].Name })
+		if len(nsPods) > limit {
+			nsPods = nsPods[:limit]
+		}
+		sampled = append(sampled, nsPods...)
+	}
+
+	return sampled
+}
+
+// proxyMetricsPort returns the port on which the pod's proxy serves metrics.
+func proxyMetricsPort(pod v1.Pod) int32 {
+	for _, container := range pod.Spec.Containers {
+		if container.Name != k8s.ProxyContainerName {
+			continue
+		}
+		for _, port := range container.Ports {
+			if port.Name == proxyMetricsPortName {
+				return port.ContainerPort
+			}
+		}
+	}
+	return defaultProxyMetricsPort
+}
+
+func parseProxyMetrics(metrics []byte) (map[string]*dto.MetricFamily, error) {
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(bytes.NewReader(metrics))
+}
+
+// gaugeValue returns the value of the first sample of the named gauge, and
+// whether the gauge was present at all.
+func gaugeValue(metrics map[string]*dto.MetricFamily, name string) (float64, bool) {
+	family, ok := metrics[name]
+	if !ok || len(family.GetMetric()) == 0 {
+		return 0, false
+	}
+	return family.GetMetric()[0].GetGauge().GetValue(), true
+}
+
+// labelValue returns the value of the named label on a metric sample, or an
+// empty string if the label is not present.
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package healthcheck
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSampleMeshedPods(t *testing.T) {
+	pod := func(namespace, name string, meshed bool) v1.Pod {
+		p := v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{v1.Container{Name: "app"}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if meshed {
+			p.Labels["linkerd.io/control-plane-ns"] = "linkerd"
+			p.Spec.Containers = append(p.Spec.Containers, v1.Container{Name: "linkerd-proxy"})
+		}
+		return p
+	}
+
+	pods := []v1.Pod{
+		pod("emojivoto", "web-6cfbccc48-5g8px", true),
+		pod("emojivoto", "emoji-d9c7866bb-7v74n", true),
+		pod("emojivoto", "voting-65b9fffd77-rlwsd", true),
+		pod("books", "webapp-5b7d796646-hh46d", true),
+		pod("books", "traffic-74d6879cd6-bbdk6", false),
+	}
+
+	sampled := sampleMeshedPods(pods, "linkerd", 2)
+
+	names := []string{}
+	for _, p := range sampled {
+		names = append(names, p.Namespace+"/"+p.Name)
+	}
+
+	expected := []string{
+		"books/webapp-5b7d796646-hh46d",
+		"emojivoto/emoji-d9c7866bb-7v74n",
+		"emojivoto/voting-65b9fffd77-rlwsd",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected sampled pods %v, but got %v", expected, names)
+	}
+}
+
+func TestValidateSampledProxyMetrics(t *testing.T) {
+	sampled := []*proxyMetrics{
+		{pod: "emojivoto/web-6cfbccc48-5g8px", metrics: map[string]*dto.MetricFamily{}},
+	}
+	failures := []string{"emojivoto/emoji-d9c7866bb-7v74n (connection refused)"}
+
+	t.Run("Evaluates the proxies that answered", func(t *testing.T) {
+		evaluated := []string{}
+		err := validateSampledProxyMetrics(sampled, nil, func(sampled []*proxyMetrics) error {
+			for _, proxy := range sampled {
+				evaluated = append(evaluated, proxy.pod)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(evaluated, []string{"emojivoto/web-6cfbccc48-5g8px"}) {
+			t.Fatalf("Unexpected proxies evaluated: %v", evaluated)
+		}
+	})
+
+	t.Run("Names the pods whose metrics could not be fetched", func(t *testing.T) {
+		err := validateSampledProxyMetrics(sampled, failures, func([]*proxyMetrics) error { return nil })
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Failed to fetch metrics from some pods: emojivoto/emoji-d9c7866bb-7v74n (connection refused)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Reports both the validation error and the failing pods", func(t *testing.T) {
+		err := validateSampledProxyMetrics(sampled, failures, func([]*proxyMetrics) error {
+			return errors.New("Some data plane proxy certificates have expired: emojivoto/web-6cfbccc48-5g8px")
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some data plane proxy certificates have expired: emojivoto/web-6cfbccc48-5g8px; " +
+			"Failed to fetch metrics from some pods: emojivoto/emoji-d9c7866bb-7v74n (connection refused)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,506 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	linksAPIPath = "/apis/multicluster.linkerd.io/v1alpha1/links"
+
+	serviceMirrorDeploymentPrefix = "linkerd-service-mirror-"
+	remoteKubeconfigKey           = "kubeconfig"
+
+	// mirrored services and their endpoints are labeled with the name of the
+	// cluster they are mirrored from
+	mirrorClusterNameLabel = "mirror.linkerd.io/cluster-name"
+
+	defaultMulticlusterNamespace = "linkerd-multicluster"
+	gatewaySelector              = "app=linkerd-gateway"
+
+	defaultGatewayProbePeriod = 3 * time.Second
+)
+
+// multiclusterLink describes a Link resource, which connects this cluster to
+// the gateway of a remote target cluster.
+type multiclusterLink struct {
+	name          string
+	namespace     string
+	targetCluster string
+
+	gatewayAddress string
+	gatewayPort    string
+
+	probePath   string
+	probePort   string
+	probePeriod time.Duration
+
+	credentialsSecret string
+}
+
+// gatewayProber probes a gateway's probe URL, failing if it does not respond
+// successfully within the timeout.
+type gatewayProber func(url string, timeout time.Duration) error
+
+func (hc *HealthChecker) addLinkerdMulticlusterChecks() {
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "remote gateways have an external address",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateGatewayAddresses(links)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "gateway pods are ready",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			if _, err := hc.getMulticlusterLinks(ctx); err != nil {
+				return err
+			}
+
+			pods, err := hc.kubeAPI.GetPodsBySelector(ctx, hc.multiclusterNamespace(), gatewaySelector)
+			if err != nil {
+				return err
+			}
+
+			return validateGatewayPods(pods)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "remote gateways respond to probes",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			hc.gatewayProbeResults = probeGateways(links, probeGateway)
+			return validateGatewayProbes(hc.gatewayProbeResults)
+		},
+		payload: func() interface{} {
+			return hc.gatewayProbeResults
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "service mirror controllers are ready",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			deployments := []appsV1.Deployment{}
+			for _, ns := range linkNamespaces(links) {
+				nsDeployments, err := hc.kubeAPI.GetDeployments(ctx, ns, "")
+				if err != nil {
+					return err
+				}
+				deployments = append(deployments, nsDeployments...)
+			}
+
+			return validateServiceMirrorDeployments(links, deployments)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "remote cluster credentials are valid",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			hc.remoteClusterAPIs = make(map[string]*k8s.KubernetesAPI)
+			failures := []string{}
+			for _, link := range links {
+				secret, err := hc.kubeAPI.GetSecret(ctx, link.namespace, link.credentialsSecret)
+				if err != nil {
+					return err
+				}
+
+				api, err := remoteClusterAPI(link, secret)
+				if err != nil {
+					failures = append(failures, fmt.Sprintf("%s (%s)", link.name, err))
+					continue
+				}
+				hc.remoteClusterAPIs[link.name] = api
+			}
+
+			return linkFailures(failures)
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "remote API servers accept the credentials",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			return validateRemoteAPIs(links, hc.remoteClusterAPIs, func(api *k8s.KubernetesAPI) error {
+				_, err := api.GetVersionInfo(ctx)
+				return err
+			})
+		},
+	})
+
+	hc.checkers = append(hc.checkers, &checker{
+		category:    LinkerdMulticlusterCategory,
+		description: "mirrored services have endpoints",
+		fatal:       false,
+		check: func(ctx context.Context) error {
+			links, err := hc.getMulticlusterLinks(ctx)
+			if err != nil {
+				return err
+			}
+
+			services, err := hc.kubeAPI.GetServices(ctx, "", mirrorClusterNameLabel)
+			if err != nil {
+				return err
+			}
+
+			endpoints, err := hc.kubeAPI.GetEndpointsBySelector(ctx, mirrorClusterNameLabel)
+			if err != nil {
+				return err
+			}
+
+			return validateMirroredServiceEndpoints(links, services, endpoints)
+		},
+	})
+}
+
+func (hc *HealthChecker) multiclusterNamespace() string {
+	if hc.MulticlusterNamespace != "" {
+		return hc.MulticlusterNamespace
+	}
+	return defaultMulticlusterNamespace
+}
+
+// getMulticlusterLinks returns the Link resources in the cluster, or a
+// SkipError if there are none. The results are cached for the remainder of
+// the check run.
+func (hc *HealthChecker) getMulticlusterLinks(ctx context.Context) ([]multiclusterLink, error) {
+	if hc.multiclusterLinks == nil {
+		list, err := hc.kubeAPI.GetUnstructuredList(ctx, linksAPIPath)
+		if err != nil {
+			return nil, err
+		}
+
+		links := []multiclusterLink{}
+		if list != nil {
+			for _, item := range list.Items {
+				link, err := parseMulticlusterLink(item)
+				if err != nil {
+					return nil, err
+				}
+				links = append(links, link)
+			}
+		}
+		hc.multiclusterLinks = links
+	}
+
+	if len(hc.multiclusterLinks) == 0 {
+		return nil, &SkipError{Reason: "No multicluster links found"}
+	}
+
+	return hc.multiclusterLinks, nil
+}
+
+func parseMulticlusterLink(item unstructured.Unstructured) (multiclusterLink, error) {
+	link := multiclusterLink{
+		name:        item.GetName(),
+		namespace:   item.GetNamespace(),
+		probePeriod: defaultGatewayProbePeriod,
+	}
+
+	fields := []struct {
+		dest *string
+		path []string
+	}{
+		{&link.targetCluster, []string{"spec", "targetClusterName"}},
+		{&link.gatewayAddress, []string{"spec", "gatewayAddress"}},
+		{&link.gatewayPort, []string{"spec", "gatewayPort"}},
+		{&link.probePath, []string{"spec", "probeSpec", "path"}},
+		{&link.probePort, []string{"spec", "probeSpec", "port"}},
+		{&link.credentialsSecret, []string{"spec", "clusterCredentialsSecret"}},
+	}
+	for _, f := range fields {
+		value, _, err := unstructured.NestedString(item.Object, f.path...)
+		if err != nil {
+			return link, fmt.Errorf("Invalid Link \"%s/%s\": %s", link.namespace, link.name, err)
+		}
+		*f.dest = value
+	}
+
+	period, _, err := unstructured.NestedString(item.Object, "spec", "probeSpec", "period")
+	if err != nil {
+		return link, fmt.Errorf("Invalid Link \"%s/%s\": %s", link.namespace, link.name, err)
+	}
+	if period != "" {
+		link.probePeriod, err = time.ParseDuration(period)
+		if err != nil {
+			return link, fmt.Errorf("Invalid Link \"%s/%s\": invalid probe period \"%s\"", link.namespace, link.name, period)
+		}
+	}
+
+	if link.targetCluster == "" {
+		link.targetCluster = link.name
+	}
+
+	return link, nil
+}
+
+// validateGatewayAddresses returns an error listing the target clusters whose
+// gateway has no external address.
+func validateGatewayAddresses(links []multiclusterLink) error {
+	missing := []string{}
+	for _, link := range links {
+		if link.gatewayAddress == "" {
+			missing = append(missing, link.targetCluster)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Some remote gateways have no external address: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func validateGatewayPods(pods []v1.Pod) error {
+	if len(pods) == 0 {
+		return fmt.Errorf("No gateway pods found")
+	}
+
+	notReady := []string{}
+	for _, pod := range pods {
+		if !isPodReady(pod) {
+			notReady = append(notReady, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	if len(notReady) > 0 {
+		return fmt.Errorf("Some gateway pods are not ready: %s", strings.Join(notReady, ", "))
+	}
+
+	return nil
+}
+
+// probeGateways probes the gateway of each link, returning the result for
+// each target cluster: "ok", or a description of the failure.
+func probeGateways(links []multiclusterLink, probe gatewayProber) map[string]string {
+	results := make(map[string]string)
+	for _, link := range links {
+		if link.gatewayAddress == "" {
+			results[link.targetCluster] = "no gateway address"
+			continue
+		}
+
+		url := fmt.Sprintf("http://%s%s", net.JoinHostPort(link.gatewayAddress, link.probePort), link.probePath)
+		if err := probe(url, link.probePeriod); err != nil {
+			results[link.targetCluster] = err.Error()
+		} else {
+			results[link.targetCluster] = "ok"
+		}
+	}
+	return results
+}
+
+func probeGateway(url string, timeout time.Duration) error {
+	client := http.Client{Timeout: timeout}
+	rsp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("probe returned %s", rsp.Status)
+	}
+	return nil
+}
+
+// validateGatewayProbes returns an error listing the target clusters whose
+// gateway probe failed.
+func validateGatewayProbes(results map[string]string) error {
+	failed := []string{}
+	for target, result := range results {
+		if result != "ok" {
+			failed = append(failed, fmt.Sprintf("%s (%s)", target, result))
+		}
+	}
+	sort.Strings(failed)
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Some remote gateways failed their probe: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// linkNamespaces returns the sorted, distinct namespaces of the given links.
+func linkNamespaces(links []multiclusterLink) []string {
+	seen := make(map[string]bool)
+	namespaces := []string{}
+	for _, link := range links {
+		if !seen[link.namespace] {
+			seen[link.namespace] = true
+			namespaces = append(namespaces, link.namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func linkFailures(failures []string) error {
+	if len(failures) > 0 {
+		return fmt.Errorf("Some links failed: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// validateServiceMirrorDeployments returns an error naming the links whose
+// service mirror controller Deployment is missing or not ready.
+func validateServiceMirrorDeployments(links []multiclusterLink, deployments []appsV1.Deployment) error {
+	failures := []string{}
+
+	for _, link := range links {
+		name := serviceMirrorDeploymentPrefix + link.targetCluster
+
+		var found *appsV1.Deployment
+		for i := range deployments {
+			if deployments[i].Namespace == link.namespace && deployments[i].Name == name {
+				found = &deployments[i]
+				break
+			}
+		}
+
+		if found == nil {
+			failures = append(failures, fmt.Sprintf("%s (service mirror Deployment \"%s\" is missing)", link.name, name))
+		} else if found.Status.ReadyReplicas == 0 || found.Status.ReadyReplicas < found.Status.Replicas {
+			failures = append(failures, fmt.Sprintf("%s (service mirror Deployment \"%s\" has %d/%d ready replicas)",
+				link.name, name, found.Status.ReadyReplicas, found.Status.Replicas))
+		}
+	}
+
+	return linkFailures(failures)
+}
+
+// remoteClusterAPI returns a client for the link's target cluster, built from
+// the kubeconfig in its credentials Secret. The error describes which stage
+// failed: the Secret is missing, lacks a kubeconfig, or the kubeconfig does
+// not parse.
+func remoteClusterAPI(link multiclusterLink, secret *v1.Secret) (*k8s.KubernetesAPI, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("credentials Secret \"%s\" is missing", link.credentialsSecret)
+	}
+
+	kubeconfig, ok := secret.Data[remoteKubeconfigKey]
+	if !ok || len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("credentials Secret \"%s\" has no \"%s\" key", link.credentialsSecret, remoteKubeconfigKey)
+	}
+
+	api, err := k8s.NewAPIForKubeconfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("credentials Secret \"%s\" does not contain a valid kubeconfig: %s", link.credentialsSecret, err)
+	}
+
+	return api, nil
+}
+
+// validateRemoteAPIs probes the remote API server of each link whose
+// credentials could be loaded, and returns an error naming the links whose
+// API server rejected the credentials or could not be reached. Links without
+// valid credentials are reported by the credentials check; if no remote API
+// server could be probed at all, the check is skipped rather than passed.
+func validateRemoteAPIs(links []multiclusterLink, apis map[string]*k8s.KubernetesAPI, probe func(*k8s.KubernetesAPI) error) error {
+	checked := 0
+	failures := []string{}
+	for _, link := range links {
+		api, ok := apis[link.name]
+		if !ok {
+			continue
+		}
+
+		checked++
+		if err := probe(api); err != nil {
+			failures = append(failures, fmt.Sprintf("%s (%s)", link.name, describeRemoteAPIError(err)))
+		}
+	}
+
+	if checked == 0 {
+		return &SkipError{Reason: "No link has valid credentials, so no remote API server could be checked"}
+	}
+
+	return linkFailures(failures)
+}
+
+// describeRemoteAPIError distinguishes remote API servers rejecting the
+// link's credentials from API servers that could not be reached.
+func describeRemoteAPIError(err error) string {
+	msg := err.Error()
+	if k8s.IsForbidden(err) || k8s.IsUnauthorized(err) {
+		return fmt.Sprintf("remote API server rejected the credentials: %s", msg)
+	}
+	return fmt.Sprintf("remote API server is unreachable: %s", msg)
+}
+
+// validateMirroredServiceEndpoints returns an error naming, per link, the
+// mirrored services that have no endpoint addresses.
+func validateMirroredServiceEndpoints(links []multiclusterLink, services []v1.Service, endpoints []v1.Endpoints) error {
+	addresses := make(map[string]int)
+	for _, ep := range endpoints {
+		for _, subset := range ep.Subsets {
+			addresses[ep.Namespace+"/"+ep.Name] += len(subset.Addresses)
+		}
+	}
+
+	failures := []string{}
+	for _, link := range links {
+		empty := []string{}
+		for _, svc := range services {
+			if svc.Labels[mirrorClusterNameLabel] != link.targetCluster {
+				continue
+			}
+			name := svc.Namespace + "/" + svc.Name
+			if addresses[name] == 0 {
+				empty = append(empty, name)
+			}
+		}
+
+		if len(empty) > 0 {
+			failures = append(failures, fmt.Sprintf("%s (endpoints are empty for %s)", link.name, strings.Join(empty, ", ")))
+		}
+	}
+
+	return linkFailures(failures)
+}
@@ -0,0 +1,271 @@
+package healthcheck
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMulticlusterGatewayChecks(t *testing.T) {
+	link := func(name string, spec map[string]interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "multicluster.linkerd.io/v1alpha1",
+			"kind":       "Link",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "linkerd-multicluster"},
+			"spec":       spec,
+		}}
+	}
+
+	east, err := parseMulticlusterLink(link("east", map[string]interface{}{
+		"targetClusterName": "east",
+		"gatewayAddress":    "203.0.113.10",
+		"gatewayPort":       "4143",
+		"probeSpec": map[string]interface{}{
+			"path":   "/health",
+			"port":   "4181",
+			"period": "5s",
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if east.probePeriod != 5*time.Second || east.probePath != "/health" || east.probePort != "4181" {
+		t.Fatalf("Unexpected link: %+v", east)
+	}
+
+	west, err := parseMulticlusterLink(link("west", map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if west.targetCluster != "west" || west.probePeriod != defaultGatewayProbePeriod {
+		t.Fatalf("Unexpected link: %+v", west)
+	}
+
+	t.Run("Returns an error for an invalid probe period", func(t *testing.T) {
+		_, err := parseMulticlusterLink(link("north", map[string]interface{}{
+			"probeSpec": map[string]interface{}{"period": "soon"},
+		}))
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Invalid Link \"linkerd-multicluster/north\": invalid probe period \"soon\"" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if a gateway has no address", func(t *testing.T) {
+		err := validateGatewayAddresses([]multiclusterLink{east, west})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some remote gateways have no external address: west" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Reports probe results per target cluster", func(t *testing.T) {
+		probed := []string{}
+		probe := func(url string, timeout time.Duration) error {
+			probed = append(probed, fmt.Sprintf("%s %s", url, timeout))
+			return fmt.Errorf("connection refused")
+		}
+
+		results := probeGateways([]multiclusterLink{east, west}, probe)
+
+		if !reflect.DeepEqual(probed, []string{"http://203.0.113.10:4181/health 5s"}) {
+			t.Fatalf("Unexpected probes: %v", probed)
+		}
+		expected := map[string]string{"east": "connection refused", "west": "no gateway address"}
+		if !reflect.DeepEqual(results, expected) {
+			t.Fatalf("Expected results %v, got %v", expected, results)
+		}
+
+		err := validateGatewayProbes(results)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some remote gateways failed their probe: east (connection refused), west (no gateway address)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns nil if all probes succeed", func(t *testing.T) {
+		results := probeGateways([]multiclusterLink{east}, func(string, time.Duration) error { return nil })
+
+		err := validateGatewayProbes(results)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestServiceMirrorChecks(t *testing.T) {
+	links := []multiclusterLink{
+		{name: "east", namespace: "linkerd-multicluster", targetCluster: "east", credentialsSecret: "cluster-credentials-east"},
+		{name: "west", namespace: "linkerd-multicluster", targetCluster: "west", credentialsSecret: "cluster-credentials-west"},
+	}
+
+	t.Run("Returns an error naming links with unready service mirrors", func(t *testing.T) {
+		deployments := []appsV1.Deployment{
+			appsV1.Deployment{
+				ObjectMeta: meta.ObjectMeta{Name: "linkerd-service-mirror-east", Namespace: "linkerd-multicluster"},
+				Status:     appsV1.DeploymentStatus{Replicas: 1, ReadyReplicas: 0},
+			},
+		}
+
+		err := validateServiceMirrorDeployments(links, deployments)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some links failed: " +
+			"east (service mirror Deployment \"linkerd-service-mirror-east\" has 0/1 ready replicas), " +
+			"west (service mirror Deployment \"linkerd-service-mirror-west\" is missing)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Distinguishes missing and invalid credentials", func(t *testing.T) {
+		testCases := []struct {
+			secret *v1.Secret
+			err    string
+		}{
+			{nil, "credentials Secret \"cluster-credentials-east\" is missing"},
+			{&v1.Secret{}, "credentials Secret \"cluster-credentials-east\" has no \"kubeconfig\" key"},
+			{
+				&v1.Secret{Data: map[string][]byte{"kubeconfig": []byte("not: [a kubeconfig")}},
+				"credentials Secret \"cluster-credentials-east\" does not contain a valid kubeconfig",
+			},
+		}
+
+		for _, tc := range testCases {
+			_, err := remoteClusterAPI(links[0], tc.secret)
+			if err == nil {
+				t.Fatalf("Expected error \"%s\", got nothing", tc.err)
+			}
+			if !strings.HasPrefix(err.Error(), tc.err) {
+				t.Fatalf("Expected error \"%s\", got \"%s\"", tc.err, err)
+			}
+		}
+	})
+
+	t.Run("Builds a client from a valid kubeconfig", func(t *testing.T) {
+		kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster:
+    server: https://east.example.com:6443
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: service-mirror
+current-context: east
+users:
+- name: service-mirror
+  user:
+    token: secret-token
+`
+		api, err := remoteClusterAPI(links[0], &v1.Secret{Data: map[string][]byte{"kubeconfig": []byte(kubeconfig)}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if api.Host != "https://east.example.com:6443" {
+			t.Fatalf("Unexpected remote API host: %s", api.Host)
+		}
+	})
+
+	t.Run("Distinguishes rejected credentials from unreachable API servers", func(t *testing.T) {
+		rejected := describeRemoteAPIError(&k8s.UnauthorizedError{Status: "401 Unauthorized"})
+		if rejected != "remote API server rejected the credentials: Unexpected Kubernetes API response: 401 Unauthorized" {
+			t.Fatalf("Unexpected description: %s", rejected)
+		}
+
+		forbidden := describeRemoteAPIError(&k8s.ForbiddenError{Verb: "get", Resource: "/version", Status: "403 Forbidden"})
+		if forbidden != "remote API server rejected the credentials: Unexpected Kubernetes API response: 403 Forbidden" {
+			t.Fatalf("Unexpected description: %s", forbidden)
+		}
+
+		unreachable := describeRemoteAPIError(fmt.Errorf("dial tcp: i/o timeout"))
+		if unreachable != "remote API server is unreachable: dial tcp: i/o timeout" {
+			t.Fatalf("Unexpected description: %s", unreachable)
+		}
+
+		// a 401 reported as text by something other than the Kubernetes API
+		// client is not taken as a rejection
+		proxied := describeRemoteAPIError(fmt.Errorf("proxy said 401 Unauthorized"))
+		if proxied != "remote API server is unreachable: proxy said 401 Unauthorized" {
+			t.Fatalf("Unexpected description: %s", proxied)
+		}
+	})
+
+	t.Run("Skips the remote API check if no link has valid credentials", func(t *testing.T) {
+		links := []multiclusterLink{{name: "east"}, {name: "west"}}
+		probed := false
+		err := validateRemoteAPIs(links, map[string]*k8s.KubernetesAPI{}, func(*k8s.KubernetesAPI) error {
+			probed = true
+			return nil
+		})
+		if _, ok := err.(*SkipError); !ok {
+			t.Fatalf("Expected a SkipError, got %v", err)
+		}
+		if probed {
+			t.Fatal("Unexpected probe of a link without valid credentials")
+		}
+	})
+
+	t.Run("Returns an error naming the links whose remote API server failed", func(t *testing.T) {
+		links := []multiclusterLink{{name: "east"}, {name: "west"}}
+		east := &k8s.KubernetesAPI{}
+		apis := map[string]*k8s.KubernetesAPI{"east": east, "west": {}}
+		err := validateRemoteAPIs(links, apis, func(api *k8s.KubernetesAPI) error {
+			if api == east {
+				return &k8s.UnauthorizedError{Status: "401 Unauthorized"}
+			}
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some links failed: east (remote API server rejected the credentials: Unexpected Kubernetes API response: 401 Unauthorized)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error naming mirrored services without endpoints", func(t *testing.T) {
+		service := func(name, cluster string) v1.Service {
+			return v1.Service{ObjectMeta: meta.ObjectMeta{
+				Name:      name,
+				Namespace: "emojivoto",
+				Labels:    map[string]string{"mirror.linkerd.io/cluster-name": cluster},
+			}}
+		}
+		services := []v1.Service{service("web-svc-east", "east"), service("web-svc-west", "west")}
+		endpoints := []v1.Endpoints{
+			v1.Endpoints{
+				ObjectMeta: meta.ObjectMeta{Name: "web-svc-east", Namespace: "emojivoto"},
+				Subsets:    []v1.EndpointSubset{{Addresses: []v1.EndpointAddress{{IP: "203.0.113.10"}}}},
+			},
+			v1.Endpoints{
+				ObjectMeta: meta.ObjectMeta{Name: "web-svc-west", Namespace: "emojivoto"},
+			},
+		}
+
+		err := validateMirroredServiceEndpoints(links, services, endpoints)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some links failed: west (endpoints are empty for emojivoto/web-svc-west)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+)
+
+// netAdminCapability is the capability proxy-init needs to set up the pod's
+// iptables rules.
+const netAdminCapability = "NET_ADMIN"
+
+// IsOpenShift returns true if the cluster the checks ran against was detected
+// to be an OpenShift cluster. It is only set if the KubernetesAPIChecks are
+// configured and run first, and is false if detection failed.
+func (hc *HealthChecker) IsOpenShift() bool {
+	return hc.openShift
+}
+
+// detectOpenShift records whether the cluster is an OpenShift cluster. If the
+// API groups cannot be discovered, the cluster is treated as a vanilla
+// Kubernetes cluster.
+func (hc *HealthChecker) detectOpenShift(ctx context.Context) {
+	openShift, err := hc.kubeAPI.IsOpenShift(ctx)
+	hc.openShift = err == nil && openShift
+}
+
+// checkSecurityContextConstraints verifies, on OpenShift, that the service
+// accounts of the control plane pods running proxy-init can use a
+// SecurityContextConstraints permitting NET_ADMIN, which OpenShift requires in
+// place of a PodSecurityPolicy. It is skipped elsewhere, and if traffic
+// redirection is set up by the CNI plugin instead.
+func (hc *HealthChecker) checkSecurityContextConstraints(ctx context.Context) error {
+	if !hc.openShift {
+		return &SkipError{Reason: "Not an OpenShift cluster"}
+	}
+
+	cniEnabled, err := hc.cniEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if cniEnabled {
+		return &SkipError{Reason: "Traffic redirection is set up by the CNI plugin, so proxy-init does not need NET_ADMIN"}
+	}
+
+	serviceAccounts := proxyInitServiceAccounts(hc.controlPlanePods)
+	if len(serviceAccounts) == 0 {
+		return &SkipError{Reason: "No control plane pods run proxy-init"}
+	}
+
+	sccs, err := hc.kubeAPI.GetSecurityContextConstraints(ctx)
+	if k8s.IsForbidden(err) {
+		return &SkipError{Reason: "Not permitted to list SecurityContextConstraints"}
+	}
+	if err != nil {
+		return err
+	}
+
+	return validateSecurityContextConstraints(sccs, hc.ControlPlaneNamespace, serviceAccounts)
+}
+
+// proxyInitServiceAccounts returns the sorted names of the service accounts
+// of the pods running proxy-init, that is the linkerd-init container.
+func proxyInitServiceAccounts(pods []v1.Pod) []string {
+	found := make(map[string]bool)
+	for _, pod := range pods {
+		for _, container := range pod.Spec.InitContainers {
+			if container.Name != k8s.InitContainerName {
+				continue
+			}
+
+			serviceAccount := pod.Spec.ServiceAccountName
+			if serviceAccount == "" {
+				serviceAccount = "default"
+			}
+			found[serviceAccount] = true
+		}
+	}
+
+	serviceAccounts := []string{}
+	for serviceAccount := range found {
+		serviceAccounts = append(serviceAccounts, serviceAccount)
+	}
+	sort.Strings(serviceAccounts)
+	return serviceAccounts
+}
+
+// validateSecurityContextConstraints returns an error listing the service
+// accounts that cannot use any of the SecurityContextConstraints permitting
+// NET_ADMIN.
+func validateSecurityContextConstraints(sccs []k8s.SecurityContextConstraints, namespace string, serviceAccounts []string) error {
+	missing := []string{}
+	for _, serviceAccount := range serviceAccounts {
+		allowed := false
+		for _, scc := range sccs {
+			if scc.PermitsCapability(netAdminCapability) && scc.UsableBy(namespace, serviceAccount) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			missing = append(missing, fmt.Sprintf("%s/%s", namespace, serviceAccount))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("The following ServiceAccounts cannot use a SecurityContextConstraints permitting %s, which proxy-init requires: %s. Grant one with \"oc adm policy add-scc-to-user privileged -z <service account> -n %s\"",
+			netAdminCapability, strings.Join(missing, ", "), namespace)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package healthcheck
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckSecurityContextConstraints(t *testing.T) {
+	initPod := func(name, serviceAccount string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "linkerd"},
+			Spec: v1.PodSpec{
+				ServiceAccountName: serviceAccount,
+				InitContainers:     []v1.Container{{Name: k8s.InitContainerName}},
+			},
+		}
+	}
+
+	t.Run("Is skipped on vanilla Kubernetes", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"})
+		if _, ok := hc.checkSecurityContextConstraints(context.Background()).(*SkipError); !ok {
+			t.Fatal("Expected the check to be skipped")
+		}
+	})
+
+	t.Run("Lists the service accounts of pods running proxy-init", func(t *testing.T) {
+		pods := []v1.Pod{
+			initPod("linkerd-web-1", "linkerd-web"),
+			initPod("linkerd-controller-1", "linkerd-controller"),
+			initPod("linkerd-controller-2", "linkerd-controller"),
+			initPod("linkerd-other-1", ""),
+			{ObjectMeta: meta.ObjectMeta{Name: "linkerd-cni-1"}, Spec: v1.PodSpec{ServiceAccountName: "linkerd-cni"}},
+		}
+
+		expected := []string{"default", "linkerd-controller", "linkerd-web"}
+		if serviceAccounts := proxyInitServiceAccounts(pods); !reflect.DeepEqual(serviceAccounts, expected) {
+			t.Fatalf("Expected %v, got %v", expected, serviceAccounts)
+		}
+	})
+
+	t.Run("Reports the service accounts that cannot use an SCC permitting NET_ADMIN", func(t *testing.T) {
+		sccs := []k8s.SecurityContextConstraints{
+			{Name: "restricted", Groups: []string{"system:authenticated"}},
+			{Name: "linkerd", AllowedCapabilities: []string{"NET_ADMIN"}, Users: []string{"system:serviceaccount:linkerd:linkerd-controller"}},
+			{Name: "privileged", AllowPrivilegedContainer: true, Groups: []string{"system:serviceaccounts:linkerd-viz"}},
+		}
+
+		if err := validateSecurityContextConstraints(sccs, "linkerd", []string{"linkerd-controller"}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := validateSecurityContextConstraints(sccs, "linkerd-viz", []string{"web", "tap"}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err := validateSecurityContextConstraints(sccs, "linkerd", []string{"linkerd-controller", "linkerd-identity", "linkerd-web"})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The following ServiceAccounts cannot use a SecurityContextConstraints permitting NET_ADMIN, which proxy-init requires: linkerd/linkerd-identity, linkerd/linkerd-web. Grant one with \"oc adm policy add-scc-to-user privileged -z <service account> -n linkerd\""
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%s]", expected, err)
+		}
+	})
+}
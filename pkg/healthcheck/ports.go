@@ -0,0 +1,102 @@
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+)
+
+var (
+	// wellKnownTLSPorts are ports conventionally serving TLS, which the proxy
+	// cannot detect as HTTP
+	wellKnownTLSPorts = map[int32]bool{443: true, 6443: true, 8443: true}
+
+	// serverSpeaksFirstPorts are ports of protocols in which the server sends
+	// the first bytes, stalling the proxy's protocol detection until it times
+	// out: SMTP, MySQL and PostgreSQL
+	serverSpeaksFirstPorts = map[int32]string{25: "SMTP", 3306: "MySQL", 5432: "PostgreSQL"}
+)
+
+// validateServicePortProtocols returns an error listing the ports of the
+// Services in meshed namespaces that the proxy's protocol detection is likely
+// to misclassify, along with the annotation that would avoid it. This is a
+// heuristic, so its findings should be reported as warnings.
+func validateServicePortProtocols(services []v1.Service, namespaces map[string]v1.Namespace, meshedNamespaces map[string]bool) error {
+	suspicious := []string{}
+
+	for _, svc := range services {
+		if !meshedNamespaces[svc.Namespace] {
+			continue
+		}
+
+		for _, port := range svc.Spec.Ports {
+			if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+				continue
+			}
+			if portExcluded(port.Port, svc.Annotations) || portExcluded(port.Port, namespaces[svc.Namespace].Annotations) {
+				continue
+			}
+
+			var reason string
+			if protocol, ok := serverSpeaksFirstPorts[port.Port]; ok {
+				reason = fmt.Sprintf("%s is a server-speaks-first protocol", protocol)
+			} else if wellKnownTLSPorts[port.Port] && isHTTPPortName(port.Name) {
+				reason = fmt.Sprintf("port is named \"%s\" but conventionally serves TLS", port.Name)
+			} else if port.Port == 443 {
+				reason = "port conventionally serves TLS"
+			} else {
+				continue
+			}
+
+			suspicious = append(suspicious, fmt.Sprintf("%s/%s port %d (%s; consider annotating with %s: \"%d\")",
+				svc.Namespace, svc.Name, port.Port, reason, k8s.ProxyOpaquePortsAnnotation, port.Port))
+		}
+	}
+
+	if len(suspicious) > 0 {
+		return fmt.Errorf("Some Service ports may be misclassified by protocol detection: %s", strings.Join(suspicious, ", "))
+	}
+
+	return nil
+}
+
+// portExcluded returns true if the port is marked as opaque or skipped by the
+// given annotations. Unparseable annotations are reported by a separate check
+// and ignored here.
+func portExcluded(port int32, annotations map[string]string) bool {
+	for _, key := range []string{k8s.ProxyOpaquePortsAnnotation, k8s.ProxySkipOutboundPortsAnnotation, k8s.ProxySkipInboundPortsAnnotation} {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+
+		ranges, err := k8s.ParsePortList(value)
+		if err != nil {
+			continue
+		}
+		for _, pr := range ranges {
+			if pr.Contains(int(port)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isHTTPPortName(name string) bool {
+	return name == "http" || strings.HasPrefix(name, "http-")
+}
+
+// meshedNamespaces returns the set of namespaces containing at least one
+// meshed pod.
+func meshedNamespaces(pods []v1.Pod, controlPlaneNamespace string) map[string]bool {
+	namespaces := make(map[string]bool)
+	for _, pod := range pods {
+		if hasProxyContainer(pod) && k8s.IsMeshed(&pod, controlPlaneNamespace) {
+			namespaces[pod.Namespace] = true
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateServicePortProtocols(t *testing.T) {
+	service := func(namespace, name string, annotations map[string]string, ports ...v1.ServicePort) v1.Service {
+		return v1.Service{
+			ObjectMeta: meta.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+			Spec:       v1.ServiceSpec{Ports: ports},
+		}
+	}
+	meshed := map[string]bool{"emojivoto": true}
+
+	t.Run("Returns nil if no ports are likely to be misclassified", func(t *testing.T) {
+		services := []v1.Service{
+			service("emojivoto", "web", nil, v1.ServicePort{Name: "http", Port: 80}),
+			service("emojivoto", "db", map[string]string{
+				"config.linkerd.io/opaque-ports": "3306,5432",
+			}, v1.ServicePort{Name: "mysql", Port: 3306}, v1.ServicePort{Name: "postgres", Port: 5432}),
+			service("emojivoto", "api", map[string]string{
+				"config.linkerd.io/skip-outbound-ports": "443",
+			}, v1.ServicePort{Name: "https", Port: 443}),
+			service("default", "smtp", nil, v1.ServicePort{Name: "smtp", Port: 25}),
+		}
+
+		err := validateServicePortProtocols(services, map[string]v1.Namespace{}, meshed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Honors namespace annotations", func(t *testing.T) {
+		services := []v1.Service{
+			service("emojivoto", "db", nil, v1.ServicePort{Name: "mysql", Port: 3306}),
+		}
+		namespaces := map[string]v1.Namespace{
+			"emojivoto": {ObjectMeta: meta.ObjectMeta{
+				Name:        "emojivoto",
+				Annotations: map[string]string{"config.linkerd.io/opaque-ports": "3000-4000"},
+			}},
+		}
+
+		err := validateServicePortProtocols(services, namespaces, meshed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if ports are likely to be misclassified", func(t *testing.T) {
+		services := []v1.Service{
+			service("emojivoto", "db", nil, v1.ServicePort{Name: "mysql", Port: 3306}),
+			service("emojivoto", "api", nil, v1.ServicePort{Name: "http", Port: 8443}, v1.ServicePort{Name: "tls", Port: 443}),
+			service("emojivoto", "dns", nil, v1.ServicePort{Name: "dns", Port: 5432, Protocol: v1.ProtocolUDP}),
+		}
+
+		err := validateServicePortProtocols(services, map[string]v1.Namespace{}, meshed)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some Service ports may be misclassified by protocol detection: " +
+			"emojivoto/db port 3306 (MySQL is a server-speaks-first protocol; consider annotating with config.linkerd.io/opaque-ports: \"3306\"), " +
+			"emojivoto/api port 8443 (port is named \"http\" but conventionally serves TLS; consider annotating with config.linkerd.io/opaque-ports: \"8443\"), " +
+			"emojivoto/api port 443 (port conventionally serves TLS; consider annotating with config.linkerd.io/opaque-ports: \"443\")"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/config"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// proxyPortConfig holds the ports the proxy listens on.
+type proxyPortConfig struct {
+	InboundPort  int `json:"inboundPort"`
+	OutboundPort int `json:"outboundPort"`
+	ControlPort  int `json:"controlPort"`
+	MetricsPort  int `json:"metricsPort"`
+}
+
+// defaultProxyPorts are the ports used when linkerd-config doesn't override
+// them; they match the defaults of `linkerd inject`.
+var defaultProxyPorts = proxyPortConfig{
+	InboundPort:  4143,
+	OutboundPort: 4140,
+	ControlPort:  4190,
+	MetricsPort:  4191,
+}
+
+// proxyListenerEnvVars maps the proxy's listener environment variables to the
+// name of the port they configure.
+var proxyListenerEnvVars = map[string]string{
+	"LINKERD2_PROXY_INBOUND_LISTENER":  "inbound",
+	"LINKERD2_PROXY_OUTBOUND_LISTENER": "outbound",
+	"LINKERD2_PROXY_CONTROL_LISTENER":  "control",
+	"LINKERD2_PROXY_METRICS_LISTENER":  "metrics",
+}
+
+// byName returns the ports keyed by name: "inbound", "outbound", "control"
+// and "metrics".
+func (c proxyPortConfig) byName() map[string]int {
+	return map[string]int{
+		"inbound":  c.InboundPort,
+		"outbound": c.OutboundPort,
+		"control":  c.ControlPort,
+		"metrics":  c.MetricsPort,
+	}
+}
+
+// list returns the inbound, outbound, control and metrics ports, in that
+// order.
+func (c proxyPortConfig) list() []int {
+	return []int{c.InboundPort, c.OutboundPort, c.ControlPort, c.MetricsPort}
+}
+
+// getProxyPortConfig returns the proxy ports configured in linkerd-config,
+// falling back to defaultProxyPorts for those that aren't set.
+func (hc *HealthChecker) getProxyPortConfig(ctx context.Context) (proxyPortConfig, error) {
+	linkerdConfig, err := hc.getLinkerdConfig(ctx)
+	if err != nil {
+		return defaultProxyPorts, err
+	}
+	if linkerdConfig == nil {
+		return defaultProxyPorts, nil
+	}
+
+	return proxyPortsFromConfig(linkerdConfig.Proxy), nil
+}
+
+func parseProxyPortConfig(data string) (proxyPortConfig, error) {
+	linkerdConfig, err := config.ParseLinkerdConfig(map[string]string{config.ProxyKey: data})
+	if err != nil {
+		return defaultProxyPorts, err
+	}
+
+	return proxyPortsFromConfig(linkerdConfig.Proxy), nil
+}
+
+func proxyPortsFromConfig(proxy config.Proxy) proxyPortConfig {
+	ports := defaultProxyPorts
+	if proxy.InboundPort != 0 {
+		ports.InboundPort = proxy.InboundPort
+	}
+	if proxy.OutboundPort != 0 {
+		ports.OutboundPort = proxy.OutboundPort
+	}
+	if proxy.ControlPort != 0 {
+		ports.ControlPort = proxy.ControlPort
+	}
+	if proxy.MetricsPort != 0 {
+		ports.MetricsPort = proxy.MetricsPort
+	}
+	return ports
+}
+
+// podProxyPorts returns the ports of the pod's proxy, as configured by its
+// listener environment variables at injection time, falling back to the given
+// configuration for those that aren't set.
+func podProxyPorts(spec *v1.PodSpec, portConfig proxyPortConfig) map[string]int {
+	ports := portConfig.byName()
+
+	proxy := k8s.GetProxyContainer(spec)
+	if proxy == nil {
+		return ports
+	}
+
+	for _, env := range proxy.Env {
+		name, ok := proxyListenerEnvVars[env.Name]
+		if !ok {
+			continue
+		}
+		// listeners are formatted as "tcp://0.0.0.0:4143"
+		addr, err := url.Parse(env.Value)
+		if err != nil {
+			continue
+		}
+		if port, err := strconv.Atoi(addr.Port()); err == nil {
+			ports[name] = port
+		}
+	}
+
+	return ports
+}
+
+// proxyPortCollision returns the name of the proxy port that the port collides
+// with, if any.
+func proxyPortCollision(port int, proxyPorts map[string]int) (string, bool) {
+	for _, name := range []string{"inbound", "outbound", "control", "metrics"} {
+		if proxyPorts[name] == port {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func containerPortCollisions(resource string, containers []v1.Container, proxyPorts map[string]int) []string {
+	collisions := []string{}
+	for _, c := range containers {
+		if c.Name == k8s.ProxyContainerName {
+			continue
+		}
+		for _, p := range c.Ports {
+			if name, ok := proxyPortCollision(int(p.ContainerPort), proxyPorts); ok {
+				collisions = append(collisions, fmt.Sprintf("%s container %s port %d (proxy %s port)", resource, c.Name, p.ContainerPort, name))
+			}
+		}
+	}
+	return collisions
+}
+
+// validateProxyPortCollisions returns an error listing the application ports
+// that collide with the ports of the proxy injected alongside them: the
+// container ports of the workloads of all kinds whose pod template has a
+// proxy or will be injected on their next rollout, of the other meshed pods,
+// and the numeric target ports of the Services selecting meshed pods.
+func validateProxyPortCollisions(pods []v1.Pod, workloads []workloadTemplate, services []v1.Service, policy autoInjectPolicy, controlPlaneNamespace string, portConfig proxyPortConfig) error {
+	reported := []workloadTemplate{}
+	workloadCollisions := []string{}
+
+	for _, w := range workloads {
+		var proxyPorts map[string]int
+		if k8s.GetProxyContainer(&w.template.Spec) != nil {
+			proxyPorts = podProxyPorts(&w.template.Spec, portConfig)
+		} else if policy.injectsTemplate(w) {
+			proxyPorts = portConfig.byName()
+		} else {
+			continue
+		}
+
+		if found := containerPortCollisions(w.String(), w.template.Spec.Containers, proxyPorts); len(found) > 0 {
+			reported = append(reported, w)
+			workloadCollisions = append(workloadCollisions, found...)
+		}
+	}
+
+	collisions := []string{}
+	meshed := []v1.Pod{}
+	for _, pod := range pods {
+		if !isActivePod(pod) || !hasProxyContainer(pod) || !k8s.IsMeshed(&pod, controlPlaneNamespace) {
+			continue
+		}
+		meshed = append(meshed, pod)
+
+		// pods of a reported workload are covered by it
+		if ownedByAny(pod, reported) {
+			continue
+		}
+		resource := fmt.Sprintf("pod %s/%s", pod.Namespace, pod.Name)
+		collisions = append(collisions, containerPortCollisions(resource, pod.Spec.Containers, podProxyPorts(&pod.Spec, portConfig))...)
+	}
+	collisions = append(collisions, workloadCollisions...)
+
+	for _, svc := range services {
+		selected := k8s.PodsForService(svc, meshed)
+		if len(selected) == 0 {
+			continue
+		}
+
+		proxyPorts := podProxyPorts(&selected[0].Spec, portConfig)
+		for _, p := range svc.Spec.Ports {
+			if p.TargetPort.Type != intstr.Int {
+				continue
+			}
+			if name, ok := proxyPortCollision(p.TargetPort.IntValue(), proxyPorts); ok {
+				collisions = append(collisions, fmt.Sprintf("service %s/%s targetPort %d (proxy %s port)", svc.Namespace, svc.Name, p.TargetPort.IntValue(), name))
+			}
+		}
+	}
+
+	if len(collisions) > 0 {
+		return fmt.Errorf("Some application ports collide with the ports of the proxy: %s", strings.Join(collisions, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseProxyPortConfig(t *testing.T) {
+	config, err := parseProxyPortConfig(`{"inboundPort":5143,"metricsPort":5191}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := proxyPortConfig{InboundPort: 5143, OutboundPort: 4140, ControlPort: 4190, MetricsPort: 5191}
+	if config != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, config)
+	}
+
+	_, err = parseProxyPortConfig("{")
+	if err == nil {
+		t.Fatal("Expected error, got nothing")
+	}
+}
+
+func TestValidateProxyPortCollisions(t *testing.T) {
+	namespaces := map[string]v1.Namespace{
+		"emojivoto": {ObjectMeta: meta.ObjectMeta{
+			Name:   "emojivoto",
+			Labels: map[string]string{"linkerd.io/auto-inject": "enabled"},
+		}},
+	}
+	policy := autoInjectPolicy{namespaces: namespaces}
+
+	pod := func(name string, appPort int32, env ...v1.EnvVar) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "emojivoto",
+				Name:      name,
+				Labels:    map[string]string{"linkerd.io/control-plane-ns": "linkerd", "app": name},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "app", Ports: []v1.ContainerPort{{ContainerPort: appPort}}},
+					{Name: "linkerd-proxy", Env: env, Ports: []v1.ContainerPort{{ContainerPort: 4143}}},
+				},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+	}
+
+	service := func(name string, targetPort intstr.IntOrString) v1.Service {
+		return v1.Service{
+			ObjectMeta: meta.ObjectMeta{Namespace: "emojivoto", Name: name},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": name},
+				Ports:    []v1.ServicePort{{Port: 80, TargetPort: targetPort}},
+			},
+		}
+	}
+
+	t.Run("Returns nil if no ports collide", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", 8080),
+			pod("emoji", 4191, v1.EnvVar{Name: "LINKERD2_PROXY_METRICS_LISTENER", Value: "tcp://0.0.0.0:5191"}),
+		}
+		services := []v1.Service{
+			service("web", intstr.FromInt(8080)),
+			service("emoji", intstr.FromString("http")),
+		}
+
+		err := validateProxyPortCollisions(pods, nil, services, policy, "linkerd", defaultProxyPorts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming colliding ports", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("web", 4191),
+			pod("voting", 8080),
+		}
+		redis := pod("redis-0", 4190)
+		redis.OwnerReferences = []meta.OwnerReference{{Kind: "StatefulSet", Name: "redis"}}
+		pods = append(pods, redis)
+
+		deployment := workloadTemplate{kind: "deployment", namespace: "emojivoto", name: "vote-bot"}
+		deployment.template.Spec.Containers = []v1.Container{{Name: "bot", Ports: []v1.ContainerPort{{ContainerPort: 5143}}}}
+		statefulSet := workloadTemplate{kind: "statefulset", namespace: "emojivoto", name: "redis"}
+		statefulSet.template.Spec = redis.Spec
+		services := []v1.Service{
+			service("voting", intstr.FromInt(4143)),
+		}
+		config := defaultProxyPorts
+		config.InboundPort = 5143
+
+		err := validateProxyPortCollisions(pods, []workloadTemplate{deployment, statefulSet}, services, policy, "linkerd", config)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some application ports collide with the ports of the proxy: " +
+			"pod emojivoto/web container app port 4191 (proxy metrics port), " +
+			"deployment emojivoto/vote-bot container bot port 5143 (proxy inbound port), " +
+			"statefulset emojivoto/redis container app port 4190 (proxy control port)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Takes the pod's proxy configuration into account", func(t *testing.T) {
+		pods := []v1.Pod{
+			pod("voting", 8080, v1.EnvVar{Name: "LINKERD2_PROXY_INBOUND_LISTENER", Value: "tcp://0.0.0.0:4143"}),
+		}
+		services := []v1.Service{
+			service("voting", intstr.FromInt(4143)),
+		}
+		config := defaultProxyPorts
+		config.InboundPort = 5143
+
+		err := validateProxyPortCollisions(pods, nil, services, policy, "linkerd", config)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Some application ports collide with the ports of the proxy: service emojivoto/voting targetPort 4143 (proxy inbound port)" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationapi "k8s.io/api/authorization/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const controllerServiceAccountName = "linkerd-controller"
+
+// resourcePermission is a verb on a resource, such as "watch pods".
+type resourcePermission struct {
+	verb     string
+	group    string
+	resource string
+}
+
+func (p resourcePermission) String() string {
+	if p.group == "" {
+		return fmt.Sprintf("%s %s", p.verb, p.resource)
+	}
+	return fmt.Sprintf("%s %s.%s", p.verb, p.resource, p.group)
+}
+
+// controllerPermissions are the permissions the controller relies on to keep
+// its caches up to date, as granted by the controller's Role in the install
+// templates.
+var controllerPermissions = []resourcePermission{
+	{"list", "", "pods"},
+	{"watch", "", "pods"},
+	{"list", "", "endpoints"},
+	{"watch", "", "endpoints"},
+	{"list", "", "services"},
+	{"watch", "", "services"},
+	{"list", "linkerd.io", "serviceprofiles"},
+	{"watch", "linkerd.io", "serviceprofiles"},
+}
+
+// accessReviewer submits a SubjectAccessReview and returns its result.
+type accessReviewer func(*authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReview, error)
+
+// selfAccessReviewer submits a SelfSubjectAccessReview and returns its result.
+type selfAccessReviewer func(*authorizationapi.SelfSubjectAccessReview) (*authorizationapi.SelfSubjectAccessReview, error)
+
+// checkControllerPermissions verifies that the controller's ServiceAccount is
+// still granted the permissions it needs. The check is skipped if the caller
+// is not permitted to review another subject's permissions.
+func (hc *HealthChecker) checkControllerPermissions(ctx context.Context) error {
+	if err := hc.initClientset(); err != nil {
+		return err
+	}
+
+	return validateServiceAccountPermissions(hc.subjectAccessReviewer(ctx), hc.ControlPlaneNamespace, controllerServiceAccountName, controllerPermissions)
+}
+
+// initClientset builds the clientset shared by the checks, which sends its
+// requests through the Kubernetes API's transport.
+func (hc *HealthChecker) initClientset() error {
+	if hc.clientset != nil {
+		return nil
+	}
+
+	var err error
+	hc.clientset, err = hc.kubeAPI.NewClientset()
+	return err
+}
+
+// subjectAccessReviewer returns an accessReviewer submitting its reviews with
+// the shared clientset, canceled once the context is done, since the typed
+// clients' requests take no context.
+func (hc *HealthChecker) subjectAccessReviewer(ctx context.Context) accessReviewer {
+	return func(sar *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReview, error) {
+		result := &authorizationapi.SubjectAccessReview{}
+		err := hc.clientset.AuthorizationV1beta1().RESTClient().Post().
+			Resource("subjectaccessreviews").
+			Body(sar).
+			Context(ctx).
+			Do().
+			Into(result)
+		return result, err
+	}
+}
+
+// selfSubjectAccessReviewer returns a selfAccessReviewer submitting its reviews
+// with the shared clientset, canceled once the context is done.
+func (hc *HealthChecker) selfSubjectAccessReviewer(ctx context.Context) selfAccessReviewer {
+	return func(sar *authorizationapi.SelfSubjectAccessReview) (*authorizationapi.SelfSubjectAccessReview, error) {
+		result := &authorizationapi.SelfSubjectAccessReview{}
+		err := hc.clientset.AuthorizationV1beta1().RESTClient().Post().
+			Resource("selfsubjectaccessreviews").
+			Body(sar).
+			Context(ctx).
+			Do().
+			Into(result)
+		return result, err
+	}
+}
+
+// validateServiceAccountPermissions returns an error listing the permissions
+// the given ServiceAccount is missing. Each permission is first reviewed across
+// all namespaces, then within the ServiceAccount's namespace, so that
+// single-namespace installs are accepted.
+func validateServiceAccountPermissions(review accessReviewer, namespace, serviceAccount string, permissions []resourcePermission) error {
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+	groups := []string{"system:serviceaccounts", fmt.Sprintf("system:serviceaccounts:%s", namespace), "system:authenticated"}
+
+	missing := []string{}
+	for _, p := range permissions {
+		allowed := false
+		for _, ns := range []string{"", namespace} {
+			sar := &authorizationapi.SubjectAccessReview{
+				Spec: authorizationapi.SubjectAccessReviewSpec{
+					User:   user,
+					Groups: groups,
+					ResourceAttributes: &authorizationapi.ResourceAttributes{
+						Namespace: ns,
+						Verb:      p.verb,
+						Group:     p.group,
+						Resource:  p.resource,
+					},
+				},
+			}
+
+			response, err := review(sar)
+			if err != nil {
+				if kerrors.IsForbidden(err) {
+					return &SkipError{Reason: fmt.Sprintf("Not permitted to review the permissions of the \"%s\" ServiceAccount", serviceAccount)}
+				}
+				return err
+			}
+			if response.Status.Allowed {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			missing = append(missing, p.String())
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("The \"%s\" ServiceAccount is missing permissions: %s", serviceAccount, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
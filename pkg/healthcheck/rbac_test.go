@@ -0,0 +1,94 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	authorizationapi "k8s.io/api/authorization/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestValidateServiceAccountPermissions(t *testing.T) {
+	permissions := []resourcePermission{
+		{"list", "", "pods"},
+		{"watch", "", "pods"},
+		{"watch", "linkerd.io", "serviceprofiles"},
+	}
+
+	reviewer := func(allowed map[string]bool) accessReviewer {
+		return func(sar *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReview, error) {
+			attrs := sar.Spec.ResourceAttributes
+			key := fmt.Sprintf("%s %s %s %s %s", sar.Spec.User, attrs.Namespace, attrs.Verb, attrs.Group, attrs.Resource)
+			sar.Status.Allowed = allowed[key]
+			return sar, nil
+		}
+	}
+
+	t.Run("Returns nil if all permissions are granted", func(t *testing.T) {
+		review := reviewer(map[string]bool{
+			"system:serviceaccount:linkerd:linkerd-controller  list  pods":                              true,
+			"system:serviceaccount:linkerd:linkerd-controller  watch  pods":                             true,
+			"system:serviceaccount:linkerd:linkerd-controller linkerd watch linkerd.io serviceprofiles": true,
+		})
+
+		err := validateServiceAccountPermissions(review, "linkerd", "linkerd-controller", permissions)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming the missing permissions", func(t *testing.T) {
+		review := reviewer(map[string]bool{
+			"system:serviceaccount:linkerd:linkerd-controller  list  pods": true,
+		})
+
+		err := validateServiceAccountPermissions(review, "linkerd", "linkerd-controller", permissions)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "The \"linkerd-controller\" ServiceAccount is missing permissions: watch pods, watch serviceprofiles.linkerd.io" {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Skips if the review is forbidden", func(t *testing.T) {
+		review := func(sar *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReview, error) {
+			return nil, kerrors.NewForbidden(schema.GroupResource{Group: "authorization.k8s.io", Resource: "subjectaccessreviews"}, "", fmt.Errorf("denied"))
+		}
+
+		err := validateServiceAccountPermissions(review, "linkerd", "linkerd-controller", permissions)
+		if _, ok := err.(*SkipError); !ok {
+			t.Fatalf("Expected skip, got %v", err)
+		}
+	})
+}
+
+func TestCheckControllerPermissions(t *testing.T) {
+	hc, done := newTestHealthChecker(t, []Checks{}, &HealthCheckOptions{ControlPlaneNamespace: "linkerd"}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/apis/authorization.k8s.io/v1beta1/subjectaccessreviews" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"SubjectAccessReview","apiVersion":"authorization.k8s.io/v1beta1","status":{"allowed":true}}`))
+	})
+	defer done()
+	recorder := &fakeRequestRecorder{}
+	hc.kubeAPI.Recorder = recorder
+
+	if err := hc.checkControllerPermissions(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(recorder.urls) != len(controllerPermissions) {
+		t.Fatalf("Expected the %d reviews to be sent through the Kubernetes API's transport, got %v", len(controllerPermissions), recorder.urls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := hc.checkControllerPermissions(ctx); err == nil {
+		t.Fatal("Expected the reviews to be canceled with the context")
+	}
+}
@@ -0,0 +1,190 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// knownSelfCheckSubsystems are the subsystems checked by the control plane
+// API's self-check, in the order it reports them.
+var knownSelfCheckSubsystems = []string{public.K8sClientSubsystemName, public.PromClientSubsystemName}
+
+// selfCheckDescriptions are the descriptions of the known subsystems' checks,
+// reported for those whose result was never received.
+var selfCheckDescriptions = map[string]string{
+	public.K8sClientSubsystemName:  public.K8sClientCheckDescription,
+	public.PromClientSubsystemName: public.PromClientCheckDescription,
+}
+
+// selfCheckStream yields the results of the control plane API's self-check as
+// its subsystems report them.
+type selfCheckStream struct {
+	// subsystems are those whose results are expected, or nil if the results
+	// are those of a unary self-check, which reports them all at once
+	subsystems []string
+
+	recv    func() (*healthcheckPb.CheckResult, error)
+	timeout time.Duration
+
+	// ctx is the context of the stream, bounded by the timeout, and runCtx
+	// the context of the run it belongs to
+	ctx    context.Context
+	runCtx context.Context
+	cancel context.CancelFunc
+}
+
+// openSelfCheckStream opens a stream of the results of the subsystems
+// requested from the control plane API's self-check, bounded by the
+// SelfCheckTimeout as well as by the run's context. For control planes that
+// don't serve the stream, the results of the unary self-check are streamed
+// instead.
+func (hc *HealthChecker) openSelfCheckStream(ctx context.Context) (*selfCheckStream, error) {
+	timeout := hc.SelfCheckTimeout
+	if timeout <= 0 {
+		timeout = defaultSelfCheckTimeout
+	}
+
+	subsystems := hc.selfCheckSubsystems()
+	streamCtx, cancel := context.WithTimeout(ctx, timeout)
+	stream, err := hc.apiClient.SelfCheckStream(streamCtx, &healthcheckPb.SelfCheckRequest{Subsystems: subsystems})
+	if status.Code(err) == codes.Unimplemented {
+		cancel()
+		return hc.unarySelfCheckStream(ctx)
+	}
+	if err != nil {
+		cancel()
+		if ctx.Err() == nil && streamCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("the control plane API did not respond to the self-check within %s", timeout)
+		}
+		return nil, apiCallError(ctx, err)
+	}
+
+	if subsystems == nil {
+		subsystems = knownSelfCheckSubsystems
+	}
+	return &selfCheckStream{
+		subsystems: subsystems,
+		recv:       stream.Recv,
+		timeout:    timeout,
+		ctx:        streamCtx,
+		runCtx:     ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+// unarySelfCheckStream streams the results of the unary self-check.
+func (hc *HealthChecker) unarySelfCheckStream(ctx context.Context) (*selfCheckStream, error) {
+	rsp, err := hc.selfCheck(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := rsp.Results
+	return &selfCheckStream{
+		recv: func() (*healthcheckPb.CheckResult, error) {
+			if len(results) == 0 {
+				return nil, io.EOF
+			}
+			result := results[0]
+			results = results[1:]
+			return result, nil
+		},
+		ctx:    ctx,
+		runCtx: ctx,
+		cancel: func() {},
+	}, nil
+}
+
+// missingResultError returns the error reported for a subsystem whose result
+// wasn't received before the stream ended with the given error, or with
+// io.EOF.
+func (s *selfCheckStream) missingResultError(streamErr error) error {
+	switch {
+	case streamErr == io.EOF:
+		return fmt.Errorf("the control plane API ended the self-check without reporting this subsystem's result")
+	case s.runCtx.Err() == nil && s.ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("the control plane API did not report this subsystem's result within %s", s.timeout)
+	default:
+		return fmt.Errorf("the self-check failed before this subsystem's result was reported: %s", apiCallError(s.runCtx, streamErr))
+	}
+}
+
+// runCheckStream reports the result of opening the checker's stream, then
+// that of each subsystem as it is received. The subsystems whose results were
+// expected but not received by the end of the stream are reported as failed.
+func (hc *HealthChecker) runCheckStream(ctx context.Context, c *checker, observer checkObserver) bool {
+	stream, err := c.checkStream(ctx)
+	observer(&CheckResult{
+		Category:    c.category,
+		Description: c.description,
+		Warning:     c.warning,
+		Err:         err,
+	})
+	if err != nil {
+		return false
+	}
+	defer stream.cancel()
+
+	reported := map[string]bool{}
+	var streamErr error
+	for {
+		var check *healthcheckPb.CheckResult
+		check, streamErr = stream.recv()
+		if streamErr != nil {
+			break
+		}
+		if stream.subsystems != nil && !containsSubsystem(stream.subsystems, check.SubsystemName) {
+			continue
+		}
+		reported[check.SubsystemName] = true
+
+		var err error
+		if check.Status != healthcheckPb.CheckStatus_OK {
+			err = fmt.Errorf(check.FriendlyMessageToUser)
+		}
+		observer(&CheckResult{
+			Category:    fmt.Sprintf("%s[%s]", c.category, check.SubsystemName),
+			Description: check.CheckDescription,
+			Warning:     c.warning,
+			Err:         err,
+		})
+		if err != nil {
+			return false
+		}
+	}
+
+	success := true
+	for _, subsystem := range stream.subsystems {
+		if reported[subsystem] {
+			continue
+		}
+		description, ok := selfCheckDescriptions[subsystem]
+		if !ok {
+			description = fmt.Sprintf("control plane can check %s", subsystem)
+		}
+		observer(&CheckResult{
+			Category:    fmt.Sprintf("%s[%s]", c.category, subsystem),
+			Description: description,
+			Warning:     c.warning,
+			Err:         stream.missingResultError(streamErr),
+		})
+		success = false
+	}
+	return success
+}
+
+func containsSubsystem(subsystems []string, subsystem string) bool {
+	for _, s := range subsystems {
+		if s == subsystem {
+			return true
+		}
+	}
+	return false
+}
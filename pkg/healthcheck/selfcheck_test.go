@@ -0,0 +1,227 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	healthcheckPb "github.com/linkerd/linkerd2/controller/gen/common/healthcheck"
+)
+
+func TestSelfCheckTimeout(t *testing.T) {
+	rsp := &healthcheckPb.SelfCheckResponse{}
+
+	t.Run("Waits for a slow self-check within the timeout", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{SelfCheckTimeout: time.Second})
+		hc.apiClient = &public.MockApiClient{SelfCheckResponseToReturn: rsp, SelfCheckDelay: 20 * time.Millisecond}
+
+		if _, err := hc.selfCheck(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Reports a self-check that doesn't respond within the timeout", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{}, &HealthCheckOptions{SelfCheckTimeout: 20 * time.Millisecond})
+		hc.apiClient = &public.MockApiClient{SelfCheckResponseToReturn: rsp, SelfCheckDelay: time.Minute}
+
+		_, err := hc.selfCheck(context.Background())
+		expected := "the control plane API did not respond to the self-check within 20ms"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	})
+
+	t.Run("Is bounded by the run's deadline", func(t *testing.T) {
+		hc := NewHealthChecker([]Checks{LinkerdAPIChecks}, &HealthCheckOptions{SelfCheckTimeout: time.Minute})
+		hc.apiClient = &public.MockApiClient{SelfCheckResponseToReturn: rsp, SelfCheckDelay: time.Minute}
+
+		var selfCheck *checker
+		for _, c := range hc.checkers {
+			if c.checkStream != nil {
+				selfCheck = c
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := selfCheck.checkStream(ctx); err != context.DeadlineExceeded {
+			t.Fatalf("Expected the run's deadline to be exceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Expected the self-check to stop at the run's deadline, took %s", elapsed)
+		}
+	})
+}
+
+func TestSelfCheckSubsystems(t *testing.T) {
+	testCases := []struct {
+		options   *HealthCheckOptions
+		requested []string
+		observed  []string
+	}{
+		{
+			&HealthCheckOptions{},
+			nil,
+			[]string{"linkerd-api[kubernetes]", "linkerd-api[prometheus]"},
+		},
+		{
+			&HealthCheckOptions{SelfCheckSubsystems: []string{"prometheus"}},
+			[]string{"prometheus"},
+			[]string{"linkerd-api[prometheus]"},
+		},
+		{
+			&HealthCheckOptions{SkipChecks: []string{"linkerd-api[prometheus]"}},
+			[]string{"kubernetes"},
+			[]string{"linkerd-api[kubernetes]"},
+		},
+		{
+			&HealthCheckOptions{SkipChecks: []string{"linkerd-api[kubernetes]: control plane can talk to Kubernetes"}},
+			[]string{"prometheus"},
+			[]string{"linkerd-api[prometheus]"},
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			hc := NewHealthChecker([]Checks{}, tc.options)
+			client := &public.MockApiClient{
+				SelfCheckResponseToReturn: &healthcheckPb.SelfCheckResponse{
+					Results: []*healthcheckPb.CheckResult{
+						{SubsystemName: "kubernetes", CheckDescription: "control plane can talk to Kubernetes", Status: healthcheckPb.CheckStatus_OK},
+						{SubsystemName: "prometheus", CheckDescription: "control plane can talk to Prometheus", Status: healthcheckPb.CheckStatus_OK},
+					},
+				},
+			}
+			hc.apiClient = client
+
+			observed := []string{}
+			hc.runCheckStream(context.Background(), &checker{
+				category:    LinkerdAPICategory,
+				description: "can query the control plane API",
+				checkStream: hc.openSelfCheckStream,
+			}, func(result *CheckResult) {
+				if result.Category != LinkerdAPICategory {
+					observed = append(observed, result.Category)
+				}
+			})
+
+			if requested := client.SelfCheckRequestReceived.GetSubsystems(); !reflect.DeepEqual(requested, tc.requested) {
+				t.Fatalf("Expected the %v subsystems to be requested, got %v", tc.requested, requested)
+			}
+			if !reflect.DeepEqual(observed, tc.observed) {
+				t.Fatalf("Expected the results of %v, got %v", tc.observed, observed)
+			}
+		})
+	}
+}
+
+func TestSelfCheckStream(t *testing.T) {
+	k8sResult := &healthcheckPb.CheckResult{
+		SubsystemName:    "kubernetes",
+		CheckDescription: "control plane can talk to Kubernetes",
+		Status:           healthcheckPb.CheckStatus_OK,
+	}
+	promResult := &healthcheckPb.CheckResult{
+		SubsystemName:    "prometheus",
+		CheckDescription: "control plane can talk to Prometheus",
+		Status:           healthcheckPb.CheckStatus_OK,
+	}
+
+	testCases := []struct {
+		description string
+		options     *HealthCheckOptions
+		client      *public.MockApiClient
+		success     bool
+		observed    []string
+	}{
+		{
+			"reports each subsystem's result",
+			&HealthCheckOptions{},
+			&public.MockApiClient{SelfCheckStreamResultsToReturn: []*healthcheckPb.CheckResult{promResult, k8sResult}},
+			true,
+			[]string{
+				"linkerd-api can query the control plane API",
+				"linkerd-api[prometheus] control plane can talk to Prometheus",
+				"linkerd-api[kubernetes] control plane can talk to Kubernetes",
+			},
+		},
+		{
+			"reports the subsystems missing from an ended stream as failed",
+			&HealthCheckOptions{},
+			&public.MockApiClient{SelfCheckStreamResultsToReturn: []*healthcheckPb.CheckResult{k8sResult}},
+			false,
+			[]string{
+				"linkerd-api can query the control plane API",
+				"linkerd-api[kubernetes] control plane can talk to Kubernetes",
+				"linkerd-api[prometheus] control plane can talk to Prometheus: the control plane API ended the self-check without reporting this subsystem's result",
+			},
+		},
+		{
+			"reports the results received before a slow subsystem times out",
+			&HealthCheckOptions{SelfCheckTimeout: 20 * time.Millisecond},
+			&public.MockApiClient{SelfCheckStreamResultsToReturn: []*healthcheckPb.CheckResult{k8sResult}, SelfCheckStreamHangs: true},
+			false,
+			[]string{
+				"linkerd-api can query the control plane API",
+				"linkerd-api[kubernetes] control plane can talk to Kubernetes",
+				"linkerd-api[prometheus] control plane can talk to Prometheus: the control plane API did not report this subsystem's result within 20ms",
+			},
+		},
+		{
+			"reports the subsystems missing from a failed stream as failed",
+			&HealthCheckOptions{SelfCheckSubsystems: []string{"prometheus"}},
+			&public.MockApiClient{SelfCheckStreamErrorToReturn: errors.New("connection reset")},
+			false,
+			[]string{
+				"linkerd-api can query the control plane API",
+				"linkerd-api[prometheus] control plane can talk to Prometheus: the self-check failed before this subsystem's result was reported: connection reset",
+			},
+		},
+		{
+			"falls back to the unary self-check",
+			&HealthCheckOptions{},
+			&public.MockApiClient{SelfCheckResponseToReturn: &healthcheckPb.SelfCheckResponse{Results: []*healthcheckPb.CheckResult{k8sResult, promResult}}},
+			true,
+			[]string{
+				"linkerd-api can query the control plane API",
+				"linkerd-api[kubernetes] control plane can talk to Kubernetes",
+				"linkerd-api[prometheus] control plane can talk to Prometheus",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			hc := NewHealthChecker([]Checks{}, tc.options)
+			hc.apiClient = tc.client
+
+			observed := []string{}
+			success := hc.runCheckStream(context.Background(), &checker{
+				category:    LinkerdAPICategory,
+				description: "can query the control plane API",
+				checkStream: hc.openSelfCheckStream,
+			}, func(result *CheckResult) {
+				entry := fmt.Sprintf("%s %s", result.Category, result.Description)
+				if result.Err != nil {
+					entry += fmt.Sprintf(": %s", result.Err)
+				}
+				observed = append(observed, entry)
+			})
+
+			if success != tc.success {
+				t.Fatalf("Expected success to be %t, got %t", tc.success, success)
+			}
+			if !reflect.DeepEqual(observed, tc.observed) {
+				t.Fatalf("Expected results\n%s\ngot\n%s", strings.Join(tc.observed, "\n"), strings.Join(observed, "\n"))
+			}
+		})
+	}
+}
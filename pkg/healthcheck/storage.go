@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/api/core/v1"
+)
+
+// pvcPendingGracePeriod is how long a PersistentVolumeClaim may be Pending
+// before it's reported, giving dynamic provisioners time to bind it.
+const pvcPendingGracePeriod = 2 * time.Minute
+
+// checkControlPlaneClaims verifies that the PersistentVolumeClaims in the
+// control plane namespace are bound, reporting the provisioning events of
+// those that aren't. It is skipped if the control plane uses no claims.
+func (hc *HealthChecker) checkControlPlaneClaims(ctx context.Context) error {
+	pvcs, err := hc.kubeAPI.GetPersistentVolumeClaims(ctx, hc.ControlPlaneNamespace)
+	if err != nil {
+		return err
+	}
+	if len(pvcs) == 0 {
+		return &SkipError{Reason: "The control plane uses no PersistentVolumeClaims"}
+	}
+
+	now := time.Now()
+	events := make(map[string][]v1.Event)
+	for _, pvc := range pvcs {
+		if !claimPendingSince(pvc, now, pvcPendingGracePeriod) {
+			continue
+		}
+
+		events[pvc.Name], err = hc.kubeAPI.GetEventsFor(ctx, pvc.Namespace, "PersistentVolumeClaim", pvc.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validatePendingClaims(pvcs, events, now, pvcPendingGracePeriod)
+}
+
+func claimPendingSince(pvc v1.PersistentVolumeClaim, now time.Time, grace time.Duration) bool {
+	return pvc.Status.Phase == v1.ClaimPending && now.Sub(pvc.CreationTimestamp.Time) > grace
+}
+
+// validatePendingClaims returns an error listing the PersistentVolumeClaims
+// that have been Pending for longer than the grace period, along with the
+// message of their most recent warning event, preferring ProvisioningFailed
+// events. events is keyed by claim name.
+func validatePendingClaims(pvcs []v1.PersistentVolumeClaim, events map[string][]v1.Event, now time.Time, grace time.Duration) error {
+	pending := []string{}
+
+	for _, pvc := range pvcs {
+		if !claimPendingSince(pvc, now, grace) {
+			continue
+		}
+
+		detail := fmt.Sprintf("Pending for %s", now.Sub(pvc.CreationTimestamp.Time).Round(time.Second))
+		if event := provisioningEvent(events[pvc.Name]); event != nil {
+			detail = fmt.Sprintf("%s: %s: %s", detail, event.Reason, event.Message)
+		}
+		pending = append(pending, fmt.Sprintf("%s (%s)", pvc.Name, detail))
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("Some PersistentVolumeClaims are not bound: %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
+
+// provisioningEvent returns the most recent ProvisioningFailed event, or else
+// the most recent warning event, or nil if there are none.
+func provisioningEvent(events []v1.Event) *v1.Event {
+	var latest, latestFailed *v1.Event
+	for i := range events {
+		e := &events[i]
+		if e.Type != v1.EventTypeWarning {
+			continue
+		}
+		if latest == nil || latest.LastTimestamp.Before(&e.LastTimestamp) {
+			latest = e
+		}
+		if e.Reason == "ProvisioningFailed" && (latestFailed == nil || latestFailed.LastTimestamp.Before(&e.LastTimestamp)) {
+			latestFailed = e
+		}
+	}
+
+	if latestFailed != nil {
+		return latestFailed
+	}
+	return latest
+}
@@ -0,0 +1,68 @@
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatePendingClaims(t *testing.T) {
+	now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	pvc := func(name string, phase v1.PersistentVolumeClaimPhase, age time.Duration) v1.PersistentVolumeClaim {
+		return v1.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace:         "linkerd",
+				Name:              name,
+				CreationTimestamp: meta.NewTime(now.Add(-age)),
+			},
+			Status: v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	event := func(eventType, reason, message string, age time.Duration) v1.Event {
+		return v1.Event{
+			Type:          eventType,
+			Reason:        reason,
+			Message:       message,
+			LastTimestamp: meta.NewTime(now.Add(-age)),
+		}
+	}
+
+	t.Run("Returns nil if all claims are bound or within the grace period", func(t *testing.T) {
+		pvcs := []v1.PersistentVolumeClaim{
+			pvc("prometheus", v1.ClaimBound, time.Hour),
+			pvc("grafana", v1.ClaimPending, time.Minute),
+		}
+
+		err := validatePendingClaims(pvcs, map[string][]v1.Event{}, now, 2*time.Minute)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error with the provisioning events of pending claims", func(t *testing.T) {
+		pvcs := []v1.PersistentVolumeClaim{
+			pvc("prometheus", v1.ClaimPending, 5*time.Minute),
+			pvc("grafana", v1.ClaimPending, 10*time.Minute),
+		}
+		events := map[string][]v1.Event{
+			"prometheus": {
+				event(v1.EventTypeWarning, "ProvisioningFailed", "no persistent volumes available for this claim and no storage class is set", 2*time.Minute),
+				event(v1.EventTypeWarning, "FailedBinding", "no volume plugin matched", time.Minute),
+				event(v1.EventTypeNormal, "ExternalProvisioning", "waiting for a volume to be created", 30*time.Second),
+			},
+		}
+
+		err := validatePendingClaims(pvcs, events, now, 2*time.Minute)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some PersistentVolumeClaims are not bound: prometheus (Pending for 5m0s: ProvisioningFailed: no persistent volumes available for this claim and no storage class is set), grafana (Pending for 10m0s)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const trafficSplitResource = "trafficsplits"
+
+// trafficSplitGroupVersions lists the API versions of the SMI TrafficSplit
+// resource, which differ in how backend weights are expressed.
+var trafficSplitGroupVersions = []string{
+	"split.smi-spec.io/v1alpha1",
+	"split.smi-spec.io/v1alpha2",
+}
+
+// trafficSplit describes a TrafficSplit resource, independently of its API
+// version.
+type trafficSplit struct {
+	namespace string
+	name      string
+	apex      string
+	backends  []trafficSplitBackend
+}
+
+type trafficSplitBackend struct {
+	service string
+	weight  int64
+}
+
+// getTrafficSplits returns the TrafficSplits of all the served API versions,
+// or a SkipError if the TrafficSplit CRD is not installed. A TrafficSplit
+// served under several versions is only returned once.
+func (hc *HealthChecker) getTrafficSplits(ctx context.Context) ([]trafficSplit, error) {
+	installed := false
+	seen := make(map[string]bool)
+	splits := []trafficSplit{}
+
+	for _, groupVersion := range trafficSplitGroupVersions {
+		served, err := hc.kubeAPI.ResourceExists(ctx, groupVersion, trafficSplitResource)
+		if err != nil {
+			return nil, err
+		}
+		if !served {
+			continue
+		}
+
+		list, err := hc.kubeAPI.GetUnstructuredList(ctx, fmt.Sprintf("/apis/%s/%s", groupVersion, trafficSplitResource))
+		if err != nil {
+			return nil, err
+		}
+		if list == nil {
+			continue
+		}
+		installed = true
+
+		for _, item := range list.Items {
+			key := item.GetNamespace() + "/" + item.GetName()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			split, err := parseTrafficSplit(item)
+			if err != nil {
+				return nil, err
+			}
+			splits = append(splits, split)
+		}
+	}
+
+	if !installed {
+		return nil, &SkipError{Reason: "The TrafficSplit CRD is not installed"}
+	}
+
+	return splits, nil
+}
+
+func parseTrafficSplit(item unstructured.Unstructured) (trafficSplit, error) {
+	split := trafficSplit{namespace: item.GetNamespace(), name: item.GetName()}
+	invalid := func(err interface{}) error {
+		return fmt.Errorf("Invalid TrafficSplit \"%s/%s\": %v", split.namespace, split.name, err)
+	}
+
+	apex, _, err := unstructured.NestedString(item.Object, "spec", "service")
+	if err != nil {
+		return split, invalid(err)
+	}
+	split.apex = apex
+
+	backends, _, err := unstructured.NestedSlice(item.Object, "spec", "backends")
+	if err != nil {
+		return split, invalid(err)
+	}
+	for _, b := range backends {
+		backend, ok := b.(map[string]interface{})
+		if !ok {
+			return split, invalid("backends must be objects")
+		}
+
+		service, _, err := unstructured.NestedString(backend, "service")
+		if err != nil {
+			return split, invalid(err)
+		}
+
+		weight, err := parseTrafficSplitWeight(backend["weight"])
+		if err != nil {
+			return split, invalid(err)
+		}
+
+		split.backends = append(split.backends, trafficSplitBackend{service: service, weight: weight})
+	}
+
+	return split, nil
+}
+
+// parseTrafficSplitWeight parses a backend weight, expressed as a quantity
+// (e.g. "500m") in v1alpha1 and as an integer in v1alpha2. Quantities are
+// returned in thousandths.
+func parseTrafficSplitWeight(weight interface{}) (int64, error) {
+	switch w := weight.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return w, nil
+	case float64:
+		return int64(w), nil
+	case string:
+		q, err := resource.ParseQuantity(w)
+		if err != nil {
+			return 0, fmt.Errorf("invalid weight \"%s\"", w)
+		}
+		return q.MilliValue(), nil
+	default:
+		return 0, fmt.Errorf("invalid weight %v", w)
+	}
+}
+
+// validateTrafficSplits returns an error describing, for each TrafficSplit,
+// the referenced services that do not exist in its namespace and any invalid
+// weights.
+func validateTrafficSplits(splits []trafficSplit, services []v1.Service) error {
+	exists := make(map[string]bool)
+	for _, svc := range services {
+		exists[svc.Namespace+"/"+svc.Name] = true
+	}
+
+	failures := []string{}
+	for _, split := range splits {
+		problems := []string{}
+
+		if split.apex == "" {
+			problems = append(problems, "no apex service")
+		} else if !exists[split.namespace+"/"+split.apex] {
+			problems = append(problems, fmt.Sprintf("apex service \"%s\" does not exist", split.apex))
+		}
+
+		positive := false
+		for _, backend := range split.backends {
+			if !exists[split.namespace+"/"+backend.service] {
+				problems = append(problems, fmt.Sprintf("backend service \"%s\" does not exist", backend.service))
+			}
+			if backend.weight < 0 {
+				problems = append(problems, fmt.Sprintf("backend service \"%s\" has a negative weight", backend.service))
+			}
+			if backend.weight > 0 {
+				positive = true
+			}
+		}
+		if !positive {
+			problems = append(problems, "no backend has a positive weight")
+		}
+
+		if len(problems) > 0 {
+			failures = append(failures, fmt.Sprintf("%s/%s (%s)", split.namespace, split.name, strings.Join(problems, "; ")))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("Some TrafficSplits are invalid: %s", strings.Join(failures, ", "))
+	}
+
+	return nil
+}
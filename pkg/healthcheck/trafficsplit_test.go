@@ -0,0 +1,69 @@
+package healthcheck
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidateTrafficSplits(t *testing.T) {
+	split := func(name string, backends ...interface{}) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "books"},
+			"spec": map[string]interface{}{
+				"service":  "webapp",
+				"backends": backends,
+			},
+		}}
+	}
+	backend := func(service string, weight interface{}) map[string]interface{} {
+		return map[string]interface{}{"service": service, "weight": weight}
+	}
+
+	v1alpha1, err := parseTrafficSplit(split("canary-v1alpha1", backend("webapp-v1", "900m"), backend("webapp-v2", "100m")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	v1alpha2, err := parseTrafficSplit(split("canary-v1alpha2", backend("webapp-v1", int64(0)), backend("webapp-v3", int64(0))))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v1alpha1.backends[0].weight != 900 || v1alpha2.backends[1].service != "webapp-v3" {
+		t.Fatalf("Unexpected traffic splits: %+v, %+v", v1alpha1, v1alpha2)
+	}
+
+	services := []v1.Service{
+		v1.Service{ObjectMeta: meta.ObjectMeta{Name: "webapp", Namespace: "books"}},
+		v1.Service{ObjectMeta: meta.ObjectMeta{Name: "webapp-v1", Namespace: "books"}},
+		v1.Service{ObjectMeta: meta.ObjectMeta{Name: "webapp-v2", Namespace: "books"}},
+		v1.Service{ObjectMeta: meta.ObjectMeta{Name: "webapp-v3", Namespace: "emojivoto"}},
+	}
+
+	t.Run("Returns nil for valid TrafficSplits", func(t *testing.T) {
+		err := validateTrafficSplits([]trafficSplit{v1alpha1}, services)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns the specific problem per TrafficSplit", func(t *testing.T) {
+		err := validateTrafficSplits([]trafficSplit{v1alpha1, v1alpha2}, services)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "Some TrafficSplits are invalid: books/canary-v1alpha2 " +
+			"(backend service \"webapp-v3\" does not exist; no backend has a positive weight)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error for an invalid weight", func(t *testing.T) {
+		_, err := parseTrafficSplit(split("broken", backend("webapp-v1", "lots")))
+		if err == nil || err.Error() != "Invalid TrafficSplit \"books/broken\": invalid weight \"lots\"" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,268 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/linkerd/linkerd2/controller/api/public"
+	pb "github.com/linkerd/linkerd2/controller/gen/public"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	authorizationapi "k8s.io/api/authorization/v1beta1"
+	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	vizExtensionName = "viz"
+
+	vizConfigMapName       = "linkerd-viz-config"
+	vizPrometheusURLKey    = "prometheusUrl"
+	vizMetricsAPIService   = "metrics-api"
+	tapInjectorWebhookName = "linkerd-tap-injector-webhook-config"
+	tapInjectorTLSSecret   = "tap-injector-k8s-tls"
+
+	tapServiceAccountName = "tap"
+
+	extensionAPIServerAuthNamespace = "kube-system"
+	extensionAPIServerAuthConfigMap = "extension-apiserver-authentication"
+	extensionAPIServerAuthReader    = "extension-apiserver-authentication-reader"
+	requestHeaderClientCAKey        = "requestheader-client-ca-file"
+
+	tapAPIGroup      = "tap.linkerd.io"
+	tapAdminRoleName = "linkerd-linkerd-viz-tap-admin"
+	tapSubresource   = "tap"
+)
+
+// tapResources are the resources of the tap API that `linkerd tap` watches.
+var tapResources = []string{"namespaces", "pods", "deployments"}
+
+// vizCheckSuite returns the checks for the viz extension installed in the
+// given namespace.
+func vizCheckSuite(hc *HealthChecker, namespace string) []ExtensionCheck {
+	deploymentReady := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			deployments, err := hc.kubeAPI.GetDeployments(ctx, namespace, "")
+			if err != nil {
+				return err
+			}
+			return validateDeploymentReady(deployments, namespace, name)
+		}
+	}
+
+	return []ExtensionCheck{
+		{
+			Description: "tap deployment is ready",
+			Check:       deploymentReady("tap"),
+		},
+		{
+			Description: "tap-injector deployment is ready",
+			Check:       deploymentReady("tap-injector"),
+		},
+		{
+			Description: "metrics-api answers queries",
+			Check: func(ctx context.Context) error {
+				client, err := public.NewExternalClientForService(namespace, vizMetricsAPIService, hc.kubeAPI)
+				if err != nil {
+					return err
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+				_, err = client.Version(ctx, &pb.Empty{})
+				return err
+			},
+		},
+		{
+			Description: "tap-injector webhook caBundle is valid for its serving certificate",
+			Check: func(ctx context.Context) error {
+				webhookConfig, err := hc.kubeAPI.GetMutatingWebhookConfiguration(ctx, tapInjectorWebhookName)
+				if err != nil {
+					return err
+				}
+				if webhookConfig == nil || len(webhookConfig.Webhooks) == 0 {
+					return fmt.Errorf("The \"%s\" MutatingWebhookConfiguration does not exist", tapInjectorWebhookName)
+				}
+
+				servingCert, err := hc.kubeAPI.GetTLSCertFromSecret(ctx, namespace, tapInjectorTLSSecret)
+				if err != nil {
+					return err
+				}
+
+				return validateWebhookCABundle(webhookConfig.Webhooks[0].ClientConfig.CABundle, servingCert, time.Now())
+			},
+		},
+		{
+			Description: "tap can read the extension-apiserver-authentication ConfigMap",
+			Check: func(ctx context.Context) error {
+				if err := hc.initClientset(); err != nil {
+					return err
+				}
+				return validateTapAuthenticationAccess(hc.subjectAccessReviewer(ctx), namespace)
+			},
+		},
+		{
+			Description: "extension-apiserver-authentication ConfigMap has the requestheader client CA",
+			Check: func(ctx context.Context) error {
+				configMap, err := hc.kubeAPI.GetConfigMap(ctx, extensionAPIServerAuthNamespace, extensionAPIServerAuthConfigMap)
+				if err != nil {
+					return err
+				}
+				return validateExtensionAPIServerAuthentication(configMap)
+			},
+		},
+		{
+			Description: "current user can tap workloads",
+			Warning:     true,
+			Check: func(ctx context.Context) error {
+				if err := hc.initClientset(); err != nil {
+					return err
+				}
+				return validateTapAccess(hc.selfSubjectAccessReviewer(ctx))
+			},
+		},
+		{
+			Description: "prometheus deployment is ready",
+			Check: func(ctx context.Context) error {
+				configMap, err := hc.kubeAPI.GetConfigMap(ctx, namespace, vizConfigMapName)
+				if err != nil {
+					return err
+				}
+				if configMap != nil && configMap.Data[vizPrometheusURLKey] != "" {
+					return &SkipError{Reason: fmt.Sprintf("The viz extension uses an external Prometheus at %s", configMap.Data[vizPrometheusURLKey])}
+				}
+
+				return deploymentReady("prometheus")(ctx)
+			},
+		},
+	}
+}
+
+// validateDeploymentReady returns an error if the named Deployment is missing,
+// its rollout is stuck, or it has fewer ready replicas than desired.
+func validateDeploymentReady(deployments []appsV1.Deployment, namespace, name string) error {
+	for _, d := range deployments {
+		if d.Namespace != namespace || d.Name != name {
+			continue
+		}
+
+		if stuck, message := k8s.RolloutStuck(&d); stuck {
+			return fmt.Errorf("The \"%s\" Deployment's rollout is stuck: %s", name, message)
+		}
+
+		if d.Status.ReadyReplicas == 0 || d.Status.ReadyReplicas < d.Status.Replicas {
+			return fmt.Errorf("The \"%s\" Deployment has %d/%d ready replicas", name, d.Status.ReadyReplicas, d.Status.Replicas)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("The \"%s\" Deployment does not exist in the \"%s\" namespace", name, namespace)
+}
+
+// validateWebhookCABundle returns an error if the serving certificate does
+// not chain up to one of the certificates in the webhook's PEM-encoded
+// caBundle, as of now.
+func validateWebhookCABundle(caBundle []byte, servingCert *x509.Certificate, now time.Time) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		return fmt.Errorf("The webhook caBundle does not contain any valid certificates")
+	}
+
+	_, err := servingCert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("The webhook serving certificate is not valid for the caBundle: %s", err)
+	}
+
+	return nil
+}
+
+// validateTapAccess returns an error naming the tap API resources the current
+// user is not permitted to watch.
+func validateTapAccess(review selfAccessReviewer) error {
+	denied := []string{}
+	for _, resource := range tapResources {
+		sar := &authorizationapi.SelfSubjectAccessReview{
+			Spec: authorizationapi.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationapi.ResourceAttributes{
+					Verb:        "watch",
+					Group:       tapAPIGroup,
+					Resource:    resource,
+					Subresource: tapSubresource,
+				},
+			},
+		}
+
+		response, err := review(sar)
+		if err != nil {
+			return err
+		}
+		if !response.Status.Allowed {
+			denied = append(denied, fmt.Sprintf("%s/%s.%s", resource, tapSubresource, tapAPIGroup))
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("The current user is not permitted to watch %s, so `linkerd tap` will be forbidden; bind the \"%s\" ClusterRole to grant tap access",
+			strings.Join(denied, ", "), tapAdminRoleName)
+	}
+
+	return nil
+}
+
+// validateTapAuthenticationAccess returns an error if the tap ServiceAccount in
+// the given namespace may not read the ConfigMap it authenticates aggregated
+// API requests with. The check is skipped if the caller is not permitted to
+// review another subject's permissions.
+func validateTapAuthenticationAccess(review accessReviewer, namespace string) error {
+	sar := &authorizationapi.SubjectAccessReview{
+		Spec: authorizationapi.SubjectAccessReviewSpec{
+			User:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, tapServiceAccountName),
+			Groups: []string{"system:serviceaccounts", fmt.Sprintf("system:serviceaccounts:%s", namespace), "system:authenticated"},
+			ResourceAttributes: &authorizationapi.ResourceAttributes{
+				Namespace: extensionAPIServerAuthNamespace,
+				Verb:      "get",
+				Resource:  "configmaps",
+				Name:      extensionAPIServerAuthConfigMap,
+			},
+		},
+	}
+
+	response, err := review(sar)
+	if err != nil {
+		if kerrors.IsForbidden(err) {
+			return &SkipError{Reason: fmt.Sprintf("Not permitted to review the permissions of the \"%s\" ServiceAccount", tapServiceAccountName)}
+		}
+		return err
+	}
+
+	if !response.Status.Allowed {
+		return fmt.Errorf("The \"%s\" ServiceAccount cannot get the %s/%s ConfigMap; bind it to the \"%s\" Role in the \"%s\" namespace",
+			tapServiceAccountName, extensionAPIServerAuthNamespace, extensionAPIServerAuthConfigMap, extensionAPIServerAuthReader, extensionAPIServerAuthNamespace)
+	}
+
+	return nil
+}
+
+// validateExtensionAPIServerAuthentication returns an error if the
+// extension-apiserver-authentication ConfigMap is missing, or lacks the CA
+// used to verify the API server's requests to aggregated APIs.
+func validateExtensionAPIServerAuthentication(configMap *v1.ConfigMap) error {
+	if configMap == nil {
+		return fmt.Errorf("The %s/%s ConfigMap does not exist; the API server must be configured with --requestheader-client-ca-file to serve aggregated APIs",
+			extensionAPIServerAuthNamespace, extensionAPIServerAuthConfigMap)
+	}
+
+	if configMap.Data[requestHeaderClientCAKey] == "" {
+		return fmt.Errorf("The %s/%s ConfigMap does not contain the \"%s\" key; the API server must be configured with --requestheader-client-ca-file to serve aggregated APIs",
+			extensionAPIServerAuthNamespace, extensionAPIServerAuthConfigMap, requestHeaderClientCAKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package healthcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	authorizationapi "k8s.io/api/authorization/v1beta1"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateTapAccess(t *testing.T) {
+	reviewer := func(allowed map[string]bool) selfAccessReviewer {
+		return func(sar *authorizationapi.SelfSubjectAccessReview) (*authorizationapi.SelfSubjectAccessReview, error) {
+			attrs := sar.Spec.ResourceAttributes
+			sar.Status.Allowed = attrs.Verb == "watch" && attrs.Group == "tap.linkerd.io" && attrs.Subresource == "tap" && allowed[attrs.Resource]
+			return sar, nil
+		}
+	}
+
+	t.Run("Returns nil if the user can tap all resources", func(t *testing.T) {
+		err := validateTapAccess(reviewer(map[string]bool{"namespaces": true, "pods": true, "deployments": true}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error naming the missing permissions", func(t *testing.T) {
+		err := validateTapAccess(reviewer(map[string]bool{"pods": true}))
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The current user is not permitted to watch namespaces/tap.tap.linkerd.io, deployments/tap.tap.linkerd.io, so `linkerd tap` will be forbidden; bind the \"linkerd-linkerd-viz-tap-admin\" ClusterRole to grant tap access"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateTapAuthenticationAccess(t *testing.T) {
+	reviewer := func(allowed bool) accessReviewer {
+		return func(sar *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReview, error) {
+			attrs := sar.Spec.ResourceAttributes
+			sar.Status.Allowed = allowed && sar.Spec.User == "system:serviceaccount:linkerd-viz:tap" &&
+				attrs.Namespace == "kube-system" && attrs.Name == "extension-apiserver-authentication" && attrs.Verb == "get"
+			return sar, nil
+		}
+	}
+
+	t.Run("Returns nil if tap can read the ConfigMap", func(t *testing.T) {
+		err := validateTapAuthenticationAccess(reviewer(true), "linkerd-viz")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if tap cannot read the ConfigMap", func(t *testing.T) {
+		err := validateTapAuthenticationAccess(reviewer(false), "linkerd-viz")
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		expected := "The \"tap\" ServiceAccount cannot get the kube-system/extension-apiserver-authentication ConfigMap; bind it to the \"extension-apiserver-authentication-reader\" Role in the \"kube-system\" namespace"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateExtensionAPIServerAuthentication(t *testing.T) {
+	t.Run("Returns nil if the ConfigMap has the requestheader client CA", func(t *testing.T) {
+		configMap := &v1.ConfigMap{Data: map[string]string{"requestheader-client-ca-file": "-----BEGIN CERTIFICATE-----"}}
+		err := validateExtensionAPIServerAuthentication(configMap)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if the ConfigMap is missing", func(t *testing.T) {
+		err := validateExtensionAPIServerAuthentication(nil)
+		expected := "The kube-system/extension-apiserver-authentication ConfigMap does not exist; the API server must be configured with --requestheader-client-ca-file to serve aggregated APIs"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Returns an error if the ConfigMap lacks the requestheader client CA", func(t *testing.T) {
+		err := validateExtensionAPIServerAuthentication(&v1.ConfigMap{Data: map[string]string{"client-ca-file": "-----BEGIN CERTIFICATE-----"}})
+		expected := "The kube-system/extension-apiserver-authentication ConfigMap does not contain the \"requestheader-client-ca-file\" key; the API server must be configured with --requestheader-client-ca-file to serve aggregated APIs"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestVizChecks(t *testing.T) {
+	t.Run("Validates that deployments are ready", func(t *testing.T) {
+		deployments := []appsV1.Deployment{
+			appsV1.Deployment{
+				ObjectMeta: meta.ObjectMeta{Name: "tap", Namespace: "linkerd-viz"},
+				Status:     appsV1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1},
+			},
+			appsV1.Deployment{
+				ObjectMeta: meta.ObjectMeta{Name: "tap-injector", Namespace: "linkerd-viz"},
+				Status:     appsV1.DeploymentStatus{Replicas: 2, ReadyReplicas: 1},
+			},
+		}
+
+		if err := validateDeploymentReady(deployments, "linkerd-viz", "tap"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		err := validateDeploymentReady(deployments, "linkerd-viz", "tap-injector")
+		if err == nil || err.Error() != "The \"tap-injector\" Deployment has 1/2 ready replicas" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		err = validateDeploymentReady(deployments, "linkerd-viz", "prometheus")
+		if err == nil || err.Error() != "The \"prometheus\" Deployment does not exist in the \"linkerd-viz\" namespace" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Reports deployments whose rollout is stuck", func(t *testing.T) {
+		deployments := []appsV1.Deployment{
+			appsV1.Deployment{
+				ObjectMeta: meta.ObjectMeta{Name: "tap", Namespace: "linkerd-viz"},
+				Status: appsV1.DeploymentStatus{
+					Replicas:      2,
+					ReadyReplicas: 2,
+					Conditions: []appsV1.DeploymentCondition{{
+						Type:    appsV1.DeploymentProgressing,
+						Status:  v1.ConditionFalse,
+						Reason:  "ProgressDeadlineExceeded",
+						Message: "ReplicaSet \"tap-7d9c8c6f5b\" has timed out progressing.",
+					}},
+				},
+			},
+		}
+
+		err := validateDeploymentReady(deployments, "linkerd-viz", "tap")
+		if err == nil || err.Error() != "The \"tap\" Deployment's rollout is stuck: ReplicaSet \"tap-7d9c8c6f5b\" has timed out progressing." {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	now := time.Now()
+	issue := func(name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             now.Add(-time.Hour),
+			NotAfter:              now.Add(time.Hour),
+			IsCA:                  isCA,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+
+	ca, caKey, caPEM := issue("tap-injector-ca", nil, nil, true)
+	serving, _, _ := issue("tap-injector.linkerd-viz.svc", ca, caKey, false)
+	_, _, otherCAPEM := issue("other-ca", nil, nil, true)
+
+	t.Run("Returns nil if the serving certificate matches the caBundle", func(t *testing.T) {
+		err := validateWebhookCABundle(caPEM, serving, now)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns an error if the serving certificate does not match the caBundle", func(t *testing.T) {
+		err := validateWebhookCABundle(otherCAPEM, serving, now)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.HasPrefix(err.Error(), "The webhook serving certificate is not valid for the caBundle: ") {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("Returns an error if the caBundle is empty", func(t *testing.T) {
+		err := validateWebhookCABundle(nil, serving, now)
+		if err == nil || err.Error() != "The webhook caBundle does not contain any valid certificates" {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+}
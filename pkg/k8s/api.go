@@ -1,17 +1,31 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	arV1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsV1 "k8s.io/api/apps/v1"
+	authorizationV1beta1 "k8s.io/api/authorization/v1beta1"
 	"k8s.io/api/core/v1"
+	policyV1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
 	// Load all the auth plugins for the cloud providers.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -19,33 +33,178 @@ import (
 
 var minApiVersion = [3]int{1, 8, 0}
 
+// DefaultListPageSize is the number of items requested per page when listing
+// resources, unless the KubernetesAPI's ListPageSize is set.
+const DefaultListPageSize = 500
+
+// minDryRunVersion is the first Kubernetes version on which server-side
+// dry-run is enabled by default. Earlier API servers ignore the dryRun
+// parameter, and would persist the objects submitted with it.
+var minDryRunVersion = [3]int{1, 13, 0}
+
+// ErrDryRunUnsupported is returned by the dry-run methods when the API server
+// does not support server-side dry-run.
+var ErrDryRunUnsupported = errors.New("The Kubernetes API server does not support dry-run requests")
+
+// ErrAuthorizationAPIUnavailable is returned by CheckAccess when the API
+// server does not serve the authorization API.
+var ErrAuthorizationAPIUnavailable = errors.New("The Kubernetes API server does not serve the authorization.k8s.io API")
+
+// maxObjectEvents caps the Events returned by GetEventsFor.
+const maxObjectEvents = 20
+
+// DefaultRequestTimeout bounds each Kubernetes API request made without a
+// deadline, unless the KubernetesAPI's RequestTimeout is set.
+const DefaultRequestTimeout = 5 * time.Second
+
 type KubernetesAPI struct {
 	*rest.Config
+
+	// RequestTimeout bounds each request made without a deadline. Defaults to
+	// DefaultRequestTimeout.
+	RequestTimeout time.Duration
+
+	// MaxRequestAttempts is the number of times idempotent requests are
+	// attempted before giving up on transient errors. Defaults to
+	// DefaultMaxRequestAttempts.
+	MaxRequestAttempts int
+
+	// ListPageSize is the number of items requested per page when listing
+	// resources. Defaults to DefaultListPageSize.
+	ListPageSize int
+
+	// Recorder, if set, is invoked with each request sent by the API's
+	// clients, once per attempt.
+	Recorder RequestRecorder
+
+	// MinVersion is the oldest Kubernetes version accepted by CheckVersion.
+	// Defaults to the oldest version supported by Linkerd.
+	MinVersion [3]int
+
+	source string
+
+	// proxyURL is the proxy set by the kubeconfig's proxy-url, if any.
+	// Otherwise the proxy is taken from the environment.
+	proxyURL *url.URL
+
+	// client is shared by the API's methods, and built on first use
+	clientOnce sync.Once
+	client     *http.Client
+	clientErr  error
+
+	// cache keeps the responses to GET requests, if enabled
+	cache *responseCache
+
+	// discovery documents cached by ServesGroupVersion and ResourceExists
+	discoveryMu   sync.Mutex
+	groupVersions map[string]bool
+	resources     map[string]map[string]bool
+}
+
+// transport returns the transport for the configured cluster. The transports
+// built by client-go use the proxy from the environment; a transport using
+// the proxy-url is built when one is set.
+func (kubeAPI *KubernetesAPI) transport() (http.RoundTripper, error) {
+	if kubeAPI.proxyURL == nil {
+		return rest.TransportFor(kubeAPI.Config)
+	}
+
+	tlsConfig, err := rest.TLSConfigFor(kubeAPI.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := utilnet.SetTransportDefaults(&http.Transport{
+		Proxy:               http.ProxyURL(kubeAPI.proxyURL),
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	})
+	return rest.HTTPWrappersForConfig(kubeAPI.Config, transport)
 }
 
+// recordedTransport returns the transport for the configured cluster,
+// invoking the Recorder, if set, with each request sent.
+func (kubeAPI *KubernetesAPI) recordedTransport() (http.RoundTripper, error) {
+	transport, err := kubeAPI.transport()
+	if err != nil || kubeAPI.Recorder == nil {
+		return transport, err
+	}
+	return &recordingTransport{transport: transport, recorder: kubeAPI.Recorder}, nil
+}
+
+// proxyFor returns the proxy the request is sent through, if any.
+func (kubeAPI *KubernetesAPI) proxyFor(req *http.Request) *url.URL {
+	if kubeAPI.proxyURL != nil {
+		return kubeAPI.proxyURL
+	}
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return nil
+	}
+	return proxyURL
+}
+
+// requestContext returns a context bounded by the RequestTimeout, unless the
+// given context already has a deadline, which is then left unchanged.
+func (kubeAPI *KubernetesAPI) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+
+	timeout := kubeAPI.RequestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Source returns how the client configuration was obtained: KubeconfigSource
+// or InClusterConfigSource. It is empty for clients built from a serialized
+// kubeconfig.
+func (kubeAPI *KubernetesAPI) Source() string {
+	return kubeAPI.source
+}
+
+// NewClient returns a client for the configured cluster. Its requests are
+// limited to the rate set by the config's QPS and Burst, each attempt of a
+// retried request included; a negative QPS disables the limit. Requests
+// whose credentials are rejected are retried once with refreshed credentials.
+// Each call builds a transport of its own; the API's methods share the client
+// returned by Client instead.
 func (kubeAPI *KubernetesAPI) NewClient() (*http.Client, error) {
-	secureTransport, err := rest.TransportFor(kubeAPI.Config)
+	secureTransport, err := newCredentialRefreshTransport(kubeAPI.recordedTransport)
 	if err != nil {
 		return nil, fmt.Errorf("error instantiating Kubernetes API client: %v", err)
 	}
 
+	limited := newRateLimitTransport(secureTransport, kubeAPI.QPS, kubeAPI.Burst)
 	return &http.Client{
-		Transport: secureTransport,
+		Transport: newRetryTransport(limited, kubeAPI.MaxRequestAttempts),
 	}, nil
 }
 
-func (kubeAPI *KubernetesAPI) GetVersionInfo(client *http.Client) (*version.Info, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Client returns the client shared by the API's methods, built by NewClient
+// on their first request, so that they reuse its connections. Options that
+// must be set before NewClient must be set before this first request.
+func (kubeAPI *KubernetesAPI) Client() (*http.Client, error) {
+	kubeAPI.clientOnce.Do(func() {
+		kubeAPI.client, kubeAPI.clientErr = kubeAPI.NewClient()
+	})
+	return kubeAPI.client, kubeAPI.clientErr
+}
+
+func (kubeAPI *KubernetesAPI) GetVersionInfo(ctx context.Context) (*version.Info, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
 	defer cancel()
 
-	rsp, err := kubeAPI.getRequest(ctx, client, "/version")
+	rsp, err := kubeAPI.getRequest(ctx, "/version")
 	if err != nil {
 		return nil, err
 	}
 	defer rsp.Body.Close()
 
 	if rsp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+		return nil, unexpectedResponse(rsp)
 	}
 
 	bytes, err := ioutil.ReadAll(rsp.Body)
@@ -58,55 +217,296 @@ func (kubeAPI *KubernetesAPI) GetVersionInfo(client *http.Client) (*version.Info
 	return &versionInfo, err
 }
 
+// CheckVersion returns an error if the API server's version is older than the
+// API's MinVersion, or an UnknownVersionError if its version cannot be
+// determined.
 func (kubeAPI *KubernetesAPI) CheckVersion(versionInfo *version.Info) error {
-	apiVersion, err := getK8sVersion(versionInfo.String())
+	min := kubeAPI.MinVersion
+	if min == [3]int{} {
+		min = minApiVersion
+	}
+	return kubeAPI.CheckVersionAtLeast(versionInfo, min)
+}
+
+// CheckVersionAtLeast returns an error if the API server's version is older
+// than the given version, or an UnknownVersionError if its version cannot be
+// determined.
+func (kubeAPI *KubernetesAPI) CheckVersionAtLeast(versionInfo *version.Info, min [3]int) error {
+	apiVersion, err := getVersionInfoVersion(versionInfo)
 	if err != nil {
 		return err
 	}
 
-	if !isCompatibleVersion(minApiVersion, apiVersion) {
+	if !AtLeast(apiVersion, min) {
 		return fmt.Errorf("Kubernetes is on version [%d.%d.%d], but version [%d.%d.%d] or more recent is required",
 			apiVersion[0], apiVersion[1], apiVersion[2],
-			minApiVersion[0], minApiVersion[1], minApiVersion[2])
+			min[0], min[1], min[2])
 	}
 
 	return nil
 }
 
-func (kubeAPI *KubernetesAPI) NamespaceExists(client *http.Client, namespace string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// SupportsDryRun returns true if the API server's version enables server-side
+// dry-run by default.
+func (kubeAPI *KubernetesAPI) SupportsDryRun(versionInfo *version.Info) bool {
+	return IsVersionAtLeast(versionInfo, minDryRunVersion)
+}
+
+// CheckNamespaceExists returns a NamespaceNotFoundError if the namespace does
+// not exist, or a ForbiddenError if the current user may not get it.
+func (kubeAPI *KubernetesAPI) CheckNamespaceExists(ctx context.Context, namespace string) error {
+	exists, err := kubeAPI.NamespaceExists(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &NamespaceNotFoundError{Namespace: namespace}
+	}
+	return nil
+}
 
-	rsp, err := kubeAPI.getRequest(ctx, client, "/api/v1/namespaces/"+namespace)
+func (kubeAPI *KubernetesAPI) NamespaceExists(ctx context.Context, namespace string) (bool, error) {
+	ns, err := kubeAPI.GetNamespace(ctx, namespace)
 	if err != nil {
 		return false, err
 	}
+	return ns != nil, nil
+}
+
+// GetNamespace returns the named namespace, or nil if it does not exist. A
+// namespace being deleted is returned with its phase set to Terminating.
+func (kubeAPI *KubernetesAPI) GetNamespace(ctx context.Context, name string) (*v1.Namespace, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, ClusterResourcePath("namespaces", name))
+	if err != nil {
+		return nil, err
+	}
 	defer rsp.Body.Close()
 
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
-		return false, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
 	}
 
-	return rsp.StatusCode == http.StatusOK, nil
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var ns v1.Namespace
+	if err := json.Unmarshal(bytes, &ns); err != nil {
+		return nil, err
+	}
+	return &ns, nil
+}
+
+// PodsPath returns the API path of the pods in the given namespace, or in all
+// namespaces if it is empty.
+func PodsPath(namespace string) string {
+	return ResourcePath("v1", "pods", namespace, "")
 }
 
-// GetPodsByNamespace returns all pods in a given namespace
-func (kubeAPI *KubernetesAPI) GetPodsByNamespace(client *http.Client, namespace string) ([]v1.Pod, error) {
-	return kubeAPI.getPods(client, "/api/v1/namespaces/"+namespace+"/pods")
+// GetPodsByNamespace returns the pods in the given namespace, or in all
+// namespaces if it is empty, matching the label selector, if any.
+func (kubeAPI *KubernetesAPI) GetPodsByNamespace(ctx context.Context, namespace, labelSelector string) ([]v1.Pod, error) {
+	return kubeAPI.GetPods(ctx, namespace, labelSelector, "")
 }
 
-func (kubeAPI *KubernetesAPI) getPods(client *http.Client, path string) ([]v1.Pod, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// GetAllPods returns all pods in all namespaces
+func (kubeAPI *KubernetesAPI) GetAllPods(ctx context.Context) ([]v1.Pod, error) {
+	return kubeAPI.GetPods(ctx, "", "", "")
+}
+
+// GetPods returns the pods in the given namespace, or in all namespaces if it
+// is empty, matching the label and field selectors, either of which may be
+// empty. The pods are listed in pages, all of which are fetched.
+func (kubeAPI *KubernetesAPI) GetPods(ctx context.Context, namespace, labelSelector, fieldSelector string) ([]v1.Pod, error) {
+	path := PodsPath(namespace)
+	query := selectorQuery(labelSelector)
+	if fieldSelector != "" {
+		query.Set("fieldSelector", fieldSelector)
+	}
+
+	pods := []v1.Pod{}
+	err := kubeAPI.getPagedList(ctx, path, query, func() metav1.ListInterface {
+		return &v1.PodList{}
+	}, func(page metav1.ListInterface) {
+		pods = append(pods, page.(*v1.PodList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pods, nil
+}
+
+// ListNamespaces returns the namespaces matching the label selector, or all
+// namespaces if it is empty. The namespaces are listed in pages, all of which
+// are fetched.
+func (kubeAPI *KubernetesAPI) ListNamespaces(ctx context.Context, labelSelector string) ([]v1.Namespace, error) {
+	namespaces := []v1.Namespace{}
+	err := kubeAPI.getPagedList(ctx, ClusterResourcePath("namespaces", ""), selectorQuery(labelSelector), func() metav1.ListInterface {
+		return &v1.NamespaceList{}
+	}, func(page metav1.ListInterface) {
+		namespaces = append(namespaces, page.(*v1.NamespaceList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces, nil
+}
+
+func selectorQuery(labelSelector string) url.Values {
+	query := url.Values{}
+	if labelSelector != "" {
+		query.Set("labelSelector", labelSelector)
+	}
+	return query
+}
+
+func fieldSelectorQuery(fieldSelector string) url.Values {
+	query := url.Values{}
+	if fieldSelector != "" {
+		query.Set("fieldSelector", fieldSelector)
+	}
+	return query
+}
+
+// PathWithQuery returns the API path with the query parameters, if any,
+// encoded as its query string. Selectors and continue tokens must be passed
+// unencoded.
+func PathWithQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// getPagedList lists the resources at the given path page by page, following
+// the continue token of each page until the last one. Each page is decoded
+// into a list returned by newPage, and the pages are passed to collect once
+// all of them were fetched. If the continue token expires mid-list, the list
+// is restarted once from its first page. Every page is bounded by the
+// context's deadline, if any.
+func (kubeAPI *KubernetesAPI) getPagedList(ctx context.Context, path string, query url.Values, newPage func() metav1.ListInterface, collect func(page metav1.ListInterface)) error {
+	pageSize := kubeAPI.ListPageSize
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	query.Set("limit", strconv.Itoa(pageSize))
+	query.Del("continue")
+
+	pages := []metav1.ListInterface{}
+	restarted := false
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page := newPage()
+		err := kubeAPI.getListContext(ctx, PathWithQuery(path, query), page)
+		if isExpired(err) && query.Get("continue") != "" && !restarted {
+			restarted = true
+			pages = []metav1.ListInterface{}
+			query.Del("continue")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		pages = append(pages, page)
+		if page.GetContinue() == "" {
+			break
+		}
+		query.Set("continue", page.GetContinue())
+	}
+
+	for _, page := range pages {
+		collect(page)
+	}
+	return nil
+}
+
+// isExpired returns true if the API server rejected a list's continue token
+// because the resource version it refers to was compacted.
+func isExpired(err error) bool {
+	e, ok := err.(*StatusError)
+	return ok && e.StatusCode == http.StatusGone
+}
+
+// GetNamespaces returns all namespaces in the cluster
+func (kubeAPI *KubernetesAPI) GetNamespaces(ctx context.Context) ([]v1.Namespace, error) {
+	return kubeAPI.ListNamespaces(ctx, "")
+}
+
+// GetPodsBySelector returns the pods in the given namespace matching the label
+// selector.
+func (kubeAPI *KubernetesAPI) GetPodsBySelector(ctx context.Context, namespace, selector string) ([]v1.Pod, error) {
+	return kubeAPI.GetPods(ctx, namespace, selector, "")
+}
+
+// GetUnstructuredList returns the resources listed at the given API path, such
+// as "/apis/example.com/v1/widgets". This supports custom resources for which
+// no typed client is available. The path may include a query, such as a
+// label selector. It returns nil if the API server does not serve the path,
+// e.g. because the resource's CRD is not installed.
+func (kubeAPI *KubernetesAPI) GetUnstructuredList(ctx context.Context, path string) (*unstructured.UnstructuredList, error) {
+	endpoint, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list *unstructured.UnstructuredList
+	err = kubeAPI.getPagedList(ctx, endpoint.EscapedPath(), endpoint.Query(), func() metav1.ListInterface {
+		return &unstructured.UnstructuredList{}
+	}, func(page metav1.ListInterface) {
+		if list == nil {
+			list = page.(*unstructured.UnstructuredList)
+			return
+		}
+		list.Items = append(list.Items, page.(*unstructured.UnstructuredList).Items...)
+	})
+	if e, ok := err.(*StatusError); ok && e.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list.SetContinue("")
+	return list, nil
+}
+
+// GetClusterResourcesBySelector returns the cluster-scoped resources of the
+// given API group version and resource type, such as
+// "rbac.authorization.k8s.io/v1" and "clusterroles", matching the label
+// selector. It returns nil if the API server does not serve the resource type.
+func (kubeAPI *KubernetesAPI) GetClusterResourcesBySelector(ctx context.Context, groupVersion, resource, selector string) (*unstructured.UnstructuredList, error) {
+	return kubeAPI.GetUnstructuredList(ctx, PathWithQuery(ResourcePath(groupVersion, resource, "", ""), selectorQuery(selector)))
+}
+
+// GetConfigMap returns the named ConfigMap, or nil if it does not exist.
+func (kubeAPI *KubernetesAPI) GetConfigMap(ctx context.Context, namespace, name string) (*v1.ConfigMap, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
 	defer cancel()
 
-	rsp, err := kubeAPI.getRequest(ctx, client, path)
+	rsp, err := kubeAPI.getRequest(ctx, ResourcePath("v1", "configmaps", namespace, name))
 	if err != nil {
 		return nil, err
 	}
 	defer rsp.Body.Close()
 
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
 	if rsp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Unexpected Kubernetes API response: %s", rsp.Status)
+		return nil, unexpectedResponse(rsp)
 	}
 
 	bytes, err := ioutil.ReadAll(rsp.Body)
@@ -114,21 +514,446 @@ func (kubeAPI *KubernetesAPI) getPods(client *http.Client, path string) ([]v1.Po
 		return nil, err
 	}
 
-	var podList v1.PodList
-	err = json.Unmarshal(bytes, &podList)
+	var configMap v1.ConfigMap
+	err = json.Unmarshal(bytes, &configMap)
 	if err != nil {
 		return nil, err
 	}
 
-	return podList.Items, nil
+	return &configMap, nil
+}
+
+// GetSecret returns the named Secret, or nil if it does not exist.
+func (kubeAPI *KubernetesAPI) GetSecret(ctx context.Context, namespace, name string) (*v1.Secret, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, ResourcePath("v1", "secrets", namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret v1.Secret
+	err = json.Unmarshal(bytes, &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// GetEndpoints returns the named Endpoints, or nil if they do not exist.
+func (kubeAPI *KubernetesAPI) GetEndpoints(ctx context.Context, namespace, name string) (*v1.Endpoints, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, ResourcePath("v1", "endpoints", namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints v1.Endpoints
+	err = json.Unmarshal(bytes, &endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpoints, nil
+}
+
+// GetServices returns the Services in the given namespace, or in all
+// namespaces if namespace is empty, matching the label selector, if any. Use
+// PodsForService to find the pods a Service selects.
+func (kubeAPI *KubernetesAPI) GetServices(ctx context.Context, namespace, labelSelector string) ([]v1.Service, error) {
+	services := []v1.Service{}
+	err := kubeAPI.getPagedList(ctx, ResourcePath("v1", "services", namespace, ""), selectorQuery(labelSelector), func() metav1.ListInterface {
+		return &v1.ServiceList{}
+	}, func(page metav1.ListInterface) {
+		services = append(services, page.(*v1.ServiceList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// GetEndpointsBySelector returns the Endpoints in all namespaces matching the
+// label selector.
+func (kubeAPI *KubernetesAPI) GetEndpointsBySelector(ctx context.Context, selector string) ([]v1.Endpoints, error) {
+	endpoints := []v1.Endpoints{}
+	err := kubeAPI.getPagedList(ctx, ResourcePath("v1", "endpoints", "", ""), selectorQuery(selector), func() metav1.ListInterface {
+		return &v1.EndpointsList{}
+	}, func(page metav1.ListInterface) {
+		endpoints = append(endpoints, page.(*v1.EndpointsList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// GetMutatingWebhookConfiguration returns the named
+// MutatingWebhookConfiguration, or nil if it does not exist.
+func (kubeAPI *KubernetesAPI) GetMutatingWebhookConfiguration(ctx context.Context, name string) (*arV1beta1.MutatingWebhookConfiguration, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, ResourcePath("admissionregistration.k8s.io/v1beta1", "mutatingwebhookconfigurations", "", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var config arV1beta1.MutatingWebhookConfiguration
+	err = json.Unmarshal(bytes, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// GetDeployments returns the Deployments in the given namespace, or in all
+// namespaces if namespace is empty, matching the label selector, if any.
+func (kubeAPI *KubernetesAPI) GetDeployments(ctx context.Context, namespace, labelSelector string) ([]appsV1.Deployment, error) {
+	deployments := []appsV1.Deployment{}
+	err := kubeAPI.getPagedList(ctx, appsPath(namespace, "deployments"), selectorQuery(labelSelector), func() metav1.ListInterface {
+		return &appsV1.DeploymentList{}
+	}, func(page metav1.ListInterface) {
+		deployments = append(deployments, page.(*appsV1.DeploymentList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// GetDaemonSets returns the DaemonSets in the given namespace, or in all
+// namespaces if namespace is empty, matching the label selector, if any.
+func (kubeAPI *KubernetesAPI) GetDaemonSets(ctx context.Context, namespace, labelSelector string) ([]appsV1.DaemonSet, error) {
+	daemonSets := []appsV1.DaemonSet{}
+	err := kubeAPI.getPagedList(ctx, appsPath(namespace, "daemonsets"), selectorQuery(labelSelector), func() metav1.ListInterface {
+		return &appsV1.DaemonSetList{}
+	}, func(page metav1.ListInterface) {
+		daemonSets = append(daemonSets, page.(*appsV1.DaemonSetList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return daemonSets, nil
+}
+
+// GetStatefulSets returns the StatefulSets in the given namespace, or in all
+// namespaces if namespace is empty.
+func (kubeAPI *KubernetesAPI) GetStatefulSets(ctx context.Context, namespace string) ([]appsV1.StatefulSet, error) {
+	statefulSets := []appsV1.StatefulSet{}
+	err := kubeAPI.getPagedList(ctx, appsPath(namespace, "statefulsets"), url.Values{}, func() metav1.ListInterface {
+		return &appsV1.StatefulSetList{}
+	}, func(page metav1.ListInterface) {
+		statefulSets = append(statefulSets, page.(*appsV1.StatefulSetList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statefulSets, nil
+}
+
+// GetPodDisruptionBudgets returns the PodDisruptionBudgets in the given
+// namespace.
+func (kubeAPI *KubernetesAPI) GetPodDisruptionBudgets(ctx context.Context, namespace string) ([]policyV1beta1.PodDisruptionBudget, error) {
+	budgets := []policyV1beta1.PodDisruptionBudget{}
+	err := kubeAPI.getPagedList(ctx, ResourcePath("policy/v1beta1", "poddisruptionbudgets", namespace, ""), url.Values{}, func() metav1.ListInterface {
+		return &policyV1beta1.PodDisruptionBudgetList{}
+	}, func(page metav1.ListInterface) {
+		budgets = append(budgets, page.(*policyV1beta1.PodDisruptionBudgetList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// GetPersistentVolumeClaims returns the PersistentVolumeClaims in the given
+// namespace.
+func (kubeAPI *KubernetesAPI) GetPersistentVolumeClaims(ctx context.Context, namespace string) ([]v1.PersistentVolumeClaim, error) {
+	claims := []v1.PersistentVolumeClaim{}
+	err := kubeAPI.getPagedList(ctx, ResourcePath("v1", "persistentvolumeclaims", namespace, ""), url.Values{}, func() metav1.ListInterface {
+		return &v1.PersistentVolumeClaimList{}
+	}, func(page metav1.ListInterface) {
+		claims = append(claims, page.(*v1.PersistentVolumeClaimList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GetEventsFor returns the most recent Events recorded in the given namespace
+// about the object of the given kind and name, such as "PersistentVolumeClaim",
+// newest first. At most maxObjectEvents are returned.
+func (kubeAPI *KubernetesAPI) GetEventsFor(ctx context.Context, namespace, kind, name string) ([]v1.Event, error) {
+	selector := fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", kind, name)
+
+	events := []v1.Event{}
+	err := kubeAPI.getPagedList(ctx, ResourcePath("v1", "events", namespace, ""), fieldSelectorQuery(selector), func() metav1.ListInterface {
+		return &v1.EventList{}
+	}, func(page metav1.ListInterface) {
+		events = append(events, page.(*v1.EventList).Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[j]).Before(eventTime(events[i]))
+	})
+	if len(events) > maxObjectEvents {
+		events = events[:maxObjectEvents]
+	}
+	return events, nil
+}
+
+// eventTime returns the time the Event last occurred, falling back to the
+// fields set by older or newer event recorders.
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// CheckAccess asks the API server whether the current user, or the
+// impersonated one if configured, may perform the action described by the
+// given attributes. The reason given by the authorizer, and any error it
+// encountered evaluating the request, are returned alongside the decision.
+// ErrAuthorizationAPIUnavailable is returned if the API server does not serve
+// the authorization API.
+func (kubeAPI *KubernetesAPI) CheckAccess(ctx context.Context, attributes authorizationV1beta1.ResourceAttributes) (bool, string, error) {
+	review := authorizationV1beta1.SelfSubjectAccessReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "authorization.k8s.io/v1beta1",
+			Kind:       "SelfSubjectAccessReview",
+		},
+		Spec: authorizationV1beta1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+		},
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return false, "", err
+	}
+
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.postRequest(ctx, ResourcePath("authorization.k8s.io/v1beta1", "selfsubjectaccessreviews", "", ""), body)
+	if err != nil {
+		return false, "", err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return false, "", ErrAuthorizationAPIUnavailable
+	}
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusCreated {
+		return false, "", unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return false, "", err
+	}
+
+	var result authorizationV1beta1.SelfSubjectAccessReview
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return false, "", err
+	}
+
+	reason := result.Status.Reason
+	if result.Status.EvaluationError != "" {
+		if reason != "" {
+			reason += "; "
+		}
+		reason += "evaluation error: " + result.Status.EvaluationError
+	}
+
+	return result.Status.Allowed, reason, nil
+}
+
+// DryRunCreateDeployment submits the Deployment for creation with server-side
+// dry-run, and returns it as it would have been persisted, after admission
+// webhooks have mutated it. The request is never sent to API servers whose
+// version doesn't support dry-run, returning ErrDryRunUnsupported instead.
+func (kubeAPI *KubernetesAPI) DryRunCreateDeployment(ctx context.Context, versionInfo *version.Info, deployment *appsV1.Deployment) (*appsV1.Deployment, error) {
+	if !kubeAPI.SupportsDryRun(versionInfo) {
+		return nil, ErrDryRunUnsupported
+	}
+
+	body, err := json.Marshal(deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	// admission webhooks may take up to 30 seconds to respond
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	ctx, cancel = kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.postRequest(ctx, appsPath(deployment.Namespace, "deployments")+"?dryRun=All", body)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusBadRequest {
+		// the dry-run feature gate is disabled
+		return nil, ErrDryRunUnsupported
+	}
+	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusCreated {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var created appsV1.Deployment
+	err = json.Unmarshal(bytes, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func appsPath(namespace, resource string) string {
+	return ResourcePath("apps/v1", resource, namespace, "")
+}
+
+// getListContext decodes the page of a list at the given path, bounded by
+// the context's deadline, or by the RequestTimeout if it has none.
+func (kubeAPI *KubernetesAPI) getListContext(ctx context.Context, path string, list interface{}) error {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bytes, list)
+}
+
+// GetPodMetrics returns the raw Prometheus metrics served on the given port of
+// a pod, fetched through the Kubernetes API server's pod proxy.
+func (kubeAPI *KubernetesAPI) GetPodMetrics(ctx context.Context, namespace, pod string, port int32) ([]byte, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	path := ResourcePath("v1", "pods/proxy", namespace, fmt.Sprintf("%s:%d", pod, port)) + "/metrics"
+	rsp, err := kubeAPI.getRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	return ioutil.ReadAll(rsp.Body)
 }
 
 // UrlFor generates a URL based on the Kubernetes config.
 func (kubeAPI *KubernetesAPI) UrlFor(namespace string, extraPathStartingWithSlash string) (*url.URL, error) {
-	return generateKubernetesApiBaseUrlFor(kubeAPI.Host, namespace, extraPathStartingWithSlash)
+	return kubeAPI.UrlForWithQuery(namespace, extraPathStartingWithSlash, nil)
+}
+
+// UrlForWithQuery generates a URL based on the Kubernetes config, with the
+// query parameters, if any, encoded as its query string.
+func (kubeAPI *KubernetesAPI) UrlForWithQuery(namespace string, extraPathStartingWithSlash string, query url.Values) (*url.URL, error) {
+	apiURL, err := generateKubernetesApiBaseUrlFor(kubeAPI.Host, namespace, extraPathStartingWithSlash)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		apiURL.RawQuery = query.Encode()
+	}
+	return apiURL, nil
+}
+
+// UrlForGVR generates a URL based on the Kubernetes config for a resource of
+// the given API group version, such as "v1" or "apiextensions.k8s.io/v1beta1".
+// The URL addresses the named object, or the collection if name is empty, in
+// the given namespace, or cluster-wide if namespace is empty. The resource may
+// name a subresource, such as "pods/log", in which case a name is required.
+func (kubeAPI *KubernetesAPI) UrlForGVR(groupVersion, resource, namespace, name string) (*url.URL, error) {
+	return generateKubernetesApiUrlForResource(kubeAPI.Host, groupVersion, resource, namespace, name)
+}
+
+// UrlForClusterResource generates a URL based on the Kubernetes config for a
+// cluster-scoped core resource, such as "nodes" or "namespaces", or for the
+// named object if name is set.
+func (kubeAPI *KubernetesAPI) UrlForClusterResource(resource, name string) (*url.URL, error) {
+	return kubeAPI.UrlForGVR("v1", resource, "", name)
 }
 
-func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, client *http.Client, path string) (*http.Response, error) {
+func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, path string) (*http.Response, error) {
 	endpoint, err := url.Parse(kubeAPI.Host + path)
 	if err != nil {
 		return nil, err
@@ -139,16 +964,138 @@ func (kubeAPI *KubernetesAPI) getRequest(ctx context.Context, client *http.Clien
 		return nil, err
 	}
 
-	return client.Do(req.WithContext(ctx))
+	cache := kubeAPI.responseCacheFor(ctx, req)
+	if cache == nil {
+		return kubeAPI.do(ctx, req)
+	}
+	if rsp := cache.get(req); rsp != nil {
+		return rsp, nil
+	}
+
+	rsp, err := kubeAPI.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return cache.put(req, rsp)
+}
+
+func (kubeAPI *KubernetesAPI) postRequest(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	endpoint, err := url.Parse(kubeAPI.Host + path)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return kubeAPI.do(ctx, req)
+}
+
+func (kubeAPI *KubernetesAPI) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	client, err := kubeAPI.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if proxyURL := kubeAPI.proxyFor(req); proxyURL != nil {
+			return nil, fmt.Errorf("%s (via proxy %s://%s)", err, proxyURL.Scheme, proxyURL.Host)
+		}
+		return nil, err
+	}
+
+	if err := kubeAPI.impersonationError(rsp); err != nil {
+		rsp.Body.Close()
+		return nil, err
+	}
+
+	if rsp.StatusCode == http.StatusUnauthorized {
+		err := unexpectedResponse(rsp).(*UnauthorizedError)
+		err.CredentialSource = kubeAPI.credentialSource()
+		rsp.Body.Close()
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+// Impersonate configures the client to make every request as the given user
+// and groups. It must be called before NewClient and the API's first request.
+func (kubeAPI *KubernetesAPI) Impersonate(user string, groups []string) {
+	kubeAPI.Config.Impersonate = rest.ImpersonationConfig{
+		UserName: user,
+		Groups:   groups,
+	}
+}
+
+// impersonationError returns an error explaining that the current user may
+// not impersonate the configured identity, if the API server rejected the
+// request for that reason. The response body is preserved for the caller.
+func (kubeAPI *KubernetesAPI) impersonationError(rsp *http.Response) error {
+	if rsp.StatusCode != http.StatusForbidden {
+		return nil
+	}
+	impersonate := kubeAPI.Config.Impersonate
+	if impersonate.UserName == "" && len(impersonate.Groups) == 0 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return err
+	}
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var status metav1.Status
+	if err := json.Unmarshal(body, &status); err != nil || !strings.Contains(status.Message, "cannot impersonate") {
+		return nil
+	}
+
+	return fmt.Errorf("The current user is not permitted to impersonate user \"%s\" with groups [%s]; it needs the \"impersonate\" verb on users and groups: %s",
+		impersonate.UserName, strings.Join(impersonate.Groups, ", "), status.Message)
+}
+
+// NewAPIForKubeconfig returns a client for accessing the cluster described by
+// the given serialized kubeconfig, using its current context.
+func NewAPIForKubeconfig(kubeconfig []byte) (*KubernetesAPI, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
+	}
+
+	return &KubernetesAPI{Config: config}, nil
+}
+
+// NewAPIForCurrentContext returns a client for accessing the cluster selected
+// by the current context of the given Kubernetes config.
+func NewAPIForCurrentContext(configPath string) (*KubernetesAPI, error) {
+	return NewAPI(configPath, "")
 }
 
 // NewAPI validates a Kubernetes config and returns a client for accessing the
-// configured cluster
+// cluster selected by the given context, or by the config's current context
+// if kubeContext is empty. If configPath is empty and there is no kubeconfig
+// in the default locations, the in-cluster configuration is used. The
+// clients it makes are limited to DefaultQPS and DefaultBurst, unless the
+// config's QPS and Burst are set.
 func NewAPI(configPath, kubeContext string) (*KubernetesAPI, error) {
-	config, err := getConfig(configPath, kubeContext)
+	config, source, err := getConfig(configPath, kubeContext)
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
 	}
 
-	return &KubernetesAPI{Config: config}, nil
+	var proxyURL *url.URL
+	if source == KubeconfigSource {
+		proxyURL, err = getProxyURL(configPath, kubeContext)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
+		}
+	}
+
+	return &KubernetesAPI{Config: config, source: source, proxyURL: proxyURL}, nil
 }
@@ -1,8 +1,30 @@
 package k8s
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	authorizationV1beta1 "k8s.io/api/authorization/v1beta1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 )
 
 func TestKubernetesApiUrlFor(t *testing.T) {
@@ -24,3 +46,775 @@ func TestKubernetesApiUrlFor(t *testing.T) {
 		}
 	})
 }
+
+func TestPathWithQuery(t *testing.T) {
+	testCases := []struct {
+		labelSelector string
+		fieldSelector string
+	}{
+		{"app=web,version!=v2", ""},
+		{"environment in (production, qa),tier notin (frontend)", "status.phase!=Running,spec.nodeName=node-1"},
+		{"linkerd.io/control-plane-ns=linkerd", "metadata.name=a=b"},
+		{"!linkerd.io/proxy-deployment", "metadata.namespace!=kube-system"},
+	}
+
+	for _, tc := range testCases {
+		query := selectorQuery(tc.labelSelector)
+		for key, values := range fieldSelectorQuery(tc.fieldSelector) {
+			query[key] = values
+		}
+		query.Set("limit", "500")
+		query.Set("continue", "eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6+/=")
+		actual := PathWithQuery("/api/v1/pods", query)
+
+		// client-go's own encoding of the same list options
+		expected, err := scheme.ParameterCodec.EncodeParameters(&metav1.ListOptions{
+			LabelSelector: tc.labelSelector,
+			FieldSelector: tc.fieldSelector,
+			Limit:         500,
+			Continue:      "eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6+/=",
+		}, v1.SchemeGroupVersion)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if actual != "/api/v1/pods?"+expected.Encode() {
+			t.Fatalf("Expected [/api/v1/pods?%s], got [%s]", expected.Encode(), actual)
+		}
+
+		parsed, err := url.Parse(actual)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if parsed.Query().Get("labelSelector") != tc.labelSelector || parsed.Query().Get("fieldSelector") != tc.fieldSelector {
+			t.Fatalf("Selectors did not round-trip: %s", actual)
+		}
+	}
+
+	if path := PathWithQuery("/api/v1/pods", url.Values{}); path != "/api/v1/pods" {
+		t.Fatalf("Unexpected path without query: %s", path)
+	}
+}
+
+func TestKubernetesApiUrlForWithQuery(t *testing.T) {
+	api, err := NewAPI("testdata/config.test", "")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+	}
+
+	actualURL, err := api.UrlForWithQuery("emojivoto", "/pods", url.Values{"labelSelector": {"app in (web, voting)"}})
+	if err != nil {
+		t.Fatalf("Unexpected error generating URL: %+v", err)
+	}
+	expected := "https://55.197.171.239/api/v1/namespaces/emojivoto/pods?labelSelector=app+in+%28web%2C+voting%29"
+	if actualURL.String() != expected {
+		t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, actualURL.String())
+	}
+}
+
+func TestKubernetesApiUrlForGVR(t *testing.T) {
+	api, err := NewAPI("testdata/config.test", "")
+	if err != nil {
+		t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+	}
+
+	actualURL, err := api.UrlForGVR("apiregistration.k8s.io/v1", "apiservices", "", "v1alpha1.tap.linkerd.io")
+	if err != nil {
+		t.Fatalf("Unexpected error generating URL: %+v", err)
+	}
+	expected := "https://55.197.171.239/apis/apiregistration.k8s.io/v1/apiservices/v1alpha1.tap.linkerd.io"
+	if actualURL.String() != expected {
+		t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, actualURL.String())
+	}
+
+	actualURL, err = api.UrlForClusterResource("nodes", "node-1")
+	if err != nil {
+		t.Fatalf("Unexpected error generating URL: %+v", err)
+	}
+	expected = "https://55.197.171.239/api/v1/nodes/node-1"
+	if actualURL.String() != expected {
+		t.Fatalf("Expected generated URL to be [%s], but got [%s]", expected, actualURL.String())
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	versionInfo := &version.Info{Major: "1", Minor: "11", GitVersion: "v1.11.3"}
+
+	t.Run("Accepts versions at least as recent as the default minimum", func(t *testing.T) {
+		api := &KubernetesAPI{}
+		if err := api.CheckVersion(versionInfo); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Rejects versions older than the configured minimum", func(t *testing.T) {
+		api := &KubernetesAPI{MinVersion: [3]int{1, 12, 0}}
+		err := api.CheckVersion(versionInfo)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != "Kubernetes is on version [1.11.3], but version [1.12.0] or more recent is required" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Checks against the given minimum", func(t *testing.T) {
+		api := &KubernetesAPI{MinVersion: [3]int{1, 12, 0}}
+		if err := api.CheckVersionAtLeast(versionInfo, [3]int{1, 10, 0}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestGetPods(t *testing.T) {
+	pages := map[string]string{
+		"": "testdata/pods_page1.json",
+		"eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6MjA2Mywic3RhcnQiOiJlbW9qaS02ZjdiZDQ4Y2Q3LXR2dmxqXHUwMDAwIn0": "testdata/pods_page2.json",
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+
+		page, ok := pages[r.URL.Query().Get("continue")]
+		if !ok {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		body, err := ioutil.ReadFile(page)
+		if err != nil {
+			t.Errorf("Unexpected error reading %s: %s", page, err)
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	t.Run("Fetches every page of the list", func(t *testing.T) {
+		queries = nil
+		pods, err := api.GetPods(context.Background(), "emojivoto", "linkerd.io/control-plane-ns=linkerd", "status.phase!=Failed")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if len(pods) != 2 || pods[0].Name != "emoji-6f7bd48cd7-tvvlj" || pods[1].Name != "web-7c5f6b9c9d-9kbhx" {
+			t.Fatalf("Unexpected pods: %v", pods)
+		}
+		if pods[0].Spec.NodeName != "gke-cluster-default-pool-4b3c1d7a-0xqk" ||
+			pods[0].OwnerReferences[0].Name != "emoji-6f7bd48cd7" ||
+			pods[0].Status.ContainerStatuses[1].RestartCount != 1 ||
+			pods[1].Status.Phase != "Pending" {
+			t.Fatalf("Unexpected pod details: %+v", pods)
+		}
+
+		expected := []string{
+			"/api/v1/namespaces/emojivoto/pods?fieldSelector=status.phase%21%3DFailed&labelSelector=linkerd.io%2Fcontrol-plane-ns%3Dlinkerd&limit=500",
+			"/api/v1/namespaces/emojivoto/pods?continue=eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6MjA2Mywic3RhcnQiOiJlbW9qaS02ZjdiZDQ4Y2Q3LXR2dmxqXHUwMDAwIn0&fieldSelector=status.phase%21%3DFailed&labelSelector=linkerd.io%2Fcontrol-plane-ns%3Dlinkerd&limit=500",
+		}
+		if !reflect.DeepEqual(queries, expected) {
+			t.Fatalf("Unexpected requests: %v", queries)
+		}
+	})
+
+	t.Run("Lists pods in all namespaces if none is given", func(t *testing.T) {
+		queries = nil
+		if _, err := api.GetPodsByNamespace(context.Background(), "", ""); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if queries[0] != "/api/v1/pods?limit=500" {
+			t.Fatalf("Unexpected request: %s", queries[0])
+		}
+	})
+}
+
+func TestGetNamespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/namespaces/linkerd" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"kind":"Namespace","metadata":{"name":"linkerd","labels":{"linkerd.io/is-control-plane":"true"},` +
+			`"annotations":{"linkerd.io/inject":"disabled"}},"status":{"phase":"Terminating"}}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	ns, err := api.GetNamespace(context.Background(), "linkerd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ns.Labels["linkerd.io/is-control-plane"] != "true" || ns.Annotations["linkerd.io/inject"] != "disabled" || ns.Status.Phase != v1.NamespaceTerminating {
+		t.Fatalf("Unexpected namespace: %+v", ns)
+	}
+
+	ns, err = api.GetNamespace(context.Background(), "emojivoto")
+	if err != nil || ns != nil {
+		t.Fatalf("Expected no namespace, got %+v, %v", ns, err)
+	}
+	if err := api.CheckNamespaceExists(context.Background(), "emojivoto"); !IsNamespaceNotFound(err) {
+		t.Fatalf("Expected a NamespaceNotFoundError, got %v", err)
+	}
+	if err := api.CheckNamespaceExists(context.Background(), "linkerd"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func TestListNamespaces(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+
+		switch r.URL.Query().Get("continue") {
+		case "":
+			w.Write([]byte(`{"kind":"NamespaceList","metadata":{"continue":"next"},"items":[` +
+				`{"metadata":{"name":"linkerd-viz","labels":{"linkerd.io/extension":"viz"},"annotations":{"linkerd.io/inject":"enabled"}}}]}`))
+		case "next":
+			w.Write([]byte(`{"kind":"NamespaceList","metadata":{},"items":[` +
+				`{"metadata":{"name":"linkerd-jaeger","labels":{"linkerd.io/extension":"jaeger"}}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	t.Run("Lists the namespaces matching the selector across all pages", func(t *testing.T) {
+		namespaces, err := api.ListNamespaces(context.Background(), "linkerd.io/extension in (viz, jaeger)")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if len(namespaces) != 2 || namespaces[0].Name != "linkerd-viz" || namespaces[1].Name != "linkerd-jaeger" {
+			t.Fatalf("Unexpected namespaces: %v", namespaces)
+		}
+		if namespaces[0].Labels["linkerd.io/extension"] != "viz" || namespaces[0].Annotations["linkerd.io/inject"] != "enabled" {
+			t.Fatalf("Unexpected namespace metadata: %+v", namespaces[0].ObjectMeta)
+		}
+
+		expected := []string{
+			"/api/v1/namespaces?labelSelector=linkerd.io%2Fextension+in+%28viz%2C+jaeger%29&limit=500",
+			"/api/v1/namespaces?continue=next&labelSelector=linkerd.io%2Fextension+in+%28viz%2C+jaeger%29&limit=500",
+		}
+		if !reflect.DeepEqual(queries, expected) {
+			t.Fatalf("Unexpected requests: %v", queries)
+		}
+	})
+
+	t.Run("Returns a ForbiddenError if the user may not list namespaces", func(t *testing.T) {
+		forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer forbidden.Close()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: forbidden.URL}}
+
+		_, err := api.ListNamespaces(context.Background(), "")
+		if !IsForbidden(err) {
+			t.Fatalf("Expected a ForbiddenError, got %v", err)
+		}
+	})
+}
+
+func TestGetEventsFor(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+
+		items := []string{}
+		for i := 0; i < maxObjectEvents+5; i++ {
+			// the oldest events are listed first, and one only has an eventTime
+			field := fmt.Sprintf(`"lastTimestamp":"2019-03-01T10:%02d:00Z"`, i)
+			if i == 3 {
+				field = fmt.Sprintf(`"eventTime":"2019-03-01T10:%02d:00.000000Z"`, i)
+			}
+			items = append(items, fmt.Sprintf(`{"metadata":{"name":"e%d"},"reason":"FailedScheduling","count":%d,%s}`, i, i+1, field))
+		}
+		w.Write([]byte(`{"kind":"EventList","metadata":{},"items":[` + strings.Join(items, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	events, err := api.GetEventsFor(context.Background(), "linkerd", "Pod", "linkerd-controller-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if selector := query.Get("fieldSelector"); selector != "involvedObject.kind=Pod,involvedObject.name=linkerd-controller-1" {
+		t.Fatalf("Unexpected field selector: %s", selector)
+	}
+	if len(events) != maxObjectEvents {
+		t.Fatalf("Expected %d events, got %d", maxObjectEvents, len(events))
+	}
+	for i, event := range events {
+		if expected := fmt.Sprintf("e%d", maxObjectEvents+4-i); event.Name != expected {
+			t.Fatalf("Expected event %s at position %d, got %s", expected, i, event.Name)
+		}
+	}
+}
+
+func TestGetPagedList(t *testing.T) {
+	// serves the nodes two per page, the continue token naming the next node;
+	// expired tokens are rejected with 410 Gone
+	newAPI := func(nodes []string, expired map[string]int) (*KubernetesAPI, func() []string, func()) {
+		var queries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+
+			start := 0
+			if token := r.URL.Query().Get("continue"); token != "" {
+				if expired[token] > 0 {
+					expired[token]--
+					w.WriteHeader(http.StatusGone)
+					w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"The provided continue parameter is too old to display a consistent list result.","reason":"Expired","code":410}`))
+					return
+				}
+				start, _ = strconv.Atoi(token)
+			}
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+			list := v1.NodeList{}
+			for i := start; i < len(nodes) && i < start+limit; i++ {
+				list.Items = append(list.Items, v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodes[i]}})
+			}
+			if start+limit < len(nodes) {
+				list.Continue = strconv.Itoa(start + limit)
+			}
+			body, _ := json.Marshal(list)
+			w.Write(body)
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, ListPageSize: 2}
+		return api, func() []string { return queries }, server.Close
+	}
+
+	nodeNames := func(nodes []NodeInfo) []string {
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+		return names
+	}
+
+	t.Run("Requests pages of the configured size", func(t *testing.T) {
+		api, queries, done := newAPI([]string{"node-1", "node-2", "node-3", "node-4", "node-5"}, nil)
+		defer done()
+
+		nodes, err := api.GetNodes(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if names := nodeNames(nodes); !reflect.DeepEqual(names, []string{"node-1", "node-2", "node-3", "node-4", "node-5"}) {
+			t.Fatalf("Unexpected nodes: %v", names)
+		}
+		if expected := []string{"limit=2", "continue=2&limit=2", "continue=4&limit=2"}; !reflect.DeepEqual(queries(), expected) {
+			t.Fatalf("Unexpected requests: %v", queries())
+		}
+	})
+
+	t.Run("Restarts the list once when the continue token expires", func(t *testing.T) {
+		api, queries, done := newAPI([]string{"node-1", "node-2", "node-3"}, map[string]int{"2": 1})
+		defer done()
+
+		nodes, err := api.GetNodes(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if names := nodeNames(nodes); !reflect.DeepEqual(names, []string{"node-1", "node-2", "node-3"}) {
+			t.Fatalf("Expected the nodes of the restarted list only, got %v", names)
+		}
+		if expected := []string{"limit=2", "continue=2&limit=2", "limit=2", "continue=2&limit=2"}; !reflect.DeepEqual(queries(), expected) {
+			t.Fatalf("Unexpected requests: %v", queries())
+		}
+	})
+
+	t.Run("Returns the error if the continue token expires again", func(t *testing.T) {
+		api, _, done := newAPI([]string{"node-1", "node-2", "node-3"}, map[string]int{"2": 2})
+		defer done()
+
+		_, err := api.GetNodes(context.Background())
+		if status, ok := err.(*StatusError); !ok || status.StatusCode != http.StatusGone {
+			t.Fatalf("Expected a 410 StatusError, got %v", err)
+		}
+	})
+
+	t.Run("Stops once the context is done", func(t *testing.T) {
+		api, queries, done := newAPI([]string{"node-1", "node-2", "node-3"}, nil)
+		defer done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := api.getPagedList(ctx, ClusterResourcePath("nodes", ""), url.Values{}, func() metav1.ListInterface {
+			return &v1.NodeList{}
+		}, func(page metav1.ListInterface) {})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		cancel()
+		err = api.getPagedList(ctx, ClusterResourcePath("nodes", ""), url.Values{}, func() metav1.ListInterface {
+			return &v1.NodeList{}
+		}, func(page metav1.ListInterface) {
+			t.Fatal("Unexpected page after the context was canceled")
+		})
+		if err != context.Canceled {
+			t.Fatalf("Expected the context's error, got %v", err)
+		}
+		if len(queries()) != 2 {
+			t.Fatalf("Expected no request once the context was canceled, got %v", queries())
+		}
+	})
+}
+
+func TestCheckAccess(t *testing.T) {
+	attributes := authorizationV1beta1.ResourceAttributes{Namespace: "linkerd", Verb: "list", Resource: "pods"}
+
+	newAPI := func(handler http.HandlerFunc) (*KubernetesAPI, func()) {
+		server := httptest.NewServer(handler)
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		api.Impersonate("jane", nil)
+		return api, server.Close
+	}
+
+	t.Run("Returns the decision and reason of the review", func(t *testing.T) {
+		api, done := newAPI(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/apis/authorization.k8s.io/v1beta1/selfsubjectaccessreviews" {
+				t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			if r.Header.Get("Impersonate-User") != "jane" {
+				t.Errorf("Expected the request to impersonate jane, got %v", r.Header)
+			}
+
+			var review authorizationV1beta1.SelfSubjectAccessReview
+			if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+				t.Errorf("Unexpected error decoding review: %s", err)
+			}
+			if review.Spec.ResourceAttributes == nil || *review.Spec.ResourceAttributes != attributes {
+				t.Errorf("Unexpected resource attributes: %+v", review.Spec.ResourceAttributes)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"status":{"allowed":false,"reason":"no RBAC policy matched","evaluationError":"webhook timed out"}}`))
+		})
+		defer done()
+
+		allowed, reason, err := api.CheckAccess(context.Background(), attributes)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if allowed {
+			t.Fatal("Expected the access to be denied")
+		}
+		if reason != "no RBAC policy matched; evaluation error: webhook timed out" {
+			t.Fatalf("Unexpected reason: %s", reason)
+		}
+	})
+
+	t.Run("Returns ErrAuthorizationAPIUnavailable if the API is not served", func(t *testing.T) {
+		api, done := newAPI(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer done()
+
+		_, _, err := api.CheckAccess(context.Background(), attributes)
+		if err != ErrAuthorizationAPIUnavailable {
+			t.Fatalf("Expected ErrAuthorizationAPIUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestDryRunCreateDeployment(t *testing.T) {
+	t.Run("Does not send requests to API servers without dry-run support", func(t *testing.T) {
+		api, err := NewAPI("testdata/config.test", "")
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %+v", err)
+		}
+
+		// requests always fail, to detect whether any is sent
+		api.WrapTransport = func(http.RoundTripper) http.RoundTripper { return failingTransport{t} }
+
+		for _, v := range []string{"v1.11.3", "v1.12.1-gke.0", "unknown"} {
+			_, err := api.DryRunCreateDeployment(context.Background(), &version.Info{GitVersion: v}, &appsV1.Deployment{})
+			if err != ErrDryRunUnsupported {
+				t.Fatalf("Expected ErrDryRunUnsupported for version %s, got %v", v, err)
+			}
+		}
+	})
+}
+
+func TestImpersonation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Impersonate-User") != "jane" || r.Header.Get("Impersonate-Group") != "devs" {
+			t.Errorf("Unexpected impersonation headers: %v", r.Header)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"kind":"Status","status":"Failure","reason":"Forbidden","code":403,` +
+			`"message":"users \"jane\" is forbidden: User \"bob\" cannot impersonate users at the cluster scope"}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+	api.Impersonate("jane", []string{"devs"})
+
+	_, err := api.GetVersionInfo(context.Background())
+	if err == nil {
+		t.Fatal("Expected error, got nothing")
+	}
+	expected := "The current user is not permitted to impersonate user \"jane\" with groups [devs]; it needs the \"impersonate\" verb on users and groups: " +
+		"users \"jane\" is forbidden: User \"bob\" cannot impersonate users at the cluster scope"
+	if err.Error() != expected {
+		t.Fatalf("Unexpected error message: %s", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"gitVersion":"v1.11.3"}`))
+	}))
+	defer server.Close()
+
+	t.Run("Fails requests that take longer than the RequestTimeout", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, RequestTimeout: 50 * time.Millisecond}
+
+		_, err := api.GetVersionInfo(context.Background())
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.Contains(err.Error(), "context deadline exceeded") {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Succeeds within the RequestTimeout", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, RequestTimeout: 2 * time.Second}
+
+		versionInfo, err := api.GetVersionInfo(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if versionInfo.GitVersion != "v1.11.3" {
+			t.Fatalf("Unexpected version: %s", versionInfo.GitVersion)
+		}
+	})
+
+	t.Run("Prefers the deadline of the caller's context", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, RequestTimeout: 50 * time.Millisecond}
+
+		parent, cancelParent := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancelParent()
+		ctx, cancel := api.requestContext(parent)
+		defer cancel()
+
+		rsp, err := api.getRequest(ctx, "/version")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		rsp.Body.Close()
+	})
+
+	t.Run("Defaults to the DefaultRequestTimeout", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		ctx, cancel := api.requestContext(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("Expected a deadline, got none")
+		}
+		if remaining := time.Until(deadline); remaining > DefaultRequestTimeout || remaining < DefaultRequestTimeout-time.Second {
+			t.Fatalf("Unexpected deadline in %s", remaining)
+		}
+	})
+}
+
+func TestClient(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"gitVersion":"v1.11.3"}`))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{Insecure: true}}}
+
+	t.Run("Reuses the connection across requests", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if _, err := api.GetVersionInfo(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if connections != 1 {
+			t.Fatalf("Expected a single TLS connection, got %d", connections)
+		}
+	})
+
+	t.Run("Returns the same client on each call", func(t *testing.T) {
+		first, err := api.Client()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		second, err := api.Client()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if first != second {
+			t.Fatal("Expected the same client to be returned")
+		}
+
+		raw, err := api.NewClient()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if raw == first {
+			t.Fatal("Expected NewClient to return a client of its own")
+		}
+	})
+}
+
+func TestRequestCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, RequestTimeout: 10 * time.Second}
+
+	requests := map[string]func(ctx context.Context) error{
+		"GetVersionInfo": func(ctx context.Context) error {
+			_, err := api.GetVersionInfo(ctx)
+			return err
+		},
+		"GetNamespaces": func(ctx context.Context) error {
+			_, err := api.GetNamespaces(ctx)
+			return err
+		},
+		"GetPodsByNamespace": func(ctx context.Context) error {
+			_, err := api.GetPodsByNamespace(ctx, "linkerd", "")
+			return err
+		},
+	}
+
+	for name, request := range requests {
+		request := request
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := request(ctx)
+			if err == nil {
+				t.Fatal("Expected error, got nothing")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("Expected the request to be aborted once the context was canceled, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(`{"gitVersion":"v1.11.3"}`))
+	}))
+	defer proxy.Close()
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: proxied
+  cluster:
+    server: http://kubernetes.example.com
+    proxy-url: %s
+contexts:
+- name: proxied
+  context:
+    cluster: proxied
+    user: proxied
+current-context: proxied
+users:
+- name: proxied
+  user:
+    token: secret
+`, proxy.URL)
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	t.Run("Sends requests through the kubeconfig's proxy-url", func(t *testing.T) {
+		api, err := NewAPI(path, "")
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %s", err)
+		}
+
+		versionInfo, err := api.GetVersionInfo(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if versionInfo.GitVersion != "v1.11.3" {
+			t.Fatalf("Unexpected version: %s", versionInfo.GitVersion)
+		}
+		if len(proxied) != 1 || proxied[0] != "http://kubernetes.example.com/version" {
+			t.Fatalf("Expected the request to traverse the proxy, got %v", proxied)
+		}
+	})
+
+	t.Run("Names the proxy when the connection fails", func(t *testing.T) {
+		api, err := NewAPI(path, "")
+		if err != nil {
+			t.Fatalf("Unexpected error creating Kubernetes API: %s", err)
+		}
+		api.proxyURL = &url.URL{Scheme: "http", Host: "127.0.0.1:1"}
+		api.MaxRequestAttempts = 1
+
+		_, err = api.GetVersionInfo(context.Background())
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.Contains(err.Error(), "(via proxy http://127.0.0.1:1)") {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Has no proxy-url when the kubeconfig sets none", func(t *testing.T) {
+		proxyURL, err := getProxyURL("testdata/config.test", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if proxyURL != nil {
+			t.Fatalf("Unexpected proxy-url: %s", proxyURL)
+		}
+	})
+}
+
+type failingTransport struct {
+	t *testing.T
+}
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Fatalf("Unexpected request: %s %s", req.Method, req.URL)
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+package k8s
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// credentialRefreshTransport retries a request once when the API server
+// rejects its credentials, after rebuilding the underlying transport so that
+// the kubeconfig's auth provider or exec plugin obtains new credentials, e.g.
+// once a token has expired during a long run. Concurrent requests rejected
+// with the same credentials share a single rebuild.
+type credentialRefreshTransport struct {
+	build func() (http.RoundTripper, error)
+
+	mu         sync.Mutex
+	transport  http.RoundTripper
+	generation int
+}
+
+func newCredentialRefreshTransport(build func() (http.RoundTripper, error)) (*credentialRefreshTransport, error) {
+	transport, err := build()
+	if err != nil {
+		return nil, err
+	}
+	return &credentialRefreshTransport{build: build, transport: transport}, nil
+}
+
+func (rt *credentialRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, generation := rt.current()
+
+	rsp, err := transport.RoundTrip(req)
+	if err != nil || rsp.StatusCode != http.StatusUnauthorized || !replayable(req) {
+		return rsp, err
+	}
+
+	retry := req
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return rsp, nil
+		}
+		retry = cloneRequest(req)
+		retry.Body = body
+	}
+
+	transport, err = rt.refresh(generation)
+	if err != nil {
+		return rsp, nil
+	}
+	io.Copy(ioutil.Discard, rsp.Body)
+	rsp.Body.Close()
+
+	return transport.RoundTrip(retry)
+}
+
+func (rt *credentialRefreshTransport) current() (http.RoundTripper, int) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.transport, rt.generation
+}
+
+// refresh rebuilds the transport, unless it was already rebuilt since the
+// given generation was current, and returns the new transport.
+func (rt *credentialRefreshTransport) refresh(generation int) (http.RoundTripper, error) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if rt.generation == generation {
+		transport, err := rt.build()
+		if err != nil {
+			return nil, err
+		}
+		rt.transport = transport
+		rt.generation++
+	}
+	return rt.transport, nil
+}
+
+// replayable returns true if the request can be sent again, i.e. it has no
+// body, or one that can be read again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		clone.Header[k] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+// credentialSource describes where the configured credentials come from,
+// e.g. `the "aws-iam-authenticator" exec plugin`.
+func (kubeAPI *KubernetesAPI) credentialSource() string {
+	config := kubeAPI.Config
+	switch {
+	case config.ExecProvider != nil:
+		return fmt.Sprintf("the \"%s\" exec plugin", config.ExecProvider.Command)
+	case config.AuthProvider != nil:
+		return fmt.Sprintf("the \"%s\" auth provider", config.AuthProvider.Name)
+	case config.BearerToken != "":
+		return "the configured bearer token"
+	case len(config.CertData) > 0 || config.CertFile != "":
+		return "the configured client certificate"
+	case config.Username != "":
+		return "the configured username and password"
+	default:
+		return "no configured credentials"
+	}
+}
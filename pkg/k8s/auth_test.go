@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// tokenTransport authenticates requests with the given token.
+type tokenTransport string
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCredentialRefreshTransport(t *testing.T) {
+	// the server only accepts the token obtained by the second build
+	newServer := func(requests *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(requests, 1)
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(body)
+		}))
+	}
+	newTransport := func(builds *int32) *credentialRefreshTransport {
+		rt, err := newCredentialRefreshTransport(func() (http.RoundTripper, error) {
+			return tokenTransport(fmt.Sprintf("token-%d", atomic.AddInt32(builds, 1))), nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return rt
+	}
+
+	t.Run("Retries once with refreshed credentials", func(t *testing.T) {
+		var requests, builds int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := &http.Client{Transport: newTransport(&builds)}
+		rsp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{"kind":"SelfSubjectAccessReview"}`)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer rsp.Body.Close()
+
+		body, _ := ioutil.ReadAll(rsp.Body)
+		if rsp.StatusCode != http.StatusOK || string(body) != `{"kind":"SelfSubjectAccessReview"}` {
+			t.Fatalf("Expected the request to be replayed, got %d %s", rsp.StatusCode, body)
+		}
+		if requests != 2 || builds != 2 {
+			t.Fatalf("Expected 2 requests and 2 builds, got %d and %d", requests, builds)
+		}
+	})
+
+	t.Run("Rebuilds the transport once for concurrent requests", func(t *testing.T) {
+		var requests, builds int32
+		server := newServer(&requests)
+		defer server.Close()
+
+		client := &http.Client{Transport: newTransport(&builds)}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rsp, err := client.Get(server.URL)
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+					return
+				}
+				rsp.Body.Close()
+				if rsp.StatusCode != http.StatusOK {
+					t.Errorf("Expected status 200, got %d", rsp.StatusCode)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if builds != 2 {
+			t.Fatalf("Expected the transport to be rebuilt once, got %d builds", builds)
+		}
+	})
+
+	t.Run("Returns an UnauthorizedError naming the credential source", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"Unauthorized","reason":"Unauthorized","code":401}`))
+		}))
+		defer server.Close()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, BearerToken: "expired"}}
+
+		_, err := api.GetVersionInfo(context.Background())
+		if !IsUnauthorized(err) {
+			t.Fatalf("Expected an UnauthorizedError, got %v", err)
+		}
+		expected := "Unexpected Kubernetes API response: 401 Unauthorized: Unauthorized (the Kubernetes API server rejected the credentials from the configured bearer token)"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+		if requests != 2 {
+			t.Fatalf("Expected the request to be retried once, got %d requests", requests)
+		}
+	})
+}
+
+func TestCredentialSource(t *testing.T) {
+	testCases := []struct {
+		config   *rest.Config
+		expected string
+	}{
+		{&rest.Config{}, "no configured credentials"},
+		{&rest.Config{ExecProvider: &clientcmdapi.ExecConfig{Command: "aws-iam-authenticator"}}, `the "aws-iam-authenticator" exec plugin`},
+		{&rest.Config{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "gcp"}}, `the "gcp" auth provider`},
+		{&rest.Config{TLSClientConfig: rest.TLSClientConfig{CertFile: "client.crt"}}, "the configured client certificate"},
+	}
+
+	for _, tc := range testCases {
+		api := &KubernetesAPI{Config: tc.config}
+		if source := api.credentialSource(); source != tc.expected {
+			t.Errorf("Expected credential source [%s], got [%s]", tc.expected, source)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// DefaultResponseCacheBytes bounds the size of the bodies kept by the
+// response cache, unless another bound is given to EnableResponseCache.
+const DefaultResponseCacheBytes = 16 << 20
+
+type noResponseCacheKey struct{}
+
+// WithoutResponseCache returns a context whose GET requests always reach the
+// API server, for callers that need fresh data while the response cache is
+// enabled.
+func WithoutResponseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noResponseCacheKey{}, true)
+}
+
+// responseCache keeps the successful responses to GET requests, keyed by
+// their URL, so that repeated fetches of the same objects are served from
+// memory. The oldest responses are evicted once the bodies kept exceed
+// maxBytes.
+type responseCache struct {
+	maxBytes int
+
+	mu        sync.Mutex
+	responses map[string]*cachedResponse
+	order     []string
+	size      int
+}
+
+type cachedResponse struct {
+	status string
+	header http.Header
+	body   []byte
+}
+
+// EnableResponseCache makes the API keep the successful responses to its GET
+// requests, other than watches, until ResetResponseCache is called, so that
+// identical requests are only sent once. maxBytes bounds the size of the
+// bodies kept, and defaults to DefaultResponseCacheBytes. It must be called
+// before any request is made.
+func (kubeAPI *KubernetesAPI) EnableResponseCache(maxBytes int) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultResponseCacheBytes
+	}
+	kubeAPI.cache = &responseCache{maxBytes: maxBytes, responses: make(map[string]*cachedResponse)}
+}
+
+// ResetResponseCache drops the responses kept by the response cache, if it is
+// enabled, so that the next requests are sent to the API server.
+func (kubeAPI *KubernetesAPI) ResetResponseCache() {
+	if kubeAPI.cache == nil {
+		return
+	}
+
+	kubeAPI.cache.mu.Lock()
+	defer kubeAPI.cache.mu.Unlock()
+
+	kubeAPI.cache.responses = make(map[string]*cachedResponse)
+	kubeAPI.cache.order = nil
+	kubeAPI.cache.size = 0
+}
+
+// responseCacheFor returns the response cache to use for the request, or nil
+// if it is disabled, bypassed by the request's context, or the request is a
+// watch.
+func (kubeAPI *KubernetesAPI) responseCacheFor(ctx context.Context, req *http.Request) *responseCache {
+	if kubeAPI.cache == nil || req.Method != http.MethodGet {
+		return nil
+	}
+	if bypass, _ := ctx.Value(noResponseCacheKey{}).(bool); bypass {
+		return nil
+	}
+	if req.URL.Query().Get("watch") == "true" {
+		return nil
+	}
+	return kubeAPI.cache
+}
+
+// get returns the response kept for the request, if any.
+func (c *responseCache) get(req *http.Request) *http.Response {
+	c.mu.Lock()
+	cached, ok := c.responses[req.URL.String()]
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return cached.response(req)
+}
+
+// put keeps the response if it is successful, and returns a response to use
+// in its place, as its body is consumed.
+func (c *responseCache) put(req *http.Request, rsp *http.Response) (*http.Response, error) {
+	if rsp.StatusCode != http.StatusOK {
+		return rsp, nil
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedResponse{status: rsp.Status, header: rsp.Header, body: body}
+
+	if len(body) <= c.maxBytes {
+		c.mu.Lock()
+		key := req.URL.String()
+		if _, ok := c.responses[key]; !ok {
+			c.responses[key] = cached
+			c.order = append(c.order, key)
+			c.size += len(body)
+			for c.size > c.maxBytes {
+				oldest := c.order[0]
+				c.order = c.order[1:]
+				c.size -= len(c.responses[oldest].body)
+				delete(c.responses, oldest)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	return cached.response(req), nil
+}
+
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        r.status,
+		StatusCode:    http.StatusOK,
+		Header:        r.header,
+		Body:          ioutil.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestResponseCache(t *testing.T) {
+	newAPI := func(maxBytes int) (*KubernetesAPI, *int32, func()) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if strings.HasSuffix(r.URL.Path, "/missing") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"` + r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:] + `"}}`))
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		if maxBytes >= 0 {
+			api.EnableResponseCache(maxBytes)
+		}
+		return api, &requests, server.Close
+	}
+
+	getConfigMap := func(t *testing.T, api *KubernetesAPI, name string) {
+		configMap, err := api.GetConfigMap(context.Background(), "linkerd", name)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if name != "missing" && configMap.Name != name {
+			t.Fatalf("Expected the \"%s\" ConfigMap, got %+v", name, configMap)
+		}
+	}
+
+	t.Run("Serves repeated requests from memory", func(t *testing.T) {
+		api, requests, done := newAPI(0)
+		defer done()
+
+		getConfigMap(t, api, "linkerd-config")
+		getConfigMap(t, api, "linkerd-config")
+		getConfigMap(t, api, "linkerd-identity")
+		if *requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", *requests)
+		}
+
+		api.ResetResponseCache()
+		getConfigMap(t, api, "linkerd-config")
+		if *requests != 3 {
+			t.Fatalf("Expected the request to be sent again once reset, got %d requests", *requests)
+		}
+	})
+
+	t.Run("Sends every request when disabled", func(t *testing.T) {
+		api, requests, done := newAPI(-1)
+		defer done()
+
+		getConfigMap(t, api, "linkerd-config")
+		getConfigMap(t, api, "linkerd-config")
+		api.ResetResponseCache()
+		if *requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("Doesn't keep unsuccessful responses", func(t *testing.T) {
+		api, requests, done := newAPI(0)
+		defer done()
+
+		getConfigMap(t, api, "missing")
+		getConfigMap(t, api, "missing")
+		if *requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("Is bypassed by the request's context", func(t *testing.T) {
+		api, requests, done := newAPI(0)
+		defer done()
+
+		path := ResourcePath("v1", "configmaps", "linkerd", "linkerd-config")
+		for _, ctx := range []context.Context{context.Background(), WithoutResponseCache(context.Background())} {
+			rsp, err := api.getRequest(ctx, path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			rsp.Body.Close()
+		}
+		if *requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("Evicts the oldest responses beyond its size", func(t *testing.T) {
+		// each body is about 70 bytes, so only one is kept
+		api, requests, done := newAPI(100)
+		defer done()
+
+		getConfigMap(t, api, "linkerd-config")
+		getConfigMap(t, api, "linkerd-identity")
+		getConfigMap(t, api, "linkerd-identity")
+		if *requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", *requests)
+		}
+
+		getConfigMap(t, api, "linkerd-config")
+		if *requests != 3 {
+			t.Fatalf("Expected the evicted response to be fetched again, got %d requests", *requests)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"fmt"
+
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// RESTConfig returns a deep copy of the API's configuration, so that changes
+// made by the caller are not seen by the API or its clients.
+func (kubeAPI *KubernetesAPI) RESTConfig() *rest.Config {
+	config := rest.CopyConfig(kubeAPI.Config)
+
+	config.TLSClientConfig.CertData = copyBytes(config.TLSClientConfig.CertData)
+	config.TLSClientConfig.KeyData = copyBytes(config.TLSClientConfig.KeyData)
+	config.TLSClientConfig.CAData = copyBytes(config.TLSClientConfig.CAData)
+	if groups := config.Impersonate.Groups; groups != nil {
+		config.Impersonate.Groups = append([]string{}, groups...)
+	}
+	if extra := config.Impersonate.Extra; extra != nil {
+		config.Impersonate.Extra = make(map[string][]string, len(extra))
+		for key, values := range extra {
+			config.Impersonate.Extra[key] = append([]string{}, values...)
+		}
+	}
+	if config.AuthProvider != nil {
+		config.AuthProvider = config.AuthProvider.DeepCopy()
+	}
+	if config.ExecProvider != nil {
+		config.ExecProvider = config.ExecProvider.DeepCopy()
+	}
+
+	return config
+}
+
+// NewClientset returns a clientset sending its requests through the transport
+// of the client returned by Client, so that it shares its connections,
+// credentials, proxy, TLS settings, rate limit and retries.
+func (kubeAPI *KubernetesAPI) NewClientset() (kubernetes.Interface, error) {
+	config, err := kubeAPI.clientsetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error instantiating Kubernetes API clientset: %v", err)
+	}
+	return clientset, nil
+}
+
+// NewServiceProfileClientset returns a ServiceProfile clientset sending its
+// requests through the same transport as the clientset returned by
+// NewClientset.
+func (kubeAPI *KubernetesAPI) NewServiceProfileClientset() (spclient.Interface, error) {
+	config, err := kubeAPI.clientsetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := spclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error instantiating ServiceProfile clientset: %v", err)
+	}
+	return clientset, nil
+}
+
+// clientsetConfig returns the configuration of the clientsets, whose requests
+// are sent through the transport of the client returned by Client.
+func (kubeAPI *KubernetesAPI) clientsetConfig() (*rest.Config, error) {
+	client, err := kubeAPI.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return &rest.Config{
+		Host:          kubeAPI.Host,
+		APIPath:       kubeAPI.APIPath,
+		ContentConfig: kubeAPI.ContentConfig,
+		UserAgent:     kubeAPI.UserAgent,
+		Timeout:       kubeAPI.Timeout,
+		// the transport authenticates, impersonates and rate limits the
+		// requests itself
+		Transport:   client.Transport,
+		RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter(),
+	}, nil
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRESTConfig(t *testing.T) {
+	api := &KubernetesAPI{Config: &rest.Config{
+		Host:            "https://kubernetes.example.com",
+		TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")},
+		Impersonate:     rest.ImpersonationConfig{UserName: "jane", Groups: []string{"admins"}},
+		AuthProvider:    &clientcmdapi.AuthProviderConfig{Name: "gcp", Config: map[string]string{"access-token": "secret"}},
+	}}
+
+	config := api.RESTConfig()
+	config.Host = "https://other.example.com"
+	config.TLSClientConfig.CAData[0] = 'x'
+	config.Impersonate.Groups[0] = "viewers"
+	config.AuthProvider.Config["access-token"] = "changed"
+
+	if api.Host != "https://kubernetes.example.com" ||
+		string(api.TLSClientConfig.CAData) != "ca" ||
+		api.Config.Impersonate.Groups[0] != "admins" ||
+		api.AuthProvider.Config["access-token"] != "secret" {
+		t.Fatalf("Expected the API's configuration to be unchanged, got %+v", api.Config)
+	}
+}
+
+func TestNewClientset(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"linkerd"}}`))
+	}))
+	defer server.Close()
+
+	recorder := &testRecorder{}
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, BearerToken: "secret"}, Recorder: recorder}
+	clientset, err := api.NewClientset()
+	if err != nil {
+		t.Fatalf("Unexpected error creating clientset: %s", err)
+	}
+
+	ns, err := clientset.CoreV1().Namespaces().Get("linkerd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ns.Name != "linkerd" {
+		t.Fatalf("Unexpected namespace: %+v", ns)
+	}
+
+	if len(authorization) != 1 || authorization[0] != "Bearer secret" {
+		t.Fatalf("Expected the request to be authenticated once, got %v", authorization)
+	}
+	if len(recorder.records) != 1 || recorder.records[0].Pattern != "/api/v1/namespaces/{name}" {
+		t.Fatalf("Expected the request to be sent through the API's transport, got %+v", recorder.records)
+	}
+}
+
+func TestNewServiceProfileClientset(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"ServiceProfileList","apiVersion":"linkerd.io/v1alpha1","items":[{"metadata":{"name":"web.emojivoto.svc.cluster.local"}}]}`))
+	}))
+	defer server.Close()
+
+	recorder := &testRecorder{}
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, BearerToken: "secret"}, Recorder: recorder}
+	clientset, err := api.NewServiceProfileClientset()
+	if err != nil {
+		t.Fatalf("Unexpected error creating clientset: %s", err)
+	}
+
+	profiles, err := clientset.LinkerdV1alpha1().ServiceProfiles("linkerd").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(profiles.Items) != 1 || profiles.Items[0].Name != "web.emojivoto.svc.cluster.local" {
+		t.Fatalf("Unexpected profiles: %+v", profiles.Items)
+	}
+
+	if len(authorization) != 1 || authorization[0] != "Bearer secret" {
+		t.Fatalf("Expected the request to be authenticated once, got %v", authorization)
+	}
+	if len(recorder.records) != 1 {
+		t.Fatalf("Expected the request to be sent through the API's transport, got %+v", recorder.records)
+	}
+}
@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// crdGroupVersions are the apiextensions.k8s.io group versions serving
+// CustomResourceDefinitions, in order of preference.
+var crdGroupVersions = []string{"apiextensions.k8s.io/v1", "apiextensions.k8s.io/v1beta1"}
+
+// ErrCRDAPIUnavailable is returned when the API server serves none of the
+// apiextensions.k8s.io group versions known to Linkerd.
+var ErrCRDAPIUnavailable = errors.New("The Kubernetes API server does not serve the apiextensions.k8s.io API")
+
+// CRDNotFoundError is returned when a CustomResourceDefinition does not exist.
+type CRDNotFoundError struct {
+	Name string
+}
+
+func (e *CRDNotFoundError) Error() string {
+	return fmt.Sprintf("The \"%s\" CustomResourceDefinition does not exist", e.Name)
+}
+
+// IsCRDNotFound returns true if the error is a CRDNotFoundError.
+func IsCRDNotFound(err error) bool {
+	_, ok := err.(*CRDNotFoundError)
+	return ok
+}
+
+// CRDCondition is a condition of a CustomResourceDefinition. Status is empty
+// if the API server has not reported the condition.
+type CRDCondition struct {
+	Status  string
+	Reason  string
+	Message string
+}
+
+// CRD summarizes a CustomResourceDefinition, whichever group version it was
+// read from.
+type CRD struct {
+	Name           string
+	Labels         map[string]string
+	ServedVersions []string
+	StorageVersion string
+	Established    CRDCondition
+	NamesAccepted  CRDCondition
+}
+
+// crdDocument holds the fields of a CustomResourceDefinition read from
+// either group version; v1beta1 may set a single spec.version rather than
+// spec.versions.
+type crdDocument struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		Version  string `json:"version"`
+		Versions []struct {
+			Name    string `json:"name"`
+			Served  bool   `json:"served"`
+			Storage bool   `json:"storage"`
+		} `json:"versions"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+func (d *crdDocument) crd() *CRD {
+	crd := &CRD{Name: d.Metadata.Name, Labels: d.Metadata.Labels, ServedVersions: []string{}}
+
+	if len(d.Spec.Versions) == 0 && d.Spec.Version != "" {
+		crd.ServedVersions = append(crd.ServedVersions, d.Spec.Version)
+		crd.StorageVersion = d.Spec.Version
+	}
+	for _, version := range d.Spec.Versions {
+		if version.Served {
+			crd.ServedVersions = append(crd.ServedVersions, version.Name)
+		}
+		if version.Storage {
+			crd.StorageVersion = version.Name
+		}
+	}
+
+	for _, condition := range d.Status.Conditions {
+		c := CRDCondition{Status: condition.Status, Reason: condition.Reason, Message: condition.Message}
+		switch condition.Type {
+		case "Established":
+			crd.Established = c
+		case "NamesAccepted":
+			crd.NamesAccepted = c
+		}
+	}
+
+	return crd
+}
+
+// CRDGroupVersion returns the preferred apiextensions.k8s.io group version
+// served by the API server, or ErrCRDAPIUnavailable if it serves none.
+func (kubeAPI *KubernetesAPI) CRDGroupVersion(ctx context.Context) (string, error) {
+	for _, groupVersion := range crdGroupVersions {
+		served, err := kubeAPI.ServesGroupVersion(ctx, groupVersion)
+		if err != nil {
+			return "", err
+		}
+		if served {
+			return groupVersion, nil
+		}
+	}
+	return "", ErrCRDAPIUnavailable
+}
+
+// GetCRD returns the named CustomResourceDefinition, such as
+// "serviceprofiles.linkerd.io", read from the group version returned by
+// CRDGroupVersion. A CRDNotFoundError is returned if it does not exist.
+func (kubeAPI *KubernetesAPI) GetCRD(ctx context.Context, name string) (*CRD, error) {
+	groupVersion, err := kubeAPI.CRDGroupVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, ResourcePath(groupVersion, "customresourcedefinitions", "", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, &CRDNotFoundError{Name: name}
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var document crdDocument
+	if err := json.Unmarshal(bytes, &document); err != nil {
+		return nil, err
+	}
+	return document.crd(), nil
+}
+
+// CRDIsEstablished returns true if the CustomResourceDefinition's names were
+// accepted and it is established, i.e. its resources are being served.
+// Otherwise the reason given by the unmet condition is returned.
+func CRDIsEstablished(crd *CRD) (bool, string) {
+	for _, c := range []struct {
+		name      string
+		condition CRDCondition
+	}{
+		{"NamesAccepted", crd.NamesAccepted},
+		{"Established", crd.Established},
+	} {
+		if c.condition.Status == "True" {
+			continue
+		}
+		if c.condition.Status == "" {
+			return false, fmt.Sprintf("The \"%s\" CustomResourceDefinition has no %s condition yet", crd.Name, c.name)
+		}
+		reason := fmt.Sprintf("The \"%s\" CustomResourceDefinition's %s condition is %s", crd.Name, c.name, c.condition.Status)
+		if c.condition.Message != "" {
+			reason = fmt.Sprintf("%s: %s", reason, c.condition.Message)
+		}
+		return false, reason
+	}
+	return true, ""
+}
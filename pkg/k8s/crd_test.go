@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestGetCRD(t *testing.T) {
+	v1CRD := `{"kind":"CustomResourceDefinition","apiVersion":"apiextensions.k8s.io/v1",
+		"metadata":{"name":"serviceprofiles.linkerd.io","labels":{"linkerd.io/control-plane-ns":"linkerd"}},
+		"spec":{"versions":[{"name":"v1alpha1","served":true,"storage":false},{"name":"v1alpha2","served":true,"storage":true}]},
+		"status":{"conditions":[{"type":"NamesAccepted","status":"True"},{"type":"Established","status":"True"}]}}`
+	v1beta1CRD := `{"kind":"CustomResourceDefinition","apiVersion":"apiextensions.k8s.io/v1beta1",
+		"metadata":{"name":"serviceprofiles.linkerd.io"},
+		"spec":{"version":"v1alpha1"},
+		"status":{"conditions":[{"type":"NamesAccepted","status":"False","message":"\"serviceprofiles\" is already in use"},{"type":"Established","status":"False"}]}}`
+
+	newAPI := func(t *testing.T, groupVersions []string, crds map[string]string) (*KubernetesAPI, func()) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/apis" {
+				versions := ""
+				for i, gv := range groupVersions {
+					if i > 0 {
+						versions += ","
+					}
+					versions += `{"groupVersion":"` + gv + `"}`
+				}
+				w.Write([]byte(`{"kind":"APIGroupList","groups":[{"name":"apiextensions.k8s.io","versions":[` + versions + `]}]}`))
+				return
+			}
+			crd, ok := crds[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(crd))
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		return api, server.Close
+	}
+
+	t.Run("Prefers the v1 API when both are served", func(t *testing.T) {
+		api, done := newAPI(t, []string{"apiextensions.k8s.io/v1", "apiextensions.k8s.io/v1beta1"}, map[string]string{
+			"/apis/apiextensions.k8s.io/v1/customresourcedefinitions/serviceprofiles.linkerd.io":      v1CRD,
+			"/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/serviceprofiles.linkerd.io": v1beta1CRD,
+		})
+		defer done()
+
+		crd, err := api.GetCRD(context.Background(), "serviceprofiles.linkerd.io")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if crd.Name != "serviceprofiles.linkerd.io" || crd.Labels["linkerd.io/control-plane-ns"] != "linkerd" ||
+			!reflect.DeepEqual(crd.ServedVersions, []string{"v1alpha1", "v1alpha2"}) || crd.StorageVersion != "v1alpha2" {
+			t.Fatalf("Unexpected CRD: %+v", crd)
+		}
+		if established, reason := CRDIsEstablished(crd); !established {
+			t.Fatalf("Expected the CRD to be established: %s", reason)
+		}
+	})
+
+	t.Run("Falls back to the v1beta1 API when v1 is not served", func(t *testing.T) {
+		api, done := newAPI(t, []string{"apiextensions.k8s.io/v1beta1"}, map[string]string{
+			"/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/serviceprofiles.linkerd.io": v1beta1CRD,
+		})
+		defer done()
+
+		crd, err := api.GetCRD(context.Background(), "serviceprofiles.linkerd.io")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if !reflect.DeepEqual(crd.ServedVersions, []string{"v1alpha1"}) || crd.StorageVersion != "v1alpha1" {
+			t.Fatalf("Unexpected CRD: %+v", crd)
+		}
+		established, reason := CRDIsEstablished(crd)
+		expected := "The \"serviceprofiles.linkerd.io\" CustomResourceDefinition's NamesAccepted condition is False: \"serviceprofiles\" is already in use"
+		if established || reason != expected {
+			t.Fatalf("Expected reason [%s], got [%s]", expected, reason)
+		}
+	})
+
+	t.Run("Returns a CRDNotFoundError if the CRD does not exist", func(t *testing.T) {
+		api, done := newAPI(t, []string{"apiextensions.k8s.io/v1"}, map[string]string{})
+		defer done()
+
+		if _, err := api.GetCRD(context.Background(), "serviceprofiles.linkerd.io"); !IsCRDNotFound(err) {
+			t.Fatalf("Expected a CRDNotFoundError, got %v", err)
+		}
+	})
+
+	t.Run("Returns ErrCRDAPIUnavailable if no API is served", func(t *testing.T) {
+		api, done := newAPI(t, []string{}, map[string]string{})
+		defer done()
+
+		if _, err := api.GetCRD(context.Background(), "serviceprofiles.linkerd.io"); err != ErrCRDAPIUnavailable {
+			t.Fatalf("Expected ErrCRDAPIUnavailable, got %v", err)
+		}
+	})
+}
+
+func TestCRDIsEstablished(t *testing.T) {
+	crd := &CRD{Name: "serviceprofiles.linkerd.io", NamesAccepted: CRDCondition{Status: "True"}}
+	established, reason := CRDIsEstablished(crd)
+	expected := "The \"serviceprofiles.linkerd.io\" CustomResourceDefinition has no Established condition yet"
+	if established || reason != expected {
+		t.Fatalf("Expected reason [%s], got [%s]", expected, reason)
+	}
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServesGroupVersion returns true if the API server serves the given API
+// group version, such as "admissionregistration.k8s.io/v1beta1", or "v1" for
+// the core API. The served group versions are read from the /apis discovery
+// document, once until ResetDiscovery is called.
+func (kubeAPI *KubernetesAPI) ServesGroupVersion(ctx context.Context, groupVersion string) (bool, error) {
+	if groupVersion == "v1" {
+		return true, nil
+	}
+
+	kubeAPI.discoveryMu.Lock()
+	defer kubeAPI.discoveryMu.Unlock()
+
+	if kubeAPI.groupVersions == nil {
+		var groups metav1.APIGroupList
+		found, err := kubeAPI.getDiscoveryDocument(ctx, "/apis", &groups)
+		if err != nil {
+			return false, err
+		}
+
+		served := make(map[string]bool)
+		if found {
+			for _, group := range groups.Groups {
+				for _, version := range group.Versions {
+					served[version.GroupVersion] = true
+				}
+			}
+		}
+		kubeAPI.groupVersions = served
+	}
+
+	return kubeAPI.groupVersions[groupVersion], nil
+}
+
+// ResourceExists returns true if the API server serves the given resource,
+// such as "serviceprofiles", under the given API group version, such as
+// "linkerd.io/v1alpha2". The resources of each group version are read from
+// its discovery document, once until ResetDiscovery is called.
+func (kubeAPI *KubernetesAPI) ResourceExists(ctx context.Context, groupVersion, resource string) (bool, error) {
+	served, err := kubeAPI.ServesGroupVersion(ctx, groupVersion)
+	if err != nil || !served {
+		return false, err
+	}
+
+	kubeAPI.discoveryMu.Lock()
+	defer kubeAPI.discoveryMu.Unlock()
+
+	resources, ok := kubeAPI.resources[groupVersion]
+	if !ok {
+		path := "/apis/" + groupVersion
+		if groupVersion == "v1" {
+			path = "/api/v1"
+		}
+
+		var list metav1.APIResourceList
+		if _, err := kubeAPI.getDiscoveryDocument(ctx, path, &list); err != nil {
+			return false, err
+		}
+
+		resources = make(map[string]bool)
+		for _, r := range list.APIResources {
+			resources[r.Name] = true
+		}
+		if kubeAPI.resources == nil {
+			kubeAPI.resources = make(map[string]map[string]bool)
+		}
+		kubeAPI.resources[groupVersion] = resources
+	}
+
+	return resources[resource], nil
+}
+
+// ResetDiscovery clears the discovery documents cached by ServesGroupVersion
+// and ResourceExists, so that they are read again on their next call.
+func (kubeAPI *KubernetesAPI) ResetDiscovery() {
+	kubeAPI.discoveryMu.Lock()
+	defer kubeAPI.discoveryMu.Unlock()
+
+	kubeAPI.groupVersions = nil
+	kubeAPI.resources = nil
+}
+
+// getDiscoveryDocument decodes the discovery document at the given path into
+// v, and returns false if the API server doesn't serve it. Forbidden requests
+// return a ForbiddenError.
+func (kubeAPI *KubernetesAPI) getDiscoveryDocument(ctx context.Context, path string, v interface{}) (bool, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	rsp, err := kubeAPI.getRequest(ctx, path)
+	if err != nil {
+		return false, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return false, unexpectedResponse(rsp)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return false, err
+	}
+
+	return true, json.Unmarshal(bytes, v)
+}
@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestDiscovery(t *testing.T) {
+	documents := map[string]string{
+		"/apis": `{"kind":"APIGroupList","groups":[
+			{"name":"admissionregistration.k8s.io","versions":[{"groupVersion":"admissionregistration.k8s.io/v1beta1","version":"v1beta1"}]},
+			{"name":"linkerd.io","versions":[{"groupVersion":"linkerd.io/v1alpha1","version":"v1alpha1"}]}]}`,
+		"/apis/linkerd.io/v1alpha1": `{"kind":"APIResourceList","groupVersion":"linkerd.io/v1alpha1","resources":[
+			{"name":"serviceprofiles","namespaced":true,"kind":"ServiceProfile","verbs":["get","list"]}]}`,
+		"/api/v1": `{"kind":"APIResourceList","groupVersion":"v1","resources":[
+			{"name":"pods","namespaced":true,"kind":"Pod","verbs":["get","list"]}]}`,
+	}
+
+	newAPI := func(t *testing.T, status int) (*KubernetesAPI, map[string]int, func()) {
+		requests := make(map[string]int)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests[r.URL.Path]++
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+			document, ok := documents[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(document))
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		return api, requests, server.Close
+	}
+
+	t.Run("Reports the served group versions and resources", func(t *testing.T) {
+		api, _, done := newAPI(t, http.StatusOK)
+		defer done()
+
+		testCases := []struct {
+			groupVersion string
+			resource     string
+			served       bool
+			exists       bool
+		}{
+			{"admissionregistration.k8s.io/v1beta1", "", true, false},
+			{"policy/v1beta1", "podsecuritypolicies", false, false},
+			{"linkerd.io/v1alpha1", "serviceprofiles", true, true},
+			{"linkerd.io/v1alpha1", "trafficsplits", true, false},
+			{"v1", "pods", true, true},
+		}
+
+		for _, tc := range testCases {
+			served, err := api.ServesGroupVersion(context.Background(), tc.groupVersion)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if served != tc.served {
+				t.Fatalf("Expected ServesGroupVersion(%s) to be %t", tc.groupVersion, tc.served)
+			}
+
+			if tc.resource == "" {
+				continue
+			}
+			exists, err := api.ResourceExists(context.Background(), tc.groupVersion, tc.resource)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if exists != tc.exists {
+				t.Fatalf("Expected ResourceExists(%s, %s) to be %t", tc.groupVersion, tc.resource, tc.exists)
+			}
+		}
+	})
+
+	t.Run("Caches the discovery documents until reset", func(t *testing.T) {
+		api, requests, done := newAPI(t, http.StatusOK)
+		defer done()
+
+		query := func() {
+			for i := 0; i < 3; i++ {
+				if _, err := api.ResourceExists(context.Background(), "linkerd.io/v1alpha1", "serviceprofiles"); err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			}
+		}
+
+		query()
+		if requests["/apis"] != 1 || requests["/apis/linkerd.io/v1alpha1"] != 1 {
+			t.Fatalf("Expected each discovery document to be fetched once, got %v", requests)
+		}
+
+		api.ResetDiscovery()
+		query()
+		if requests["/apis"] != 2 || requests["/apis/linkerd.io/v1alpha1"] != 2 {
+			t.Fatalf("Expected each discovery document to be fetched again after a reset, got %v", requests)
+		}
+	})
+
+	t.Run("Returns a ForbiddenError if discovery is forbidden", func(t *testing.T) {
+		api, _, done := newAPI(t, http.StatusForbidden)
+		defer done()
+
+		if _, err := api.ServesGroupVersion(context.Background(), "linkerd.io/v1alpha1"); !IsForbidden(err) {
+			t.Fatalf("Expected a ForbiddenError, got %v", err)
+		}
+	})
+}
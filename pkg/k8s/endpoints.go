@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// endpointSlicesGroupVersion is the API group version of EndpointSlices,
+// served by Kubernetes 1.16 and later.
+const endpointSlicesGroupVersion = "discovery.k8s.io/v1beta1"
+
+// EndpointCounts counts the ready and not ready addresses backing a Service.
+type EndpointCounts struct {
+	Ready    int
+	NotReady int
+}
+
+// EndpointsNotFoundError is returned when a Service has no Endpoints object,
+// either because the Service does not exist or because no endpoints have been
+// recorded for it, e.g. as it has no selector.
+type EndpointsNotFoundError struct {
+	Namespace string
+	Service   string
+}
+
+func (e *EndpointsNotFoundError) Error() string {
+	return fmt.Sprintf("The \"%s/%s\" Service has no Endpoints", e.Namespace, e.Service)
+}
+
+// ServiceHasReadyEndpoints returns true if the named Service has at least one
+// ready address, along with the counts of its ready and not ready addresses.
+// The counts are taken from the Service's EndpointSlices when the API server
+// serves them, as Endpoints objects are truncated for large Services, and
+// from its Endpoints otherwise.
+func (kubeAPI *KubernetesAPI) ServiceHasReadyEndpoints(ctx context.Context, namespace, service string) (bool, EndpointCounts, error) {
+	counts, found, err := kubeAPI.countEndpointSliceAddresses(ctx, namespace, service)
+	if err != nil {
+		return false, counts, err
+	}
+
+	if !found {
+		endpoints, err := kubeAPI.GetEndpoints(ctx, namespace, service)
+		if err != nil {
+			return false, counts, err
+		}
+		if endpoints == nil {
+			return false, counts, &EndpointsNotFoundError{Namespace: namespace, Service: service}
+		}
+		counts = countEndpointsAddresses(endpoints)
+	}
+
+	return counts.Ready > 0, counts, nil
+}
+
+func countEndpointsAddresses(endpoints *v1.Endpoints) EndpointCounts {
+	counts := EndpointCounts{}
+	for _, subset := range endpoints.Subsets {
+		counts.Ready += len(subset.Addresses)
+		counts.NotReady += len(subset.NotReadyAddresses)
+	}
+	return counts
+}
+
+// countEndpointSliceAddresses counts the addresses in the Service's
+// EndpointSlices. It returns false if the API server does not serve
+// EndpointSlices, or none exist for the Service.
+func (kubeAPI *KubernetesAPI) countEndpointSliceAddresses(ctx context.Context, namespace, service string) (EndpointCounts, bool, error) {
+	counts := EndpointCounts{}
+
+	path := PathWithQuery(ResourcePath(endpointSlicesGroupVersion, "endpointslices", namespace, ""), selectorQuery("kubernetes.io/service-name="+service))
+	slices, err := kubeAPI.GetUnstructuredList(ctx, path)
+	if err != nil || slices == nil || len(slices.Items) == 0 {
+		return counts, false, err
+	}
+
+	for _, slice := range slices.Items {
+		endpoints, _, err := unstructured.NestedSlice(slice.Object, "endpoints")
+		if err != nil {
+			return counts, false, fmt.Errorf("invalid EndpointSlice %s/%s: %s", slice.GetNamespace(), slice.GetName(), err)
+		}
+
+		for _, e := range endpoints {
+			endpoint, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			addresses, _, _ := unstructured.NestedStringSlice(endpoint, "addresses")
+
+			// an endpoint without a ready condition is to be considered ready
+			ready, found, _ := unstructured.NestedBool(endpoint, "conditions", "ready")
+			if !found || ready {
+				counts.Ready += len(addresses)
+			} else {
+				counts.NotReady += len(addresses)
+			}
+		}
+	}
+
+	return counts, true, nil
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestServiceHasReadyEndpoints(t *testing.T) {
+	newAPI := func(responses map[string]string) (*KubernetesAPI, func()) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, ok := responses[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(body))
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		return api, server.Close
+	}
+
+	endpoints := `{"subsets":[{"addresses":[{"ip":"10.0.0.1"}],"notReadyAddresses":[{"ip":"10.0.0.2"},{"ip":"10.0.0.3"}],"ports":[{"port":8085}]}]}`
+
+	t.Run("Counts the addresses of the Service's EndpointSlices", func(t *testing.T) {
+		api, done := newAPI(map[string]string{
+			"/apis/discovery.k8s.io/v1beta1/namespaces/linkerd/endpointslices": `{"kind":"EndpointSliceList","apiVersion":"discovery.k8s.io/v1beta1","items":[` +
+				`{"kind":"EndpointSlice","apiVersion":"discovery.k8s.io/v1beta1","metadata":{"name":"linkerd-controller-api-abcde"},"endpoints":[` +
+				`{"addresses":["10.0.0.1"],"conditions":{"ready":true}},` +
+				`{"addresses":["10.0.0.2"],"conditions":{"ready":false}},` +
+				`{"addresses":["10.0.0.3"]}]}]}`,
+			"/api/v1/namespaces/linkerd/endpoints/linkerd-controller-api": endpoints,
+		})
+		defer done()
+
+		ready, counts, err := api.ServiceHasReadyEndpoints(context.Background(), "linkerd", "linkerd-controller-api")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !ready || counts != (EndpointCounts{Ready: 2, NotReady: 1}) {
+			t.Fatalf("Unexpected result: %t %+v", ready, counts)
+		}
+	})
+
+	t.Run("Falls back to the Service's Endpoints", func(t *testing.T) {
+		api, done := newAPI(map[string]string{
+			"/api/v1/namespaces/linkerd/endpoints/linkerd-controller-api": endpoints,
+		})
+		defer done()
+
+		ready, counts, err := api.ServiceHasReadyEndpoints(context.Background(), "linkerd", "linkerd-controller-api")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !ready || counts != (EndpointCounts{Ready: 1, NotReady: 2}) {
+			t.Fatalf("Unexpected result: %t %+v", ready, counts)
+		}
+	})
+
+	t.Run("Returns an EndpointsNotFoundError if the Service has no Endpoints", func(t *testing.T) {
+		api, done := newAPI(map[string]string{})
+		defer done()
+
+		_, _, err := api.ServiceHasReadyEndpoints(context.Background(), "linkerd", "linkerd-controller-api")
+		if _, ok := err.(*EndpointsNotFoundError); !ok {
+			t.Fatalf("Expected an EndpointsNotFoundError, got %v", err)
+		}
+		if err.Error() != "The \"linkerd/linkerd-controller-api\" Service has no Endpoints" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+}
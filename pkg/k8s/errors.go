@@ -0,0 +1,173 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxErrorBodyBytes bounds how much of an unexpected response's body is
+	// read to describe the error.
+	maxErrorBodyBytes = 4096
+
+	// maxErrorBodyExcerpt bounds how much of a body that isn't a Status
+	// object is included in the error.
+	maxErrorBodyExcerpt = 256
+)
+
+// NamespaceNotFoundError is returned when a namespace does not exist.
+type NamespaceNotFoundError struct {
+	Namespace string
+}
+
+func (e *NamespaceNotFoundError) Error() string {
+	return fmt.Sprintf("The \"%s\" namespace does not exist", e.Namespace)
+}
+
+// SecretNotFoundError is returned when a Secret does not exist.
+type SecretNotFoundError struct {
+	Namespace string
+	Name      string
+}
+
+func (e *SecretNotFoundError) Error() string {
+	return fmt.Sprintf("The \"%s\" Secret does not exist in the \"%s\" namespace", e.Name, e.Namespace)
+}
+
+// ForbiddenError is returned when the Kubernetes API server forbids a
+// request. Message is the reason given in the response's body, if any.
+type ForbiddenError struct {
+	Verb     string
+	Resource string
+	Status   string
+	Message  string
+}
+
+func (e *ForbiddenError) Error() string {
+	return responseError(e.Status, e.Message)
+}
+
+// StatusError is returned when the Kubernetes API server responds with an
+// unexpected status, other than forbidden or unauthorized. Message is the reason given in the
+// response's body, if any.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return responseError(e.Status, e.Message)
+}
+
+// UnauthorizedError is returned when the Kubernetes API server rejects the
+// request's credentials, even once they were refreshed. CredentialSource
+// describes where the credentials came from, if known.
+type UnauthorizedError struct {
+	Status           string
+	Message          string
+	CredentialSource string
+}
+
+func (e *UnauthorizedError) Error() string {
+	if e.CredentialSource == "" {
+		return responseError(e.Status, e.Message)
+	}
+	return fmt.Sprintf("%s (the Kubernetes API server rejected the credentials from %s)", responseError(e.Status, e.Message), e.CredentialSource)
+}
+
+func responseError(status, message string) string {
+	if message == "" {
+		return fmt.Sprintf("Unexpected Kubernetes API response: %s", status)
+	}
+	return fmt.Sprintf("Unexpected Kubernetes API response: %s: %s", status, message)
+}
+
+// IsNamespaceNotFound returns true if the error is a NamespaceNotFoundError.
+func IsNamespaceNotFound(err error) bool {
+	_, ok := err.(*NamespaceNotFoundError)
+	return ok
+}
+
+// IsSecretNotFound returns true if the error is a SecretNotFoundError.
+func IsSecretNotFound(err error) bool {
+	_, ok := err.(*SecretNotFoundError)
+	return ok
+}
+
+// IsForbidden returns true if the error is a ForbiddenError.
+func IsForbidden(err error) bool {
+	_, ok := err.(*ForbiddenError)
+	return ok
+}
+
+// IsUnauthorized returns true if the error is an UnauthorizedError.
+func IsUnauthorized(err error) bool {
+	_, ok := err.(*UnauthorizedError)
+	return ok
+}
+
+// unexpectedResponse returns the error for a response with an unexpected
+// status; a ForbiddenError if the request was forbidden, an
+// UnauthorizedError if its credentials were rejected, a StatusError
+// otherwise. Each carries the message read from the response's body.
+func unexpectedResponse(rsp *http.Response) error {
+	message := responseMessage(rsp)
+
+	if rsp.StatusCode == http.StatusUnauthorized {
+		return &UnauthorizedError{Status: rsp.Status, Message: message}
+	}
+
+	if rsp.StatusCode == http.StatusForbidden {
+		e := &ForbiddenError{Status: rsp.Status, Message: message}
+		if rsp.Request != nil {
+			e.Verb = requestVerb(rsp.Request.Method)
+			e.Resource = rsp.Request.URL.Path
+		}
+		return e
+	}
+
+	return &StatusError{StatusCode: rsp.StatusCode, Status: rsp.Status, Message: message}
+}
+
+// responseMessage reads up to maxErrorBodyBytes of the response's body, and
+// returns the message of the Status object it holds, or else an excerpt of
+// the body on a single line, such as an HTML error page served by a proxy.
+func responseMessage(rsp *http.Response) string {
+	if rsp.Body == nil {
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(rsp.Body, maxErrorBodyBytes))
+	if err != nil {
+		return ""
+	}
+
+	var status metav1.Status
+	if err := json.Unmarshal(body, &status); err == nil && status.Message != "" {
+		return status.Message
+	}
+
+	excerpt := []rune(strings.Join(strings.Fields(string(body)), " "))
+	if len(excerpt) > maxErrorBodyExcerpt {
+		return string(excerpt[:maxErrorBodyExcerpt]) + "..."
+	}
+	return string(excerpt)
+}
+
+func requestVerb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	default:
+		return strings.ToLower(method)
+	}
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestUnexpectedResponse(t *testing.T) {
+	t.Run("Returns a ForbiddenError for forbidden requests", func(t *testing.T) {
+		rsp := &http.Response{
+			StatusCode: http.StatusForbidden,
+			Status:     "403 Forbidden",
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/api/v1/namespaces/linkerd"}},
+		}
+
+		err := unexpectedResponse(rsp)
+		forbidden, ok := err.(*ForbiddenError)
+		if !ok {
+			t.Fatalf("Expected a ForbiddenError, got %T", err)
+		}
+		if forbidden.Verb != "get" || forbidden.Resource != "/api/v1/namespaces/linkerd" {
+			t.Fatalf("Unexpected verb and resource: %s %s", forbidden.Verb, forbidden.Resource)
+		}
+		if err.Error() != "Unexpected Kubernetes API response: 403 Forbidden" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Returns a StatusError for other statuses", func(t *testing.T) {
+		err := unexpectedResponse(&http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"})
+		if IsForbidden(err) || IsNamespaceNotFound(err) || IsUnauthorized(err) {
+			t.Fatalf("Unexpected error type %T", err)
+		}
+		if status, ok := err.(*StatusError); !ok || status.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected a StatusError, got %#v", err)
+		}
+		if err.Error() != "Unexpected Kubernetes API response: 503 Service Unavailable" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Returns an UnauthorizedError for rejected credentials", func(t *testing.T) {
+		err := unexpectedResponse(&http.Response{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"})
+		if !IsUnauthorized(err) {
+			t.Fatalf("Expected IsUnauthorized to be true for %#v", err)
+		}
+	})
+}
+
+func TestUnexpectedResponseBody(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	t.Run("Includes the message of a Status body", func(t *testing.T) {
+		body := `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"namespaces \"linkerd\" is forbidden: User \"jane\" cannot get resource \"namespaces\" in API group \"\" in the namespace \"linkerd\"","reason":"Forbidden","code":403}`
+
+		err := unexpectedResponse(response(http.StatusForbidden, body))
+		forbidden, ok := err.(*ForbiddenError)
+		if !ok {
+			t.Fatalf("Expected a ForbiddenError, got %T", err)
+		}
+		expected := `namespaces "linkerd" is forbidden: User "jane" cannot get resource "namespaces" in API group "" in the namespace "linkerd"`
+		if forbidden.Message != expected {
+			t.Fatalf("Unexpected message: %s", forbidden.Message)
+		}
+		if err.Error() != "Unexpected Kubernetes API response: 403 Forbidden: "+expected {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Includes an excerpt of an HTML error page", func(t *testing.T) {
+		body := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>\n<center><h1>502 Bad Gateway</h1></center>\n</body>\n</html>\n"
+
+		err := unexpectedResponse(response(http.StatusBadGateway, body))
+		expected := "Unexpected Kubernetes API response: 502 Bad Gateway: <html> <head><title>502 Bad Gateway</title></head> <body> <center><h1>502 Bad Gateway</h1></center> </body> </html>"
+		if err.Error() != expected {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Truncates long bodies", func(t *testing.T) {
+		err := unexpectedResponse(response(http.StatusInternalServerError, strings.Repeat("x", 2*maxErrorBodyBytes)))
+		status, ok := err.(*StatusError)
+		if !ok {
+			t.Fatalf("Expected a StatusError, got %T", err)
+		}
+		if status.Message != strings.Repeat("x", maxErrorBodyExcerpt)+"..." {
+			t.Fatalf("Unexpected message of %d bytes", len(status.Message))
+		}
+	})
+
+	t.Run("Omits empty bodies", func(t *testing.T) {
+		err := unexpectedResponse(response(http.StatusServiceUnavailable, " \n"))
+		if err.Error() != "Unexpected Kubernetes API response: 503 Service Unavailable" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Applies to every request helper", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"access denied","reason":"Forbidden","code":403}`))
+		}))
+		defer server.Close()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+		if _, err := api.GetVersionInfo(context.Background()); err == nil || !strings.HasSuffix(err.Error(), ": access denied") {
+			t.Fatalf("Unexpected error from GetVersionInfo: %v", err)
+		}
+		if err := api.CheckNamespaceExists(context.Background(), "linkerd"); !IsForbidden(err) || !strings.HasSuffix(err.Error(), ": access denied") {
+			t.Fatalf("Unexpected error from CheckNamespaceExists: %v", err)
+		}
+		if _, err := api.GetNodes(context.Background()); err == nil || !strings.HasSuffix(err.Error(), ": access denied") {
+			t.Fatalf("Unexpected error from GetNodes: %v", err)
+		}
+	})
+}
+
+func TestNamespaceNotFoundError(t *testing.T) {
+	err := error(&NamespaceNotFoundError{Namespace: "linkerd"})
+	if !IsNamespaceNotFound(err) {
+		t.Fatal("Expected IsNamespaceNotFound to be true")
+	}
+	if err.Error() != "The \"linkerd\" namespace does not exist" {
+		t.Fatalf("Unexpected error message: %s", err)
+	}
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// apiServerHealthPaths are the API server's health endpoints, in order of
+// preference; /readyz is only served since Kubernetes 1.16.
+var apiServerHealthPaths = []string{"/readyz", "/healthz"}
+
+// APIServerUnhealthyError is returned by CheckAPIServerHealth when the API
+// server reports itself unhealthy. Failed lists the failing components, such
+// as "etcd", if the API server detailed them.
+type APIServerUnhealthyError struct {
+	Path    string
+	Status  string
+	Failed  []string
+	Message string
+}
+
+func (e *APIServerUnhealthyError) Error() string {
+	detail := e.Message
+	if len(e.Failed) > 0 {
+		detail = fmt.Sprintf("failing checks: %s", strings.Join(e.Failed, ", "))
+	}
+	if detail == "" {
+		return fmt.Sprintf("The Kubernetes API server is unhealthy: %s responded %s", e.Path, e.Status)
+	}
+	return fmt.Sprintf("The Kubernetes API server is unhealthy: %s responded %s: %s", e.Path, e.Status, detail)
+}
+
+// CheckAPIServerHealth asks the API server whether it is ready to serve
+// requests, through /readyz, or /healthz if it does not serve /readyz. An
+// APIServerUnhealthyError naming the failing components is returned if it is
+// not, and a ForbiddenError if the health endpoints may not be read.
+func (kubeAPI *KubernetesAPI) CheckAPIServerHealth(ctx context.Context) error {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	for _, path := range apiServerHealthPaths {
+		rsp, err := kubeAPI.getRequest(WithFinalResponse(WithoutResponseCache(ctx)), path+"?verbose")
+		if err != nil {
+			return err
+		}
+		defer rsp.Body.Close()
+
+		switch rsp.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusNotFound:
+			continue
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return unexpectedResponse(rsp)
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(rsp.Body, maxErrorBodyBytes))
+		if err != nil {
+			return err
+		}
+		failed, message := parseHealthOutput(body)
+		return &APIServerUnhealthyError{Path: path, Status: rsp.Status, Failed: failed, Message: message}
+	}
+
+	return fmt.Errorf("The Kubernetes API server serves none of the %s health endpoints", strings.Join(apiServerHealthPaths, ", "))
+}
+
+// parseHealthOutput returns the failing checks listed in the verbose output
+// of a health endpoint, such as "[-]etcd failed: reason withheld", or else
+// the output on a single line.
+func parseHealthOutput(body []byte) ([]string, string) {
+	failed := []string{}
+	lines := []string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if strings.HasPrefix(line, "[-]") {
+			failed = append(failed, strings.TrimPrefix(line, "[-]"))
+		}
+	}
+
+	if len(failed) > 0 {
+		return failed, ""
+	}
+	return nil, strings.Join(lines, " ")
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestCheckAPIServerHealth(t *testing.T) {
+	newAPI := func(t *testing.T, handler http.HandlerFunc) (*KubernetesAPI, func()) {
+		server := httptest.NewServer(handler)
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, MaxRequestAttempts: 1}
+		return api, server.Close
+	}
+
+	t.Run("Returns nil if the API server is ready", func(t *testing.T) {
+		var requested []string
+		api, done := newAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.String())
+			w.Write([]byte("[+]ping ok\n[+]etcd ok\nreadyz check passed\n"))
+		})
+		defer done()
+
+		if err := api.CheckAPIServerHealth(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(requested, []string{"/readyz?verbose"}) {
+			t.Fatalf("Unexpected requests: %v", requested)
+		}
+	})
+
+	t.Run("Falls back to /healthz if /readyz is not served", func(t *testing.T) {
+		var requested []string
+		api, done := newAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.Path)
+			if r.URL.Path != "/healthz" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("[+]ping ok\n[-]etcd failed: reason withheld\n[+]poststarthook/start-informers ok\nhealthz check failed\n"))
+		})
+		defer done()
+
+		err := api.CheckAPIServerHealth(context.Background())
+		unhealthy, ok := err.(*APIServerUnhealthyError)
+		if !ok {
+			t.Fatalf("Expected an APIServerUnhealthyError, got %v", err)
+		}
+		if unhealthy.Path != "/healthz" || !reflect.DeepEqual(unhealthy.Failed, []string{"etcd failed: reason withheld"}) {
+			t.Fatalf("Unexpected error: %+v", unhealthy)
+		}
+		expected := "The Kubernetes API server is unhealthy: /healthz responded 500 Internal Server Error: failing checks: etcd failed: reason withheld"
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%s]", expected, err)
+		}
+		if !reflect.DeepEqual(requested, []string{"/readyz", "/healthz"}) {
+			t.Fatalf("Unexpected requests: %v", requested)
+		}
+	})
+
+	t.Run("Includes the output if no failing check is listed", func(t *testing.T) {
+		api, done := newAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("shutting down\n"))
+		})
+		defer done()
+
+		err := api.CheckAPIServerHealth(context.Background())
+		expected := "The Kubernetes API server is unhealthy: /readyz responded 503 Service Unavailable: shutting down"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got %v", expected, err)
+		}
+	})
+
+	t.Run("Returns a ForbiddenError if the endpoints may not be read", func(t *testing.T) {
+		api, done := newAPI(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+		defer done()
+
+		if err := api.CheckAPIServerHealth(context.Background()); !IsForbidden(err) {
+			t.Fatalf("Expected a ForbiddenError, got %v", err)
+		}
+	})
+}
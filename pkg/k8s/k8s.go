@@ -2,8 +2,12 @@ package k8s
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/url"
+	"os"
+	"strings"
 
+	"github.com/ghodss/yaml"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -22,6 +26,17 @@ const (
 	StatefulSet           = "statefulset"
 )
 
+const (
+	// KubeconfigSource indicates a configuration loaded from a kubeconfig file.
+	KubeconfigSource = "kubeconfig"
+
+	// InClusterConfigSource indicates a configuration built from the service
+	// account mounted into the current pod.
+	InClusterConfigSource = "in-cluster"
+
+	serviceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
 // resources to query in StatSummary when Resource.Type is "all"
 var StatAllResourceTypes = []string{
 	// TODO: add Namespace here to decrease queries from the web process
@@ -51,6 +66,55 @@ func generateKubernetesApiBaseUrlFor(schemeHostAndPort string, namespace string,
 	return url, nil
 }
 
+// ResourcePath returns the API path of a resource of the given API group
+// version, such as "v1" for the core API or "apiregistration.k8s.io/v1". The
+// path addresses the named object, or the collection if name is empty, in the
+// given namespace, or cluster-wide if namespace is empty. The resource may
+// name a subresource, such as "pods/log", which is only valid with a name.
+// The namespace and name are escaped.
+func ResourcePath(groupVersion, resource, namespace, name string) string {
+	path := "/apis/" + groupVersion
+	if groupVersion == "v1" {
+		path = "/api/v1"
+	}
+	if namespace != "" {
+		path += "/namespaces/" + url.PathEscape(namespace)
+	}
+
+	subresource := ""
+	if i := strings.Index(resource, "/"); i >= 0 {
+		resource, subresource = resource[:i], resource[i:]
+	}
+	path += "/" + resource
+	if name != "" {
+		path += "/" + url.PathEscape(name) + subresource
+	}
+	return path
+}
+
+// ClusterResourcePath returns the API path of a cluster-scoped core resource,
+// such as "nodes" or "namespaces", or of the named object if name is set.
+func ClusterResourcePath(resource, name string) string {
+	return ResourcePath("v1", resource, "", name)
+}
+
+func generateKubernetesApiUrlForResource(schemeHostAndPort, groupVersion, resource, namespace, name string) (*url.URL, error) {
+	if groupVersion == "" || resource == "" {
+		return nil, fmt.Errorf("Both the group version and resource are required, got [%s] and [%s]", groupVersion, resource)
+	}
+	if strings.Contains(resource, "/") && name == "" {
+		return nil, fmt.Errorf("A name is required to address the [%s] subresource", resource)
+	}
+
+	urlString := schemeHostAndPort + ResourcePath(groupVersion, resource, namespace, name)
+	url, err := url.Parse(urlString)
+	if err != nil {
+		return nil, fmt.Errorf("error generating resource URL for Kubernetes API from [%s]", urlString)
+	}
+
+	return url, nil
+}
+
 func generateBaseKubernetesApiUrl(schemeHostAndPort string) (*url.URL, error) {
 	urlString := fmt.Sprintf("%s/api/v1/", schemeHostAndPort)
 	url, err := url.Parse(urlString)
@@ -60,15 +124,127 @@ func generateBaseKubernetesApiUrl(schemeHostAndPort string) (*url.URL, error) {
 	return url, nil
 }
 
-func getConfig(fpath, kubeContext string) (*rest.Config, error) {
+// getConfig loads the configuration for the given kubeconfig and context. If
+// no kubeconfig path is given, the files listed in $KUBECONFIG, or else
+// ~/.kube/config, are merged as kubectl does; if none of them exists, the
+// in-cluster configuration is used instead. The source of the configuration
+// is returned alongside it.
+func getConfig(fpath, kubeContext string) (*rest.Config, string, error) {
+	rules := loadingRules(fpath)
+	if fpath == "" && !anyFileExists(rules.Precedence) {
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			tried := append(append([]string{}, rules.Precedence...), serviceAccountPath)
+			return nil, "", fmt.Errorf("no Kubernetes configuration found, tried: %s (%s)", strings.Join(tried, ", "), err)
+		}
+		return config, InClusterConfigSource, nil
+	}
+
+	config, err := loadKubeconfig(rules, kubeContext)
+	return config, KubeconfigSource, err
+}
+
+// loadingRules returns kubectl's rules for loading the kubeconfig: an
+// explicit path is loaded alone, otherwise the files of $KUBECONFIG are
+// merged, the first file to set a value winning.
+func loadingRules(fpath string) *clientcmd.ClientConfigLoadingRules {
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if fpath != "" {
 		rules.ExplicitPath = fpath
 	}
+	return rules
+}
+
+// kubeconfigFiles returns the files consulted by the given loading rules, in
+// order of precedence.
+func kubeconfigFiles(rules *clientcmd.ClientConfigLoadingRules) []string {
+	if rules.ExplicitPath != "" {
+		return []string{rules.ExplicitPath}
+	}
+	return rules.Precedence
+}
+
+// proxyKubeconfig holds the fields of a kubeconfig needed to find the
+// proxy-url of a cluster, which client-go does not support yet.
+type proxyKubeconfig struct {
+	Clusters []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			ProxyURL string `json:"proxy-url"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+}
+
+// getProxyURL returns the proxy-url set in the kubeconfig for the cluster
+// selected by the given context, or nil if none is set. As when merging, the
+// cluster is read from the first file defining it.
+func getProxyURL(fpath, kubeContext string) (*url.URL, error) {
+	rules := loadingRules(fpath)
+	raw, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if kubeContext == "" {
+		kubeContext = raw.CurrentContext
+	}
+	context, ok := raw.Contexts[kubeContext]
+	if !ok {
+		return nil, nil
+	}
+
+	for _, path := range kubeconfigFiles(rules) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var kubeconfig proxyKubeconfig
+		if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %s", path, err)
+		}
+
+		for _, cluster := range kubeconfig.Clusters {
+			if cluster.Name != context.Cluster {
+				continue
+			}
+			if cluster.Cluster.ProxyURL == "" {
+				return nil, nil
+			}
+
+			proxyURL, err := url.Parse(cluster.Cluster.ProxyURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy-url for cluster \"%s\": %s", cluster.Name, err)
+			}
+			return proxyURL, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// loadKubeconfig loads the configuration for the given context, or else the
+// current context, of the kubeconfig. The context is validated first, so that
+// a missing context, cluster, user or referenced file, or a malformed server
+// URL, is reported as a ContextNotFoundError or an InvalidKubeconfigError.
+func loadKubeconfig(rules *clientcmd.ClientConfigLoadingRules, kubeContext string) (*rest.Config, error) {
 	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
-	return clientcmd.
-		NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).
-		ClientConfig()
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	files := kubeconfigFiles(rules)
+
+	raw, err := loader.RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig from %s: %s", strings.Join(files, ", "), err)
+	}
+	if err := validateKubeconfig(raw, kubeContext, files); err != nil {
+		return nil, err
+	}
+
+	config, err := loader.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig from %s: %s", strings.Join(files, ", "), err)
+	}
+	return config, nil
 }
 
 // CanonicalResourceNameFromFriendlyName returns a canonical name from common shorthands used in command line tools.
@@ -125,3 +301,12 @@ func ShortNameFromCanonicalResourceName(canonicalName string) string {
 		return ""
 	}
 }
+
+func anyFileExists(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
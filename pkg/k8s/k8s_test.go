@@ -1,6 +1,11 @@
 package k8s
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -33,6 +38,66 @@ func TestGenerateKubernetesApiBaseUrlFor(t *testing.T) {
 	})
 }
 
+func TestResourcePath(t *testing.T) {
+	testCases := []struct {
+		groupVersion string
+		resource     string
+		namespace    string
+		name         string
+		expected     string
+	}{
+		{"v1", "nodes", "", "", "/api/v1/nodes"},
+		{"v1", "namespaces", "", "linkerd", "/api/v1/namespaces/linkerd"},
+		{"v1", "pods", "", "", "/api/v1/pods"},
+		{"v1", "pods", "emojivoto", "", "/api/v1/namespaces/emojivoto/pods"},
+		{"v1", "pods/log", "emojivoto", "web-1", "/api/v1/namespaces/emojivoto/pods/web-1/log"},
+		{"v1", "pods/portforward", "emojivoto", "web-1", "/api/v1/namespaces/emojivoto/pods/web-1/portforward"},
+		{"v1", "pods/proxy", "emojivoto", "web-1:4191", "/api/v1/namespaces/emojivoto/pods/web-1:4191/proxy"},
+		{"apps/v1", "deployments", "emojivoto", "web", "/apis/apps/v1/namespaces/emojivoto/deployments/web"},
+		{"rbac.authorization.k8s.io/v1", "clusterroles", "", "linkerd-linkerd-controller", "/apis/rbac.authorization.k8s.io/v1/clusterroles/linkerd-linkerd-controller"},
+		{"apiextensions.k8s.io/v1beta1", "customresourcedefinitions", "", "serviceprofiles.linkerd.io", "/apis/apiextensions.k8s.io/v1beta1/customresourcedefinitions/serviceprofiles.linkerd.io"},
+		{"apiregistration.k8s.io/v1", "apiservices", "", "v1alpha1.tap.linkerd.io", "/apis/apiregistration.k8s.io/v1/apiservices/v1alpha1.tap.linkerd.io"},
+		{"rbac.authorization.k8s.io/v1", "clusterroles", "", "system:aggregate-to-view", "/apis/rbac.authorization.k8s.io/v1/clusterroles/system:aggregate-to-view"},
+		{"v1", "configmaps", "default", "a/b c?d", "/api/v1/namespaces/default/configmaps/a%2Fb%20c%3Fd"},
+	}
+
+	for _, tc := range testCases {
+		if actual := ResourcePath(tc.groupVersion, tc.resource, tc.namespace, tc.name); actual != tc.expected {
+			t.Errorf("Expected path [%s], got [%s]", tc.expected, actual)
+		}
+	}
+
+	if actual := ClusterResourcePath("namespaces", "kube-system"); actual != "/api/v1/namespaces/kube-system" {
+		t.Errorf("Unexpected cluster resource path: %s", actual)
+	}
+}
+
+func TestGenerateKubernetesApiUrlForResource(t *testing.T) {
+	t.Run("Preserves the escaping of names", func(t *testing.T) {
+		url, err := generateKubernetesApiUrlForResource("https://some-server.example.com:6443", "v1", "secrets", "default", "a/b c")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedUrlString := "https://some-server.example.com:6443/api/v1/namespaces/default/secrets/a%2Fb%20c"
+		if url.String() != expectedUrlString {
+			t.Fatalf("Expected generated URL to be [%s], but got [%s]", expectedUrlString, url.String())
+		}
+	})
+
+	t.Run("Returns an error if a subresource has no name", func(t *testing.T) {
+		if _, err := generateKubernetesApiUrlForResource("https://some-server.example.com:6443", "v1", "pods/log", "default", ""); err == nil {
+			t.Fatal("Expected an error, got nothing")
+		}
+	})
+
+	t.Run("Returns an error if the resource is missing", func(t *testing.T) {
+		if _, err := generateKubernetesApiUrlForResource("https://some-server.example.com:6443", "apps/v1", "", "", ""); err == nil {
+			t.Fatal("Expected an error, got nothing")
+		}
+	})
+}
+
 func TestGenerateBaseKubernetesApiUrl(t *testing.T) {
 	t.Run("Generates correct URL when all elements are present", func(t *testing.T) {
 		schemeHostAndPort := "gopher://some-server.example.com:661"
@@ -60,7 +125,7 @@ func TestGenerateBaseKubernetesApiUrl(t *testing.T) {
 
 func TestGetConfig(t *testing.T) {
 	t.Run("Gets host correctly form existing file", func(t *testing.T) {
-		config, err := getConfig("testdata/config.test", "")
+		config, _, err := getConfig("testdata/config.test", "")
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -72,11 +137,167 @@ func TestGetConfig(t *testing.T) {
 	})
 
 	t.Run("Returns error if configuration cannot be found", func(t *testing.T) {
-		_, err := getConfig("/this/doest./not/exist.config", "")
+		_, _, err := getConfig("/this/doest./not/exist.config", "")
 		if err == nil {
 			t.Fatalf("Expecting error when config file doesnt exist, got nothing")
 		}
 	})
+
+	t.Run("Reports the kubeconfig as the source", func(t *testing.T) {
+		_, source, err := getConfig("testdata/config.test", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if source != KubeconfigSource {
+			t.Fatalf("Expected source to be [%s] got [%s]", KubeconfigSource, source)
+		}
+	})
+
+	t.Run("Lists the locations tried if no kubeconfig exists outside of a cluster", func(t *testing.T) {
+		defer setenv(t, "KUBECONFIG", "/this/doest./not/exist.config")()
+		defer setenv(t, "KUBERNETES_SERVICE_HOST", "")()
+
+		_, _, err := getConfig("", "")
+		if err == nil {
+			t.Fatalf("Expecting error when no configuration exists, got nothing")
+		}
+
+		for _, location := range []string{"/this/doest./not/exist.config", serviceAccountPath} {
+			if !strings.Contains(err.Error(), location) {
+				t.Fatalf("Expected error to mention [%s] got [%s]", location, err.Error())
+			}
+		}
+	})
+
+	t.Run("Uses the given context", func(t *testing.T) {
+		config, _, err := getConfig("testdata/config.test", "cluster2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expectedHost := "https://30.88.172.234"
+		if config.Host != expectedHost {
+			t.Fatalf("Expected host to be [%s] got [%s]", expectedHost, config.Host)
+		}
+	})
+
+	t.Run("Returns error naming the available contexts if the context does not exist", func(t *testing.T) {
+		_, _, err := getConfig("testdata/config.test", "staging")
+		if err == nil {
+			t.Fatalf("Expecting error when context doesnt exist, got nothing")
+		}
+
+		expected := "context \"staging\" does not exist in the kubeconfig loaded from testdata/config.test; available contexts: cluster1, cluster2, cluster3, cluster4, dev"
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s] got [%s]", expected, err.Error())
+		}
+	})
+}
+
+func TestGetConfigMergesKubeconfigs(t *testing.T) {
+	kubeconfig := func(current string, clusters map[string]string) string {
+		config := "apiVersion: v1\nkind: Config\n"
+		if current != "" {
+			config += "current-context: " + current + "\n"
+		}
+		config += "clusters:\n"
+		for name, server := range clusters {
+			config += fmt.Sprintf("- name: %s\n  cluster:\n    server: %s\n", name, server)
+		}
+		config += "contexts:\n"
+		for name := range clusters {
+			config += fmt.Sprintf("- name: %s\n  context:\n    cluster: %s\n    user: %s\n", name, name, name)
+		}
+		config += "users:\n"
+		for name := range clusters {
+			config += fmt.Sprintf("- name: %s\n  user:\n    token: %s\n", name, name)
+		}
+		return config
+	}
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return path
+	}
+	first := write("first", kubeconfig("dev", map[string]string{
+		"dev":    "https://dev.example.com",
+		"shared": "https://first.example.com",
+	}))
+	second := write("second", kubeconfig("prod", map[string]string{
+		"prod":   "https://prod.example.com",
+		"shared": "https://second.example.com",
+	}))
+	missing := filepath.Join(dir, "missing")
+	list := func(paths ...string) string {
+		return strings.Join(paths, string(os.PathListSeparator))
+	}
+
+	testCases := []struct {
+		name         string
+		kubeconfigs  string
+		explicitPath string
+		kubeContext  string
+		expectedHost string
+	}{
+		{"Uses the current context of the first file setting one", list(first, second), "", "", "https://dev.example.com"},
+		{"Finds contexts defined in any of the files", list(first, second), "", "prod", "https://prod.example.com"},
+		{"Uses the first file defining a context", list(second, first), "", "shared", "https://second.example.com"},
+		{"Ignores missing files", list(missing, second), "", "", "https://prod.example.com"},
+		{"Loads an explicit path alone", list(first), second, "", "https://prod.example.com"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer setenv(t, "KUBECONFIG", tc.kubeconfigs)()
+
+			config, source, err := getConfig(tc.explicitPath, tc.kubeContext)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if source != KubeconfigSource {
+				t.Fatalf("Expected source to be [%s] got [%s]", KubeconfigSource, source)
+			}
+			if config.Host != tc.expectedHost {
+				t.Fatalf("Expected host to be [%s] got [%s]", tc.expectedHost, config.Host)
+			}
+		})
+	}
+
+	t.Run("Ignores $KUBECONFIG contexts when given an explicit path", func(t *testing.T) {
+		defer setenv(t, "KUBECONFIG", list(first))()
+
+		_, _, err := getConfig(second, "dev")
+		if err == nil {
+			t.Fatalf("Expecting error when context doesnt exist, got nothing")
+		}
+		if strings.Contains(err.Error(), first) || !strings.Contains(err.Error(), second) {
+			t.Fatalf("Expected error to only mention [%s] got [%s]", second, err.Error())
+		}
+	})
+
+	t.Run("Lists the files consulted if the context does not exist", func(t *testing.T) {
+		defer setenv(t, "KUBECONFIG", list(first, second))()
+
+		_, _, err := getConfig("", "staging")
+		if err == nil {
+			t.Fatalf("Expecting error when context doesnt exist, got nothing")
+		}
+
+		expected := fmt.Sprintf("context \"staging\" does not exist in the kubeconfig loaded from %s, %s; available contexts: dev, prod, shared", first, second)
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s] got [%s]", expected, err.Error())
+		}
+	})
 }
 
 func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
@@ -115,3 +336,19 @@ func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
 		}
 	})
 }
+
+// setenv sets an environment variable, returning a function that restores its
+// previous value.
+func setenv(t *testing.T, key, value string) func() {
+	previous, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return func() {
+		if ok {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ContextNotFoundError is returned when the requested context, or the
+// kubeconfig's current-context if none is requested, does not exist in the
+// kubeconfig. Context is empty if no context is requested and the kubeconfig
+// doesn't set a current-context.
+type ContextNotFoundError struct {
+	Context   string
+	Files     []string
+	Available []string
+}
+
+func (e *ContextNotFoundError) Error() string {
+	files := strings.Join(e.Files, ", ")
+	available := "none"
+	if len(e.Available) > 0 {
+		available = strings.Join(e.Available, ", ")
+	}
+
+	if e.Context == "" {
+		return fmt.Sprintf("no context is selected and the kubeconfig loaded from %s does not set a current-context; available contexts: %s", files, available)
+	}
+	return fmt.Sprintf("context \"%s\" does not exist in the kubeconfig loaded from %s; available contexts: %s", e.Context, files, available)
+}
+
+// InvalidKubeconfigError is returned when the selected context of the
+// kubeconfig refers to a cluster or user that doesn't exist, to a file that
+// cannot be read, or to a server whose URL is malformed.
+type InvalidKubeconfigError struct {
+	Context string
+	Files   []string
+	Reason  string
+}
+
+func (e *InvalidKubeconfigError) Error() string {
+	return fmt.Sprintf("context \"%s\" of the kubeconfig loaded from %s is invalid: %s", e.Context, strings.Join(e.Files, ", "), e.Reason)
+}
+
+// IsContextNotFound returns true if the error is a ContextNotFoundError.
+func IsContextNotFound(err error) bool {
+	_, ok := err.(*ContextNotFoundError)
+	return ok
+}
+
+// IsInvalidKubeconfig returns true if the error is an InvalidKubeconfigError.
+func IsInvalidKubeconfig(err error) bool {
+	_, ok := err.(*InvalidKubeconfigError)
+	return ok
+}
+
+// validateKubeconfig checks that the given context, or else the current
+// context, of the merged kubeconfig exists, and that the cluster and user it
+// refers to exist, along with the files they reference.
+func validateKubeconfig(raw clientcmdapi.Config, kubeContext string, files []string) error {
+	if kubeContext == "" {
+		kubeContext = raw.CurrentContext
+	}
+
+	context, ok := raw.Contexts[kubeContext]
+	if !ok || kubeContext == "" {
+		available := []string{}
+		for name := range raw.Contexts {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		return &ContextNotFoundError{Context: kubeContext, Files: files, Available: available}
+	}
+
+	invalid := func(format string, args ...interface{}) error {
+		return &InvalidKubeconfigError{Context: kubeContext, Files: files, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if context.Cluster == "" {
+		return invalid("it does not name a cluster")
+	}
+	cluster, ok := raw.Clusters[context.Cluster]
+	if !ok {
+		return invalid("cluster \"%s\" does not exist", context.Cluster)
+	}
+	if err := validateServer(cluster.Server); err != nil {
+		return invalid("the server of cluster \"%s\" %s", context.Cluster, err)
+	}
+	if err := readable(cluster.CertificateAuthority); err != nil {
+		return invalid("the certificate-authority of cluster \"%s\" cannot be read: %s", context.Cluster, err)
+	}
+
+	// a context without a user makes anonymous requests
+	if context.AuthInfo == "" {
+		return nil
+	}
+	user, ok := raw.AuthInfos[context.AuthInfo]
+	if !ok {
+		return invalid("user \"%s\" does not exist", context.AuthInfo)
+	}
+
+	userFiles := []struct {
+		field string
+		path  string
+	}{
+		{"client-certificate", user.ClientCertificate},
+		{"client-key", user.ClientKey},
+		{"tokenFile", user.TokenFile},
+	}
+	for _, f := range userFiles {
+		if err := readable(f.path); err != nil {
+			return invalid("the %s of user \"%s\" cannot be read: %s", f.field, context.AuthInfo, err)
+		}
+	}
+
+	return nil
+}
+
+// validateServer returns an error completing "the server of cluster x" if the
+// server URL is missing or malformed. As for kubectl, the scheme defaults to
+// https.
+func validateServer(server string) error {
+	if server == "" {
+		return fmt.Errorf("is not set")
+	}
+
+	withScheme := server
+	if !strings.Contains(server, "://") {
+		withScheme = "https://" + server
+	}
+	u, err := url.Parse(withScheme)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("is not a valid URL: \"%s\"", server)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("has an unsupported scheme: \"%s\"", server)
+	}
+	return nil
+}
+
+// readable returns an error if the file at the given path, if any, cannot be
+// opened.
+func readable(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,165 @@
+package k8s
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateKubeconfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "ca.crt"), []byte("not checked"), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	write := func(t *testing.T, content string) string {
+		path := filepath.Join(dir, "config")
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return path
+	}
+
+	kubeconfig := func(current, server, cluster, user, userFields string) string {
+		return `apiVersion: v1
+kind: Config
+current-context: ` + current + `
+clusters:
+- name: prod
+  cluster:
+    server: ` + server + `
+    certificate-authority: ca.crt
+contexts:
+- name: prod
+  context:
+    cluster: ` + cluster + `
+    user: ` + user + `
+- name: dev
+  context:
+    cluster: prod
+users:
+- name: admin
+  user:
+` + userFields
+	}
+
+	testCases := []struct {
+		name        string
+		config      string
+		kubeContext string
+		expected    string
+		notFound    bool
+	}{
+		{
+			"Reports a current-context that no longer exists",
+			kubeconfig("staging", "https://prod.example.com", "prod", "admin", "    token: secret\n"),
+			"",
+			"context \"staging\" does not exist in the kubeconfig loaded from %s; available contexts: dev, prod",
+			true,
+		},
+		{
+			"Reports a kubeconfig without a current-context",
+			kubeconfig(`""`, "https://prod.example.com", "prod", "admin", "    token: secret\n"),
+			"",
+			"no context is selected and the kubeconfig loaded from %s does not set a current-context; available contexts: dev, prod",
+			true,
+		},
+		{
+			"Reports a missing cluster",
+			kubeconfig("prod", "https://prod.example.com", "staging", "admin", "    token: secret\n"),
+			"",
+			"context \"prod\" of the kubeconfig loaded from %s is invalid: cluster \"staging\" does not exist",
+			false,
+		},
+		{
+			"Reports a missing user",
+			kubeconfig("prod", "https://prod.example.com", "prod", "root", "    token: secret\n"),
+			"",
+			"context \"prod\" of the kubeconfig loaded from %s is invalid: user \"root\" does not exist",
+			false,
+		},
+		{
+			"Reports a client certificate that cannot be read",
+			kubeconfig("prod", "https://prod.example.com", "prod", "admin", "    client-certificate: missing.crt\n    client-key: missing.key\n"),
+			"",
+			"context \"prod\" of the kubeconfig loaded from %s is invalid: the client-certificate of user \"admin\" cannot be read: open " + filepath.Join(dir, "missing.crt") + ": no such file or directory",
+			false,
+		},
+		{
+			"Reports a malformed server URL",
+			kubeconfig("prod", "https://", "prod", "admin", "    token: secret\n"),
+			"",
+			"context \"prod\" of the kubeconfig loaded from %s is invalid: the server of cluster \"prod\" is not a valid URL: \"https://\"",
+			false,
+		},
+		{
+			"Validates the requested context rather than the current one",
+			kubeconfig("prod", "https://prod.example.com", "staging", "admin", "    token: secret\n"),
+			"dev",
+			"",
+			false,
+		},
+		{
+			"Accepts a server without a scheme and files relative to the kubeconfig",
+			kubeconfig("prod", "prod.example.com:6443", "prod", "admin", "    token: secret\n"),
+			"",
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			path := write(t, tc.config)
+
+			_, _, err := getConfig(path, tc.kubeContext)
+			if tc.expected == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("Expected error, got nothing")
+			}
+			if expected := strings.Replace(tc.expected, "%s", path, 1); err.Error() != expected {
+				t.Fatalf("Expected error [%s] got [%s]", expected, err)
+			}
+			if IsContextNotFound(err) != tc.notFound || IsInvalidKubeconfig(err) == tc.notFound {
+				t.Fatalf("Unexpected error type %T", err)
+			}
+		})
+	}
+}
+
+func TestValidateServer(t *testing.T) {
+	for _, server := range []string{"https://10.0.0.1", "http://localhost:8080", "10.0.0.1:6443"} {
+		if err := validateServer(server); err != nil {
+			t.Fatalf("Unexpected error for %s: %s", server, err)
+		}
+	}
+
+	for server, expected := range map[string]string{
+		"":                   "is not set",
+		"https://":           "is not a valid URL: \"https://\"",
+		"ftp://10.0.0.1":     "has an unsupported scheme: \"ftp://10.0.0.1\"",
+		"https://10.0.0.1:x": "is not a valid URL: \"https://10.0.0.1:x\"",
+	} {
+		err := validateServer(server)
+		if err == nil {
+			t.Fatalf("Expected error for %s, got nothing", server)
+		}
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s] for %s, got [%s]", expected, server, err)
+		}
+	}
+}
@@ -50,6 +50,10 @@ const (
 	// StatefulSet that this proxy belongs to.
 	ProxyStatefulSetLabel = "linkerd.io/proxy-statefulset"
 
+	// ExtensionLabel is set on the namespace of an installed Linkerd extension,
+	// with the extension's name as its value (e.g. "viz").
+	ExtensionLabel = "linkerd.io/extension"
+
 	/*
 	 * Annotations
 	 */
@@ -62,6 +66,22 @@ const (
 	// (e.g. v0.1.3).
 	ProxyVersionAnnotation = "linkerd.io/proxy-version"
 
+	// ProxySkipInboundPortsAnnotation lists the inbound ports and port ranges
+	// (e.g. "25,8000-8080") that should bypass the proxy.
+	ProxySkipInboundPortsAnnotation = "config.linkerd.io/skip-inbound-ports"
+
+	// ProxySkipOutboundPortsAnnotation lists the outbound ports and port ranges
+	// that should bypass the proxy.
+	ProxySkipOutboundPortsAnnotation = "config.linkerd.io/skip-outbound-ports"
+
+	// ProxyOpaquePortsAnnotation lists the ports and port ranges on which the
+	// proxy should not attempt protocol detection.
+	ProxyOpaquePortsAnnotation = "config.linkerd.io/opaque-ports"
+
+	// ProxyTraceCollectorAnnotation is the address of the trace collector the
+	// proxies of a namespace or workload export spans to.
+	ProxyTraceCollectorAnnotation = "config.linkerd.io/trace-collector"
+
 	// ProxyAutoInjectLabel indicates if sidecar auto-inject should be performed
 	// on the pod. Supported values are "enabled", "disabled" or "completed".
 	ProxyAutoInjectLabel = "linkerd.io/auto-inject"
@@ -192,6 +212,37 @@ func GetPodLabels(ownerKind, ownerName string, pod *coreV1.Pod) map[string]strin
 	return labels
 }
 
+// AutoInjectSetting returns the effective value of the ProxyAutoInjectLabel
+// for a workload, along with the kind of resource it was taken from
+// ("namespace" or "workload"), as applied by the proxy injector. A namespace
+// labeled as disabled is excluded from the injector's webhook entirely, so it
+// takes precedence over the workload's pod template; otherwise a label on the
+// pod template takes precedence over a label on its namespace. If neither is
+// labeled, both return values are empty.
+func AutoInjectSetting(namespaceLabels, workloadLabels map[string]string) (string, string) {
+	if namespaceLabels[ProxyAutoInjectLabel] == ProxyAutoInjectDisabled {
+		return ProxyAutoInjectDisabled, "namespace"
+	}
+	if setting, ok := workloadLabels[ProxyAutoInjectLabel]; ok {
+		return setting, "workload"
+	}
+	if setting, ok := namespaceLabels[ProxyAutoInjectLabel]; ok {
+		return setting, "namespace"
+	}
+	return "", ""
+}
+
+// AutoInjected returns true if the proxy injector injects a Deployment whose
+// pod template has the given labels, in a namespace with the given labels.
+// The injector's webhook is called for all namespaces not labeled as
+// disabled, so such workloads are injected, even in unlabeled namespaces,
+// unless their pod template is labeled as disabled, or as completed once
+// already injected.
+func AutoInjected(namespaceLabels, workloadLabels map[string]string) bool {
+	setting, _ := AutoInjectSetting(namespaceLabels, workloadLabels)
+	return setting != ProxyAutoInjectDisabled && setting != ProxyAutoInjectCompleted
+}
+
 func IsMeshed(pod *coreV1.Pod, controllerNS string) bool {
 	return pod.Labels[ControllerNSLabel] == controllerNS
 }
@@ -38,3 +38,40 @@ func TestGetPodLabels(t *testing.T) {
 		}
 	})
 }
+
+func TestAutoInjectSetting(t *testing.T) {
+	labels := func(setting string) map[string]string {
+		if setting == "" {
+			return map[string]string{}
+		}
+		return map[string]string{ProxyAutoInjectLabel: setting}
+	}
+
+	testCases := []struct {
+		namespace      string
+		workload       string
+		expectedValue  string
+		expectedSource string
+	}{
+		{"", "", "", ""},
+		{"enabled", "", "enabled", "namespace"},
+		{"", "enabled", "enabled", "workload"},
+		{"enabled", "disabled", "disabled", "workload"},
+		{"enabled", "completed", "completed", "workload"},
+		{"disabled", "enabled", "disabled", "namespace"},
+		{"disabled", "", "disabled", "namespace"},
+	}
+
+	for _, tc := range testCases {
+		value, source := AutoInjectSetting(labels(tc.namespace), labels(tc.workload))
+		if value != tc.expectedValue || source != tc.expectedSource {
+			t.Fatalf("Expected (%q, %q) for namespace %q and workload %q, got (%q, %q)",
+				tc.expectedValue, tc.expectedSource, tc.namespace, tc.workload, value, source)
+		}
+
+		expectedInjected := value != "disabled" && value != "completed"
+		if injected := AutoInjected(labels(tc.namespace), labels(tc.workload)); injected != expectedInjected {
+			t.Fatalf("Expected AutoInjected to be %t for namespace %q and workload %q", expectedInjected, tc.namespace, tc.workload)
+		}
+	}
+}
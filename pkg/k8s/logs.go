@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPodLogLimitBytes caps the logs read by GetPodLogs, unless the
+// PodLogOptions' LimitBytes is set.
+const DefaultPodLogLimitBytes = 1 << 20
+
+// PodLogOptions selects the logs returned by GetPodLogs. Zero values are left
+// to the API server's defaults.
+type PodLogOptions struct {
+	// TailLines is the number of lines read from the end of the logs.
+	TailLines int64
+
+	// SinceSeconds only selects the lines logged in the last SinceSeconds.
+	SinceSeconds int64
+
+	// LimitBytes caps the bytes read, whether or not the API server honors
+	// it. Defaults to DefaultPodLogLimitBytes.
+	LimitBytes int64
+
+	// Previous selects the logs of the previous instance of the container,
+	// such as one that is crash-looping.
+	Previous bool
+}
+
+// ContainerNotFoundError is returned by GetPodLogs when the pod has no such
+// container, or when the container has no previous instance.
+type ContainerNotFoundError struct {
+	Namespace string
+	Pod       string
+	Container string
+	Previous  bool
+	Message   string
+}
+
+func (e *ContainerNotFoundError) Error() string {
+	if e.Previous {
+		return fmt.Sprintf("The \"%s\" container of the \"%s/%s\" pod has no previous instance: %s", e.Container, e.Namespace, e.Pod, e.Message)
+	}
+	return fmt.Sprintf("The \"%s/%s\" pod has no \"%s\" container: %s", e.Namespace, e.Pod, e.Container, e.Message)
+}
+
+// ContainerNotReadyError is returned by GetPodLogs when the container has not
+// started yet, so that it has no logs.
+type ContainerNotReadyError struct {
+	Namespace string
+	Pod       string
+	Container string
+	Message   string
+}
+
+func (e *ContainerNotReadyError) Error() string {
+	return fmt.Sprintf("The \"%s\" container of the \"%s/%s\" pod is not ready for logs: %s", e.Container, e.Namespace, e.Pod, e.Message)
+}
+
+// IsContainerNotFound returns true if the error is a ContainerNotFoundError.
+func IsContainerNotFound(err error) bool {
+	_, ok := err.(*ContainerNotFoundError)
+	return ok
+}
+
+// IsContainerNotReady returns true if the error is a ContainerNotReadyError.
+func IsContainerNotReady(err error) bool {
+	_, ok := err.(*ContainerNotReadyError)
+	return ok
+}
+
+// GetPodLogs returns the logs of the given container selected by the options.
+// The response is read until the options' LimitBytes, at which point the
+// stream is closed rather than drained. A ContainerNotFoundError or a
+// ContainerNotReadyError is returned if the container has no logs to read,
+// and a ForbiddenError if reading them is not permitted.
+func (kubeAPI *KubernetesAPI) GetPodLogs(ctx context.Context, namespace, pod, container string, opts PodLogOptions) ([]byte, error) {
+	ctx, cancel := kubeAPI.requestContext(ctx)
+	defer cancel()
+
+	limitBytes := opts.LimitBytes
+	if limitBytes <= 0 {
+		limitBytes = DefaultPodLogLimitBytes
+	}
+
+	query := url.Values{}
+	query.Set("container", container)
+	query.Set("limitBytes", strconv.FormatInt(limitBytes, 10))
+	if opts.TailLines > 0 {
+		query.Set("tailLines", strconv.FormatInt(opts.TailLines, 10))
+	}
+	if opts.SinceSeconds > 0 {
+		query.Set("sinceSeconds", strconv.FormatInt(opts.SinceSeconds, 10))
+	}
+	if opts.Previous {
+		query.Set("previous", "true")
+	}
+
+	path := PathWithQuery(ResourcePath("v1", "pods/log", namespace, pod), query)
+	rsp, err := kubeAPI.getRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusBadRequest {
+		return nil, podLogsError(rsp, namespace, pod, container, opts.Previous)
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(rsp)
+	}
+
+	return ioutil.ReadAll(io.LimitReader(rsp.Body, limitBytes))
+}
+
+// podLogsError classifies the bad request returned when a container has no
+// logs to read, from the messages of the API server and the kubelet.
+func podLogsError(rsp *http.Response, namespace, pod, container string, previous bool) error {
+	message := responseMessage(rsp)
+
+	switch {
+	case strings.Contains(message, "is not valid for pod"),
+		strings.Contains(message, "a container name must be specified"),
+		previous && strings.Contains(message, "not found"):
+		return &ContainerNotFoundError{Namespace: namespace, Pod: pod, Container: container, Previous: previous, Message: message}
+	case strings.Contains(message, "is waiting to start"),
+		strings.Contains(message, "is not available"),
+		strings.Contains(message, "does not have a host assigned"):
+		return &ContainerNotReadyError{Namespace: namespace, Pod: pod, Container: container, Message: message}
+	}
+
+	return &StatusError{StatusCode: rsp.StatusCode, Status: rsp.Status, Message: message}
+}
@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestGetPodLogs(t *testing.T) {
+	newAPI := func(handler http.HandlerFunc) (*KubernetesAPI, func()) {
+		server := httptest.NewServer(handler)
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, MaxRequestAttempts: 1}
+		return api, server.Close
+	}
+
+	t.Run("Passes the options as query parameters", func(t *testing.T) {
+		var query string
+		api, done := newAPI(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/namespaces/linkerd/pods/web-1/log" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			query = r.URL.RawQuery
+			w.Write([]byte("log line\n"))
+		})
+		defer done()
+
+		logs, err := api.GetPodLogs(context.Background(), "linkerd", "web-1", "linkerd-proxy", PodLogOptions{TailLines: 100, SinceSeconds: 60, Previous: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if string(logs) != "log line\n" {
+			t.Fatalf("Unexpected logs: %q", logs)
+		}
+
+		expected := "container=linkerd-proxy&limitBytes=1048576&previous=true&sinceSeconds=60&tailLines=100"
+		if query != expected {
+			t.Fatalf("Expected query [%s], got [%s]", expected, query)
+		}
+	})
+
+	t.Run("Stops reading the stream at the byte cap", func(t *testing.T) {
+		closed := make(chan struct{})
+		api, done := newAPI(func(w http.ResponseWriter, r *http.Request) {
+			defer close(closed)
+			chunk := bytes.Repeat([]byte("x"), 1024)
+			// ignore limitBytes, streaming until the client goes away
+			for {
+				if _, err := w.Write(chunk); err != nil {
+					return
+				}
+				w.(http.Flusher).Flush()
+				select {
+				case <-r.Context().Done():
+					return
+				default:
+				}
+			}
+		})
+		defer done()
+
+		logs, err := api.GetPodLogs(context.Background(), "linkerd", "web-1", "linkerd-proxy", PodLogOptions{LimitBytes: 4096 + 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(logs) != 4096+10 {
+			t.Fatalf("Expected %d bytes, got %d", 4096+10, len(logs))
+		}
+
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the stream to be closed once the cap was reached")
+		}
+	})
+
+	testCases := []struct {
+		name     string
+		status   int
+		message  string
+		previous bool
+		check    func(error) bool
+	}{
+		{"Returns a ContainerNotFoundError for an unknown container", http.StatusBadRequest, `container linkerd-proxy is not valid for pod web-1`, false, IsContainerNotFound},
+		{"Returns a ContainerNotFoundError without a previous instance", http.StatusBadRequest, `previous terminated container \"linkerd-proxy\" in pod \"web-1\" not found`, true, IsContainerNotFound},
+		{"Returns a ContainerNotReadyError for a waiting container", http.StatusBadRequest, `container \"linkerd-proxy\" in pod \"web-1\" is waiting to start: ContainerCreating`, false, IsContainerNotReady},
+		{"Returns a ContainerNotReadyError for an unscheduled pod", http.StatusBadRequest, `pod web-1 does not have a host assigned`, false, IsContainerNotReady},
+		{"Returns a ForbiddenError when denied", http.StatusForbidden, `pods \"web-1\" is forbidden: User \"jane\" cannot get pods/log in the namespace \"linkerd\"`, false, IsForbidden},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			api, done := newAPI(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","message":"` + tc.message + `"}`))
+			})
+			defer done()
+
+			_, err := api.GetPodLogs(context.Background(), "linkerd", "web-1", "linkerd-proxy", PodLogOptions{Previous: tc.previous})
+			if !tc.check(err) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeRoleLabelPrefix prefixes the labels naming a node's roles, such as
+// "node-role.kubernetes.io/master".
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// NodeInfo summarizes the state of a node relevant to scheduling the control
+// plane and the data plane.
+type NodeInfo struct {
+	Name   string
+	Labels map[string]string
+
+	// Ready is true if the node's Ready condition is True. LastHeartbeatTime
+	// is the last time the kubelet reported the condition, and is zero if it
+	// never did.
+	Ready             bool
+	ReadyMessage      string
+	LastHeartbeatTime time.Time
+
+	Unschedulable  bool
+	Taints         []v1.Taint
+	KubeletVersion string
+
+	// AllocatableMilliCPU and AllocatableMemoryBytes are the resources
+	// available to pods, in thousandths of a CPU and in bytes.
+	AllocatableMilliCPU    int64
+	AllocatableMemoryBytes int64
+}
+
+// Roles returns the sorted roles of the node, read from its
+// "node-role.kubernetes.io/<role>" and "kubernetes.io/role" labels.
+func (n NodeInfo) Roles() []string {
+	roles := map[string]bool{}
+	for label, value := range n.Labels {
+		if strings.HasPrefix(label, nodeRoleLabelPrefix) {
+			if role := strings.TrimPrefix(label, nodeRoleLabelPrefix); role != "" {
+				roles[role] = true
+			}
+		}
+		if label == "kubernetes.io/role" && value != "" {
+			roles[value] = true
+		}
+	}
+
+	sorted := []string{}
+	for role := range roles {
+		sorted = append(sorted, role)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// NewNodeInfo summarizes the given node.
+func NewNodeInfo(node v1.Node) NodeInfo {
+	info := NodeInfo{
+		Name:           node.Name,
+		Labels:         node.Labels,
+		Unschedulable:  node.Spec.Unschedulable,
+		Taints:         node.Spec.Taints,
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			info.Ready = condition.Status == v1.ConditionTrue
+			info.ReadyMessage = condition.Message
+			info.LastHeartbeatTime = condition.LastHeartbeatTime.Time
+		}
+	}
+
+	if cpu, ok := node.Status.Allocatable[v1.ResourceCPU]; ok {
+		info.AllocatableMilliCPU = cpu.MilliValue()
+	}
+	if memory, ok := node.Status.Allocatable[v1.ResourceMemory]; ok {
+		info.AllocatableMemoryBytes = memory.Value()
+	}
+
+	return info
+}
+
+// GetNodes returns a summary of each node in the cluster.
+func (kubeAPI *KubernetesAPI) GetNodes(ctx context.Context) ([]NodeInfo, error) {
+	nodes := []NodeInfo{}
+	err := kubeAPI.getPagedList(ctx, ClusterResourcePath("nodes", ""), url.Values{}, func() metav1.ListInterface {
+		return &v1.NodeList{}
+	}, func(page metav1.ListInterface) {
+		for _, node := range page.(*v1.NodeList).Items {
+			nodes = append(nodes, NewNodeInfo(node))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// ParseMilliCPU parses a CPU quantity, such as "500m" or "2", into thousandths
+// of a CPU, rounding up.
+func ParseMilliCPU(quantity string) (int64, error) {
+	q, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU quantity \"%s\": %s", quantity, err)
+	}
+	return q.MilliValue(), nil
+}
+
+// ParseMemoryBytes parses a memory quantity, such as "128Mi", "1Gi" or "1G",
+// into bytes, rounding up.
+func ParseMemoryBytes(quantity string) (int64, error) {
+	q, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity \"%s\": %s", quantity, err)
+	}
+	return q.Value(), nil
+}
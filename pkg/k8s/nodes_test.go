@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetNodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/nodes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"kind":"NodeList","metadata":{},"items":[
+			{
+				"metadata":{"name":"node-a","labels":{"node-role.kubernetes.io/master":"","kubernetes.io/role":"etcd"}},
+				"spec":{"unschedulable":true,"taints":[{"key":"node-role.kubernetes.io/master","effect":"NoSchedule"}]},
+				"status":{
+					"conditions":[
+						{"type":"MemoryPressure","status":"False"},
+						{"type":"Ready","status":"True","lastHeartbeatTime":"2019-03-01T10:00:00Z","message":"kubelet is posting ready status"}
+					],
+					"allocatable":{"cpu":"1930m","memory":"5951Mi"},
+					"nodeInfo":{"kubeletVersion":"v1.13.4"}
+				}
+			},
+			{"metadata":{"name":"node-b"},"status":{"conditions":[{"type":"Ready","status":"Unknown"}]}}
+		]}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+	nodes, err := api.GetNodes(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("Expected 2 nodes, got %+v", nodes)
+	}
+
+	a := nodes[0]
+	if a.Name != "node-a" || !a.Ready || a.ReadyMessage != "kubelet is posting ready status" ||
+		!a.LastHeartbeatTime.Equal(time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC)) ||
+		!a.Unschedulable || a.KubeletVersion != "v1.13.4" ||
+		a.AllocatableMilliCPU != 1930 || a.AllocatableMemoryBytes != 5951*1024*1024 {
+		t.Fatalf("Unexpected node: %+v", a)
+	}
+	if len(a.Taints) != 1 || a.Taints[0].Effect != v1.TaintEffectNoSchedule {
+		t.Fatalf("Unexpected taints: %+v", a.Taints)
+	}
+	if roles := a.Roles(); !reflect.DeepEqual(roles, []string{"etcd", "master"}) {
+		t.Fatalf("Unexpected roles: %v", roles)
+	}
+
+	b := nodes[1]
+	if b.Ready || !b.LastHeartbeatTime.IsZero() || b.AllocatableMilliCPU != 0 || len(b.Roles()) != 0 {
+		t.Fatalf("Unexpected node: %+v", b)
+	}
+}
+
+func TestParseQuantities(t *testing.T) {
+	cpu := []struct {
+		quantity string
+		expected int64
+	}{
+		{"500m", 500},
+		{"2", 2000},
+		{"0.5", 500},
+		{"1500u", 2},
+	}
+	for _, tc := range cpu {
+		actual, err := ParseMilliCPU(tc.quantity)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing [%s]: %s", tc.quantity, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("Expected [%s] to be %dm, got %dm", tc.quantity, tc.expected, actual)
+		}
+	}
+
+	memory := []struct {
+		quantity string
+		expected int64
+	}{
+		{"512", 512},
+		{"64Ki", 64 * 1024},
+		{"128Mi", 128 * 1024 * 1024},
+		{"2Gi", 2 * 1024 * 1024 * 1024},
+		{"1G", 1000 * 1000 * 1000},
+		{"1500m", 2},
+	}
+	for _, tc := range memory {
+		actual, err := ParseMemoryBytes(tc.quantity)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing [%s]: %s", tc.quantity, err)
+		}
+		if actual != tc.expected {
+			t.Fatalf("Expected [%s] to be %d bytes, got %d", tc.quantity, tc.expected, actual)
+		}
+	}
+
+	for _, invalid := range []string{"", "12Mb", "one"} {
+		if _, err := ParseMilliCPU(invalid); err == nil {
+			t.Fatalf("Expected an error parsing [%s]", invalid)
+		}
+		if _, err := ParseMemoryBytes(invalid); err == nil {
+			t.Fatalf("Expected an error parsing [%s]", invalid)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// openShiftGroupVersions are API group versions only served by OpenShift
+// clusters.
+var openShiftGroupVersions = []string{
+	"security.openshift.io/v1",
+	"route.openshift.io/v1",
+}
+
+// securityContextConstraintsPath lists OpenShift's SecurityContextConstraints,
+// which take the place of PodSecurityPolicies.
+const securityContextConstraintsPath = "/apis/security.openshift.io/v1/securitycontextconstraints"
+
+// IsOpenShift returns true if the API server serves any of the OpenShift API
+// groups.
+func (kubeAPI *KubernetesAPI) IsOpenShift(ctx context.Context) (bool, error) {
+	for _, groupVersion := range openShiftGroupVersions {
+		served, err := kubeAPI.ServesGroupVersion(ctx, groupVersion)
+		if err != nil {
+			return false, err
+		}
+		if served {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SecurityContextConstraints holds the fields of an OpenShift
+// SecurityContextConstraints needed to tell whether the pods of a service
+// account may use it, and with which capabilities.
+type SecurityContextConstraints struct {
+	Name                     string
+	AllowPrivilegedContainer bool
+	AllowedCapabilities      []string
+	DefaultAddCapabilities   []string
+	Users                    []string
+	Groups                   []string
+}
+
+// NewSecurityContextConstraints reads the SecurityContextConstraints from the
+// given object.
+func NewSecurityContextConstraints(item unstructured.Unstructured) (SecurityContextConstraints, error) {
+	scc := SecurityContextConstraints{Name: item.GetName()}
+
+	var err error
+	if scc.AllowPrivilegedContainer, _, err = unstructured.NestedBool(item.Object, "allowPrivilegedContainer"); err != nil {
+		return scc, fmt.Errorf("invalid SecurityContextConstraints \"%s\": %s", scc.Name, err)
+	}
+	fields := []struct {
+		name  string
+		value *[]string
+	}{
+		{"allowedCapabilities", &scc.AllowedCapabilities},
+		{"defaultAddCapabilities", &scc.DefaultAddCapabilities},
+		{"users", &scc.Users},
+		{"groups", &scc.Groups},
+	}
+	for _, f := range fields {
+		// OpenShift serializes empty lists as null
+		if item.Object[f.name] == nil {
+			continue
+		}
+		if *f.value, _, err = unstructured.NestedStringSlice(item.Object, f.name); err != nil {
+			return scc, fmt.Errorf("invalid SecurityContextConstraints \"%s\": %s", scc.Name, err)
+		}
+	}
+
+	return scc, nil
+}
+
+// PermitsCapability returns true if containers may add the given capability,
+// such as "NET_ADMIN", under the SecurityContextConstraints.
+func (scc SecurityContextConstraints) PermitsCapability(capability string) bool {
+	if scc.AllowPrivilegedContainer {
+		return true
+	}
+	for _, allowed := range append(scc.AllowedCapabilities, scc.DefaultAddCapabilities...) {
+		if allowed == capability || allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// UsableBy returns true if the SecurityContextConstraints lists the given
+// service account, or a group it belongs to, among its users and groups.
+// Access granted through RBAC's "use" verb is not considered.
+func (scc SecurityContextConstraints) UsableBy(namespace, serviceAccount string) bool {
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+	for _, u := range scc.Users {
+		if u == user {
+			return true
+		}
+	}
+
+	groups := map[string]bool{
+		"system:authenticated":                              true,
+		"system:serviceaccounts":                            true,
+		fmt.Sprintf("system:serviceaccounts:%s", namespace): true,
+	}
+	for _, g := range scc.Groups {
+		if groups[g] {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSecurityContextConstraints returns the cluster's
+// SecurityContextConstraints, or nil if the cluster doesn't serve them.
+func (kubeAPI *KubernetesAPI) GetSecurityContextConstraints(ctx context.Context) ([]SecurityContextConstraints, error) {
+	list, err := kubeAPI.GetUnstructuredList(ctx, securityContextConstraintsPath)
+	if err != nil || list == nil {
+		return nil, err
+	}
+
+	sccs := []SecurityContextConstraints{}
+	for _, item := range list.Items {
+		scc, err := NewSecurityContextConstraints(item)
+		if err != nil {
+			return nil, err
+		}
+		sccs = append(sccs, scc)
+	}
+	return sccs, nil
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestIsOpenShift(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		groups   string
+		expected bool
+		err      bool
+	}{
+		{"Detects the OpenShift security API", http.StatusOK, `{"name":"security.openshift.io","versions":[{"groupVersion":"security.openshift.io/v1","version":"v1"}]}`, true, false},
+		{"Detects the OpenShift route API", http.StatusOK, `{"name":"route.openshift.io","versions":[{"groupVersion":"route.openshift.io/v1","version":"v1"}]}`, true, false},
+		{"Reports vanilla Kubernetes", http.StatusOK, `{"name":"apps","versions":[{"groupVersion":"apps/v1","version":"v1"}]}`, false, false},
+		{"Returns an error if the API groups cannot be discovered", http.StatusForbidden, "", false, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/apis" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"kind":"APIGroupList","groups":[` + tc.groups + `]}`))
+			}))
+			defer server.Close()
+
+			api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+			openShift, err := api.IsOpenShift(context.Background())
+			if tc.err != (err != nil) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if openShift != tc.expected {
+				t.Fatalf("Expected IsOpenShift to be %t, got %t", tc.expected, openShift)
+			}
+		})
+	}
+}
+
+func TestGetSecurityContextConstraints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != securityContextConstraintsPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"kind":"SecurityContextConstraintsList","apiVersion":"security.openshift.io/v1","metadata":{},"items":[
+			{"kind":"SecurityContextConstraints","apiVersion":"security.openshift.io/v1","metadata":{"name":"privileged"},
+				"allowPrivilegedContainer":true,"users":["system:admin"],"groups":["system:cluster-admins"]},
+			{"kind":"SecurityContextConstraints","apiVersion":"security.openshift.io/v1","metadata":{"name":"linkerd"},
+				"allowPrivilegedContainer":false,"allowedCapabilities":["NET_ADMIN","NET_RAW"],"users":["system:serviceaccount:linkerd:linkerd-controller"]},
+			{"kind":"SecurityContextConstraints","apiVersion":"security.openshift.io/v1","metadata":{"name":"restricted"},
+				"allowPrivilegedContainer":false,"allowedCapabilities":null,"groups":["system:authenticated"]}]}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+	sccs, err := api.GetSecurityContextConstraints(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(sccs) != 3 {
+		t.Fatalf("Expected 3 SecurityContextConstraints, got %d", len(sccs))
+	}
+
+	testCases := []struct {
+		scc             SecurityContextConstraints
+		permitsNetAdmin bool
+		usableBy        map[string]bool
+	}{
+		{sccs[0], true, map[string]bool{"linkerd-controller": false}},
+		{sccs[1], true, map[string]bool{"linkerd-controller": true, "linkerd-web": false}},
+		{sccs[2], false, map[string]bool{"linkerd-controller": true}},
+	}
+	for _, tc := range testCases {
+		if permits := tc.scc.PermitsCapability("NET_ADMIN"); permits != tc.permitsNetAdmin {
+			t.Fatalf("Expected %s to permit NET_ADMIN to be %t, got %t", tc.scc.Name, tc.permitsNetAdmin, permits)
+		}
+		for serviceAccount, expected := range tc.usableBy {
+			if usable := tc.scc.UsableBy("linkerd", serviceAccount); usable != expected {
+				t.Fatalf("Expected %s to be usable by %s to be %t, got %t", tc.scc.Name, serviceAccount, expected, usable)
+			}
+		}
+	}
+
+	t.Run("Returns nothing if the cluster doesn't serve SecurityContextConstraints", func(t *testing.T) {
+		vanilla := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer vanilla.Close()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: vanilla.URL}}
+		sccs, err := api.GetSecurityContextConstraints(context.Background())
+		if err != nil || sccs != nil {
+			t.Fatalf("Expected no SecurityContextConstraints, got %v, %v", sccs, err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"net/url"
+	"strings"
+
+	"k8s.io/api/core/v1"
+)
+
+const (
+	proxyControlURLEnvVarName = "LINKERD2_PROXY_CONTROL_URL"
+	proxyAPIServiceName       = "proxy-api"
+)
+
+// GetProxyContainer returns the proxy container of the given pod spec, or nil
+// if the pod has not been injected.
+func GetProxyContainer(podSpec *v1.PodSpec) *v1.Container {
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == ProxyContainerName {
+			return &podSpec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// GetProxyControlPlaneNamespace returns the namespace of the control plane
+// that the pod's proxy reports to. The ControllerNSLabel is used if present;
+// otherwise the namespace is derived from the proxy's control URL. An empty
+// string is returned if the pod has no proxy, or if the proxy is configured to
+// reach the control plane over localhost (as control plane components are).
+func GetProxyControlPlaneNamespace(pod *v1.Pod) string {
+	if ns := pod.Labels[ControllerNSLabel]; ns != "" {
+		return ns
+	}
+
+	proxy := GetProxyContainer(&pod.Spec)
+	if proxy == nil {
+		return ""
+	}
+
+	for _, env := range proxy.Env {
+		if env.Name != proxyControlURLEnvVarName {
+			continue
+		}
+
+		controlURL, err := url.Parse(env.Value)
+		if err != nil {
+			return ""
+		}
+
+		// the control URL has the form tcp://proxy-api.<namespace>.svc.cluster.local:<port>
+		labels := strings.Split(controlURL.Hostname(), ".")
+		if len(labels) > 1 && labels[0] == proxyAPIServiceName {
+			return labels[1]
+		}
+	}
+
+	return ""
+}
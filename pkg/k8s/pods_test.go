@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetProxyControlPlaneNamespace(t *testing.T) {
+	proxy := func(controlURL string) v1.Container {
+		return v1.Container{
+			Name: ProxyContainerName,
+			Env: []v1.EnvVar{
+				{Name: "LINKERD2_PROXY_LOG", Value: "warn,linkerd2_proxy=info"},
+				{Name: "LINKERD2_PROXY_CONTROL_URL", Value: controlURL},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		pod      v1.Pod
+		expected string
+	}{
+		{
+			name: "uses the control plane namespace label",
+			pod: v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{ControllerNSLabel: "linkerd"}},
+				Spec: v1.PodSpec{Containers: []v1.Container{
+					proxy("tcp://proxy-api.other.svc.cluster.local:8086"),
+				}},
+			},
+			expected: "linkerd",
+		},
+		{
+			name: "falls back to the proxy control URL",
+			pod: v1.Pod{
+				Spec: v1.PodSpec{Containers: []v1.Container{
+					{Name: "app"},
+					proxy("tcp://proxy-api.linkerd-test.svc.cluster.local:8086"),
+				}},
+			},
+			expected: "linkerd-test",
+		},
+		{
+			name: "ignores proxies that reach the control plane over localhost",
+			pod: v1.Pod{
+				Spec: v1.PodSpec{Containers: []v1.Container{
+					proxy("tcp://localhost.:8086"),
+				}},
+			},
+			expected: "",
+		},
+		{
+			name: "ignores pods without a proxy",
+			pod: v1.Pod{
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := GetProxyControlPlaneNamespace(&tc.pod)
+			if ns != tc.expected {
+				t.Fatalf("Expected namespace [%s], got [%s]", tc.expected, ns)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForward forwards a free local port to the remote port of the named pod,
+// and returns the local address, e.g. "127.0.0.1:40123", once the forward is
+// established. The forward runs until the returned func is called or the
+// context is canceled.
+func (kubeAPI *KubernetesAPI) PortForward(ctx context.Context, namespace, podName string, remotePort int) (string, func(), error) {
+	localPort, err := freeLocalPort()
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to find a free local port: %s", err)
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(kubeAPI.Config)
+	if err != nil {
+		return "", nil, fmt.Errorf("error instantiating port-forward client: %v", err)
+	}
+
+	endpoint, err := kubeAPI.UrlForGVR("v1", "pods/portforward", namespace, podName)
+	if err != nil {
+		return "", nil, err
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", endpoint)
+
+	stop := make(chan struct{})
+	ready := make(chan struct{})
+	errOut := &syncBuffer{}
+	ports := []string{fmt.Sprintf("%d:%d", localPort, remotePort)}
+	forwarder, err := portforward.New(dialer, ports, stop, ready, nil, errOut)
+	if err != nil {
+		return "", nil, err
+	}
+
+	failed := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := forwarder.ForwardPorts(); err != nil {
+			failed <- err
+		}
+	}()
+
+	var once sync.Once
+	stopForward := func() {
+		once.Do(func() { close(stop) })
+	}
+	closeForward := func() {
+		stopForward()
+		<-done
+	}
+
+	select {
+	case <-ready:
+	case err := <-failed:
+		return "", nil, portForwardError(namespace, podName, remotePort, err, errOut.String())
+	case <-ctx.Done():
+		// the SPDY upgrade cannot be interrupted, so rather than waiting for
+		// it the forwarder is left to stop as soon as it completes
+		stopForward()
+		return "", nil, ctx.Err()
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			closeForward()
+		case <-done:
+		}
+	}()
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)), closeForward, nil
+}
+
+func portForwardError(namespace, podName string, remotePort int, err error, errOut string) error {
+	message := err.Error()
+	if errOut = strings.TrimSpace(errOut); errOut != "" {
+		message = fmt.Sprintf("%s: %s", message, errOut)
+	}
+	return fmt.Errorf("Failed to port-forward to port %d of the \"%s/%s\" pod: %s", remotePort, namespace, podName, message)
+}
+
+// freeLocalPort returns a local port that is not in use.
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, collecting the
+// port-forward's error output.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
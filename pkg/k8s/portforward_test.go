@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestPortForward(t *testing.T) {
+	t.Run("Returns the pod's error if the forward cannot be established", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" || r.URL.Path != "/api/v1/namespaces/linkerd/pods/linkerd-controller-1/portforward" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("pod linkerd-controller-1 is not running"))
+		}))
+		defer server.Close()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+		_, _, err := api.PortForward(context.Background(), "linkerd", "linkerd-controller-1", 9995)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if !strings.HasPrefix(err.Error(), "Failed to port-forward to port 9995 of the \"linkerd/linkerd-controller-1\" pod: ") ||
+			!strings.Contains(err.Error(), "pod linkerd-controller-1 is not running") {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+
+	t.Run("Returns once the context is canceled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+		defer server.CloseClientConnections()
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := api.PortForward(ctx, "linkerd", "linkerd-controller-1", 9995); err != context.Canceled {
+			t.Fatalf("Expected the context's error, got %v", err)
+		}
+	})
+}
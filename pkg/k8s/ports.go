@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of TCP ports. A single port is represented
+// by a range whose lower and upper bounds are equal.
+type PortRange struct {
+	LowerBound int
+	UpperBound int
+}
+
+// Contains returns true if the given port falls within the range.
+func (pr PortRange) Contains(port int) bool {
+	return port >= pr.LowerBound && port <= pr.UpperBound
+}
+
+func (pr PortRange) String() string {
+	if pr.LowerBound == pr.UpperBound {
+		return strconv.Itoa(pr.LowerBound)
+	}
+	return fmt.Sprintf("%d-%d", pr.LowerBound, pr.UpperBound)
+}
+
+// ParsePortRange parses a single port (e.g. "8080") or an inclusive port
+// range (e.g. "8000-8080").
+func ParsePortRange(spec string) (PortRange, error) {
+	bounds := strings.Split(spec, "-")
+	if len(bounds) > 2 {
+		return PortRange{}, fmt.Errorf("Invalid port range \"%s\"", spec)
+	}
+
+	lower, err := parsePort(bounds[0])
+	if err != nil {
+		return PortRange{}, err
+	}
+
+	upper := lower
+	if len(bounds) == 2 {
+		upper, err = parsePort(bounds[1])
+		if err != nil {
+			return PortRange{}, err
+		}
+		if upper < lower {
+			return PortRange{}, fmt.Errorf("Invalid port range \"%s\": upper bound is lower than lower bound", spec)
+		}
+	}
+
+	return PortRange{LowerBound: lower, UpperBound: upper}, nil
+}
+
+// ParsePortList parses a comma-separated list of ports and port ranges, as
+// accepted by the proxy-init port flags and the skip-ports annotations, e.g.
+// "25,443,8000-8080".
+func ParsePortList(list string) ([]PortRange, error) {
+	ranges := []PortRange{}
+	for _, spec := range strings.Split(list, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			return nil, fmt.Errorf("Invalid port list \"%s\": empty entry", list)
+		}
+
+		pr, err := ParsePortRange(spec)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, pr)
+	}
+	return ranges, nil
+}
+
+func parsePort(port string) (int, error) {
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 1 || p > 65535 {
+		return 0, fmt.Errorf("Invalid port \"%s\": must be a number between 1 and 65535", port)
+	}
+	return p, nil
+}
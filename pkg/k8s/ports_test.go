@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePortList(t *testing.T) {
+	t.Run("Parses ports and port ranges", func(t *testing.T) {
+		ranges, err := ParsePortList("25, 443,8000-8080")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		expected := []PortRange{
+			{LowerBound: 25, UpperBound: 25},
+			{LowerBound: 443, UpperBound: 443},
+			{LowerBound: 8000, UpperBound: 8080},
+		}
+		if !reflect.DeepEqual(ranges, expected) {
+			t.Fatalf("Expected port ranges %v but got %v", expected, ranges)
+		}
+	})
+
+	t.Run("Returns an error for invalid port lists", func(t *testing.T) {
+		testCases := []struct {
+			list string
+			err  string
+		}{
+			{"80,abc", "Invalid port \"abc\": must be a number between 1 and 65535"},
+			{"8080-8000", "Invalid port range \"8080-8000\": upper bound is lower than lower bound"},
+			{"80-90-100", "Invalid port range \"80-90-100\""},
+			{"0", "Invalid port \"0\": must be a number between 1 and 65535"},
+			{"65536", "Invalid port \"65536\": must be a number between 1 and 65535"},
+			{"80,,90", "Invalid port list \"80,,90\": empty entry"},
+			{"", "Invalid port list \"\": empty entry"},
+		}
+
+		for _, tc := range testCases {
+			_, err := ParsePortList(tc.list)
+			if err == nil {
+				t.Fatalf("Expected error for \"%s\", got nil", tc.list)
+			}
+			if err.Error() != tc.err {
+				t.Fatalf("Expected error \"%s\" for \"%s\", got \"%s\"", tc.err, tc.list, err)
+			}
+		}
+	})
+}
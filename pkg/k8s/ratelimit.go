@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultQPS is the sustained rate of requests, per second, made by a
+	// client unless the KubernetesAPI's QPS is set.
+	DefaultQPS = 5
+
+	// DefaultBurst is the number of requests a client may make at once, above
+	// its sustained rate, unless the KubernetesAPI's Burst is set.
+	DefaultBurst = 10
+)
+
+// RateLimitError is returned when a request is held back by the client-side
+// rate limiter until its deadline passes or its context is canceled. Such a
+// request is never sent to the API server.
+type RateLimitError struct {
+	QPS   float32
+	Burst int
+	Err   error
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Request delayed past its deadline by the client-side rate limit of %g requests per second (burst of %d), not by the Kubernetes API server: %s", e.QPS, e.Burst, e.Err)
+}
+
+// IsRateLimited returns true if the error is a RateLimitError, as returned by
+// a client's transport or by its requests.
+func IsRateLimited(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	_, ok := err.(*RateLimitError)
+	return ok
+}
+
+// rateLimitTransport holds each request back until the client-side rate
+// limiter allows it, so that a client doesn't overwhelm a shared API server.
+type rateLimitTransport struct {
+	transport http.RoundTripper
+	limiter   *rate.Limiter
+	qps       float32
+	burst     int
+}
+
+// newRateLimitTransport returns a transport limited to the given QPS and
+// burst, which default to DefaultQPS and DefaultBurst if they are zero. The
+// transport is returned as is if qps is negative.
+func newRateLimitTransport(transport http.RoundTripper, qps float32, burst int) http.RoundTripper {
+	if qps < 0 {
+		return transport
+	}
+	if qps == 0 {
+		qps = DefaultQPS
+	}
+	if burst <= 0 {
+		burst = DefaultBurst
+	}
+
+	return &rateLimitTransport{
+		transport: transport,
+		limiter:   rate.NewLimiter(rate.Limit(qps), burst),
+		qps:       qps,
+		burst:     burst,
+	}
+}
+
+func (rt *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := rt.limiter.Wait(req.Context()); err != nil {
+		return nil, &RateLimitError{QPS: rt.qps, Burst: rt.burst, Err: err}
+	}
+	return rt.transport.RoundTrip(req)
+}
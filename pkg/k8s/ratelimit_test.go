@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	newAPI := func(qps float32, burst int) (*KubernetesAPI, *int32, func()) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.Write([]byte(`{"major":"1","minor":"11","gitVersion":"v1.11.1"}`))
+		}))
+
+		config := &rest.Config{Host: server.URL, QPS: qps, Burst: burst}
+		return &KubernetesAPI{Config: config}, &requests, server.Close
+	}
+
+	t.Run("Lets a burst of requests through", func(t *testing.T) {
+		api, requests, done := newAPI(1, 3)
+		defer done()
+
+		for i := 0; i < 3; i++ {
+			if _, err := api.GetVersionInfo(context.Background()); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		}
+		if *requests != 3 {
+			t.Fatalf("Expected 3 requests, got %d", *requests)
+		}
+	})
+
+	t.Run("Reports requests held back past their deadline", func(t *testing.T) {
+		api, requests, done := newAPI(0.1, 1)
+		defer done()
+		api.RequestTimeout = 100 * time.Millisecond
+
+		if _, err := api.GetVersionInfo(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		_, err := api.GetVersionInfo(context.Background())
+		if !IsRateLimited(err) {
+			t.Fatalf("Expected a RateLimitError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "client-side rate limit of 0.1 requests per second (burst of 1), not by the Kubernetes API server") {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+		if *requests != 1 {
+			t.Fatalf("Expected the throttled request not to be sent, got %d requests", *requests)
+		}
+	})
+
+	t.Run("Is disabled by a negative QPS", func(t *testing.T) {
+		transport := newRateLimitTransport(http.DefaultTransport, -1, 0)
+		if transport != http.DefaultTransport {
+			t.Fatalf("Expected the transport to be unwrapped, got %T", transport)
+		}
+	})
+
+	t.Run("Defaults the QPS and burst", func(t *testing.T) {
+		transport := newRateLimitTransport(http.DefaultTransport, 0, 0).(*rateLimitTransport)
+		if transport.qps != DefaultQPS || transport.burst != DefaultBurst {
+			t.Fatalf("Expected the default QPS and burst, got %g and %d", transport.qps, transport.burst)
+		}
+	})
+
+	t.Run("Returns the context's error once canceled", func(t *testing.T) {
+		transport := newRateLimitTransport(http.DefaultTransport, 0.1, 1)
+		req, _ := http.NewRequest("GET", "http://127.0.0.1:1/", nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// the first request passes the limiter, then fails to connect
+		transport.RoundTrip(req)
+		if _, err := transport.RoundTrip(req.WithContext(ctx)); !IsRateLimited(err) {
+			t.Fatalf("Expected a RateLimitError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequestRecord describes a request sent to the Kubernetes API server. Retried
+// requests are recorded once per attempt.
+type RequestRecord struct {
+	Method string
+
+	// URL is the request's path and query, with the values of the query
+	// parameters that may hold tokens redacted.
+	URL string
+
+	// Pattern is the request's path with the namespace and name replaced by
+	// placeholders, e.g. "/api/v1/namespaces/{namespace}/pods/{name}/log".
+	Pattern string
+
+	// StatusCode is the response's status, or 0 if no response was received.
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+	Attempt    int
+}
+
+// RequestRecorder is invoked with each request sent to the Kubernetes API
+// server by the clients of an API whose Recorder is set. It must be safe for
+// concurrent use.
+type RequestRecorder interface {
+	RecordRequest(record RequestRecord)
+}
+
+// safeQueryParameters are the query parameters whose values are recorded; the
+// values of others, such as continue tokens, are redacted.
+var safeQueryParameters = map[string]bool{
+	"container":       true,
+	"dryRun":          true,
+	"fieldSelector":   true,
+	"labelSelector":   true,
+	"limit":           true,
+	"limitBytes":      true,
+	"resourceVersion": true,
+	"tailLines":       true,
+	"timeoutSeconds":  true,
+	"watch":           true,
+}
+
+type attemptKey struct{}
+
+// withAttempt returns a copy of the request whose context carries its attempt
+// number, for the recorder.
+func withAttempt(req *http.Request, attempt int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptKey{}, attempt))
+}
+
+// recordingTransport invokes the recorder with each request it sends.
+type recordingTransport struct {
+	transport http.RoundTripper
+	recorder  RequestRecorder
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	rsp, err := rt.transport.RoundTrip(req)
+
+	attempt, ok := req.Context().Value(attemptKey{}).(int)
+	if !ok {
+		attempt = 1
+	}
+	record := RequestRecord{
+		Method:   req.Method,
+		URL:      redactedURL(req.URL),
+		Pattern:  pathPattern(req.URL.Path),
+		Err:      err,
+		Duration: time.Since(start),
+		Attempt:  attempt,
+	}
+	if rsp != nil {
+		record.StatusCode = rsp.StatusCode
+	}
+	rt.recorder.RecordRequest(record)
+
+	return rsp, err
+}
+
+// redactedURL returns the URL's path and query, with the values of the query
+// parameters other than safeQueryParameters redacted.
+func redactedURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		if safeQueryParameters[key] {
+			continue
+		}
+		for i := range values {
+			values[i] = "REDACTED"
+		}
+	}
+	return PathWithQuery(u.EscapedPath(), query)
+}
+
+// pathPattern replaces the namespace and name in a resource path with
+// placeholders, so that requests for different objects of the same resource
+// are aggregated together.
+func pathPattern(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	// the resource follows "api/v1" or "apis/<group>/<version>"
+	var prefix int
+	switch {
+	case len(segments) >= 2 && segments[0] == "api":
+		prefix = 2
+	case len(segments) >= 3 && segments[0] == "apis":
+		prefix = 3
+	default:
+		return path
+	}
+
+	pattern := append([]string{}, segments[:prefix]...)
+	rest := segments[prefix:]
+	if len(rest) > 2 && rest[0] == "namespaces" {
+		pattern = append(pattern, "namespaces", "{namespace}")
+		rest = rest[2:]
+	}
+	if len(rest) >= 2 {
+		// the resource, the object's name and its subresource, if any
+		pattern = append(pattern, rest[0], "{name}")
+		pattern = append(pattern, rest[2:]...)
+	} else {
+		pattern = append(pattern, rest...)
+	}
+
+	return "/" + strings.Join(pattern, "/")
+}
+
+// RequestStats is a RequestRecorder aggregating the requests by method and
+// path pattern, for a summary of the requests made during a run.
+type RequestStats struct {
+	mu        sync.Mutex
+	durations map[requestStatKey][]time.Duration
+	errors    map[requestStatKey]int
+}
+
+type requestStatKey struct {
+	method  string
+	pattern string
+}
+
+// RequestStat summarizes the requests made with a method and path pattern.
+type RequestStat struct {
+	Method  string
+	Pattern string
+	Count   int
+
+	// Errors counts the requests that failed or got an unsuccessful status.
+	Errors int
+
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+}
+
+// NewRequestStats returns an empty RequestStats.
+func NewRequestStats() *RequestStats {
+	return &RequestStats{
+		durations: make(map[requestStatKey][]time.Duration),
+		errors:    make(map[requestStatKey]int),
+	}
+}
+
+// RecordRequest implements RequestRecorder.
+func (s *RequestStats) RecordRequest(record RequestRecord) {
+	key := requestStatKey{method: record.Method, pattern: record.Pattern}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.durations[key] = append(s.durations[key], record.Duration)
+	if record.Err != nil || record.StatusCode < 200 || record.StatusCode > 299 {
+		s.errors[key]++
+	}
+}
+
+// Summary returns the stats of each method and path pattern, the most
+// requested first.
+func (s *RequestStats) Summary() []RequestStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := []RequestStat{}
+	for key, durations := range s.durations {
+		sorted := append([]time.Duration{}, durations...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		stats = append(stats, RequestStat{
+			Method:  key.method,
+			Pattern: key.pattern,
+			Count:   len(sorted),
+			Errors:  s.errors[key],
+			P50:     percentile(sorted, 50),
+			P90:     percentile(sorted, 90),
+			P99:     percentile(sorted, 99),
+			Max:     sorted[len(sorted)-1],
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		if stats[i].Pattern != stats[j].Pattern {
+			return stats[i].Pattern < stats[j].Pattern
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+type testRecorder struct {
+	mu      sync.Mutex
+	records []RequestRecord
+}
+
+func (r *testRecorder) RecordRequest(record RequestRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+func TestRecorder(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"kind":"PodList","metadata":{},"items":[]}`))
+	}))
+	defer server.Close()
+
+	recorder := &testRecorder{}
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}, Recorder: recorder}
+
+	if _, err := api.GetPods(context.Background(), "emojivoto", "app=web", ""); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(recorder.records) != 2 {
+		t.Fatalf("Expected a record per attempt, got %+v", recorder.records)
+	}
+	for i, record := range recorder.records {
+		if record.Method != "GET" ||
+			record.URL != "/api/v1/namespaces/emojivoto/pods?labelSelector=app%3Dweb&limit=500" ||
+			record.Pattern != "/api/v1/namespaces/{namespace}/pods" ||
+			record.Attempt != i+1 {
+			t.Fatalf("Unexpected record: %+v", record)
+		}
+	}
+	if recorder.records[0].StatusCode != http.StatusServiceUnavailable || recorder.records[1].StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected statuses: %d, %d", recorder.records[0].StatusCode, recorder.records[1].StatusCode)
+	}
+}
+
+func TestRedactedURL(t *testing.T) {
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"/api/v1/namespaces/linkerd", "/api/v1/namespaces/linkerd"},
+		{"/api/v1/pods?labelSelector=app%3Dweb&limit=500", "/api/v1/pods?labelSelector=app%3Dweb&limit=500"},
+		{"/api/v1/pods?continue=eyJ2IjoibWV0YS5rOHMuaW8vdjEifQ&limit=500", "/api/v1/pods?continue=REDACTED&limit=500"},
+		{"/api/v1/namespaces/linkerd/pods/web-1/log?access_token=secret&container=linkerd-proxy", "/api/v1/namespaces/linkerd/pods/web-1/log?access_token=REDACTED&container=linkerd-proxy"},
+	}
+
+	for _, tc := range testCases {
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if actual := redactedURL(u); actual != tc.expected {
+			t.Errorf("Expected [%s], got [%s]", tc.expected, actual)
+		}
+	}
+}
+
+func TestPathPattern(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/version", "/version"},
+		{"/apis", "/apis"},
+		{"/apis/linkerd.io/v1alpha2", "/apis/linkerd.io/v1alpha2"},
+		{"/api/v1/nodes", "/api/v1/nodes"},
+		{"/api/v1/namespaces", "/api/v1/namespaces"},
+		{"/api/v1/namespaces/linkerd", "/api/v1/namespaces/{name}"},
+		{"/api/v1/namespaces/linkerd/pods", "/api/v1/namespaces/{namespace}/pods"},
+		{"/api/v1/namespaces/linkerd/configmaps/linkerd-config", "/api/v1/namespaces/{namespace}/configmaps/{name}"},
+		{"/api/v1/namespaces/linkerd/pods/web-1/log", "/api/v1/namespaces/{namespace}/pods/{name}/log"},
+		{"/api/v1/namespaces/linkerd/pods/web-1:4191/proxy/metrics", "/api/v1/namespaces/{namespace}/pods/{name}/proxy/metrics"},
+		{"/apis/apps/v1/deployments", "/apis/apps/v1/deployments"},
+		{"/apis/apiregistration.k8s.io/v1/apiservices/v1alpha1.tap.linkerd.io", "/apis/apiregistration.k8s.io/v1/apiservices/{name}"},
+	}
+
+	for _, tc := range testCases {
+		if actual := pathPattern(tc.path); actual != tc.expected {
+			t.Errorf("Expected [%s] for [%s], got [%s]", tc.expected, tc.path, actual)
+		}
+	}
+}
+
+func TestRequestStats(t *testing.T) {
+	stats := NewRequestStats()
+	for i := 1; i <= 10; i++ {
+		stats.RecordRequest(RequestRecord{Method: "GET", Pattern: "/api/v1/namespaces/{namespace}/pods", StatusCode: http.StatusOK, Duration: time.Duration(i) * time.Millisecond})
+	}
+	stats.RecordRequest(RequestRecord{Method: "GET", Pattern: "/version", StatusCode: http.StatusForbidden, Duration: time.Millisecond})
+	stats.RecordRequest(RequestRecord{Method: "POST", Pattern: "/version", Err: http.ErrHandlerTimeout, Duration: 2 * time.Millisecond})
+
+	expected := []RequestStat{
+		{Method: "GET", Pattern: "/api/v1/namespaces/{namespace}/pods", Count: 10, Errors: 0, P50: 5 * time.Millisecond, P90: 9 * time.Millisecond, P99: 10 * time.Millisecond, Max: 10 * time.Millisecond},
+		{Method: "GET", Pattern: "/version", Count: 1, Errors: 1, P50: time.Millisecond, P90: time.Millisecond, P99: time.Millisecond, Max: time.Millisecond},
+		{Method: "POST", Pattern: "/version", Count: 1, Errors: 1, P50: 2 * time.Millisecond, P90: 2 * time.Millisecond, P99: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+	}
+	if actual := stats.Summary(); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Unexpected summary:\n%+v\nexpected:\n%+v", actual, expected)
+	}
+}
@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRequestAttempts is the number of times an idempotent request
+	// is attempted, unless the KubernetesAPI's MaxRequestAttempts is set.
+	DefaultMaxRequestAttempts = 3
+
+	retryBaseBackoff = 200 * time.Millisecond
+	retryMaxBackoff  = 2 * time.Second
+)
+
+type finalResponseKey struct{}
+
+// WithFinalResponse returns a context whose requests get their last response
+// once their attempts are exhausted, rather than an error, for callers that
+// interpret 5xx responses themselves, such as those of a proxied service.
+func WithFinalResponse(ctx context.Context) context.Context {
+	return context.WithValue(ctx, finalResponseKey{}, true)
+}
+
+// retryTransport retries idempotent requests that fail with a connection
+// error, a 429 or a 5xx response, with capped exponential backoff. Other
+// requests are sent once.
+type retryTransport struct {
+	transport   http.RoundTripper
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+func newRetryTransport(transport http.RoundTripper, maxAttempts int) *retryTransport {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxRequestAttempts
+	}
+
+	return &retryTransport{
+		transport:   transport,
+		maxAttempts: maxAttempts,
+		baseBackoff: retryBaseBackoff,
+		maxBackoff:  retryMaxBackoff,
+	}
+}
+
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return rt.transport.RoundTrip(req)
+	}
+
+	backoff := rt.baseBackoff
+	for attempt := 1; ; attempt++ {
+		rsp, err := rt.transport.RoundTrip(withAttempt(req, attempt))
+		if err == nil && !retryableStatus(rsp.StatusCode) {
+			return rsp, nil
+		}
+		if err != nil && (req.Context().Err() != nil || IsRateLimited(err)) {
+			return nil, err
+		}
+
+		if attempt >= rt.maxAttempts {
+			if err != nil {
+				return nil, fmt.Errorf("%s (after %d attempts)", err, attempt)
+			}
+			if final, _ := req.Context().Value(finalResponseKey{}).(bool); final {
+				return rsp, nil
+			}
+			err := unexpectedResponse(rsp)
+			rsp.Body.Close()
+			return nil, fmt.Errorf("%s (after %d attempts)", err, attempt)
+		}
+
+		wait := backoff
+		if err == nil {
+			if retryAfter, ok := parseRetryAfter(rsp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+			io.Copy(ioutil.Discard, rsp.Body)
+			rsp.Body.Close()
+		}
+		if wait > rt.maxBackoff {
+			wait = rt.maxBackoff
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		backoff *= 2
+		if backoff > rt.maxBackoff {
+			backoff = rt.maxBackoff
+		}
+	}
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
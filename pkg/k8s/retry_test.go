@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	newClient := func(maxAttempts int) *http.Client {
+		rt := newRetryTransport(http.DefaultTransport, maxAttempts)
+		rt.baseBackoff = time.Millisecond
+		rt.maxBackoff = 10 * time.Millisecond
+		return &http.Client{Transport: rt}
+	}
+
+	t.Run("Retries GET requests on transient errors", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch atomic.AddInt32(&requests, 1) {
+			case 1:
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+			case 2:
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				w.Write([]byte("ok"))
+			}
+		}))
+		defer server.Close()
+
+		rsp, err := newClient(3).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		rsp.Body.Close()
+		if rsp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rsp.StatusCode)
+		}
+		if requests != 3 {
+			t.Fatalf("Expected 3 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Reports the number of attempts made", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := newClient(4).Get(server.URL)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.Contains(err.Error(), "Unexpected Kubernetes API response: 503 Service Unavailable (after 4 attempts)") {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if requests != 4 {
+			t.Fatalf("Expected 4 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Retries connection errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		_, err := newClient(2).Get(server.URL)
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if !strings.Contains(err.Error(), "(after 2 attempts)") {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Does not retry non-idempotent requests", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		rsp, err := newClient(3).Post(server.URL, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		rsp.Body.Close()
+		if rsp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected status 503, got %d", rsp.StatusCode)
+		}
+		if requests != 1 {
+			t.Fatalf("Expected 1 request, got %d", requests)
+		}
+	})
+
+	t.Run("Does not retry client errors", func(t *testing.T) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		rsp, err := newClient(3).Get(server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		rsp.Body.Close()
+		if requests != 1 {
+			t.Fatalf("Expected 1 request, got %d", requests)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("3"); !ok || wait != 3*time.Second {
+		t.Fatalf("Expected 3s, got %s (%t)", wait, ok)
+	}
+	if wait, ok := parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)); !ok || wait != 0 {
+		t.Fatalf("Expected 0s for a date in the past, got %s (%t)", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatal("Expected an invalid Retry-After to be ignored")
+	}
+}
@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+)
+
+// tlsCertKeys are the Secret keys under which a PEM-encoded certificate is
+// looked up, in order: kubernetes.io/tls Secrets use tls.crt, and the
+// identity issuer Secret uses crt.pem.
+var tlsCertKeys = []string{v1.TLSCertKey, "crt.pem"}
+
+// GetSecretData returns the decoded data of the named Secret, a
+// SecretNotFoundError if it does not exist, or a ForbiddenError if it cannot
+// be read. The data may hold key material, so callers must never log or
+// print it.
+func (kubeAPI *KubernetesAPI) GetSecretData(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	secret, err := kubeAPI.GetSecret(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, &SecretNotFoundError{Namespace: namespace, Name: name}
+	}
+
+	return secret.Data, nil
+}
+
+// GetTLSCertFromSecret returns the certificate stored in the named Secret,
+// under the tls.crt or crt.pem key. Only the certificate is parsed; the
+// returned errors never include the Secret's contents.
+func (kubeAPI *KubernetesAPI) GetTLSCertFromSecret(ctx context.Context, namespace, name string) (*x509.Certificate, error) {
+	data, err := kubeAPI.GetSecretData(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTLSCertFromSecretData(namespace, name, data)
+}
+
+func parseTLSCertFromSecretData(namespace, name string, data map[string][]byte) (*x509.Certificate, error) {
+	for _, key := range tlsCertKeys {
+		encoded, ok := data[key]
+		if !ok {
+			continue
+		}
+
+		block, _ := pem.Decode(encoded)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("The \"%s\" key of the \"%s/%s\" Secret does not hold a PEM-encoded certificate", key, namespace, name)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("The \"%s\" key of the \"%s/%s\" Secret holds an invalid certificate: %s", key, namespace, name, err)
+		}
+		return cert, nil
+	}
+
+	return nil, fmt.Errorf("The \"%s/%s\" Secret has no certificate under the %s or %s keys", namespace, name, tlsCertKeys[0], tlsCertKeys[1])
+}
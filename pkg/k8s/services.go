@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"k8s.io/api/core/v1"
+)
+
+// PodsForService returns the pods, among the given ones, selected by the
+// Service, as the endpoints controller would: a pod is selected if it is in
+// the Service's namespace and carries every label of its selector, with the
+// same value. Services without a selector select no pods, since their
+// endpoints are managed by hand.
+func PodsForService(service v1.Service, pods []v1.Pod) []v1.Pod {
+	selected := []v1.Pod{}
+	if len(service.Spec.Selector) == 0 {
+		return selected
+	}
+
+	for _, pod := range pods {
+		if pod.Namespace == service.Namespace && selectorMatches(service.Spec.Selector, pod.Labels) {
+			selected = append(selected, pod)
+		}
+	}
+	return selected
+}
+
+// selectorMatches returns true if the labels include every key of the
+// equality-based selector, with the same value.
+func selectorMatches(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
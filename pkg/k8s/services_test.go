@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetServices(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path + "?" + r.URL.Query().Get("labelSelector")
+		w.Write([]byte(`{"kind":"ServiceList","apiVersion":"v1","metadata":{},"items":[
+			{"metadata":{"name":"web","namespace":"emojivoto","annotations":{"config.linkerd.io/opaque-ports":"8080"}},
+				"spec":{"type":"ClusterIP","selector":{"app":"web-svc"},
+					"ports":[{"name":"http","port":80,"targetPort":"http"},{"name":"grpc","port":8080,"targetPort":8080}]}}]}`))
+	}))
+	defer server.Close()
+
+	api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+	services, err := api.GetServices(context.Background(), "emojivoto", "app=web-svc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if requested != "/api/v1/namespaces/emojivoto/services?app=web-svc" {
+		t.Fatalf("Unexpected request: %s", requested)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 Service, got %d", len(services))
+	}
+
+	svc := services[0]
+	if svc.Name != "web" || svc.Spec.Type != v1.ServiceTypeClusterIP ||
+		svc.Annotations["config.linkerd.io/opaque-ports"] != "8080" ||
+		!reflect.DeepEqual(svc.Spec.Selector, map[string]string{"app": "web-svc"}) {
+		t.Fatalf("Unexpected Service: %+v", svc)
+	}
+	if svc.Spec.Ports[0].Name != "http" || svc.Spec.Ports[0].TargetPort != intstr.FromString("http") ||
+		svc.Spec.Ports[1].Name != "grpc" || svc.Spec.Ports[1].TargetPort != intstr.FromInt(8080) {
+		t.Fatalf("Unexpected ports: %+v", svc.Spec.Ports)
+	}
+
+	if _, err := api.GetServices(context.Background(), "", ""); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if requested != "/api/v1/services?" {
+		t.Fatalf("Unexpected request: %s", requested)
+	}
+}
+
+func TestPodsForService(t *testing.T) {
+	pod := func(name, namespace string, labels map[string]string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+	}
+	pods := []v1.Pod{
+		pod("web-1", "emojivoto", map[string]string{"app": "web-svc", "version": "v1"}),
+		pod("web-2", "emojivoto", map[string]string{"app": "web-svc", "version": "v2"}),
+		pod("web-other", "other", map[string]string{"app": "web-svc", "version": "v1"}),
+		pod("voting-1", "emojivoto", map[string]string{"app": "voting-svc"}),
+		pod("unlabeled", "emojivoto", nil),
+		pod("empty-value", "emojivoto", map[string]string{"app": ""}),
+	}
+
+	testCases := []struct {
+		name     string
+		selector map[string]string
+		expected []string
+	}{
+		{"Selects the pods carrying the selector's label", map[string]string{"app": "web-svc"}, []string{"web-1", "web-2"}},
+		{"Requires every label of the selector", map[string]string{"app": "web-svc", "version": "v1"}, []string{"web-1"}},
+		{"Requires the same value", map[string]string{"app": "WEB-SVC"}, []string{}},
+		{"Requires the label to be set", map[string]string{"tier": ""}, []string{}},
+		{"Matches empty values", map[string]string{"app": ""}, []string{"empty-value"}},
+		{"Selects no pods without a selector", map[string]string{}, []string{}},
+		{"Selects no pods with a nil selector", nil, []string{}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			svc := v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "emojivoto"},
+				Spec:       v1.ServiceSpec{Selector: tc.selector},
+			}
+
+			names := []string{}
+			for _, p := range PodsForService(svc, pods) {
+				names = append(names, p.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// ErrInsecureWithCA is returned when a CA bundle is given while TLS
+// verification is skipped, as the bundle would be ignored.
+var ErrInsecureWithCA = errors.New("A certificate authority cannot be set while TLS verification is skipped")
+
+// UseCertificateAuthority makes the API verify the server's certificate with
+// the CA bundle read from caFile, or given as PEM-encoded caPEM, instead of
+// the kubeconfig's, e.g. for clusters fronted by a TLS-terminating proxy.
+// It must be called before NewClient and the API's first request.
+func (kubeAPI *KubernetesAPI) UseCertificateAuthority(caFile string, caPEM []byte) error {
+	if kubeAPI.Insecure {
+		return ErrInsecureWithCA
+	}
+	if (caFile == "") == (len(caPEM) == 0) {
+		return errors.New("Exactly one of a certificate authority file or PEM-encoded bundle must be given")
+	}
+
+	if len(caPEM) == 0 {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("Failed to read the certificate authority: %s", err)
+		}
+		caPEM = data
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(caPEM) {
+		source := "the given certificate authority"
+		if caFile != "" {
+			source = caFile
+		}
+		return fmt.Errorf("No PEM-encoded certificate found in %s", source)
+	}
+
+	kubeAPI.TLSClientConfig.CAFile = ""
+	kubeAPI.TLSClientConfig.CAData = caPEM
+	return nil
+}
+
+// SkipTLSVerify makes the API accept any certificate presented by the server,
+// which leaves its connections open to interception. The kubeconfig's CA, if
+// any, is ignored. It must be called before NewClient and the API's first
+// request.
+func (kubeAPI *KubernetesAPI) SkipTLSVerify() {
+	kubeAPI.TLSClientConfig.Insecure = true
+	kubeAPI.TLSClientConfig.CAFile = ""
+	kubeAPI.TLSClientConfig.CAData = nil
+}
+
+// TLSVerifySkipped returns true if the server's certificate is not verified,
+// whether by SkipTLSVerify or by the kubeconfig.
+func (kubeAPI *KubernetesAPI) TLSVerifySkipped() bool {
+	return kubeAPI.TLSClientConfig.Insecure
+}
@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestUseCertificateAuthority(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	dir, err := ioutil.TempDir("", "linkerd-tls")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	get := func(t *testing.T, api *KubernetesAPI) error {
+		client, err := api.NewClient()
+		if err != nil {
+			t.Fatalf("Unexpected error creating client: %s", err)
+		}
+		rsp, err := client.Get(server.URL + "/version")
+		if err != nil {
+			return err
+		}
+		rsp.Body.Close()
+		return nil
+	}
+
+	t.Run("Fails to verify the server without its CA", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		if err := get(t, api); err == nil {
+			t.Fatal("Expected a certificate verification error")
+		}
+	})
+
+	for name, args := range map[string]struct {
+		file string
+		pem  []byte
+	}{
+		"Verifies the server with a CA file":       {file: caFile},
+		"Verifies the server with a PEM CA bundle": {pem: caPEM},
+	} {
+		args := args
+		t.Run(name, func(t *testing.T) {
+			api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{CAFile: "/missing/ca.crt"}}}
+			if err := api.UseCertificateAuthority(args.file, args.pem); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err := get(t, api); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		})
+	}
+
+	t.Run("Skips verification", func(t *testing.T) {
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL, TLSClientConfig: rest.TLSClientConfig{CAData: caPEM}}}
+		api.SkipTLSVerify()
+		if !api.TLSVerifySkipped() {
+			t.Fatal("Expected TLS verification to be skipped")
+		}
+		if err := get(t, api); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	testCases := []struct {
+		name     string
+		insecure bool
+		file     string
+		pem      []byte
+	}{
+		{"Rejects a CA while verification is skipped", true, "", caPEM},
+		{"Rejects both a CA file and bundle", false, caFile, caPEM},
+		{"Rejects neither a CA file nor bundle", false, "", nil},
+		{"Rejects a missing CA file", false, filepath.Join(dir, "missing.crt"), nil},
+		{"Rejects a bundle without certificates", false, "", []byte("not a certificate")},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+			if tc.insecure {
+				api.SkipTLSVerify()
+			}
+			if err := api.UseCertificateAuthority(tc.file, tc.pem); err == nil {
+				t.Fatal("Expected an error")
+			}
+		})
+	}
+}
@@ -5,11 +5,52 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/version"
 )
 
-var revisionSeparator = regexp.MustCompile("[^0-9.]")
+var (
+	revisionSeparator = regexp.MustCompile("[^0-9.]")
+	leadingNumber     = regexp.MustCompile("^[0-9]+")
+)
 
-func getK8sVersion(versionString string) ([3]int, error) {
+// UnknownVersionError is returned when the Kubernetes version cannot be
+// determined from the API server's version info.
+type UnknownVersionError struct {
+	Version string
+}
+
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("unable to determine the Kubernetes version from [%s]", e.Version)
+}
+
+// getVersionInfoVersion returns the major, minor and patch version of the
+// given version info. Vendor suffixes following the patch number, as in
+// "v1.12.7-gke.10", are ignored. If the GitVersion cannot be parsed, the Major
+// and Minor fields are used instead, ignoring suffixes such as the "+" in
+// "12+", with a patch version of 0.
+func getVersionInfoVersion(versionInfo *version.Info) ([3]int, error) {
+	if v, err := ParseK8sVersion(versionInfo.GitVersion); err == nil {
+		return v, nil
+	}
+
+	major, err := strconv.Atoi(leadingNumber.FindString(strings.TrimSpace(versionInfo.Major)))
+	if err != nil {
+		return [3]int{}, &UnknownVersionError{Version: versionInfo.GitVersion}
+	}
+	minor, err := strconv.Atoi(leadingNumber.FindString(strings.TrimSpace(versionInfo.Minor)))
+	if err != nil {
+		return [3]int{}, &UnknownVersionError{Version: versionInfo.GitVersion}
+	}
+
+	return [3]int{major, minor, 0}, nil
+}
+
+// ParseK8sVersion parses a Kubernetes version string such as "v1.12.7" into
+// its major, minor and patch versions. The "v" prefix is optional, and vendor
+// suffixes following the patch number, as in "v1.12.7-gke.10" or
+// "v1.11.0+d4cacc0", are ignored.
+func ParseK8sVersion(versionString string) ([3]int, error) {
 	var version [3]int
 	justTheVersionString := strings.TrimPrefix(versionString, "v")
 	justTheMajorMinorRevisionNumbers := revisionSeparator.Split(justTheVersionString, -1)[0]
@@ -19,7 +60,7 @@ func getK8sVersion(versionString string) ([3]int, error) {
 		return version, fmt.Errorf("unknown version string format [%s]", versionString)
 	}
 
-	for i, segment := range split {
+	for i, segment := range split[:3] {
 		v, err := strconv.Atoi(strings.TrimSpace(segment))
 		if err != nil {
 			return version, fmt.Errorf("unknown version string format [%s]", versionString)
@@ -30,18 +71,34 @@ func getK8sVersion(versionString string) ([3]int, error) {
 	return version, nil
 }
 
-func isCompatibleVersion(minimalRequirementVersion [3]int, actualVersion [3]int) bool {
-	if minimalRequirementVersion[0] < actualVersion[0] {
-		return true
+// ParseVersion parses a Kubernetes version given as "1.12" or "1.12.1", with
+// an optional "v" prefix, into its major, minor and patch versions. The patch
+// version defaults to 0.
+func ParseVersion(versionString string) ([3]int, error) {
+	if strings.Count(strings.TrimPrefix(versionString, "v"), ".") == 1 {
+		versionString += ".0"
 	}
+	return ParseK8sVersion(versionString)
+}
 
-	if (minimalRequirementVersion[0] == actualVersion[0]) && minimalRequirementVersion[1] < actualVersion[1] {
-		return true
+// IsVersionAtLeast returns true if the given Kubernetes version info is at
+// least the given major, minor and patch version. It returns false if the
+// version cannot be determined.
+func IsVersionAtLeast(versionInfo *version.Info, min [3]int) bool {
+	v, err := getVersionInfoVersion(versionInfo)
+	if err != nil {
+		return false
 	}
+	return AtLeast(v, min)
+}
 
-	if (minimalRequirementVersion[0] == actualVersion[0]) && (minimalRequirementVersion[1] == actualVersion[1]) && (minimalRequirementVersion[2] <= actualVersion[2]) {
-		return true
+// AtLeast returns true if the Kubernetes version v, as returned by
+// ParseK8sVersion, is the same as or more recent than min.
+func AtLeast(v, min [3]int) bool {
+	for i := range v {
+		if v[i] != min[i] {
+			return v[i] > min[i]
+		}
 	}
-
-	return false
+	return true
 }
@@ -2,9 +2,11 @@ package k8s
 
 import (
 	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
 )
 
-func TestGetK8sVersion(t *testing.T) {
+func TestParseK8sVersion(t *testing.T) {
 	t.Run("Correctly parses a Version string", func(t *testing.T) {
 		versions := map[string][3]int{
 			"v1.8.4":               {1, 8, 4},
@@ -12,10 +14,16 @@ func TestGetK8sVersion(t *testing.T) {
 			"v2.0.1":               {2, 0, 1},
 			"v1.9.0-beta.2":        {1, 9, 0},
 			"v1.7.9+7f63532e4ff4f": {1, 7, 9},
+			"1.10.3":               {1, 10, 3},
+			"v1.12.7-gke.10":       {1, 12, 7},
+			"v1.11.5-eks-6bad6d":   {1, 11, 5},
+			"v1.11.0+d4cacc0":      {1, 11, 0},
+			"v1.13.5-rancher1-2":   {1, 13, 5},
+			"v1.16.2-k3s.1":        {1, 16, 2},
 		}
 
 		for k, expectedVersion := range versions {
-			actualVersion, err := getK8sVersion(k)
+			actualVersion, err := ParseK8sVersion(k)
 			if err != nil {
 				t.Fatalf("Error parsing string: %v", err)
 			}
@@ -38,7 +46,7 @@ func TestGetK8sVersion(t *testing.T) {
 		}
 
 		for _, invalidVersion := range versions {
-			_, err := getK8sVersion(invalidVersion)
+			_, err := ParseK8sVersion(invalidVersion)
 
 			if err == nil {
 				t.Fatalf("Expected error parsing string: %s", invalidVersion)
@@ -47,7 +55,89 @@ func TestGetK8sVersion(t *testing.T) {
 	})
 }
 
-func TestIsCompatibleVersion(t *testing.T) {
+func TestGetVersionInfoVersion(t *testing.T) {
+	testCases := []struct {
+		provider    string
+		versionInfo version.Info
+		expected    [3]int
+	}{
+		{"GKE", version.Info{Major: "1", Minor: "12+", GitVersion: "v1.12.7-gke.10"}, [3]int{1, 12, 7}},
+		{"EKS", version.Info{Major: "1", Minor: "11+", GitVersion: "v1.11.5-eks-6bad6d"}, [3]int{1, 11, 5}},
+		{"AKS", version.Info{Major: "1", Minor: "12", GitVersion: "v1.12.8"}, [3]int{1, 12, 8}},
+		{"OpenShift", version.Info{Major: "1", Minor: "11+", GitVersion: "v1.11.0+d4cacc0"}, [3]int{1, 11, 0}},
+		{"OpenShift 4", version.Info{Major: "1", Minor: "18+", GitVersion: "v1.18.3+6c42de8"}, [3]int{1, 18, 3}},
+		{"OpenShift without a GitVersion", version.Info{Major: "1", Minor: "11+", GitVersion: ""}, [3]int{1, 11, 0}},
+		{"Rancher", version.Info{Major: "1", Minor: "13", GitVersion: "v1.13.5-rancher1-2"}, [3]int{1, 13, 5}},
+		{"minikube", version.Info{Major: "1", Minor: "14", GitVersion: "v1.14.0"}, [3]int{1, 14, 0}},
+		{"a four-part version", version.Info{GitVersion: "v1.12.7.1"}, [3]int{1, 12, 7}},
+		{"an unparseable GitVersion", version.Info{Major: "1", Minor: "10+", GitVersion: "v1.10-custom"}, [3]int{1, 10, 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.provider, func(t *testing.T) {
+			actual, err := getVersionInfoVersion(&tc.versionInfo)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("Expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+
+	t.Run("Returns an error naming the raw version if it cannot be determined", func(t *testing.T) {
+		_, err := getVersionInfoVersion(&version.Info{GitVersion: "unknown"})
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if _, ok := err.(*UnknownVersionError); !ok {
+			t.Fatalf("Expected an UnknownVersionError, got %T", err)
+		}
+		if err.Error() != "unable to determine the Kubernetes version from [unknown]" {
+			t.Fatalf("Unexpected error message: %s", err)
+		}
+	})
+}
+
+func TestParseVersion(t *testing.T) {
+	versions := map[string][3]int{
+		"1.12":    {1, 12, 0},
+		"v1.12":   {1, 12, 0},
+		"1.12.1":  {1, 12, 1},
+		"v1.9.10": {1, 9, 10},
+	}
+	for k, expectedVersion := range versions {
+		actualVersion, err := ParseVersion(k)
+		if err != nil {
+			t.Fatalf("Error parsing string %s: %v", k, err)
+		}
+		if actualVersion != expectedVersion {
+			t.Fatalf("Expecting %s to be parsed into %v but got %v", k, expectedVersion, actualVersion)
+		}
+	}
+
+	for _, k := range []string{"", "1", "1.x", "1.9-beta.2"} {
+		if _, err := ParseVersion(k); err == nil {
+			t.Fatalf("Expected error parsing %s", k)
+		}
+	}
+}
+
+func TestIsVersionAtLeast(t *testing.T) {
+	versionInfo := &version.Info{Major: "1", Minor: "12+", GitVersion: "v1.12.7-gke.10"}
+
+	if !IsVersionAtLeast(versionInfo, [3]int{1, 12, 0}) {
+		t.Fatalf("Expected %s to be at least 1.12.0", versionInfo.GitVersion)
+	}
+	if IsVersionAtLeast(versionInfo, [3]int{1, 13, 0}) {
+		t.Fatalf("Expected %s not to be at least 1.13.0", versionInfo.GitVersion)
+	}
+	if IsVersionAtLeast(&version.Info{GitVersion: "unknown"}, [3]int{1, 0, 0}) {
+		t.Fatal("Expected an unknown version not to be at least 1.0.0")
+	}
+}
+
+func TestAtLeast(t *testing.T) {
 	t.Run("Success when compatible versions", func(t *testing.T) {
 		compatibleVersions := map[[3]int][3]int{
 			{1, 8, 4}: {1, 8, 4},
@@ -60,7 +150,7 @@ func TestIsCompatibleVersion(t *testing.T) {
 		}
 
 		for e, a := range compatibleVersions {
-			if !isCompatibleVersion(e, a) {
+			if !AtLeast(a, e) {
 				t.Fatalf("Expected required version [%v] to be compatible with [%v] but it wasn't", e, a)
 			}
 		}
@@ -76,7 +166,7 @@ func TestIsCompatibleVersion(t *testing.T) {
 			{10, 10, 10}: {0, 10, 9},
 		}
 		for e, a := range inCompatibleVersions {
-			if isCompatibleVersion(e, a) {
+			if AtLeast(a, e) {
 				t.Fatalf("Expected required version [%v] to  NOT be compatible with [%v] but it was'", e, a)
 			}
 		}
@@ -0,0 +1,178 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	watchBaseBackoff = 200 * time.Millisecond
+	watchMaxBackoff  = 5 * time.Second
+)
+
+// WatchEvent is a change to a watched resource. Object holds the JSON-encoded
+// resource, or a metav1.Status describing the failure of Error events.
+type WatchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// watchObjectMeta holds the metadata of a watched resource needed to resume
+// the watch.
+type watchObjectMeta struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+}
+
+// Watch watches the resources listed at the given path, such as
+// "/api/v1/namespaces/linkerd/pods", matching the label selector, if any.
+// The watch starts with an Added event for each existing resource, and is
+// resumed from the last resource version seen whenever the API server closes
+// it. If that version is too old (410 Gone), the watch is re-established from
+// scratch, again starting with an Added event for each existing resource.
+// Reconnects are spaced out with a capped exponential backoff, reset once a
+// stream stays open for longer than the cap.
+//
+// The returned channel is closed once the context is canceled, or after an
+// Error event if the watch cannot be re-established.
+func (kubeAPI *KubernetesAPI) Watch(ctx context.Context, resourcePath, labelSelector string) (<-chan WatchEvent, error) {
+	rsp, err := kubeAPI.watchRequest(ctx, resourcePath, labelSelector, "")
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+
+		resourceVersion := ""
+		backoff := watchBaseBackoff
+		for {
+			started := time.Now()
+			resourceVersion = kubeAPI.streamWatchEvents(ctx, rsp, events, resourceVersion)
+			if ctx.Err() != nil {
+				return
+			}
+
+			if time.Since(started) > watchMaxBackoff {
+				backoff = watchBaseBackoff
+			}
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff *= 2
+			if backoff > watchMaxBackoff {
+				backoff = watchMaxBackoff
+			}
+
+			rsp, err = kubeAPI.watchRequest(ctx, resourcePath, labelSelector, resourceVersion)
+			if err == errWatchExpired {
+				resourceVersion = ""
+				rsp, err = kubeAPI.watchRequest(ctx, resourcePath, labelSelector, "")
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					sendWatchEvent(ctx, events, watchErrorEvent(err))
+				}
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// errWatchExpired is returned when the resource version a watch is resumed
+// from is too old.
+var errWatchExpired = errors.New("watch resource version expired")
+
+func (kubeAPI *KubernetesAPI) watchRequest(ctx context.Context, resourcePath, labelSelector, resourceVersion string) (*http.Response, error) {
+	query := selectorQuery(labelSelector)
+	query.Set("watch", "true")
+	if resourceVersion != "" {
+		query.Set("resourceVersion", resourceVersion)
+	}
+
+	rsp, err := kubeAPI.getRequest(ctx, PathWithQuery(resourcePath, query))
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode == http.StatusGone {
+		rsp.Body.Close()
+		return nil, errWatchExpired
+	}
+	if rsp.StatusCode != http.StatusOK {
+		rsp.Body.Close()
+		return nil, unexpectedResponse(rsp)
+	}
+
+	return rsp, nil
+}
+
+// streamWatchEvents sends the events read from the watch response until the
+// stream ends, and returns the resource version to resume the watch from.
+func (kubeAPI *KubernetesAPI) streamWatchEvents(ctx context.Context, rsp *http.Response, events chan<- WatchEvent, resourceVersion string) string {
+	defer rsp.Body.Close()
+
+	// unblock the decoder once the context is canceled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			rsp.Body.Close()
+		case <-done:
+		}
+	}()
+
+	decoder := json.NewDecoder(rsp.Body)
+	for {
+		var event WatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			return resourceVersion
+		}
+
+		if event.Type == watch.Error {
+			var status metav1.Status
+			if err := json.Unmarshal(event.Object, &status); err == nil && status.Code == http.StatusGone {
+				return ""
+			}
+		} else {
+			var meta watchObjectMeta
+			if err := json.Unmarshal(event.Object, &meta); err == nil && meta.Metadata.ResourceVersion != "" {
+				resourceVersion = meta.Metadata.ResourceVersion
+			}
+		}
+
+		if !sendWatchEvent(ctx, events, event) {
+			return resourceVersion
+		}
+	}
+}
+
+func sendWatchEvent(ctx context.Context, events chan<- WatchEvent, event WatchEvent) bool {
+	select {
+	case events <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func watchErrorEvent(err error) WatchEvent {
+	status, _ := json.Marshal(metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+	})
+	return WatchEvent{Type: watch.Error, Object: status}
+}
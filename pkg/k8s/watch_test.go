@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+)
+
+func TestWatch(t *testing.T) {
+	newAPI := func(streams []string) (*KubernetesAPI, func() []string, func()) {
+		var mu sync.Mutex
+		versions := []string{}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/namespaces/linkerd/pods" || r.URL.Query().Get("watch") != "true" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			mu.Lock()
+			n := len(versions)
+			versions = append(versions, r.URL.Query().Get("resourceVersion"))
+			mu.Unlock()
+
+			if n >= len(streams) {
+				// hold the watch open until the client goes away
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+				return
+			}
+			w.Write([]byte(streams[n]))
+		}))
+
+		api := &KubernetesAPI{Config: &rest.Config{Host: server.URL}}
+		requested := func() []string {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]string{}, versions...)
+		}
+		return api, requested, server.Close
+	}
+
+	receive := func(t *testing.T, events <-chan WatchEvent) WatchEvent {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("Unexpected close of the watch")
+			}
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a watch event")
+		}
+		return WatchEvent{}
+	}
+
+	t.Run("Resumes the watch from the last resource version seen", func(t *testing.T) {
+		api, versions, done := newAPI([]string{
+			`{"type":"ADDED","object":{"metadata":{"name":"linkerd-controller-1","resourceVersion":"10"}}}`,
+			`{"type":"MODIFIED","object":{"metadata":{"name":"linkerd-controller-1","resourceVersion":"11"}}}`,
+		})
+		defer done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := api.Watch(ctx, PodsPath("linkerd"), "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if event := receive(t, events); event.Type != watch.Added {
+			t.Fatalf("Expected an Added event, got %s", event.Type)
+		}
+		if event := receive(t, events); event.Type != watch.Modified {
+			t.Fatalf("Expected a Modified event, got %s", event.Type)
+		}
+
+		cancel()
+		for range events {
+		}
+
+		if requested := versions(); requested[0] != "" || requested[1] != "10" {
+			t.Fatalf("Unexpected resource versions requested: %v", requested)
+		}
+	})
+
+	t.Run("Restarts the watch once the resource version has expired", func(t *testing.T) {
+		api, versions, done := newAPI([]string{
+			`{"type":"ADDED","object":{"metadata":{"name":"linkerd-controller-1","resourceVersion":"10"}}}` +
+				`{"type":"ERROR","object":{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Expired","code":410}}`,
+			`{"type":"ADDED","object":{"metadata":{"name":"linkerd-controller-1","resourceVersion":"20"}}}`,
+		})
+		defer done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events, err := api.Watch(ctx, PodsPath("linkerd"), "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		for _, expected := range []string{"10", "20"} {
+			event := receive(t, events)
+			if event.Type != watch.Added || !strings.Contains(string(event.Object), `"resourceVersion":"`+expected+`"`) {
+				t.Fatalf("Expected an Added event at version %s, got %s %s", expected, event.Type, event.Object)
+			}
+		}
+
+		cancel()
+		for range events {
+		}
+
+		if requested := versions(); requested[0] != "" || requested[1] != "" {
+			t.Fatalf("Unexpected resource versions requested: %v", requested)
+		}
+	})
+
+	t.Run("Backs off between reconnects", func(t *testing.T) {
+		streams := make([]string, 20)
+		api, versions, done := newAPI(streams)
+		defer done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		events, err := api.Watch(ctx, PodsPath("linkerd"), "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		for range events {
+		}
+
+		// 200ms, then 400ms, between reconnects
+		if requested := versions(); len(requested) > 3 {
+			t.Fatalf("Expected at most 3 watch requests, got %d", len(requested))
+		}
+	})
+
+	t.Run("Closes the channel once the context is canceled", func(t *testing.T) {
+		api, _, done := newAPI([]string{})
+		defer done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := api.Watch(ctx, PodsPath("linkerd"), "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		cancel()
+		select {
+		case _, ok := <-events:
+			if ok {
+				t.Fatal("Unexpected event after the context was canceled")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the watch to close")
+		}
+	})
+
+	t.Run("Returns an error if the watch cannot be established", func(t *testing.T) {
+		api, _, done := newAPI([]string{})
+		defer done()
+
+		if _, err := api.Watch(context.Background(), PodsPath("default"), ""); err == nil {
+			t.Fatal("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+)
+
+// progressDeadlineExceeded is the reason of a Deployment's Progressing
+// condition once it has failed to make progress within its deadline.
+const progressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+// DeploymentIsAvailable returns true if the Deployment has at least as many
+// available replicas as it desires. Its Available condition is used if set.
+func DeploymentIsAvailable(deployment *appsV1.Deployment) bool {
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsV1.DeploymentAvailable {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+
+	return deployment.Status.AvailableReplicas >= desiredReplicas(deployment)
+}
+
+// RolloutStuck returns true, along with the condition's message, if the
+// Deployment's rollout has not progressed within its progress deadline.
+func RolloutStuck(deployment *appsV1.Deployment) (bool, string) {
+	for _, c := range deployment.Status.Conditions {
+		if c.Type == appsV1.DeploymentProgressing && c.Status == v1.ConditionFalse && c.Reason == progressDeadlineExceeded {
+			return true, c.Message
+		}
+	}
+	return false, ""
+}
+
+// DaemonSetIsAvailable returns true if every node that should run the
+// DaemonSet runs an available, up-to-date pod.
+func DaemonSetIsAvailable(daemonSet *appsV1.DaemonSet) bool {
+	desired := daemonSet.Status.DesiredNumberScheduled
+	return daemonSet.Status.NumberAvailable >= desired && daemonSet.Status.UpdatedNumberScheduled >= desired
+}
+
+func desiredReplicas(deployment *appsV1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+)
+
+func TestDeploymentIsAvailable(t *testing.T) {
+	replicas := int32(3)
+
+	testCases := []struct {
+		desc       string
+		deployment appsV1.Deployment
+		expected   bool
+	}{
+		{
+			desc: "available condition is true",
+			deployment: appsV1.Deployment{Status: appsV1.DeploymentStatus{
+				Conditions: []appsV1.DeploymentCondition{{Type: appsV1.DeploymentAvailable, Status: v1.ConditionTrue}},
+			}},
+			expected: true,
+		},
+		{
+			desc: "available condition is false",
+			deployment: appsV1.Deployment{
+				Spec: appsV1.DeploymentSpec{Replicas: &replicas},
+				Status: appsV1.DeploymentStatus{
+					AvailableReplicas: 3,
+					Conditions:        []appsV1.DeploymentCondition{{Type: appsV1.DeploymentAvailable, Status: v1.ConditionFalse}},
+				},
+			},
+			expected: false,
+		},
+		{
+			desc: "no condition and enough available replicas",
+			deployment: appsV1.Deployment{
+				Spec:   appsV1.DeploymentSpec{Replicas: &replicas},
+				Status: appsV1.DeploymentStatus{AvailableReplicas: 3},
+			},
+			expected: true,
+		},
+		{
+			desc: "no condition and too few available replicas",
+			deployment: appsV1.Deployment{
+				Spec:   appsV1.DeploymentSpec{Replicas: &replicas},
+				Status: appsV1.DeploymentStatus{AvailableReplicas: 2},
+			},
+			expected: false,
+		},
+		{
+			desc:       "no condition and the default single replica unavailable",
+			deployment: appsV1.Deployment{},
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := DeploymentIsAvailable(&tc.deployment); actual != tc.expected {
+			t.Fatalf("%s: expected %t, got %t", tc.desc, tc.expected, actual)
+		}
+	}
+}
+
+func TestRolloutStuck(t *testing.T) {
+	stuck := appsV1.Deployment{Status: appsV1.DeploymentStatus{
+		Conditions: []appsV1.DeploymentCondition{{
+			Type:    appsV1.DeploymentProgressing,
+			Status:  v1.ConditionFalse,
+			Reason:  "ProgressDeadlineExceeded",
+			Message: "ReplicaSet \"linkerd-controller-5f7c9c7b8d\" has timed out progressing.",
+		}},
+	}}
+	if ok, message := RolloutStuck(&stuck); !ok || message != "ReplicaSet \"linkerd-controller-5f7c9c7b8d\" has timed out progressing." {
+		t.Fatalf("Expected the rollout to be stuck, got %t %q", ok, message)
+	}
+
+	progressing := appsV1.Deployment{Status: appsV1.DeploymentStatus{
+		Conditions: []appsV1.DeploymentCondition{{Type: appsV1.DeploymentProgressing, Status: v1.ConditionTrue, Reason: "NewReplicaSetAvailable"}},
+	}}
+	if ok, _ := RolloutStuck(&progressing); ok {
+		t.Fatal("Expected the rollout not to be stuck")
+	}
+}
+
+func TestDaemonSetIsAvailable(t *testing.T) {
+	available := appsV1.DaemonSet{Status: appsV1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 3, UpdatedNumberScheduled: 3}}
+	if !DaemonSetIsAvailable(&available) {
+		t.Fatal("Expected the DaemonSet to be available")
+	}
+
+	updating := appsV1.DaemonSet{Status: appsV1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberAvailable: 3, UpdatedNumberScheduled: 2}}
+	if DaemonSetIsAvailable(&updating) {
+		t.Fatal("Expected a DaemonSet with outdated pods not to be available")
+	}
+}
@@ -2,6 +2,9 @@ package profiles
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
@@ -248,6 +251,9 @@ func ValidateRequestMatch(reqMatch *sp.RequestMatch) error {
 	}
 	if reqMatch.Path != "" {
 		matchKindSet = true
+		if _, err := regexp.Compile(reqMatch.Path); err != nil {
+			return fmt.Errorf("Invalid path regex \"%s\": %s", reqMatch.Path, err)
+		}
 	}
 
 	if !matchKindSet {
@@ -298,3 +304,64 @@ func ValidateResponseMatch(rspMatch *sp.ResponseMatch) error {
 
 	return nil
 }
+
+// ValidateServiceProfile returns every problem found in the given
+// ServiceProfile, each prefixed with the path of the offending field, as
+// reported by `linkerd check`. It returns no errors if the ServiceProfile is
+// valid. Its rules are stricter than the destination service's, which only
+// rejects the profiles whose request or response matches are invalid.
+func ValidateServiceProfile(profile *sp.ServiceProfile) []error {
+	errs := []error{}
+
+	if !validProfileName(profile.Name) {
+		errs = append(errs, errors.New("metadata.name: must be \"<service>.<namespace>.svc.cluster.local\""))
+	}
+
+	routeNames := make(map[string]bool)
+	for i, route := range profile.Spec.Routes {
+		field := fmt.Sprintf("spec.routes[%d]", i)
+
+		if route.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name: must not be empty", field))
+		} else if routeNames[route.Name] {
+			errs = append(errs, fmt.Errorf("%s.name: duplicate route name \"%s\"", field, route.Name))
+		}
+		routeNames[route.Name] = true
+
+		if route.Condition == nil {
+			errs = append(errs, fmt.Errorf("%s.condition: must be set", field))
+		} else if err := ValidateRequestMatch(route.Condition); err != nil {
+			errs = append(errs, fmt.Errorf("%s.condition: %s", field, err))
+		}
+
+		for j, rc := range route.ResponseClasses {
+			rcField := fmt.Sprintf("%s.response_classes[%d].condition", field, j)
+			if rc.Condition == nil {
+				errs = append(errs, fmt.Errorf("%s: must be set", rcField))
+			} else if err := ValidateResponseMatch(rc.Condition); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s", rcField, err))
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ServiceProfileService returns the name and namespace of the service a
+// ServiceProfile applies to, parsed from the profile's name.
+func ServiceProfileService(profileName string) (string, string, bool) {
+	if !validProfileName(profileName) {
+		return "", "", false
+	}
+	parts := strings.Split(profileName, ".")
+	return parts[0], parts[1], true
+}
+
+func validProfileName(name string) bool {
+	parts := strings.Split(name, ".")
+	return len(parts) == 5 && parts[0] != "" && parts[1] != "" &&
+		strings.Join(parts[2:], ".") == "svc.cluster.local"
+}
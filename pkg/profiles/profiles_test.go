@@ -0,0 +1,80 @@
+package profiles
+
+import (
+	"reflect"
+	"testing"
+
+	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateServiceProfile(t *testing.T) {
+	t.Run("Returns nil for a valid ServiceProfile", func(t *testing.T) {
+		profile := &sp.ServiceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "books.default.svc.cluster.local"},
+			Spec: sp.ServiceProfileSpec{
+				Routes: []*sp.RouteSpec{
+					&sp.RouteSpec{
+						Name:      "GET /books/{id}",
+						Condition: &sp.RequestMatch{Method: "GET", Path: "^/books/[^/]*$"},
+						ResponseClasses: []*sp.ResponseClass{
+							&sp.ResponseClass{Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 500, Max: 599}}, IsFailure: true},
+						},
+					},
+				},
+			},
+		}
+
+		errs := ValidateServiceProfile(profile)
+		if errs != nil {
+			t.Fatalf("Unexpected errors: %v", errs)
+		}
+	})
+
+	t.Run("Returns every field-level problem", func(t *testing.T) {
+		profile := &sp.ServiceProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "books"},
+			Spec: sp.ServiceProfileSpec{
+				Routes: []*sp.RouteSpec{
+					&sp.RouteSpec{Name: "books", Condition: &sp.RequestMatch{Path: "^/books/(*$"}},
+					&sp.RouteSpec{
+						Name:      "books",
+						Condition: &sp.RequestMatch{Method: "GET"},
+						ResponseClasses: []*sp.ResponseClass{
+							&sp.ResponseClass{Condition: &sp.ResponseMatch{Status: &sp.Range{Min: 599, Max: 500}}},
+						},
+					},
+					&sp.RouteSpec{},
+				},
+			},
+		}
+
+		messages := []string{}
+		for _, err := range ValidateServiceProfile(profile) {
+			messages = append(messages, err.Error())
+		}
+
+		expected := []string{
+			"metadata.name: must be \"<service>.<namespace>.svc.cluster.local\"",
+			"spec.routes[0].condition: Invalid path regex \"^/books/(*$\": error parsing regexp: missing argument to repetition operator: `*`",
+			"spec.routes[1].name: duplicate route name \"books\"",
+			"spec.routes[1].response_classes[0].condition: Range maximum cannot be smaller than minimum",
+			"spec.routes[2].name: must not be empty",
+			"spec.routes[2].condition: must be set",
+		}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Fatalf("Expected errors:\n%v\nbut got:\n%v", expected, messages)
+		}
+	})
+}
+
+func TestServiceProfileService(t *testing.T) {
+	service, namespace, ok := ServiceProfileService("books.default.svc.cluster.local")
+	if !ok || service != "books" || namespace != "default" {
+		t.Fatalf("Unexpected result: %s, %s, %t", service, namespace, ok)
+	}
+
+	if _, _, ok := ServiceProfileService("books.default.svc.example.com"); ok {
+		t.Fatal("Expected invalid profile name to be rejected")
+	}
+}
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	// DefaultLatestVersionCacheTTL is how long the latest versions fetched
+	// from the version endpoint are served from the on-disk cache.
+	DefaultLatestVersionCacheTTL = 24 * time.Hour
+
+	latestVersionCacheFile = "latest-versions.json"
+)
+
+// StaleError is returned along with the cached latest versions when they have
+// expired and could not be refreshed because the version endpoint is
+// unreachable.
+type StaleError struct {
+	FetchedAt time.Time
+	Err       error
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("using the latest versions cached at %s: %s", e.FetchedAt.Format(time.RFC3339), e.Err)
+}
+
+// IsStale returns true if the error is a StaleError.
+func IsStale(err error) bool {
+	_, ok := err.(*StaleError)
+	return ok
+}
+
+// latestVersionCache is the on-disk cache of the versions published by a
+// version endpoint.
+type latestVersionCache struct {
+	URL              string    `json:"url"`
+	FetchedAt        time.Time `json:"fetchedAt"`
+	Versions         Channels  `json:"versions"`
+	MinimumSupported Channels  `json:"minimumSupported,omitempty"`
+}
+
+// published returns the cached versions.
+func (c *latestVersionCache) published() PublishedVersions {
+	return PublishedVersions{Latest: c.Versions, MinimumSupported: c.MinimumSupported}
+}
+
+// cachePath returns the path of the cache file, or "" if the cache is
+// disabled or the user has no cache directory.
+func (o LatestVersionOptions) cachePath() string {
+	if o.CacheTTL < 0 {
+		return ""
+	}
+
+	dir := o.CacheDir
+	if dir == "" {
+		userDir, err := userCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(userDir, "linkerd")
+	}
+	return filepath.Join(dir, latestVersionCacheFile)
+}
+
+// readLatestVersionCache returns the cached latest versions of the given
+// endpoint, or nil if there are none. A cache that cannot be read or parsed is
+// treated as empty.
+func readLatestVersionCache(path, url string) *latestVersionCache {
+	if path == "" {
+		return nil
+	}
+
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cache latestVersionCache
+	if err := json.Unmarshal(bytes, &cache); err != nil {
+		return nil
+	}
+	if cache.URL != url || cache.FetchedAt.IsZero() || len(cache.Versions) == 0 {
+		return nil
+	}
+	return &cache
+}
+
+// writeLatestVersionCache caches the versions published by the given
+// endpoint. The cache is written to a temporary file, then renamed, so that
+// concurrent processes never read a partially written cache.
+func writeLatestVersionCache(path, url string, published PublishedVersions, fetchedAt time.Time) error {
+	if path == "" {
+		return nil
+	}
+
+	bytes, err := json.Marshal(latestVersionCache{
+		URL:              url,
+		FetchedAt:        fetchedAt,
+		Versions:         published.Latest,
+		MinimumSupported: published.MinimumSupported,
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, latestVersionCacheFile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// userCacheDir returns the default directory for user-specific cached data,
+// as os.UserCacheDir does from Go 1.11 onwards.
+func userCacheDir() (string, error) {
+	var dir string
+
+	switch runtime.GOOS {
+	case "windows":
+		dir = os.Getenv("LocalAppData")
+		if dir == "" {
+			return "", fmt.Errorf("%%LocalAppData%% is not defined")
+		}
+
+	case "darwin":
+		dir = os.Getenv("HOME")
+		if dir == "" {
+			return "", fmt.Errorf("$HOME is not defined")
+		}
+		dir = filepath.Join(dir, "Library", "Caches")
+
+	default:
+		dir = os.Getenv("XDG_CACHE_HOME")
+		if dir == "" {
+			dir = os.Getenv("HOME")
+			if dir == "" {
+				return "", fmt.Errorf("neither $XDG_CACHE_HOME nor $HOME are defined")
+			}
+			dir = filepath.Join(dir, ".cache")
+		}
+	}
+
+	return dir, nil
+}
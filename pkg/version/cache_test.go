@@ -0,0 +1,114 @@
+package version
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLatestVersionCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "linkerd-cache")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	down := false
+	options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"stable":"stable-2.1.0","edge":"edge-18.12.1"}`))
+	})
+	defer done()
+	options.CacheTTL = time.Hour
+	options.CacheDir = dir
+	path := filepath.Join(dir, latestVersionCacheFile)
+	expected := Channels{"stable": "stable-2.1.0", "edge": "edge-18.12.1"}
+
+	get := func(t *testing.T, expectedRequests int) error {
+		requests = 0
+		latest, err := GetLatestVersions(context.Background(), "uuid", "cli", options)
+		if !reflect.DeepEqual(latest, expected) {
+			t.Fatalf("Expected %v, got %v (%v)", expected, latest, err)
+		}
+		if requests != expectedRequests {
+			t.Fatalf("Expected %d requests, got %d", expectedRequests, requests)
+		}
+		return err
+	}
+
+	t.Run("Fetches and caches the latest versions", func(t *testing.T) {
+		if err := get(t, 1); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if cache := readLatestVersionCache(path, options.URL); cache == nil || !reflect.DeepEqual(cache.Versions, expected) {
+			t.Fatalf("Expected the latest versions to be cached, got %v", cache)
+		}
+	})
+
+	t.Run("Serves fresh latest versions from the cache", func(t *testing.T) {
+		if err := get(t, 0); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Refetches expired latest versions", func(t *testing.T) {
+		writeLatestVersionCache(path, options.URL, PublishedVersions{Latest: expected}, time.Now().Add(-2*time.Hour))
+		if err := get(t, 1); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if cache := readLatestVersionCache(path, options.URL); time.Since(cache.FetchedAt) > time.Minute {
+			t.Fatalf("Expected the cache to be refreshed, fetched at %s", cache.FetchedAt)
+		}
+	})
+
+	t.Run("Falls back to stale latest versions if the endpoint is unreachable", func(t *testing.T) {
+		fetchedAt := time.Now().Add(-2 * time.Hour)
+		writeLatestVersionCache(path, options.URL, PublishedVersions{Latest: expected}, fetchedAt)
+		down = true
+		defer func() { down = false }()
+
+		err := get(t, 2)
+		if !IsStale(err) {
+			t.Fatalf("Expected the latest versions to be stale, got %v", err)
+		}
+		if !err.(*StaleError).FetchedAt.Equal(fetchedAt) {
+			t.Fatalf("Expected the latest versions to have been fetched at %s, got %s", fetchedAt, err.(*StaleError).FetchedAt)
+		}
+	})
+
+	t.Run("Treats a corrupted cache as a miss", func(t *testing.T) {
+		if err := ioutil.WriteFile(path, []byte(`{"url":`), 0644); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := get(t, 1); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Doesn't serve the latest versions cached for another endpoint", func(t *testing.T) {
+		writeLatestVersionCache(path, "https://mirror.example.com", PublishedVersions{Latest: Channels{"stable": "stable-2.0.0"}}, time.Now())
+		if err := get(t, 1); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Leaves no temporary files behind", func(t *testing.T) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if len(files) != 1 || files[0].Name() != latestVersionCacheFile {
+			t.Fatalf("Expected only the cache file, got %v", files)
+		}
+	})
+}
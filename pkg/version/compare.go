@@ -0,0 +1,187 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionMismatchError is returned when the version of a component, i.e. the
+// CLI, the control plane or the data plane, isn't the latest version of its
+// release channel. Ahead is set if the version is newer than the latest
+// version, e.g. for a release candidate; otherwise Behind counts the releases
+// it lags the latest version by, and is 0 if either version couldn't be
+// parsed, in which case they were compared for equality. For the data plane,
+// Current is the oldest version run by the proxies. It is always returned as a
+// *VersionMismatchError, which callers may assert, or match with errors.As
+// once built with Go 1.13 or later.
+type VersionMismatchError struct {
+	Component string
+	Current   string
+	Latest    string
+	Channel   string
+	Ahead     bool
+	Behind    int
+
+	// unit names the releases counted by Behind
+	unit string
+
+	// breakdown, if set, details the mismatch of each version of a component
+	// running several, in place of the mismatch of Current
+	breakdown string
+}
+
+func (e *VersionMismatchError) Error() string {
+	if e.breakdown != "" {
+		return e.breakdown
+	}
+
+	current := parseVersion(e.Current)
+	latest := parseVersion(e.Latest)
+
+	switch {
+	case e.Ahead:
+		return fmt.Sprintf("is running version %s, which is ahead of the latest %s version %s", current, e.Channel, latest)
+	case e.Behind > 0:
+		return fmt.Sprintf("is running version %s but the latest %s version is %s (%d %s behind)", current, e.Channel, latest, e.Behind, e.pluralUnit())
+	case e.Channel == "":
+		return fmt.Sprintf("is running version %s but the latest version is %s", current, latest)
+	default:
+		return fmt.Sprintf("is running version %s but the latest %s version is %s; the versions could not be compared, so only the latest version itself is accepted",
+			current, e.Channel, latest)
+	}
+}
+
+// pluralUnit names the releases counted by Behind, in the plural if needed.
+func (e *VersionMismatchError) pluralUnit() string {
+	if e.Behind > 1 {
+		return e.unit + "s"
+	}
+	return e.unit
+}
+
+// IsVersionMismatch returns true if the error is a VersionMismatchError.
+func IsVersionMismatch(err error) bool {
+	_, ok := err.(*VersionMismatchError)
+	return ok
+}
+
+// IsAheadOfLatest returns true if the error is a VersionMismatchError for a
+// version newer than the latest version.
+func IsAheadOfLatest(err error) bool {
+	mismatch, ok := err.(*VersionMismatchError)
+	return ok && mismatch.Ahead
+}
+
+// compareVersions returns a VersionMismatchError if the current version
+// differs from the latest version of its channel. The stable-2.MAJOR.MINOR and
+// edge-YY.M.N forms are compared release by release, a release candidate being
+// compared as its base version; any other version must equal the latest
+// version.
+func compareVersions(current, latest string) error {
+	if current == latest {
+		return nil
+	}
+
+	mismatch := &VersionMismatchError{
+		Channel: parseChannel(latest),
+		Current: current,
+		Latest:  latest,
+	}
+
+	currentRelease, ok := parseRelease(current)
+	if !ok || parseChannel(current) != mismatch.Channel {
+		return mismatch
+	}
+	latestRelease, ok := parseRelease(latest)
+	if !ok {
+		return mismatch
+	}
+
+	for i := range currentRelease {
+		if currentRelease[i] == latestRelease[i] {
+			continue
+		}
+		if currentRelease[i] > latestRelease[i] {
+			mismatch.Ahead = true
+			return mismatch
+		}
+		mismatch.Behind, mismatch.unit = releasesBehind(mismatch.Channel, currentRelease, latestRelease, i)
+		return mismatch
+	}
+
+	// the versions only differ in their formatting, e.g. "2.01.0", or one is a
+	// release candidate of the other
+	return nil
+}
+
+// releasesBehind counts the releases between the current and latest releases,
+// which first differ in component i. Edge releases are numbered within their
+// month, so only the months between releases of different months are counted.
+func releasesBehind(channel string, current, latest [3]int, i int) (int, string) {
+	if channel == EdgeChannel {
+		if i == 2 {
+			return latest[2] - current[2], "release"
+		}
+		return (latest[0]-current[0])*12 + latest[1] - current[1], "month"
+	}
+
+	if i == 2 {
+		return latest[2] - current[2], "patch release"
+	}
+	if i == 1 {
+		return latest[1] - current[1], "release"
+	}
+	return latest[0] - current[0], "major release"
+}
+
+// releaseLess returns true if release a precedes release b. Versions that
+// cannot be parsed precede none.
+func releaseLess(a, b string) bool {
+	releaseA, okA := parseRelease(a)
+	releaseB, okB := parseRelease(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := range releaseA {
+		if releaseA[i] != releaseB[i] {
+			return releaseA[i] < releaseB[i]
+		}
+	}
+	return false
+}
+
+// parseRelease parses the stable-2.MAJOR.MINOR and edge-YY.M.N forms into
+// their numeric components. The -rcN suffix of a release candidate is ignored,
+// so that it parses as its base version.
+func parseRelease(version string) ([3]int, bool) {
+	var release [3]int
+	if Channel(version) == "" {
+		return release, false
+	}
+
+	parts := strings.Split(trimReleaseCandidate(parseVersion(version)), ".")
+	if len(parts) != len(release) {
+		return release, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return release, false
+		}
+		release[i] = n
+	}
+	return release, true
+}
+
+// trimReleaseCandidate strips the -rcN suffix of a release candidate, if any.
+func trimReleaseCandidate(version string) string {
+	i := strings.LastIndex(version, "-rc")
+	if i < 0 {
+		return version
+	}
+	if _, err := strconv.Atoi(version[i+len("-rc"):]); err != nil {
+		return version
+	}
+	return version[:i]
+}
@@ -0,0 +1,75 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		current  string
+		latest   string
+		ahead    bool
+		behind   int
+		expected string
+	}{
+		{"stable-2.1.0", "stable-2.1.0", false, 0, ""},
+		{"stable-2.1.0", "stable-2.1.2", false, 2, "is running version 2.1.0 but the latest stable version is 2.1.2 (2 patch releases behind)"},
+		{"stable-2.1.3", "stable-2.3.0", false, 2, "is running version 2.1.3 but the latest stable version is 2.3.0 (2 releases behind)"},
+		{"stable-2.2.0", "stable-2.1.0", true, 0, "is running version 2.2.0, which is ahead of the latest stable version 2.1.0"},
+		{"edge-18.12.1", "edge-18.12.3", false, 2, "is running version 18.12.1 but the latest edge version is 18.12.3 (2 releases behind)"},
+		{"edge-18.11.4", "edge-19.2.1", false, 3, "is running version 18.11.4 but the latest edge version is 19.2.1 (3 months behind)"},
+		{"edge-19.1.1", "edge-18.12.3", true, 0, "is running version 19.1.1, which is ahead of the latest edge version 18.12.3"},
+		{"stable-2.1.0-rc1", "stable-2.1.0", false, 0, ""},
+		{"stable-2.1.0-rc2", "stable-2.1.2", false, 2, "is running version 2.1.0-rc2 but the latest stable version is 2.1.2 (2 patch releases behind)"},
+		{"edge-19.2.1-rc1", "edge-19.1.3", true, 0, "is running version 19.2.1-rc1, which is ahead of the latest edge version 19.1.3"},
+		{"stable-2.1.0-rcx", "stable-2.1.0", false, 0, "is running version 2.1.0-rcx but the latest stable version is 2.1.0; the versions could not be compared, so only the latest version itself is accepted"},
+		{"dev-0123abcd-jane", "dev-4567efab-jane", false, 0, "is running version 0123abcd-jane but the latest dev version is 4567efab-jane; the versions could not be compared, so only the latest version itself is accepted"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.current+" against "+tc.latest, func(t *testing.T) {
+			err := compareVersions(tc.current, tc.latest)
+			if tc.expected == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+
+			mismatch, ok := err.(*VersionMismatchError)
+			if !ok {
+				t.Fatalf("Expected a VersionMismatchError, got %v", err)
+			}
+			if mismatch.Current != tc.current || mismatch.Latest != tc.latest {
+				t.Fatalf("Expected versions %s and %s, got %s and %s", tc.current, tc.latest, mismatch.Current, mismatch.Latest)
+			}
+			if mismatch.Ahead != tc.ahead || mismatch.Behind != tc.behind {
+				t.Fatalf("Expected ahead %t and behind %d, got %t and %d", tc.ahead, tc.behind, mismatch.Ahead, mismatch.Behind)
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("Expected error [%s], got [%s]", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestParseRelease(t *testing.T) {
+	for version, expected := range map[string][3]int{
+		"stable-2.1.0":     {2, 1, 0},
+		"edge-18.12.1":     {18, 12, 1},
+		"stable-2.1.0-rc1": {2, 1, 0},
+		"edge-19.2.1-rc12": {19, 2, 1},
+	} {
+		release, ok := parseRelease(version)
+		if !ok || release != expected {
+			t.Fatalf("Expected %s to parse as %v, got %v", version, expected, release)
+		}
+	}
+
+	for _, version := range []string{"stable-2.1", "edge-18.12.x", "stable-2.1.0-rc", "dev-0123abcd-jane", "git-03a9b48f", "undefined"} {
+		if release, ok := parseRelease(version); ok {
+			t.Fatalf("Expected %s not to parse, got %v", version, release)
+		}
+	}
+}
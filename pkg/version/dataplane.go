@@ -0,0 +1,93 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary counts the data plane proxies by how their version compares with
+// the expected version, each map counting the proxies of every version.
+// Proxies whose version belongs to another release channel, and those whose
+// version could not be compared, such as development builds, are counted
+// separately rather than as outdated.
+type Summary struct {
+	Expected     string         `json:"expected"`
+	UpToDate     int            `json:"upToDate"`
+	Behind       map[string]int `json:"behind,omitempty"`
+	Ahead        map[string]int `json:"ahead,omitempty"`
+	OtherChannel map[string]int `json:"otherChannel,omitempty"`
+	Unparseable  map[string]int `json:"unparseable,omitempty"`
+}
+
+// CheckDataPlaneVersions classifies the observed proxy versions, given as the
+// number of proxies running each version, by comparing them with the expected
+// version, and returns a VersionMismatchError listing the versions behind it.
+func CheckDataPlaneVersions(expected string, observed map[string]int) (Summary, error) {
+	summary := Summary{
+		Expected:     expected,
+		Behind:       map[string]int{},
+		Ahead:        map[string]int{},
+		OtherChannel: map[string]int{},
+		Unparseable:  map[string]int{},
+	}
+	behind := map[string]*VersionMismatchError{}
+
+	for v, count := range observed {
+		err := compareVersions(v, expected)
+		mismatch, _ := err.(*VersionMismatchError)
+		switch {
+		case err == nil:
+			summary.UpToDate += count
+		case mismatch.Ahead:
+			summary.Ahead[v] += count
+		case mismatch.Behind > 0:
+			summary.Behind[v] += count
+			behind[v] = mismatch
+		case Channel(v) != "" && parseChannel(v) != parseChannel(expected):
+			summary.OtherChannel[v] += count
+		default:
+			summary.Unparseable[v] += count
+		}
+	}
+
+	if len(behind) == 0 {
+		return summary, nil
+	}
+
+	versions := []string{}
+	total := 0
+	for v := range behind {
+		versions = append(versions, v)
+		total += summary.Behind[v]
+	}
+	sort.Strings(versions)
+
+	breakdown := []string{}
+	for _, v := range versions {
+		breakdown = append(breakdown, fmt.Sprintf("%d running %s (%d %s behind)",
+			summary.Behind[v], parseVersion(v), behind[v].Behind, behind[v].pluralUnit()))
+	}
+	proxies := "proxies are"
+	if total == 1 {
+		proxies = "proxy is"
+	}
+
+	oldestVersion := versions[0]
+	for _, v := range versions[1:] {
+		if releaseLess(v, oldestVersion) {
+			oldestVersion = v
+		}
+	}
+	oldest := behind[oldestVersion]
+	return summary, &VersionMismatchError{
+		Component: "data plane",
+		Current:   oldest.Current,
+		Latest:    expected,
+		Channel:   oldest.Channel,
+		Behind:    oldest.Behind,
+		unit:      oldest.unit,
+		breakdown: fmt.Sprintf("%d %s not running the latest %s version %s: %s",
+			total, proxies, parseChannel(expected), parseVersion(expected), strings.Join(breakdown, ", ")),
+	}
+}
@@ -0,0 +1,84 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckDataPlaneVersions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		observed map[string]int
+		summary  Summary
+		err      string
+	}{
+		{
+			"Passes when every proxy runs the expected version",
+			"stable-2.1.0",
+			map[string]int{"stable-2.1.0": 5},
+			Summary{Expected: "stable-2.1.0", UpToDate: 5},
+			"",
+		},
+		{
+			"Lists the versions behind the expected version",
+			"stable-2.1.2",
+			map[string]int{"stable-2.1.2": 3, "stable-2.1.1": 2, "stable-2.0.0": 1, "stable-2.2.0": 1},
+			Summary{
+				Expected: "stable-2.1.2",
+				UpToDate: 3,
+				Behind:   map[string]int{"stable-2.1.1": 2, "stable-2.0.0": 1},
+				Ahead:    map[string]int{"stable-2.2.0": 1},
+			},
+			"3 proxies are not running the latest stable version 2.1.2: 1 running 2.0.0 (1 release behind), 2 running 2.1.1 (1 patch release behind)",
+		},
+		{
+			"Counts the proxies of a mixed stable and edge fleet separately",
+			"edge-18.12.3",
+			map[string]int{"edge-18.12.3": 4, "edge-18.12.1": 1, "stable-2.1.0": 2},
+			Summary{
+				Expected:     "edge-18.12.3",
+				UpToDate:     4,
+				Behind:       map[string]int{"edge-18.12.1": 1},
+				OtherChannel: map[string]int{"stable-2.1.0": 2},
+			},
+			"1 proxy is not running the latest edge version 18.12.3: 1 running 18.12.1 (2 releases behind)",
+		},
+		{
+			"Doesn't fail on development builds",
+			"stable-2.1.0",
+			map[string]int{"stable-2.1.0": 1, "dev-0123abcd-jane": 2, "git-4567efab": 1},
+			Summary{
+				Expected:    "stable-2.1.0",
+				UpToDate:    1,
+				Unparseable: map[string]int{"dev-0123abcd-jane": 2, "git-4567efab": 1},
+			},
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := CheckDataPlaneVersions(tc.expected, tc.observed)
+			if tc.err == "" && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.err != "" && (err == nil || err.Error() != tc.err) {
+				t.Fatalf("Expected error [%s], got [%v]", tc.err, err)
+			}
+			if mismatch, ok := err.(*VersionMismatchError); tc.err != "" && (!ok || mismatch.Component != "data plane" || mismatch.Latest != tc.expected) {
+				t.Fatalf("Expected a data plane VersionMismatchError, got %#v", err)
+			}
+
+			for _, m := range []*map[string]int{&tc.summary.Behind, &tc.summary.Ahead, &tc.summary.OtherChannel, &tc.summary.Unparseable} {
+				if *m == nil {
+					*m = map[string]int{}
+				}
+			}
+			if !reflect.DeepEqual(summary, tc.summary) {
+				t.Fatalf("Expected summary %+v, got %+v", tc.summary, summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package version
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLatestVersionURL is the base URL of the endpoint publishing the
+	// latest version of each release channel.
+	DefaultLatestVersionURL = "https://versioncheck.linkerd.io"
+
+	// DefaultLatestVersionTimeout bounds each request made to the version
+	// endpoint by GetLatestVersions, unless another timeout is given.
+	DefaultLatestVersionTimeout = 3 * time.Second
+
+	// minimumSupportedField is the field of the version endpoint's response
+	// holding the minimum supported version of each channel.
+	minimumSupportedField = "minimumSupported"
+)
+
+// LatestVersionOptions configures how GetLatestVersions reaches the version
+// endpoint. Requests are sent through the proxy configured by the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+type LatestVersionOptions struct {
+	// Timeout bounds each request. Defaults to DefaultLatestVersionTimeout.
+	Timeout time.Duration
+
+	// URL is the base URL of the version endpoint, e.g. that of an internal
+	// mirror serving version.json. Defaults to DefaultLatestVersionURL.
+	URL string
+
+	// CAFile, if set, is the PEM-encoded CA bundle the endpoint's certificate
+	// is verified with, instead of the system's.
+	CAFile string
+
+	// CacheTTL is how long the latest versions fetched from the endpoint are
+	// served from the on-disk cache. Defaults to DefaultLatestVersionCacheTTL;
+	// a negative TTL disables the cache.
+	CacheTTL time.Duration
+
+	// CacheDir is the directory the cache is kept in. Defaults to the
+	// "linkerd" directory of the user's cache directory.
+	CacheDir string
+
+	// Anonymous, if set, strips the CLI's version, the install's UUID and the
+	// request's source from the request, so that it carries no identifying
+	// parameters.
+	Anonymous bool
+}
+
+// endpoint returns the base URL of the version endpoint, without a trailing
+// slash.
+func (o LatestVersionOptions) endpoint() string {
+	if o.URL == "" {
+		return DefaultLatestVersionURL
+	}
+	return strings.TrimSuffix(o.URL, "/")
+}
+
+// client returns an HTTP client trusting the options' CA bundle, if any.
+func (o LatestVersionOptions) client() (*http.Client, error) {
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: o.Timeout,
+	}
+
+	if o.CAFile != "" {
+		pem, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the version endpoint's CA bundle: %s", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("the version endpoint's CA bundle %s contains no PEM-encoded certificates", o.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// UnreachableError is returned when the version endpoint could not be reached,
+// or failed to respond, within the timeout. It means that the latest versions
+// are unknown, not that Linkerd is unhealthy.
+type UnreachableError struct {
+	URL string
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("the version endpoint %s is unreachable, so the latest version could not be determined: %s", e.URL, e.Err)
+}
+
+// IsUnreachable returns true if the error is an UnreachableError.
+func IsUnreachable(err error) bool {
+	_, ok := err.(*UnreachableError)
+	return ok
+}
+
+// PublishedVersions holds the versions published by the version endpoint: the
+// latest version of each release channel and, if the endpoint publishes it,
+// the minimum version each channel still supports.
+type PublishedVersions struct {
+	Latest           Channels `json:"latest"`
+	MinimumSupported Channels `json:"minimumSupported,omitempty"`
+}
+
+// GetLatestVersions returns the latest version of each release channel, as
+// published by the version endpoint. See GetPublishedVersions.
+func GetLatestVersions(ctx context.Context, uuid string, source string, options LatestVersionOptions) (Channels, error) {
+	published, err := GetPublishedVersions(ctx, uuid, source, options)
+	return published.Latest, err
+}
+
+// GetPublishedVersions returns the versions published by the version endpoint.
+// They are served from the on-disk cache until they expire; once expired,
+// they are still returned, along with a StaleError, if the endpoint is
+// unreachable. Each request is bounded by the options' timeout, and a request
+// that fails transiently is retried once.
+func GetPublishedVersions(ctx context.Context, uuid string, source string, options LatestVersionOptions) (PublishedVersions, error) {
+	if options.CacheTTL == 0 {
+		options.CacheTTL = DefaultLatestVersionCacheTTL
+	}
+
+	endpoint := options.endpoint()
+	cachePath := options.cachePath()
+	cache := readLatestVersionCache(cachePath, endpoint)
+	if cache != nil && time.Since(cache.FetchedAt) < options.CacheTTL {
+		return cache.published(), nil
+	}
+
+	published, err := fetchLatestVersions(ctx, uuid, source, options)
+	if err != nil {
+		if cache != nil && IsUnreachable(err) {
+			return cache.published(), &StaleError{FetchedAt: cache.FetchedAt, Err: err}
+		}
+		return PublishedVersions{}, err
+	}
+
+	// the published versions are refetched next time if they cannot be cached
+	writeLatestVersionCache(cachePath, endpoint, published, time.Now())
+	return published, nil
+}
+
+// fetchLatestVersions requests the published versions from the version
+// endpoint.
+func fetchLatestVersions(ctx context.Context, uuid string, source string, options LatestVersionOptions) (PublishedVersions, error) {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultLatestVersionTimeout
+	}
+
+	client, err := options.client()
+	if err != nil {
+		return PublishedVersions{}, err
+	}
+
+	requestURL := LatestVersionRequestURL(uuid, source, options)
+
+	var published PublishedVersions
+	for attempt := 0; attempt < 2; attempt++ {
+		var transient bool
+		published, transient, err = getLatestVersions(ctx, client, requestURL, options.Timeout)
+		if !transient || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if ctx.Err() != nil {
+		return PublishedVersions{}, ctx.Err()
+	}
+	if unreachable, ok := err.(*UnreachableError); ok {
+		unreachable.URL = options.endpoint()
+	}
+	return published, err
+}
+
+// LatestVersionRequestURL returns the URL GetLatestVersions requests, with
+// the parameters sent to the version endpoint, if any.
+func LatestVersionRequestURL(uuid string, source string, options LatestVersionOptions) string {
+	requestURL := fmt.Sprintf("%s/version.json", options.endpoint())
+	if options.Anonymous {
+		return requestURL
+	}
+
+	query := url.Values{}
+	query.Set("version", Version)
+	query.Set("uuid", uuid)
+	query.Set("source", source)
+	return fmt.Sprintf("%s?%s", requestURL, query.Encode())
+}
+
+// getLatestVersions makes a single request to the version endpoint, and
+// reports whether a failure is transient, i.e. worth retrying.
+func getLatestVersions(ctx context.Context, client *http.Client, requestURL string, timeout time.Duration) (PublishedVersions, bool, error) {
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return PublishedVersions{}, false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rsp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return PublishedVersions{}, true, &UnreachableError{Err: err}
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode >= 500 {
+		return PublishedVersions{}, true, &UnreachableError{Err: fmt.Errorf("unexpected response: %s", rsp.Status)}
+	}
+	if rsp.StatusCode != 200 {
+		return PublishedVersions{}, false, fmt.Errorf("Unexpected versioncheck response: %s", rsp.Status)
+	}
+
+	bytes, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return PublishedVersions{}, true, &UnreachableError{Err: err}
+	}
+
+	published, err := parseLatestVersions(bytes)
+	if err != nil {
+		return PublishedVersions{}, false, err
+	}
+	return published, false, nil
+}
+
+// parseLatestVersions parses the version endpoint's response, which maps each
+// channel to its latest version. Every channel is kept, including those not
+// known to this version of the CLI, and fields whose value isn't a version
+// are ignored, except for the optional minimumSupported field, which maps
+// channels to the minimum version they support.
+func parseLatestVersions(body []byte) (PublishedVersions, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return PublishedVersions{}, fmt.Errorf("Invalid versioncheck response: %s", err)
+	}
+
+	published := PublishedVersions{Latest: Channels{}}
+	for channel, value := range fields {
+		var version string
+		if json.Unmarshal(value, &version) == nil {
+			published.Latest[channel] = version
+		}
+	}
+	if len(published.Latest) == 0 {
+		return PublishedVersions{}, fmt.Errorf("Invalid versioncheck response: no latest versions")
+	}
+
+	if value, ok := fields[minimumSupportedField]; ok {
+		var minimums map[string]interface{}
+		if err := json.Unmarshal(value, &minimums); err != nil {
+			return PublishedVersions{}, fmt.Errorf("Invalid versioncheck response: invalid %s field: %s", minimumSupportedField, err)
+		}
+		for channel, value := range minimums {
+			if version, ok := value.(string); ok {
+				if published.MinimumSupported == nil {
+					published.MinimumSupported = Channels{}
+				}
+				published.MinimumSupported[channel] = version
+			}
+		}
+	}
+	return published, nil
+}
+
+// GetLatestVersion returns the latest version of the CLI's release channel,
+// as published by the default version endpoint. As for GetLatestVersions, a
+// stale version is returned along with a StaleError.
+func GetLatestVersion(ctx context.Context, uuid string, source string) (string, error) {
+	return getLatestVersion(ctx, uuid, source, LatestVersionOptions{})
+}
+
+// getLatestVersion looks the CLI's release channel up in the latest versions
+// published by the configured version endpoint.
+func getLatestVersion(ctx context.Context, uuid string, source string, options LatestVersionOptions) (string, error) {
+	latest, err := GetLatestVersions(ctx, uuid, source, options)
+	if err != nil && !IsStale(err) {
+		return "", err
+	}
+
+	channel := Channel(Version)
+	if channel == "" {
+		return "", &UnreleasedVersionError{Version: Version}
+	}
+
+	version, ok := latest[channel]
+	if !ok {
+		return "", fmt.Errorf("Unsupported version channel: %s", channel)
+	}
+
+	return version, err
+}
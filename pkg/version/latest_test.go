@@ -0,0 +1,277 @@
+package version
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// serveLatestVersions serves the latest versions with the given handler, and
+// returns the options to reach it with, without a cache.
+func serveLatestVersions(handler http.HandlerFunc) (LatestVersionOptions, func()) {
+	server := httptest.NewServer(handler)
+	return LatestVersionOptions{URL: server.URL, CacheTTL: -1}, server.Close
+}
+
+func TestGetLatestVersions(t *testing.T) {
+	t.Run("Returns the latest version of each channel", func(t *testing.T) {
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"stable":"stable-2.1.0","edge":"edge-18.12.1"}`))
+		})
+		defer done()
+
+		latest, err := GetLatestVersions(context.Background(), "uuid", "cli", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := Channels{"stable": "stable-2.1.0", "edge": "edge-18.12.1"}
+		if !reflect.DeepEqual(latest, expected) {
+			t.Fatalf("Expected %v, got %v", expected, latest)
+		}
+	})
+
+	t.Run("Retries a failed request once", func(t *testing.T) {
+		requests := 0
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"stable":"stable-2.1.0"}`))
+		})
+		defer done()
+
+		if _, err := GetLatestVersions(context.Background(), "uuid", "cli", options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Reports an endpoint that doesn't respond in time as unreachable", func(t *testing.T) {
+		requests := 0
+		unblock := make(chan struct{})
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			<-unblock
+		})
+		defer done()
+		defer close(unblock)
+
+		options.Timeout = 50 * time.Millisecond
+		_, err := GetLatestVersions(context.Background(), "uuid", "cli", options)
+		if !IsUnreachable(err) {
+			t.Fatalf("Expected the endpoint to be unreachable, got %v", err)
+		}
+		if requests != 2 {
+			t.Fatalf("Expected 2 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Doesn't retry an unexpected response", func(t *testing.T) {
+		requests := 0
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer done()
+
+		_, err := GetLatestVersions(context.Background(), "uuid", "cli", options)
+		if err == nil || IsUnreachable(err) {
+			t.Fatalf("Expected an unexpected response error, got %v", err)
+		}
+		if requests != 1 {
+			t.Fatalf("Expected 1 request, got %d", requests)
+		}
+	})
+
+	t.Run("Stops once the context is canceled", func(t *testing.T) {
+		unblock := make(chan struct{})
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			<-unblock
+		})
+		defer done()
+		defer close(unblock)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		options.Timeout = time.Minute
+		_, err := GetLatestVersions(ctx, "uuid", "cli", options)
+		if err != context.DeadlineExceeded {
+			t.Fatalf("Expected the context's error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("Expected to return once the context is done, took %s", elapsed)
+		}
+	})
+
+	t.Run("Queries a mirror trusted with a custom CA bundle", func(t *testing.T) {
+		var query url.Values
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/mirror/version.json" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			query = r.URL.Query()
+			w.Write([]byte(`{"stable":"stable-2.1.0"}`))
+		}))
+		defer server.Close()
+
+		options := LatestVersionOptions{URL: server.URL + "/mirror/", CacheTTL: -1}
+		if _, err := GetLatestVersions(context.Background(), "uuid", "cli", options); !IsUnreachable(err) {
+			t.Fatalf("Expected the mirror's certificate not to be trusted, got %v", err)
+		}
+
+		caFile, err := ioutil.TempFile("", "ca")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		defer os.Remove(caFile.Name())
+		pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		caFile.Close()
+
+		options.CAFile = caFile.Name()
+		if _, err := GetLatestVersions(context.Background(), "uuid", "cli", options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := url.Values{"version": {Version}, "uuid": {"uuid"}, "source": {"cli"}}
+		if !reflect.DeepEqual(query, expected) {
+			t.Fatalf("Expected query %v, got %v", expected, query)
+		}
+	})
+}
+
+func TestParseLatestVersions(t *testing.T) {
+	testCases := []struct {
+		response string
+		expected Channels
+	}{
+		{"version.json", Channels{"stable": "stable-2.1.0", "edge": "edge-18.12.1"}},
+		{"version_unknown_channel.json", Channels{"stable": "stable-2.2.1", "edge": "edge-19.2.3", "lts": "lts-2.1.4"}},
+		{"version_extra_fields.json", Channels{"stable": "stable-2.2.1", "edge": "edge-19.2.3"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.response, func(t *testing.T) {
+			body, err := ioutil.ReadFile(filepath.Join("testdata", tc.response))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(body)
+			})
+			defer done()
+
+			latest, err := GetLatestVersions(context.Background(), "uuid", "cli", options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(latest, tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, latest)
+			}
+		})
+	}
+
+	t.Run("version_minimum_supported.json", func(t *testing.T) {
+		body, err := ioutil.ReadFile(filepath.Join("testdata", "version_minimum_supported.json"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		published, err := parseLatestVersions(body)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := PublishedVersions{
+			Latest:           Channels{"stable": "stable-2.2.1", "edge": "edge-19.2.3"},
+			MinimumSupported: Channels{"stable": "stable-2.1.0", "edge": "edge-18.12.1"},
+		}
+		if !reflect.DeepEqual(published, expected) {
+			t.Fatalf("Expected %v, got %v", expected, published)
+		}
+	})
+
+	for _, body := range []string{`{"stable":`, `{}`, `["stable-2.1.0"]`, `{"stable":"stable-2.1.0","minimumSupported":"stable-2.0.0"}`} {
+		if latest, err := parseLatestVersions([]byte(body)); err == nil {
+			t.Fatalf("Expected an error for %s, got %v", body, latest)
+		}
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	cliVersion := Version
+	defer func() { Version = cliVersion }()
+
+	body, err := ioutil.ReadFile(filepath.Join("testdata", "version_unknown_channel.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+	defer done()
+
+	for v, expected := range map[string]string{
+		"edge-19.1.1":  "edge-19.2.3",
+		"stable-2.2.0": "stable-2.2.1",
+	} {
+		Version = v
+		latest, err := getLatestVersion(context.Background(), "uuid", "cli", options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if latest != expected {
+			t.Fatalf("Expected the latest version of %s to be %s, got %s", v, expected, latest)
+		}
+	}
+
+	Version = "dev-0123abcd-jane"
+	if _, err := getLatestVersion(context.Background(), "uuid", "cli", options); !IsUnreleased(err) {
+		t.Fatalf("Expected an UnreleasedVersionError, got %v", err)
+	}
+}
+
+func TestLatestVersionRequestURL(t *testing.T) {
+	cliVersion := Version
+	defer func() { Version = cliVersion }()
+	Version = "stable-2.1.0"
+
+	for _, anonymous := range []bool{false, true} {
+		var requested *url.URL
+		options, done := serveLatestVersions(func(w http.ResponseWriter, r *http.Request) {
+			requested = r.URL
+			w.Write([]byte(`{"stable":"stable-2.1.0"}`))
+		})
+		defer done()
+		options.Anonymous = anonymous
+
+		expected := options.URL + "/version.json?source=cli&uuid=0123-abcd&version=stable-2.1.0"
+		if anonymous {
+			expected = options.URL + "/version.json"
+		}
+		if requestURL := LatestVersionRequestURL("0123-abcd", "cli", options); requestURL != expected {
+			t.Fatalf("Expected request URL %s, got %s", expected, requestURL)
+		}
+
+		if _, err := GetLatestVersions(context.Background(), "0123-abcd", "cli", options); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if actual := options.URL + requested.String(); actual != expected {
+			t.Fatalf("Expected the request to be made to %s, got %s", expected, actual)
+		}
+	}
+}
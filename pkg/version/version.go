@@ -2,11 +2,9 @@ package version
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,10 +15,7 @@ import (
 // This var is updated automatically as part of the build process
 var Version = undefinedVersion
 
-const (
-	undefinedVersion = "undefined"
-	versionCheckURL  = "https://versioncheck.linkerd.io/version.json?version=%s&uuid=%s&source=%s"
-)
+const undefinedVersion = "undefined"
 
 func init() {
 	// Use `$LINKERD_CONTAINER_VERSION_OVERRIDE` as the version only if the
@@ -36,72 +31,212 @@ func init() {
 	}
 }
 
-func CheckClientVersion(expectedVersion string) error {
-	if Version != expectedVersion {
-		return versionMismatchError(expectedVersion, Version)
-	}
+// Release channels of the versions published by the version endpoint.
+const (
+	StableChannel = "stable"
+	EdgeChannel   = "edge"
+)
 
-	return nil
+// Channels holds the latest version of each release channel, keyed by channel
+// name, e.g. "edge" => "edge-18.12.1".
+type Channels map[string]string
+
+// UnreleasedVersionError is returned when a version isn't part of a release
+// channel, as is the case for development builds, e.g. "dev-03a9b48f" or
+// "git-03a9b48f", so that it cannot be compared with a latest version.
+type UnreleasedVersionError struct {
+	Version string
 }
 
-func CheckServerVersion(apiClient pb.ApiClient, expectedVersion string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+func (e *UnreleasedVersionError) Error() string {
+	return fmt.Sprintf("version %s is a development build, not a release", e.Version)
+}
 
-	rsp, err := apiClient.Version(ctx, &pb.Empty{})
-	if err != nil {
-		return err
+// IsUnreleased returns true if the error is an UnreleasedVersionError.
+func IsUnreleased(err error) bool {
+	_, ok := err.(*UnreleasedVersionError)
+	return ok
+}
+
+// Channel returns the release channel of the given version, e.g. "stable" for
+// "stable-2.1.0", or "" if it isn't part of one.
+func Channel(version string) string {
+	switch channel := parseChannel(version); channel {
+	case StableChannel, EdgeChannel:
+		return channel
+	default:
+		return ""
 	}
+}
 
-	if v := rsp.GetReleaseVersion(); v != expectedVersion {
-		return versionMismatchError(expectedVersion, v)
+// NewChannels returns the Channels whose only latest version is the given
+// one, to compare versions against an expected version rather than against
+// those published by the version endpoint.
+func NewChannels(version string) Channels {
+	return Channels{parseChannel(version): version}
+}
+
+// Match returns the release channel of the given version, and a
+// VersionMismatchError if it isn't the latest version of that channel.
+func (c Channels) Match(actualVersion string) (string, error) {
+	channel := parseChannel(actualVersion)
+	latest, ok := c[channel]
+	if !ok {
+		if Channel(actualVersion) == "" {
+			return "", &UnreleasedVersionError{Version: actualVersion}
+		}
+		return channel, fmt.Errorf("is running version %s but the latest %s version is unknown",
+			parseVersion(actualVersion), channel)
 	}
 
-	return nil
+	return channel, compareVersions(actualVersion, latest)
 }
 
-func GetLatestVersion(uuid string, source string) (string, error) {
-	url := fmt.Sprintf(versionCheckURL, Version, uuid, source)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", err
+// CheckClientVersion compares the CLI's version with the latest version of its
+// release channel, and returns that channel.
+func CheckClientVersion(latest Channels) (string, error) {
+	channel, err := latest.Match(Version)
+	return channel, withComponent(err, "cli")
+}
+
+// ComponentVersionsError is returned when the control plane components, as
+// deployed, don't all run the version reported by the public API, e.g. during
+// or after a partial upgrade. Components maps each component to its version.
+type ComponentVersionsError struct {
+	APIVersion string
+	Components map[string]string
+	latest     Channels
+}
+
+func (e *ComponentVersionsError) Error() string {
+	names := []string{}
+	for name := range e.Components {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+
+	breakdown := []string{}
+	for _, name := range names {
+		v := e.Components[name]
+		entry := fmt.Sprintf("%s runs %s", name, v)
+		_, err := e.latest.Match(v)
+		if mismatch, ok := err.(*VersionMismatchError); ok {
+			switch {
+			case mismatch.Behind > 0:
+				entry += fmt.Sprintf(" (%d %s behind the latest %s version)", mismatch.Behind, mismatch.pluralUnit(), mismatch.Channel)
+			case !mismatch.Ahead:
+				entry += " (not the latest version)"
+			}
+		}
+		breakdown = append(breakdown, entry)
+	}
+
+	return fmt.Sprintf("the control plane components run different versions, and the public API reports %s: %s",
+		e.APIVersion, strings.Join(breakdown, ", "))
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// GetServerVersion returns the control plane's version, as reported by the
+// public API.
+func GetServerVersion(ctx context.Context, apiClient pb.ApiClient) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	rsp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	rsp, err := apiClient.Version(ctx, &pb.Empty{})
 	if err != nil {
 		return "", err
 	}
-	defer rsp.Body.Close()
+	return rsp.GetReleaseVersion(), nil
+}
 
-	if rsp.StatusCode != 200 {
-		return "", fmt.Errorf("Unexpected versioncheck response: %s", rsp.Status)
+// ChannelMismatchError is returned when the CLI and the control plane run
+// versions of different release channels.
+type ChannelMismatchError struct {
+	ClientVersion string
+	ServerVersion string
+}
+
+func (e *ChannelMismatchError) Error() string {
+	install := "https://run.linkerd.io/install"
+	if Channel(e.ServerVersion) == EdgeChannel {
+		install = "https://run.linkerd.io/install-edge"
 	}
+	return fmt.Sprintf("the CLI runs the %s version %s but the control plane runs the %s version %s; install the matching CLI with \"curl -sL %s | sh\"",
+		Channel(e.ClientVersion), parseVersion(e.ClientVersion), Channel(e.ServerVersion), parseVersion(e.ServerVersion), install)
+}
 
-	bytes, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return "", err
+// CheckChannels returns a ChannelMismatchError if the CLI and control plane
+// versions belong to different release channels, or an UnreleasedVersionError
+// if either is a development build, which isn't part of a release channel.
+func CheckChannels(clientVersion, serverVersion string) error {
+	for _, v := range []string{clientVersion, serverVersion} {
+		if Channel(v) == "" {
+			return &UnreleasedVersionError{Version: v}
+		}
 	}
 
-	var versionRsp map[string]string
-	err = json.Unmarshal(bytes, &versionRsp)
+	if Channel(clientVersion) != Channel(serverVersion) {
+		return &ChannelMismatchError{ClientVersion: clientVersion, ServerVersion: serverVersion}
+	}
+	return nil
+}
+
+// CheckServerVersion compares the control plane's version with the latest
+// version of its release channel, and returns that channel. The version
+// reported by the public API is cross-checked against the given versions of
+// the control plane components, as deployed, which must all match it.
+func CheckServerVersion(ctx context.Context, apiClient pb.ApiClient, latest Channels, components map[string]string) (string, error) {
+	apiVersion, err := GetServerVersion(ctx, apiClient)
 	if err != nil {
 		return "", err
 	}
+	return MatchServerVersion(apiVersion, latest, components)
+}
 
-	channel := parseChannel(Version)
-	if channel == "" {
-		return "", fmt.Errorf("Unsupported version format: %s", Version)
+// MatchServerVersion is CheckServerVersion for a control plane version that
+// is already known.
+func MatchServerVersion(apiVersion string, latest Channels, components map[string]string) (string, error) {
+	for _, v := range components {
+		if v != apiVersion {
+			return parseChannel(apiVersion), &ComponentVersionsError{APIVersion: apiVersion, Components: components, latest: latest}
+		}
 	}
 
-	version, ok := versionRsp[channel]
-	if !ok {
-		return "", fmt.Errorf("Unsupported version channel: %s", channel)
+	channel, err := latest.Match(apiVersion)
+	return channel, withComponent(err, "control plane")
+}
+
+// UnsupportedVersionError is returned when a version is older than the minimum
+// version its release channel still supports.
+type UnsupportedVersionError struct {
+	Current string
+	Minimum string
+	Channel string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("is running version %s, which is below the minimum supported %s version %s",
+		parseVersion(e.Current), e.Channel, parseVersion(e.Minimum))
+}
+
+// CheckMinimumVersion returns an UnsupportedVersionError if the given version
+// precedes the minimum supported version of its release channel. Versions
+// whose channel has no minimum supported version, and those that cannot be
+// compared with it, such as development builds, are accepted.
+func CheckMinimumVersion(minimums Channels, actualVersion string) error {
+	channel := parseChannel(actualVersion)
+	minimum, ok := minimums[channel]
+	if !ok || !releaseLess(actualVersion, minimum) {
+		return nil
 	}
+	return &UnsupportedVersionError{Current: actualVersion, Minimum: minimum, Channel: channel}
+}
 
-	return version, nil
+// withComponent sets the component of a VersionMismatchError.
+func withComponent(err error, component string) error {
+	if mismatch, ok := err.(*VersionMismatchError); ok {
+		mismatch.Component = component
+	}
+	return err
 }
 
 func parseVersion(version string) string {
@@ -117,17 +252,3 @@ func parseChannel(version string) string {
 	}
 	return ""
 }
-
-func versionMismatchError(expectedVersion, actualVersion string) error {
-	channel := parseChannel(expectedVersion)
-	expectedVersionStr := parseVersion(expectedVersion)
-	actualVersionStr := parseVersion(actualVersion)
-
-	if channel != "" {
-		return fmt.Errorf("is running version %s but the latest %s version is %s",
-			actualVersionStr, channel, expectedVersionStr)
-	} else {
-		return fmt.Errorf("is running version %s but the latest version is %s",
-			actualVersionStr, expectedVersionStr)
-	}
-}
@@ -1,6 +1,7 @@
 package version_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/linkerd/linkerd2/controller/api/public"
@@ -10,24 +11,31 @@ import (
 
 func TestCheckClientVersion(t *testing.T) {
 	t.Run("Passes when client version matches", func(t *testing.T) {
-		err := version.CheckClientVersion(version.Version)
+		_, err := version.CheckClientVersion(version.NewChannels(version.Version))
 		if err != nil {
 			t.Fatalf("Unexpected error: %s", err)
 		}
 	})
 
 	t.Run("Fails when client version does not match", func(t *testing.T) {
-		err := version.CheckClientVersion(version.Version + "latest")
+		_, err := version.CheckClientVersion(version.NewChannels(version.Version + "latest"))
 		if err == nil {
 			t.Fatalf("Expected error, got none")
 		}
+		mismatch, ok := err.(*version.VersionMismatchError)
+		if !ok {
+			t.Fatalf("Expected a VersionMismatchError, got %v", err)
+		}
+		if mismatch.Component != "cli" || mismatch.Current != version.Version || mismatch.Latest != version.Version+"latest" {
+			t.Fatalf("Unexpected mismatch: %+v", mismatch)
+		}
 	})
 }
 
 func TestCheckServerVersion(t *testing.T) {
 	t.Run("Passes when server version matches", func(t *testing.T) {
 		apiClient := createMockPublicApi(version.Version)
-		err := version.CheckServerVersion(apiClient, version.Version)
+		_, err := version.CheckServerVersion(context.Background(), apiClient, version.NewChannels(version.Version), nil)
 		if err != nil {
 			t.Fatalf("Unexpected error: %s", err)
 		}
@@ -35,10 +43,128 @@ func TestCheckServerVersion(t *testing.T) {
 
 	t.Run("Fails when server version does not match", func(t *testing.T) {
 		apiClient := createMockPublicApi(version.Version + "latest")
-		err := version.CheckServerVersion(apiClient, version.Version)
+		_, err := version.CheckServerVersion(context.Background(), apiClient, version.NewChannels(version.Version), nil)
 		if err == nil {
 			t.Fatalf("Expected error, got none")
 		}
+		if mismatch, ok := err.(*version.VersionMismatchError); !ok || mismatch.Component != "control plane" {
+			t.Fatalf("Expected a control plane VersionMismatchError, got %v", err)
+		}
+	})
+}
+
+func TestChannelsMatch(t *testing.T) {
+	latest := version.Channels{
+		version.StableChannel: "stable-2.1.0",
+		version.EdgeChannel:   "edge-18.12.1",
+	}
+
+	testCases := []struct {
+		version  string
+		channel  string
+		expected string
+	}{
+		{"stable-2.1.0", "stable", ""},
+		{"edge-18.12.1", "edge", ""},
+		{"stable-2.1.0-rc1", "stable", ""},
+		{"stable-2.0.0", "stable", "is running version 2.0.0 but the latest stable version is 2.1.0 (1 release behind)"},
+		{"edge-18.11.3", "edge", "is running version 18.11.3 but the latest edge version is 18.12.1 (1 month behind)"},
+		{"dev-0123abcd-jane", "", "version dev-0123abcd-jane is a development build, not a release"},
+		{"git-03a9b48f", "", "version git-03a9b48f is a development build, not a release"},
+		{"undefined", "", "version undefined is a development build, not a release"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.version, func(t *testing.T) {
+			channel, err := latest.Match(tc.version)
+			if channel != tc.channel {
+				t.Fatalf("Expected channel %q, got %q", tc.channel, channel)
+			}
+			if tc.expected == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expected, err)
+			}
+			if version.IsUnreleased(err) != (tc.channel == "") {
+				t.Fatalf("Unexpected error type %T", err)
+			}
+		})
+	}
+
+	t.Run("Fails when the channel's latest version is unknown", func(t *testing.T) {
+		_, err := version.Channels{version.StableChannel: "stable-2.1.0"}.Match("edge-18.12.1")
+		expected := "is running version 18.12.1 but the latest edge version is unknown"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	})
+
+	t.Run("Matches development builds against an expected version", func(t *testing.T) {
+		if _, err := version.NewChannels("dev-0123abcd-jane").Match("dev-0123abcd-jane"); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+}
+
+func TestChannel(t *testing.T) {
+	for v, expected := range map[string]string{
+		"stable-2.1.0":      version.StableChannel,
+		"edge-18.12.1":      version.EdgeChannel,
+		"dev-0123abcd-jane": "",
+		"git-0123abcd":      "",
+		"undefined":         "",
+	} {
+		if channel := version.Channel(v); channel != expected {
+			t.Fatalf("Expected channel of %s to be %q, got %q", v, expected, channel)
+		}
+	}
+}
+
+func TestCheckServerVersionOfComponents(t *testing.T) {
+	latest := version.Channels{version.StableChannel: "stable-2.1.0"}
+
+	t.Run("Passes when every component runs the reported version", func(t *testing.T) {
+		apiClient := createMockPublicApi("stable-2.1.0")
+		components := map[string]string{"linkerd-controller": "stable-2.1.0", "linkerd-web": "stable-2.1.0"}
+		channel, err := version.CheckServerVersion(context.Background(), apiClient, latest, components)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if channel != version.StableChannel {
+			t.Fatalf("Expected the stable channel, got %q", channel)
+		}
+	})
+
+	t.Run("Fails with a breakdown when components run different versions", func(t *testing.T) {
+		apiClient := createMockPublicApi("stable-2.1.0")
+		components := map[string]string{
+			"linkerd-web":        "stable-2.0.0",
+			"linkerd-controller": "stable-2.1.0",
+			"linkerd-grafana":    "stable-2.2.0",
+		}
+		_, err := version.CheckServerVersion(context.Background(), apiClient, latest, components)
+		if _, ok := err.(*version.ComponentVersionsError); !ok {
+			t.Fatalf("Expected a ComponentVersionsError, got %v", err)
+		}
+		expected := "the control plane components run different versions, and the public API reports stable-2.1.0: " +
+			"linkerd-controller runs stable-2.1.0, linkerd-grafana runs stable-2.2.0, linkerd-web runs stable-2.0.0 (1 release behind the latest stable version)"
+		if err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%s]", expected, err)
+		}
+	})
+
+	t.Run("Fails when the reported version hasn't been rolled out", func(t *testing.T) {
+		apiClient := createMockPublicApi("stable-2.0.0")
+		components := map[string]string{"linkerd-controller": "stable-2.1.0"}
+		_, err := version.CheckServerVersion(context.Background(), apiClient, latest, components)
+		if _, ok := err.(*version.ComponentVersionsError); !ok {
+			t.Fatalf("Expected a ComponentVersionsError, got %v", err)
+		}
 	})
 }
 
@@ -49,3 +175,47 @@ func createMockPublicApi(version string) *public.MockApiClient {
 		},
 	}
 }
+
+func TestCheckChannels(t *testing.T) {
+	if err := version.CheckChannels("stable-2.1.0", "stable-2.0.0"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err := version.CheckChannels("stable-2.1.0", "edge-18.12.1")
+	if _, ok := err.(*version.ChannelMismatchError); !ok {
+		t.Fatalf("Expected a ChannelMismatchError, got %v", err)
+	}
+	expected := "the CLI runs the stable version 2.1.0 but the control plane runs the edge version 18.12.1; install the matching CLI with \"curl -sL https://run.linkerd.io/install-edge | sh\""
+	if err.Error() != expected {
+		t.Fatalf("Expected error [%s], got [%s]", expected, err)
+	}
+
+	for _, versions := range [][2]string{{"dev-0123abcd-jane", "stable-2.1.0"}, {"edge-18.12.1", "git-0123abcd"}} {
+		if err := version.CheckChannels(versions[0], versions[1]); !version.IsUnreleased(err) {
+			t.Fatalf("Expected an UnreleasedVersionError for %v, got %v", versions, err)
+		}
+	}
+}
+
+func TestCheckMinimumVersion(t *testing.T) {
+	minimums := version.Channels{version.StableChannel: "stable-2.1.0"}
+
+	for _, v := range []string{"stable-2.1.0", "stable-2.2.0", "stable-2.1.0-rc1", "edge-18.1.1", "dev-0123abcd-jane"} {
+		if err := version.CheckMinimumVersion(minimums, v); err != nil {
+			t.Fatalf("Expected %s to be supported, got %s", v, err)
+		}
+	}
+
+	err := version.CheckMinimumVersion(minimums, "stable-2.0.3")
+	if _, ok := err.(*version.UnsupportedVersionError); !ok {
+		t.Fatalf("Expected an UnsupportedVersionError, got %v", err)
+	}
+	expected := "is running version 2.0.3, which is below the minimum supported stable version 2.1.0"
+	if err.Error() != expected {
+		t.Fatalf("Expected error [%s], got [%s]", expected, err)
+	}
+
+	if err := version.CheckMinimumVersion(nil, "stable-2.0.3"); err != nil {
+		t.Fatalf("Expected no minimum to be enforced without minimum supported versions, got %s", err)
+	}
+}